@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"dex-indexer-sol/internal/config"
+	"dex-indexer-sol/internal/logic/backfill"
+	"dex-indexer-sol/internal/logic/eventparser"
+	"dex-indexer-sol/internal/pkg/configloader"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/service"
+	"dex-indexer-sol/internal/svc"
+	"flag"
+	"fmt"
+	"github.com/zeromicro/go-zero/core/logx"
+	zerosvc "github.com/zeromicro/go-zero/core/service"
+	"log"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+	"time"
+)
+
+var (
+	configFile   = flag.String("f", "etc/grpc.yaml", "the config file")
+	fromSlot     = flag.Uint64("from-slot", 0, "start slot (inclusive)")
+	toSlot       = flag.Uint64("to-slot", 0, "end slot (inclusive)")
+	fromTime     = flag.String("from-time", "", "start time, RFC3339 (e.g. 2025-06-01T00:00:00Z), used when --from-slot is not set")
+	toTime       = flag.String("to-time", "", "end time, RFC3339, used when --to-slot is not set")
+	concurrency  = flag.Int("concurrency", 8, "number of concurrent getBlock workers")
+	rateLimit    = flag.Int("rate-limit", 20, "max getBlock requests per second, 0 means unlimited")
+	progressFile = flag.String("progress-file", "", "checkpoint file for resuming, default ./data/backfill_<from>_<to>.json")
+)
+
+func main() {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("panic: %+v\nstack: %s", r, debug.Stack())
+		}
+	}()
+	defer logger.Sync()
+
+	flag.Parse()
+
+	var c config.GrpcConfig
+	if err := configloader.LoadConfig(*configFile, &c); err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	// 历史区块已 finalized，不需要分叉检测与 finalized 标记
+	c.Grpc.EnableForkDetection = false
+	c.Grpc.EmitFinalizedMarker = false
+
+	logger.InitLogger(c.LogConf.ToLogOption())
+	logx.SetWriter(logger.ZapWriter{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+		logger.Infof("收到退出信号，保存进度后退出...")
+		cancel()
+	}()
+
+	from, to, err := resolveRange(ctx, c.Grpc.RpcEndpoint, explicitFlags())
+	if err != nil {
+		log.Fatalf("回填区间无效: %v", err)
+	}
+
+	serviceContext, err := svc.NewGrpcServiceContext(c)
+	if err != nil {
+		panic(err)
+	}
+	defer serviceContext.Close()
+
+	// 注册各协议的指令解析handler
+	eventparser.Init()
+
+	// 初始化全局 mint 注册表：加载持久化数据，按需通过 RPC 补全未知 mint（与 cmd/grpc 一致，保证 decimals 可用）
+	sg := zerosvc.NewServiceGroup()
+	mintRegistryService, err := service.NewMintRegistryService(&c.MintRegistryConf, c.RedisAddr, c.Grpc.RpcEndpoint)
+	if err != nil {
+		panic(err)
+	}
+	sg.Add(mintRegistryService)
+	go sg.Start()
+	defer sg.Stop()
+
+	path := *progressFile
+	if path == "" {
+		path = fmt.Sprintf("./data/backfill_%d_%d.json", from, to)
+	}
+	backfiller, err := backfill.NewBackfiller(serviceContext, backfill.Options{
+		FromSlot:     from,
+		ToSlot:       to,
+		Concurrency:  *concurrency,
+		RateLimit:    *rateLimit,
+		ProgressFile: path,
+	})
+	if err != nil {
+		log.Fatalf("回填初始化失败: %v", err)
+	}
+
+	if err = backfiller.Run(ctx); err != nil {
+		logger.Errorf("回填中断: %v（进度已保存至 %s，重新执行相同命令即可继续）", err, path)
+		return
+	}
+}
+
+// explicitFlags 返回命令行中显式指定的参数（区分未指定与指定为 0，如 --from-slot 0）
+func explicitFlags() map[string]bool {
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	return set
+}
+
+// resolveRange 解析回填区间：优先使用显式指定的 slot 参数，未指定时按时间换算
+func resolveRange(ctx context.Context, rpcEndpoint string, set map[string]bool) (uint64, uint64, error) {
+	from, to := *fromSlot, *toSlot
+
+	if !set["from-slot"] {
+		if *fromTime == "" {
+			return 0, 0, fmt.Errorf("--from-slot or --from-time is required")
+		}
+		slot, err := resolveTime(ctx, rpcEndpoint, *fromTime, 0)
+		if err != nil {
+			return 0, 0, err
+		}
+		from = slot
+	}
+	if !set["to-slot"] {
+		if *toTime == "" {
+			return 0, 0, fmt.Errorf("--to-slot or --to-time is required")
+		}
+		// 区间包含 to-time 当秒：取下一秒的第一个 slot 再减一
+		slot, err := resolveTime(ctx, rpcEndpoint, *toTime, time.Second)
+		if err != nil {
+			return 0, 0, err
+		}
+		if slot == 0 {
+			return 0, 0, fmt.Errorf("to time %s is before the first available block", *toTime)
+		}
+		to = slot - 1
+	}
+	if to < from {
+		return 0, 0, fmt.Errorf("to slot %d < from slot %d", to, from)
+	}
+	return from, to, nil
+}
+
+func resolveTime(ctx context.Context, rpcEndpoint string, value string, offset time.Duration) (uint64, error) {
+	ts, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %w", value, err)
+	}
+	return backfill.ResolveSlotByTime(ctx, rpcEndpoint, ts.Add(offset))
+}
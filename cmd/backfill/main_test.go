@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRpc 模拟 RPC 节点：可用区块 [1000, 2000]，slot 的 blockTime 即为 slot 本身，奇数 slot 被跳过
+func newTestRpc(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+			Params []any  `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "getFirstAvailableBlock":
+			resp["result"] = 1000
+		case "getSlot":
+			resp["result"] = 2000
+		case "getBlockTime":
+			if slot := uint64(req.Params[0].(float64)); slot%2 == 0 {
+				resp["result"] = slot
+			} else {
+				resp["error"] = map[string]any{"code": -32009, "message": "slot skipped"}
+			}
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// setRangeFlags 设置区间参数并返回显式指定的参数集合，测试结束后恢复默认值
+func setRangeFlags(t *testing.T, from, to *uint64, fromT, toT string) map[string]bool {
+	t.Helper()
+	set := make(map[string]bool)
+	*fromSlot, *toSlot, *fromTime, *toTime = 0, 0, fromT, toT
+	if from != nil {
+		*fromSlot = *from
+		set["from-slot"] = true
+	}
+	if to != nil {
+		*toSlot = *to
+		set["to-slot"] = true
+	}
+	t.Cleanup(func() { *fromSlot, *toSlot, *fromTime, *toTime = 0, 0, "", "" })
+	return set
+}
+
+func slotPtr(slot uint64) *uint64 { return &slot }
+
+func TestResolveRange_ExplicitSlots(t *testing.T) {
+	set := setRangeFlags(t, slotPtr(100), slotPtr(200), "", "")
+	from, to, err := resolveRange(context.Background(), "", set)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), from)
+	assert.Equal(t, uint64(200), to)
+}
+
+func TestResolveRange_ExplicitZeroSlot(t *testing.T) {
+	// --from-slot 0 是合法的起点，不能当作未指定去要求 --from-time
+	set := setRangeFlags(t, slotPtr(0), slotPtr(10), "", "")
+	from, to, err := resolveRange(context.Background(), "", set)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), from)
+	assert.Equal(t, uint64(10), to)
+
+	set = setRangeFlags(t, slotPtr(0), slotPtr(0), "", "")
+	from, to, err = resolveRange(context.Background(), "", set)
+	require.NoError(t, err)
+	assert.Equal(t, [2]uint64{0, 0}, [2]uint64{from, to})
+}
+
+func TestResolveRange_MissingBounds(t *testing.T) {
+	_, _, err := resolveRange(context.Background(), "", setRangeFlags(t, nil, slotPtr(10), "", ""))
+	assert.ErrorContains(t, err, "--from-slot or --from-time is required")
+
+	_, _, err = resolveRange(context.Background(), "", setRangeFlags(t, slotPtr(10), nil, "", ""))
+	assert.ErrorContains(t, err, "--to-slot or --to-time is required")
+}
+
+func TestResolveRange_InvalidRange(t *testing.T) {
+	_, _, err := resolveRange(context.Background(), "", setRangeFlags(t, slotPtr(200), slotPtr(100), "", ""))
+	assert.ErrorContains(t, err, "to slot 100 < from slot 200")
+
+	_, _, err = resolveRange(context.Background(), "", setRangeFlags(t, nil, slotPtr(100), "2025-06-01", ""))
+	assert.ErrorContains(t, err, "invalid time")
+}
+
+func TestResolveRange_ByTime(t *testing.T) {
+	endpoint := newTestRpc(t)
+	fromT := time.Unix(1500, 0).UTC().Format(time.RFC3339)
+	toT := time.Unix(1600, 0).UTC().Format(time.RFC3339)
+
+	// 二分查找可能停在被跳过的 slot 上：from = 1499（无区块），区间仍以 blockTime = 1500 的区块开始；
+	// to 包含 to-time 当秒：下一秒对应的 slot（1601，被跳过）减一
+	from, to, err := resolveRange(context.Background(), endpoint, setRangeFlags(t, nil, nil, fromT, toT))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1499), from)
+	assert.Equal(t, uint64(1600), to)
+
+	// 显式 slot 优先于时间
+	from, to, err = resolveRange(context.Background(), endpoint, setRangeFlags(t, slotPtr(1200), nil, fromT, toT))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1200), from)
+	assert.Equal(t, uint64(1600), to)
+}
@@ -10,6 +10,7 @@ import (
 	"dex-indexer-sol/internal/service"
 	"dex-indexer-sol/internal/svc"
 	"flag"
+	"fmt"
 	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
 	"github.com/zeromicro/go-zero/core/logx"
 	zerosvc "github.com/zeromicro/go-zero/core/service"
@@ -20,7 +21,16 @@ import (
 	"syscall"
 )
 
-var configFile = flag.String("f", "etc/grpc.yaml", "the config file")
+var (
+	configFile = flag.String("f", "etc/grpc.yaml", "the config file")
+	replayPath = flag.String("replay", "", "replay captured blocks from a file or directory instead of subscribing gRPC")
+	replayPace = flag.String("replay-pace", "max", "replay pace: original (as captured) or max (as fast as possible)")
+
+	// 回放不能写入线上 topic：必须显式指定输出 topic，或只解析不发送
+	replayEventTopic   = flag.String("replay-event-topic", "", "kafka topic for replayed events (required with -replay unless -replay-dry-run)")
+	replayBalanceTopic = flag.String("replay-balance-topic", "", "kafka topic for replayed balances (required with -replay unless -replay-dry-run)")
+	replayDryRun       = flag.Bool("replay-dry-run", false, "parse replayed blocks and build kafka jobs without sending")
+)
 
 func main() {
 	defer func() {
@@ -37,6 +47,12 @@ func main() {
 		log.Fatalf("配置加载失败: %v", err)
 	}
 
+	if *replayPath != "" {
+		if err := applyReplayConfig(&c); err != nil {
+			log.Fatalf("回放参数无效: %v", err)
+		}
+	}
+
 	// === 初始化 zap logger 并接管 logx 输出 ===
 	logger.InitLogger(c.LogConf.ToLogOption())
 	logx.SetWriter(logger.ZapWriter{})
@@ -59,16 +75,39 @@ func main() {
 
 	sg.Add(priceSyncService)
 
-	blockChan := make(chan *pb.SubscribeUpdateBlock, 200)
-	defer close(blockChan)
-
-	grpcService, err := grpc.NewGrpcStreamManager(serviceContext, blockChan)
+	// 初始化全局 mint 注册表：加载持久化数据，按需通过 RPC 补全未知 mint
+	mintRegistryService, err := service.NewMintRegistryService(&c.MintRegistryConf, c.RedisAddr, c.Grpc.RpcEndpoint)
 	if err != nil {
 		panic(err)
 	}
-	sg.Add(grpcService)
+	sg.Add(mintRegistryService)
+
+	blockChan := make(chan *pb.SubscribeUpdateBlock, 200)
+	defer close(blockChan)
 
 	blockProcessor := grpc.NewBlockProcessor(serviceContext, blockChan)
+	if *replayPath != "" {
+		// 回放的区块与线上进度无关：不判重也不写进度
+		serviceContext.ProgressManager = nil
+		blockProcessor.SetDryRun(*replayDryRun)
+	}
+
+	var grpcService *grpc.GrpcStreamGroup
+	if *replayPath != "" {
+		// 离线回放：读取抓包文件代替 gRPC 订阅
+		replaySource, err := grpc.NewBlockReplaySource(*replayPath, *replayPace == "original", blockChan)
+		if err != nil {
+			panic(err)
+		}
+		sg.Add(replaySource)
+	} else {
+		// 订阅全部 gRPC endpoint 并按 slot 去重；SlotChecker 检测到漏扫 slot 后，通过 RPC 补块并交给 blockProcessor 处理
+		grpcService, err = grpc.NewGrpcStreamGroup(serviceContext, blockChan, blockProcessor)
+		if err != nil {
+			panic(err)
+		}
+		sg.Add(grpcService)
+	}
 	sg.Add(blockProcessor)
 
 	if c.Monitor.Port > 0 {
@@ -76,6 +115,10 @@ func main() {
 		sg.Add(monitorServer)
 	}
 
+	// 收到 SIGHUP 时重新加载配置中的账户过滤条件，并热更新到正在运行的订阅流；
+	// 无论哪种模式都要接管 SIGHUP，否则默认行为会直接终止进程
+	go reloadAccountFilterOnSighup(grpcService)
+
 	// 启动服务
 	logger.Infof("索引器服务启动成功")
 	sg.Start()
@@ -88,3 +131,38 @@ func main() {
 	logger.Info("Shutting down services...")
 	sg.Stop()
 }
+
+// applyReplayConfig 将 Kafka 输出切换到回放专用 topic，并关闭依赖实时 slot 状态的分叉检测与 finalized 标记
+func applyReplayConfig(c *config.GrpcConfig) error {
+	if !*replayDryRun {
+		if *replayEventTopic == "" || *replayBalanceTopic == "" {
+			return fmt.Errorf("-replay requires -replay-event-topic and -replay-balance-topic, or -replay-dry-run")
+		}
+		c.KafkaProducerConf.Topics.Event = *replayEventTopic
+		c.KafkaProducerConf.Topics.Balance = *replayBalanceTopic
+	}
+	c.Grpc.EnableForkDetection = false
+	c.Grpc.EmitFinalizedMarker = false
+	return nil
+}
+
+func reloadAccountFilterOnSighup(grpcService *grpc.GrpcStreamGroup) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		if grpcService == nil {
+			logger.Warnf("离线回放模式不支持热更新账户过滤条件, 已忽略 SIGHUP")
+			continue
+		}
+		var c config.GrpcConfig
+		if err := configloader.LoadConfig(*configFile, &c); err != nil {
+			logger.Errorf("重新加载配置失败: %v", err)
+			continue
+		}
+		if err := grpcService.UpdateAccountFilter(c.Grpc.AccountFilter); err != nil {
+			logger.Errorf("更新账户过滤条件失败: %v", err)
+			continue
+		}
+		logger.Infof("账户过滤条件已更新")
+	}
+}
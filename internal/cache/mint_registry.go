@@ -0,0 +1,419 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"sync"
+	"time"
+
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/types"
+)
+
+// MintInfo 表示一个 mint 的基础信息。
+// Decimals / TokenProgram 可从任意涉及该 mint 的交易中获得；
+// Supply 与 authority 只有读取过链上 mint 账户（RPC）或见到 InitializeMint 指令时才有效（HasAccount = true）。
+type MintInfo struct {
+	Decimals        uint8
+	TokenProgram    types.Pubkey // Token / Token-2022，零值表示未知
+	Supply          uint64       // 最近一次读取链上账户时的总供应量
+	MintAuthority   types.Pubkey // 零值表示无 mint authority
+	FreezeAuthority types.Pubkey // 零值表示无 freeze authority
+	HasAccount      bool         // Supply / MintAuthority / FreezeAuthority 是否有效
+
+	// Token-2022 TransferFeeConfig 扩展，HasTransferFee = false 表示无转账手续费或未知
+	HasTransferFee   bool
+	OlderTransferFee TransferFee // NewerTransferFee 生效前使用的费率
+	NewerTransferFee TransferFee
+}
+
+// TransferFee 表示 Token-2022 转账手续费率，自 Epoch 起生效
+type TransferFee struct {
+	Epoch       uint64
+	MaximumFee  uint64 // 单笔手续费上限（最小单位）
+	BasisPoints uint16 // 费率（万分之一）
+}
+
+// TransferFeeAt 计算指定 epoch 下转账 amount 时由目标账户预扣的手续费（与 Token-2022 一致，向上取整）
+func (info *MintInfo) TransferFeeAt(epoch, amount uint64) uint64 {
+	if !info.HasTransferFee {
+		return 0
+	}
+	fee := info.OlderTransferFee
+	if epoch >= info.NewerTransferFee.Epoch {
+		fee = info.NewerTransferFee
+	}
+	if fee.BasisPoints == 0 || amount == 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(amount, uint64(fee.BasisPoints))
+	lo, carry := bits.Add64(lo, maxBasisPoints-1, 0)
+	hi += carry
+	if hi >= maxBasisPoints {
+		return fee.MaximumFee // 商溢出 u64，必然超过上限
+	}
+	quotient, _ := bits.Div64(hi, lo, maxBasisPoints)
+	return min(quotient, fee.MaximumFee)
+}
+
+const maxBasisPoints = 10000
+
+// mintRecordSize 为持久化记录长度：version(1) + mint(32) + decimals(1) + program(32) + supply(8) + mintAuth(32) + freezeAuth(32) + flags(1)
+// + older/newer transfer fee 各 (epoch(8) + maximumFee(8) + basisPoints(2))
+const mintRecordSize = 1 + 32 + 1 + 32 + 8 + 32 + 32 + 1 + 2*transferFeeRecordSize
+
+// mintRecordVersion 为记录格式版本，记录布局变化时递增；版本不一致的记录在加载时丢弃（可由交易与 RPC 重新填充）
+const mintRecordVersion = 1
+
+// errMintRecordVersion 表示记录版本或长度与当前格式不一致
+var errMintRecordVersion = errors.New("mint record version mismatch")
+
+const transferFeeRecordSize = 8 + 8 + 2
+
+const (
+	mintFlagHasAccount     = 1 << 0
+	mintFlagHasTransferFee = 1 << 1
+)
+
+// maxPendingMints 限制待 RPC 补全的未知 mint 数量，避免 RPC 不可用时无限堆积
+const maxPendingMints = 10000
+
+// maxNegativeMints 限制 RPC 确认不是 mint 的账户缓存数量
+const maxNegativeMints = 100000
+
+// defaultNegativeTTL 为未配置时 RPC 确认不是 mint 的账户的缓存时长，过期后允许再次查询（账户可能之后才被创建为 mint）
+const defaultNegativeTTL = 10 * time.Minute
+
+// MintRegistry 是进程级、并发安全的 mint 信息注册表，由所有处理过的交易持续填充，
+// 在当前交易缺少 decimals 时作为兜底（见 core.AdaptedTx.GetDecimalsByMint）。
+type MintRegistry struct {
+	mu         sync.RWMutex
+	mints      map[types.Pubkey]MintInfo
+	dirty      map[types.Pubkey]struct{}  // 自上次持久化以来变更的 mint
+	pending    map[types.Pubkey]struct{}  // 查询未命中、等待 RPC 补全的 mint
+	negative   map[types.Pubkey]time.Time // RPC 确认不存在或不是 mint 的账户 → 过期时间，期间不再重复查询
+	maxEntries int                        // 最多缓存的 mint 数量，0 表示不限制
+	trackMiss  bool                       // 是否记录未命中的 mint（开启 RPC 补全时）
+	negTTL     time.Duration              // negative 记录的缓存时长
+}
+
+var defaultMintRegistry = NewMintRegistry()
+
+// Mints 返回进程级的 mint 注册表
+func Mints() *MintRegistry {
+	return defaultMintRegistry
+}
+
+func NewMintRegistry() *MintRegistry {
+	return &MintRegistry{
+		mints:    make(map[types.Pubkey]MintInfo),
+		dirty:    make(map[types.Pubkey]struct{}),
+		pending:  make(map[types.Pubkey]struct{}),
+		negative: make(map[types.Pubkey]time.Time),
+		negTTL:   defaultNegativeTTL,
+	}
+}
+
+// Configure 设置容量上限、是否记录未命中的 mint 以及非 mint 账户的缓存时长（<= 0 时使用默认值），需在开始处理交易前调用
+func (r *MintRegistry) Configure(maxEntries int, trackMiss bool, negativeTTL time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxEntries = maxEntries
+	r.trackMiss = trackMiss
+	if negativeTTL <= 0 {
+		negativeTTL = defaultNegativeTTL
+	}
+	r.negTTL = negativeTTL
+}
+
+// Len 返回已缓存的 mint 数量
+func (r *MintRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.mints)
+}
+
+// Get 返回 mint 的完整信息
+func (r *MintRegistry) Get(mint types.Pubkey) (MintInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	info, ok := r.mints[mint]
+	return info, ok
+}
+
+// Decimals 返回 mint 的精度；未命中时记录该 mint，等待 RPC 补全
+func (r *MintRegistry) Decimals(mint types.Pubkey) (uint8, bool) {
+	r.mu.RLock()
+	info, ok := r.mints[mint]
+	trackMiss := r.trackMiss
+	r.mu.RUnlock()
+	if ok {
+		return info.Decimals, true
+	}
+
+	if trackMiss && mint != (types.Pubkey{}) {
+		r.mu.Lock()
+		if len(r.pending) < maxPendingMints && !r.isNegativeLocked(mint, time.Now()) {
+			r.pending[mint] = struct{}{}
+		}
+		r.mu.Unlock()
+	}
+	return 0, false
+}
+
+// MarkNotMint 记录 RPC 确认不存在或不是 mint 的账户，缓存期内查询未命中时不再提交 RPC 补全
+func (r *MintRegistry) MarkNotMint(accounts []types.Pubkey) {
+	if len(accounts) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if len(r.negative)+len(accounts) > maxNegativeMints {
+		for account, expire := range r.negative {
+			if !now.Before(expire) {
+				delete(r.negative, account)
+			}
+		}
+	}
+	expire := now.Add(r.negTTL)
+	for _, account := range accounts {
+		if len(r.negative) >= maxNegativeMints {
+			return
+		}
+		r.negative[account] = expire
+	}
+}
+
+// isNegativeLocked 判断账户是否在非 mint 缓存期内，过期记录顺带删除（需持有写锁）
+func (r *MintRegistry) isNegativeLocked(account types.Pubkey, now time.Time) bool {
+	expire, ok := r.negative[account]
+	if !ok {
+		return false
+	}
+	if now.Before(expire) {
+		return true
+	}
+	delete(r.negative, account)
+	return false
+}
+
+// ObserveDecimals 记录交易 token 余额中出现的 mint 精度与所属 Token Program，信息未变化时只加读锁
+func (r *MintRegistry) ObserveDecimals(mint types.Pubkey, decimals uint8, tokenProgram types.Pubkey) {
+	r.mu.RLock()
+	info, ok := r.mints[mint]
+	r.mu.RUnlock()
+	if ok && info.Decimals == decimals && (tokenProgram == (types.Pubkey{}) || info.TokenProgram == tokenProgram) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info, ok = r.mints[mint]
+	if !ok && !r.hasRoomLocked() {
+		return
+	}
+	info.Decimals = decimals
+	if tokenProgram != (types.Pubkey{}) {
+		info.TokenProgram = tokenProgram
+	}
+	r.putLocked(mint, info)
+
+	// Token-2022 mint 可能带转账手续费等扩展，只能通过读取链上账户获得
+	if !ok && r.trackMiss && tokenProgram == consts.TokenProgram2022 && len(r.pending) < maxPendingMints {
+		r.pending[mint] = struct{}{}
+	}
+}
+
+// ObserveInitMint 记录 InitializeMint 指令创建的 mint，新 mint 的 supply 为 0。
+// Token-2022 扩展在 InitializeMint 之前初始化，已记录的转账手续费配置保持不变。
+func (r *MintRegistry) ObserveInitMint(mint types.Pubkey, decimals uint8, tokenProgram, mintAuthority, freezeAuthority types.Pubkey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info, ok := r.mints[mint]
+	if !ok && !r.hasRoomLocked() {
+		return
+	}
+	info.Decimals = decimals
+	info.TokenProgram = tokenProgram
+	info.Supply = 0
+	info.MintAuthority = mintAuthority
+	info.FreezeAuthority = freezeAuthority
+	info.HasAccount = true
+	r.putLocked(mint, info)
+}
+
+// ObserveTransferFeeConfig 记录 InitializeTransferFeeConfig 指令设置的初始费率
+func (r *MintRegistry) ObserveTransferFeeConfig(mint types.Pubkey, fee TransferFee) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info, ok := r.mints[mint]
+	if !ok && !r.hasRoomLocked() {
+		return
+	}
+	info.HasTransferFee = true
+	info.OlderTransferFee = fee
+	info.NewerTransferFee = fee
+	r.putLocked(mint, info)
+}
+
+// ObserveSetTransferFee 记录 SetTransferFee 指令设置的新费率（epoch 为指令执行时的 epoch，新费率两个 epoch 后生效），
+// 仅更新已知开启转账手续费的 mint
+func (r *MintRegistry) ObserveSetTransferFee(mint types.Pubkey, epoch uint64, maximumFee uint64, basisPoints uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info, ok := r.mints[mint]
+	if !ok || !info.HasTransferFee {
+		return
+	}
+	if info.NewerTransferFee.Epoch <= epoch {
+		info.OlderTransferFee = info.NewerTransferFee
+	}
+	info.NewerTransferFee = TransferFee{Epoch: epoch + 2, MaximumFee: maximumFee, BasisPoints: basisPoints}
+	r.putLocked(mint, info)
+}
+
+// Put 写入（覆盖）mint 的完整信息，用于链上账户数据
+func (r *MintRegistry) Put(mint types.Pubkey, info MintInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.mints[mint]; !ok && !r.hasRoomLocked() {
+		return
+	}
+	r.putLocked(mint, info)
+}
+
+// Load 批量载入持久化数据，不标记为待持久化
+func (r *MintRegistry) Load(mints map[types.Pubkey]MintInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for mint, info := range mints {
+		if _, ok := r.mints[mint]; ok {
+			continue // 运行期间观察到的数据更新
+		}
+		if !r.hasRoomLocked() {
+			return
+		}
+		r.mints[mint] = info
+	}
+}
+
+// TakeDirty 取出并清空自上次调用以来变更的 mint
+func (r *MintRegistry) TakeDirty() map[types.Pubkey]MintInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.dirty) == 0 {
+		return nil
+	}
+	result := make(map[types.Pubkey]MintInfo, len(r.dirty))
+	for mint := range r.dirty {
+		result[mint] = r.mints[mint]
+	}
+	clear(r.dirty)
+	return result
+}
+
+// MarkDirty 将持久化失败的 mint 重新标记为待持久化
+func (r *MintRegistry) MarkDirty(mints map[types.Pubkey]MintInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for mint := range mints {
+		if _, ok := r.mints[mint]; ok {
+			r.dirty[mint] = struct{}{}
+		}
+	}
+}
+
+// TakePending 取出最多 limit 个等待 RPC 补全的 mint
+func (r *MintRegistry) TakePending(limit int) []types.Pubkey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	result := make([]types.Pubkey, 0, min(limit, len(r.pending)))
+	for mint := range r.pending {
+		if len(result) >= limit {
+			break
+		}
+		delete(r.pending, mint)
+		if info, ok := r.mints[mint]; ok && info.HasAccount {
+			continue // 等待期间已从交易中获得完整信息
+		}
+		if r.isNegativeLocked(mint, now) {
+			continue
+		}
+		result = append(result, mint)
+	}
+	return result
+}
+
+func (r *MintRegistry) hasRoomLocked() bool {
+	return r.maxEntries <= 0 || len(r.mints) < r.maxEntries
+}
+
+func (r *MintRegistry) putLocked(mint types.Pubkey, info MintInfo) {
+	r.mints[mint] = info
+	r.dirty[mint] = struct{}{}
+	delete(r.negative, mint)
+	if info.HasAccount {
+		delete(r.pending, mint)
+	}
+}
+
+// encodeMintRecord 将 mint 与信息编码为定长记录，用于磁盘与 Redis 持久化
+func encodeMintRecord(buf []byte, mint types.Pubkey, info MintInfo) []byte {
+	buf = append(buf, mintRecordVersion)
+	buf = append(buf, mint[:]...)
+	buf = append(buf, info.Decimals)
+	buf = append(buf, info.TokenProgram[:]...)
+	buf = binary.LittleEndian.AppendUint64(buf, info.Supply)
+	buf = append(buf, info.MintAuthority[:]...)
+	buf = append(buf, info.FreezeAuthority[:]...)
+	var flags byte
+	if info.HasAccount {
+		flags |= mintFlagHasAccount
+	}
+	if info.HasTransferFee {
+		flags |= mintFlagHasTransferFee
+	}
+	buf = append(buf, flags)
+	buf = encodeTransferFee(buf, info.OlderTransferFee)
+	return encodeTransferFee(buf, info.NewerTransferFee)
+}
+
+func encodeTransferFee(buf []byte, fee TransferFee) []byte {
+	buf = binary.LittleEndian.AppendUint64(buf, fee.Epoch)
+	buf = binary.LittleEndian.AppendUint64(buf, fee.MaximumFee)
+	return binary.LittleEndian.AppendUint16(buf, fee.BasisPoints)
+}
+
+func decodeTransferFee(data []byte) TransferFee {
+	return TransferFee{
+		Epoch:       binary.LittleEndian.Uint64(data[0:8]),
+		MaximumFee:  binary.LittleEndian.Uint64(data[8:16]),
+		BasisPoints: binary.LittleEndian.Uint16(data[16:18]),
+	}
+}
+
+// decodeMintRecord 解码 encodeMintRecord 生成的定长记录，版本或长度不一致时返回 errMintRecordVersion
+func decodeMintRecord(data []byte) (types.Pubkey, MintInfo, error) {
+	var (
+		mint types.Pubkey
+		info MintInfo
+	)
+	if len(data) != mintRecordSize || data[0] != mintRecordVersion {
+		return mint, info, errMintRecordVersion
+	}
+	data = data[1:]
+	copy(mint[:], data[0:32])
+	info.Decimals = data[32]
+	copy(info.TokenProgram[:], data[33:65])
+	info.Supply = binary.LittleEndian.Uint64(data[65:73])
+	copy(info.MintAuthority[:], data[73:105])
+	copy(info.FreezeAuthority[:], data[105:137])
+	info.HasAccount = data[137]&mintFlagHasAccount != 0
+	info.HasTransferFee = data[137]&mintFlagHasTransferFee != 0
+	info.OlderTransferFee = decodeTransferFee(data[138:156])
+	info.NewerTransferFee = decodeTransferFee(data[156:174])
+	return mint, info, nil
+}
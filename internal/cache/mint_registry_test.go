@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/internal/pkg/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMintRegistry_ObserveDecimals(t *testing.T) {
+	r := NewMintRegistry()
+	mint := testfixture.Key("mint:a")
+
+	_, ok := r.Decimals(mint)
+	assert.False(t, ok)
+	assert.Empty(t, r.TakePending(10), "未开启 RPC 补全时不记录未命中")
+
+	r.ObserveDecimals(mint, 6, consts.TokenProgram)
+	decimals, ok := r.Decimals(mint)
+	require.True(t, ok)
+	assert.Equal(t, uint8(6), decimals)
+
+	// 未知 Token Program 不覆盖已知值
+	r.ObserveDecimals(mint, 6, types.Pubkey{})
+	info, _ := r.Get(mint)
+	assert.Equal(t, consts.TokenProgram, info.TokenProgram)
+	assert.False(t, info.HasAccount, "交易余额中只能得到 decimals 与 Token Program")
+
+	assert.Equal(t, map[types.Pubkey]MintInfo{mint: info}, r.TakeDirty())
+	r.ObserveDecimals(mint, 6, consts.TokenProgram)
+	assert.Nil(t, r.TakeDirty(), "信息未变化时不标记为待持久化")
+}
+
+func TestMintRegistry_ObserveInitMint(t *testing.T) {
+	r := NewMintRegistry()
+	mint, authority := testfixture.Key("mint:a"), testfixture.Key("mint:authority")
+	r.Put(mint, MintInfo{Decimals: 9, Supply: 100, HasAccount: true})
+
+	r.ObserveInitMint(mint, 6, consts.TokenProgram2022, authority, types.Pubkey{})
+	info, ok := r.Get(mint)
+	require.True(t, ok)
+	assert.Equal(t, MintInfo{Decimals: 6, TokenProgram: consts.TokenProgram2022, MintAuthority: authority, HasAccount: true}, info,
+		"新建的 mint supply 为 0，覆盖旧数据")
+}
+
+func TestMintRegistry_PendingAndNegative(t *testing.T) {
+	r := NewMintRegistry()
+	r.Configure(0, true, time.Hour)
+	a, b, c := testfixture.Key("mint:a"), testfixture.Key("mint:b"), testfixture.Key("mint:c")
+
+	r.Decimals(a)
+	r.Decimals(b)
+	r.Decimals(types.Pubkey{}) // 零值地址不补全
+	r.ObserveDecimals(c, 6, consts.TokenProgram2022)
+	r.ObserveDecimals(testfixture.Key("mint:d"), 6, consts.TokenProgram)
+
+	// Token-2022 mint 需读取链上账户获得扩展，即使 decimals 已知也等待补全
+	assert.ElementsMatch(t, []types.Pubkey{a, b, c}, r.TakePending(10))
+	assert.Empty(t, r.TakePending(10), "取出后清空")
+
+	// RPC 确认不是 mint 的账户在缓存期内不再补全
+	r.MarkNotMint([]types.Pubkey{a})
+	r.Decimals(a)
+	r.Decimals(b)
+	assert.Equal(t, []types.Pubkey{b}, r.TakePending(10))
+
+	// 之后见到该 mint 时清除 negative 记录
+	r.ObserveDecimals(a, 6, consts.TokenProgram)
+	assert.NotContains(t, r.negative, a)
+
+	// 等待期间已获得完整信息的 mint 不再查询
+	r.Decimals(b)
+	r.Put(b, MintInfo{Decimals: 9, HasAccount: true})
+	assert.Empty(t, r.TakePending(10))
+}
+
+func TestMintRegistry_NegativeExpires(t *testing.T) {
+	r := NewMintRegistry()
+	r.Configure(0, true, time.Millisecond)
+	a := testfixture.Key("mint:a")
+
+	r.MarkNotMint([]types.Pubkey{a})
+	time.Sleep(5 * time.Millisecond)
+	r.Decimals(a)
+	assert.Equal(t, []types.Pubkey{a}, r.TakePending(10), "缓存过期后允许再次查询")
+	assert.NotContains(t, r.negative, a)
+}
+
+func TestMintRegistry_MaxEntries(t *testing.T) {
+	r := NewMintRegistry()
+	r.Configure(2, false, 0)
+	a, b, c := testfixture.Key("mint:a"), testfixture.Key("mint:b"), testfixture.Key("mint:c")
+
+	r.ObserveDecimals(a, 6, consts.TokenProgram)
+	r.Put(b, MintInfo{Decimals: 9, HasAccount: true})
+	r.ObserveDecimals(c, 6, consts.TokenProgram)
+	r.ObserveInitMint(c, 6, consts.TokenProgram, types.Pubkey{}, types.Pubkey{})
+	r.Load(map[types.Pubkey]MintInfo{c: {Decimals: 6}})
+	assert.Equal(t, 2, r.Len())
+	_, ok := r.Get(c)
+	assert.False(t, ok, "达到上限后不再新增")
+
+	// 已存在的 mint 仍可更新
+	r.ObserveDecimals(a, 8, consts.TokenProgram)
+	decimals, _ := r.Decimals(a)
+	assert.Equal(t, uint8(8), decimals)
+}
+
+func TestMintRegistry_LoadKeepsObserved(t *testing.T) {
+	r := NewMintRegistry()
+	a, b := testfixture.Key("mint:a"), testfixture.Key("mint:b")
+	r.ObserveDecimals(a, 6, consts.TokenProgram)
+	r.TakeDirty()
+
+	r.Load(map[types.Pubkey]MintInfo{a: {Decimals: 9}, b: {Decimals: 5}})
+	decimals, _ := r.Decimals(a)
+	assert.Equal(t, uint8(6), decimals, "运行期间观察到的数据优先")
+	decimals, _ = r.Decimals(b)
+	assert.Equal(t, uint8(5), decimals)
+	assert.Nil(t, r.TakeDirty(), "载入的数据不需要再次持久化")
+}
+
+func TestMintRegistry_MarkDirty(t *testing.T) {
+	r := NewMintRegistry()
+	a := testfixture.Key("mint:a")
+	r.ObserveDecimals(a, 6, consts.TokenProgram)
+	dirty := r.TakeDirty()
+	require.Len(t, dirty, 1)
+
+	// 持久化失败后重新标记；不在注册表中的 mint 忽略
+	dirty[testfixture.Key("mint:unknown")] = MintInfo{}
+	r.MarkDirty(dirty)
+	again := r.TakeDirty()
+	assert.Len(t, again, 1)
+	assert.Contains(t, again, a)
+}
+
+func TestMintRecord_RoundTrip(t *testing.T) {
+	mint := testfixture.Key("mint:a")
+	info := MintInfo{
+		Decimals:         6,
+		TokenProgram:     consts.TokenProgram2022,
+		Supply:           1<<63 + 5,
+		MintAuthority:    testfixture.Key("mint:authority"),
+		FreezeAuthority:  testfixture.Key("mint:freeze"),
+		HasAccount:       true,
+		HasTransferFee:   true,
+		OlderTransferFee: TransferFee{Epoch: 700, MaximumFee: 1_000_000, BasisPoints: 50},
+		NewerTransferFee: TransferFee{Epoch: 702, MaximumFee: 2_000_000, BasisPoints: 100},
+	}
+	record := encodeMintRecord(nil, mint, info)
+	require.Len(t, record, mintRecordSize)
+
+	gotMint, gotInfo, err := decodeMintRecord(record)
+	require.NoError(t, err)
+	assert.Equal(t, mint, gotMint)
+	assert.Equal(t, info, gotInfo)
+
+	_, _, err = decodeMintRecord(record[:len(record)-1])
+	assert.ErrorIs(t, err, errMintRecordVersion)
+	record[0] = mintRecordVersion + 1
+	_, _, err = decodeMintRecord(record)
+	assert.ErrorIs(t, err, errMintRecordVersion)
+}
+
+func TestMintInfo_TransferFeeAt(t *testing.T) {
+	info := MintInfo{
+		HasTransferFee:   true,
+		OlderTransferFee: TransferFee{Epoch: 0, MaximumFee: 5_000, BasisPoints: 100},
+		NewerTransferFee: TransferFee{Epoch: 600, MaximumFee: 1_000_000, BasisPoints: 250},
+	}
+
+	assert.Equal(t, uint64(10), info.TransferFeeAt(599, 1_000))
+	assert.Equal(t, uint64(1), info.TransferFeeAt(599, 1), "向上取整")
+	assert.Equal(t, uint64(2), info.TransferFeeAt(599, 101))
+	assert.Equal(t, uint64(5_000), info.TransferFeeAt(599, 1_000_000), "不超过 maximum_fee")
+	assert.Zero(t, info.TransferFeeAt(599, 0))
+
+	// 自 NewerTransferFee.Epoch 起使用新费率
+	assert.Equal(t, uint64(25), info.TransferFeeAt(600, 1_000))
+	assert.Equal(t, uint64(1_000_000), info.TransferFeeAt(601, 1<<62))
+	// amount * basis_points 溢出 u64 时按 128 位计算：ceil(MaxUint64 * 250 / 10000) = ceil(MaxUint64 / 40)
+	info.NewerTransferFee.MaximumFee = ^uint64(0)
+	assert.Equal(t, ^uint64(0)/40+1, info.TransferFeeAt(600, ^uint64(0)))
+
+	info.NewerTransferFee.BasisPoints = 0
+	assert.Zero(t, info.TransferFeeAt(600, 1_000))
+	info.HasTransferFee = false
+	assert.Zero(t, info.TransferFeeAt(0, 1_000), "未开启转账手续费")
+}
+
+func TestMintRegistry_ObserveTransferFee(t *testing.T) {
+	r := NewMintRegistry()
+	mint, plain := testfixture.Key("mint:fee"), testfixture.Key("mint:plain")
+
+	r.ObserveSetTransferFee(mint, 500, 10, 10)
+	_, ok := r.Get(mint)
+	assert.False(t, ok, "未知 mint 的 SetTransferFee 无法确定旧费率，忽略")
+	r.ObserveDecimals(plain, 6, consts.TokenProgram2022)
+	r.ObserveSetTransferFee(plain, 500, 10, 10)
+	info, _ := r.Get(plain)
+	assert.False(t, info.HasTransferFee)
+
+	initial := TransferFee{Epoch: 400, MaximumFee: 5_000, BasisPoints: 100}
+	r.ObserveTransferFeeConfig(mint, initial)
+	info, ok = r.Get(mint)
+	require.True(t, ok)
+	assert.True(t, info.HasTransferFee)
+	assert.Equal(t, initial, info.OlderTransferFee)
+	assert.Equal(t, initial, info.NewerTransferFee)
+
+	// 新费率两个 epoch 后生效，此前仍按旧费率
+	r.ObserveSetTransferFee(mint, 500, 9_000, 300)
+	info, _ = r.Get(mint)
+	assert.Equal(t, initial, info.OlderTransferFee)
+	assert.Equal(t, TransferFee{Epoch: 502, MaximumFee: 9_000, BasisPoints: 300}, info.NewerTransferFee)
+	assert.Equal(t, uint64(10), info.TransferFeeAt(501, 1_000))
+	assert.Equal(t, uint64(30), info.TransferFeeAt(502, 1_000))
+
+	// 同一 epoch 内再次修改：尚未生效的费率被替换，旧费率不变
+	r.ObserveSetTransferFee(mint, 501, 9_000, 200)
+	info, _ = r.Get(mint)
+	assert.Equal(t, initial, info.OlderTransferFee)
+	assert.Equal(t, uint16(200), info.NewerTransferFee.BasisPoints)
+
+	// 已生效的费率轮换为旧费率
+	r.ObserveSetTransferFee(mint, 510, 9_000, 50)
+	info, _ = r.Get(mint)
+	assert.Equal(t, TransferFee{Epoch: 503, MaximumFee: 9_000, BasisPoints: 200}, info.OlderTransferFee)
+	assert.Equal(t, TransferFee{Epoch: 512, MaximumFee: 9_000, BasisPoints: 50}, info.NewerTransferFee)
+}
@@ -0,0 +1,208 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// MintStore 是 mint 注册表的持久化后端
+type MintStore interface {
+	Load(ctx context.Context) (map[types.Pubkey]MintInfo, error)
+	Save(ctx context.Context, mints map[types.Pubkey]MintInfo) error // 增量写入变更的 mint
+	Close() error
+}
+
+// diskMintStoreMagic 为数据文件头，文件结构变化时递增版本号，旧格式文件在加载时丢弃（可由交易与 RPC 重新填充）；
+// 每条记录另带版本字节（见 mintRecordVersion）
+var diskMintStoreMagic = []byte("MINTREG\x03")
+
+// DiskMintStore 以追加写定长记录的方式持久化到单个文件，同一 mint 以最后一条记录为准。
+// 启动加载时会重写文件去除重复记录。
+type DiskMintStore struct {
+	path string
+	file *os.File
+}
+
+func NewDiskMintStore(path string) (*DiskMintStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create mint store dir failed: %w", err)
+		}
+	}
+	return &DiskMintStore{path: path}, nil
+}
+
+func (s *DiskMintStore) Load(_ context.Context) (map[types.Pubkey]MintInfo, error) {
+	mints, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.compact(mints); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open mint store failed: %w", err)
+	}
+	s.file = file
+	return mints, nil
+}
+
+func (s *DiskMintStore) Save(_ context.Context, mints map[types.Pubkey]MintInfo) error {
+	if s.file == nil {
+		return errors.New("mint store not loaded")
+	}
+	buf := make([]byte, 0, len(mints)*mintRecordSize)
+	for mint, info := range mints {
+		buf = encodeMintRecord(buf, mint, info)
+	}
+	if _, err := s.file.Write(buf); err != nil {
+		return fmt.Errorf("write mint store failed: %w", err)
+	}
+	return nil
+}
+
+func (s *DiskMintStore) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	if err := s.file.Sync(); err != nil {
+		_ = s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+// readAll 读取全部记录，末尾不完整的记录（写入时进程退出）直接忽略
+func (s *DiskMintStore) readAll() (map[types.Pubkey]MintInfo, error) {
+	mints := make(map[types.Pubkey]MintInfo)
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return mints, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open mint store failed: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 1<<20)
+	magic := make([]byte, len(diskMintStoreMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || !bytes.Equal(magic, diskMintStoreMagic) {
+		logger.Warnf("[MintRegistry] 数据文件格式不匹配，丢弃旧数据: %s", s.path)
+		return mints, nil
+	}
+	record := make([]byte, mintRecordSize)
+	skipped := 0
+	for {
+		if _, err := io.ReadFull(reader, record); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				if skipped > 0 {
+					logger.Warnf("[MintRegistry] 丢弃 %d 条版本不一致的记录: %s", skipped, s.path)
+				}
+				return mints, nil
+			}
+			return nil, fmt.Errorf("read mint store failed: %w", err)
+		}
+		mint, info, err := decodeMintRecord(record)
+		if err != nil {
+			skipped++
+			continue
+		}
+		mints[mint] = info
+	}
+}
+
+// compact 将去重后的记录写入临时文件并替换原文件
+func (s *DiskMintStore) compact(mints map[types.Pubkey]MintInfo) error {
+	tmpPath := s.path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("create mint store tmp file failed: %w", err)
+	}
+	writer := bufio.NewWriterSize(file, 1<<20)
+	record := make([]byte, 0, mintRecordSize)
+	_, err = writer.Write(diskMintStoreMagic)
+	for mint, info := range mints {
+		if err != nil {
+			break
+		}
+		_, err = writer.Write(encodeMintRecord(record[:0], mint, info))
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("compact mint store failed: %w", err)
+	}
+	return os.Rename(tmpPath, s.path)
+}
+
+// RedisMintStore 将 mint 信息保存在一个 Redis hash 中：field 为 mint（32 字节），value 为定长记录
+type RedisMintStore struct {
+	rdb *redis.Client
+	key string
+}
+
+func NewRedisMintStore(rdb *redis.Client, key string) *RedisMintStore {
+	return &RedisMintStore{rdb: rdb, key: key}
+}
+
+func (s *RedisMintStore) Load(ctx context.Context) (map[types.Pubkey]MintInfo, error) {
+	mints := make(map[types.Pubkey]MintInfo)
+	skipped := 0
+	iter := s.rdb.HScan(ctx, s.key, 0, "", 10000).Iterator()
+	for iter.Next(ctx) {
+		// HSCAN 结果为 field、value 交替出现；记录中已包含 mint，跳过 field
+		if !iter.Next(ctx) {
+			break
+		}
+		mint, info, err := decodeMintRecord([]byte(iter.Val()))
+		if err != nil {
+			skipped++ // 旧版本记录，下次写入同一 mint 时覆盖
+			continue
+		}
+		mints[mint] = info
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("redis hscan error: %w", err)
+	}
+	if skipped > 0 {
+		logger.Warnf("[MintRegistry] 丢弃 %d 条版本不一致的记录: %s", skipped, s.key)
+	}
+	return mints, nil
+}
+
+func (s *RedisMintStore) Save(ctx context.Context, mints map[types.Pubkey]MintInfo) error {
+	if len(mints) == 0 {
+		return nil
+	}
+	values := make([]any, 0, len(mints)*2)
+	for mint, info := range mints {
+		values = append(values, string(mint[:]), encodeMintRecord(make([]byte, 0, mintRecordSize), mint, info))
+	}
+	if err := s.rdb.HSet(ctx, s.key, values...).Err(); err != nil {
+		return fmt.Errorf("redis hset error: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisMintStore) Close() error {
+	return s.rdb.Close()
+}
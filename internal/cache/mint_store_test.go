@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/internal/pkg/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openDiskStore(t *testing.T, path string) (*DiskMintStore, map[types.Pubkey]MintInfo) {
+	t.Helper()
+	store, err := NewDiskMintStore(path)
+	require.NoError(t, err)
+	mints, err := store.Load(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return store, mints
+}
+
+func TestDiskMintStore_SaveAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "mints.bin")
+	a, b := testfixture.Key("mint:a"), testfixture.Key("mint:b")
+
+	store, mints := openDiskStore(t, path)
+	assert.Empty(t, mints)
+	require.NoError(t, store.Save(context.Background(), map[types.Pubkey]MintInfo{
+		a: {Decimals: 6, TokenProgram: consts.TokenProgram},
+		b: {Decimals: 9},
+	}))
+	// 同一 mint 以最后一条记录为准
+	require.NoError(t, store.Save(context.Background(), map[types.Pubkey]MintInfo{a: {Decimals: 8, HasAccount: true}}))
+	require.NoError(t, store.Close())
+
+	size := func() int64 {
+		stat, err := os.Stat(path)
+		require.NoError(t, err)
+		return stat.Size()
+	}
+	assert.Equal(t, int64(len(diskMintStoreMagic)+3*mintRecordSize), size())
+
+	_, mints = openDiskStore(t, path)
+	assert.Equal(t, map[types.Pubkey]MintInfo{a: {Decimals: 8, HasAccount: true}, b: {Decimals: 9}}, mints)
+	assert.Equal(t, int64(len(diskMintStoreMagic)+2*mintRecordSize), size(), "加载时重写文件去除重复记录")
+}
+
+func TestDiskMintStore_IgnoresTruncatedAndStaleRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mints.bin")
+	a, b := testfixture.Key("mint:a"), testfixture.Key("mint:b")
+
+	data := append([]byte{}, diskMintStoreMagic...)
+	data = encodeMintRecord(data, a, MintInfo{Decimals: 6})
+	stale := encodeMintRecord(nil, b, MintInfo{Decimals: 9})
+	stale[0] = mintRecordVersion + 1
+	data = append(data, stale...)
+	data = append(data, encodeMintRecord(nil, b, MintInfo{Decimals: 9})[:10]...) // 写入时进程退出
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	_, mints := openDiskStore(t, path)
+	assert.Equal(t, map[types.Pubkey]MintInfo{a: {Decimals: 6}}, mints)
+}
+
+func TestDiskMintStore_DiscardsOtherFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mints.bin")
+	data := append([]byte("MINTREG\x01"), encodeMintRecord(nil, testfixture.Key("mint:a"), MintInfo{Decimals: 6})...)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	store, mints := openDiskStore(t, path)
+	assert.Empty(t, mints)
+	require.NoError(t, store.Close())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, diskMintStoreMagic, data, "旧格式文件被重写")
+}
+
+func TestDiskMintStore_SaveBeforeLoad(t *testing.T) {
+	store, err := NewDiskMintStore(filepath.Join(t.TempDir(), "mints.bin"))
+	require.NoError(t, err)
+	assert.Error(t, store.Save(context.Background(), map[types.Pubkey]MintInfo{}))
+	assert.NoError(t, store.Close())
+}
@@ -70,6 +70,81 @@ type TimeConfig struct {
 	EventSendTimeoutMs    int `yaml:"event_send_timeout_ms"`    // 单条事件发送到 Kafka 并等待 ack 的超时时间
 }
 
+// SpillQueueConfig 表示 blockChan 写满时的磁盘溢出队列配置
+type SpillQueueConfig struct {
+	Dir       string `yaml:"dir"`         // 区块落盘目录，为空表示关闭（blockChan 满时直接丢弃）
+	MaxBlocks int    `yaml:"max_blocks"`  // 最多积压的区块数，0 表示不限制
+	MaxSizeMB int    `yaml:"max_size_mb"` // 最多占用的磁盘空间（MB），0 表示不限制
+}
+
+// CaptureConfig 表示原始区块抓包配置（用于离线回放复现问题）
+type CaptureConfig struct {
+	Dir           string `yaml:"dir"`              // 抓包目录，为空表示关闭
+	MaxFileSizeMB int    `yaml:"max_file_size_mb"` // 单个文件大小上限（MB，压缩后），超过后滚动到新文件，0 表示不滚动
+	MaxFiles      int    `yaml:"max_files"`        // 最多保留的文件数，超过时删除最旧的文件，0 表示不限制
+}
+
+// FailedTxConfig 表示失败交易索引配置
+type FailedTxConfig struct {
+	Enable bool `yaml:"enable"` // 是否解析执行失败的交易，为其中的 Swap 指令生成 FAILED_TRADE 事件
+}
+
+// SolBalanceConfig 表示原生 SOL 余额变更推送配置
+type SolBalanceConfig struct {
+	Enable            bool   `yaml:"enable"`              // 是否向 balance topic 推送 signer 与 System Program 所有的钱包账户的 SOL 余额变更（token = SOLMint）
+	MinChangeLamports uint64 `yaml:"min_change_lamports"` // 余额变化量（lamports，绝对值）低于该值时不推送，0 表示推送全部变化
+}
+
+// MintRegistryConfig 表示全局 mint 注册表（decimals、token program、supply、authority）配置
+type MintRegistryConfig struct {
+	Store            string   `yaml:"store"`              // 持久化方式：none（默认，不持久化）/ disk / redis（使用 redis_addr）
+	Path             string   `yaml:"path"`               // disk 模式下的数据文件
+	RedisKey         string   `yaml:"redis_key"`          // redis 模式下的 hash key，默认 mint:registry
+	FlushIntervalSec int      `yaml:"flush_interval_sec"` // 增量持久化间隔（秒），默认 5
+	MaxEntries       int      `yaml:"max_entries"`        // 最多缓存的 mint 数量，0 表示不限制
+	RpcWarmup        bool     `yaml:"rpc_warmup"`         // 通过 RPC getMultipleAccounts 补全查询未命中的 mint（使用 grpc.rpc_endpoint）
+	WarmupMints      []string `yaml:"warmup_mints"`       // 启动时通过 RPC 预热的 mint 列表（base58）
+	WarmupIntervalMs int      `yaml:"warmup_interval_ms"` // 未命中 mint 的批量查询间隔（毫秒），默认 1000
+	NegativeTtlSec   int      `yaml:"negative_ttl_sec"`   // RPC 确认不是 mint 的账户在该时间内不再查询（秒），默认 600
+}
+
+// AccountFilterConfig 表示订阅的账户过滤配置（base58 地址），修改后可通过 SIGHUP 热更新到正在运行的订阅流
+type AccountFilterConfig struct {
+	AutoPrograms bool     `yaml:"auto_programs"` // 自动加入 eventparser 已注册 handler 的 ProgramID
+	Include      []string `yaml:"include"`       // 只订阅涉及其中任一账户的交易；为空且未开启 auto_programs 时使用内置默认列表
+	Exclude      []string `yaml:"exclude"`       // 排除涉及其中任一账户的交易
+	Required     []string `yaml:"required"`      // 只保留同时涉及全部账户的交易
+}
+
+// WebsocketSourceConfig 表示 RPC websocket blockSubscribe 区块来源配置（gRPC 不可用时的备用来源）
+type WebsocketSourceConfig struct {
+	Endpoint         string `yaml:"endpoint"`           // websocket 地址，如 wss://xxx.solana-mainnet.quiknode.pro/，节点需开启 blockSubscribe
+	Mention          string `yaml:"mention"`            // mentionsAccountOrProgram 过滤（只支持单个地址），为空表示 all
+	RecvTimeoutSec   int    `yaml:"recv_timeout_sec"`   // 超过该时间未收到消息则重连（秒）
+	FailoverAfterSec int    `yaml:"failover_after_sec"` // failover 模式下，全部 gRPC 流中断超过该时间后启用 websocket，恢复同样时长后停用（秒）
+}
+
+// TLSConfig 表示 gRPC 连接的传输安全配置
+type TLSConfig struct {
+	Mode       string `yaml:"mode"`        // verify（默认，校验服务端证书）/ insecure（不校验证书，仅用于排查）/ plaintext（不加密，用于本地/测试服务）
+	CAFile     string `yaml:"ca_file"`     // 自定义 CA 证书（PEM），配置后只信任该 CA；为空时使用系统 CA
+	ServerName string `yaml:"server_name"` // 覆盖证书校验使用的服务端名称（SNI），为空时使用 endpoint 中的主机名
+	CertFile   string `yaml:"cert_file"`   // mTLS 客户端证书（PEM），与 key_file 同时配置
+	KeyFile    string `yaml:"key_file"`    // mTLS 客户端私钥（PEM）
+}
+
+// GrpcEndpointConfig 表示单个 Yellowstone gRPC 服务端配置
+type GrpcEndpointConfig struct {
+	Name     string    `yaml:"name"`     // endpoint 名称，仅用于日志和统计
+	Endpoint string    `yaml:"endpoint"` // gRPC 服务端地址
+	XToken   string    `yaml:"x_token"`  // x-token 认证
+	TLS      TLSConfig `yaml:"tls"`      // 传输安全配置
+
+	// 断线续传能力：支持 FromSlot 的服务端可从上次成功分发的 slot 继续推送；
+	// 不支持时（如 quickNode，启用会报错）断连期间的缺口由 SlotChecker 通过 RPC 补块
+	SupportsFromSlot bool `yaml:"supports_from_slot"`
+}
+
 // GrpcConfig 是主配置结构体，用于驱动索引器服务
 type GrpcConfig struct {
 	Monitor           MonitorConfig       `json:"monitor"`        // 监控配置
@@ -77,19 +152,53 @@ type GrpcConfig struct {
 	PriceServiceConf  PriceServiceConfig  `yaml:"price_service"`  // 价格服务配置
 	KafkaProducerConf KafkaProducerConfig `yaml:"kafka_producer"` // Kafka 生产者配置
 	TimeConf          TimeConfig          `yaml:"time_conf"`      // 时间相关配置
+	SpillQueueConf    SpillQueueConfig    `yaml:"spill_queue"`    // 磁盘溢出队列配置
+	CaptureConf       CaptureConfig       `yaml:"capture"`        // 原始区块抓包配置
+	FailedTxConf      FailedTxConfig      `yaml:"failed_tx"`      // 失败交易索引配置
+	MintRegistryConf  MintRegistryConfig  `yaml:"mint_registry"`  // 全局 mint 注册表配置
+	SolBalanceConf    SolBalanceConfig    `yaml:"sol_balance"`    // 原生 SOL 余额推送配置
+
+	SlotsPerEpoch uint64 `yaml:"slots_per_epoch"` // 每个 epoch 的 slot 数（计算 Token-2022 转账费率生效 epoch），默认 432000（mainnet-beta）
 
-	RedisAddr    string `yaml:"redis_addr"`   // Redis 地址
-	PostgresDSN  string `yaml:"postgres_dsn"` // PostgreSQL 数据源
+	RedisAddr    string `yaml:"redis_addr"`   // Redis 地址，为空时不启用进度管理（不判重也不写进度）
+	PostgresDSN  string `yaml:"postgres_dsn"` // PostgreSQL 数据源，为空时进度只写 Redis
 	ProgressConf struct {
 		RecentThresholdSec int `yaml:"recent_threshold_sec"` // 判定为“近期 block”的时间阈值（秒）
+		RedisTTLHours      int `yaml:"redis_ttl_hours"`      // Redis 中 slot 状态的保留时长（小时），默认 24
 	} `yaml:"progress"` // 表示索引器中的进度管理配置
 
 	// gRPC 客户端连接相关配置
 	Grpc struct {
-		Endpoint    string `yaml:"endpoint"`     // gRPC 服务端地址
-		XToken      string `yaml:"x_token"`      // x-token 认证
+		// 多个 endpoint 同时订阅、竞速下发（按 slot+blockhash 去重）；为空时使用下方单 endpoint 配置
+		Endpoints []GrpcEndpointConfig `yaml:"endpoints"`
+
+		// 单 endpoint 配置（兼容旧配置）
+		Endpoint         string    `yaml:"endpoint"`           // gRPC 服务端地址
+		XToken           string    `yaml:"x_token"`            // x-token 认证
+		SupportsFromSlot bool      `yaml:"supports_from_slot"` // 是否支持 FromSlot 断线续传
+		TLS              TLSConfig `yaml:"tls"`                // 传输安全配置
+
 		RpcEndpoint string `yaml:"rpc_endpoint"` // RPC endpoint，用于 SlotChecker 等模块
 
+		// 区块来源：grpc（默认）/ websocket（只使用 RPC websocket blockSubscribe）/ failover（gRPC 全部中断时自动切换到 websocket）
+		Source    string                `yaml:"source"`
+		Websocket WebsocketSourceConfig `yaml:"websocket"`
+
+		// 订阅模式：block（全量区块，默认）/ transaction（按已注册的 DEX ProgramID 过滤交易，按 slot 重新组装）
+		SubscribeMode string `yaml:"subscribe_mode"`
+
+		// 账户过滤：block 模式下服务端只支持 include，exclude / required 在本地过滤；transaction 模式下全部由服务端过滤
+		AccountFilter AccountFilterConfig `yaml:"account_filter"`
+
+		// 订阅的 commitment 级别：processed / confirmed / finalized，默认 confirmed
+		Commitment string `yaml:"commitment"`
+
+		// 分叉检测：订阅 slot 状态更新，已下发的 slot 被孤立时广播 SLOT_ROLLBACK 消息
+		EnableForkDetection bool `yaml:"enable_fork_detection"`
+
+		// 双模式：以 processed/confirmed 快速下发事件，slot finalized 后再向 event topic 广播 SLOT_FINALIZED 标记
+		EmitFinalizedMarker bool `yaml:"emit_finalized_marker"`
+
 		// 应用级逻辑心跳（ping）配置
 		StreamPingIntervalSec int `yaml:"stream_ping_interval_sec"` // 应用层 ping 心跳间隔（秒）
 
@@ -114,3 +223,17 @@ type GrpcConfig struct {
 		MaxLatencyDropMs     int `yaml:"max_latency_drop_ms"`    // 延迟断连阈值（毫秒）
 	} `yaml:"grpc"`
 }
+
+// GrpcEndpoints 返回需要订阅的全部 gRPC endpoint；未配置 endpoints 时退化为单 endpoint 配置
+func (c *GrpcConfig) GrpcEndpoints() []GrpcEndpointConfig {
+	if len(c.Grpc.Endpoints) > 0 {
+		return c.Grpc.Endpoints
+	}
+	return []GrpcEndpointConfig{{
+		Name:             "default",
+		Endpoint:         c.Grpc.Endpoint,
+		XToken:           c.Grpc.XToken,
+		SupportsFromSlot: c.Grpc.SupportsFromSlot,
+		TLS:              c.Grpc.TLS,
+	}}
+}
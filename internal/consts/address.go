@@ -11,6 +11,7 @@ const (
 	AssociatedTokenProgramStr = "ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL"
 	TokenMetaProgramIdStr     = "metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s"
 	ComputeBudgetProgramIdStr = "ComputeBudget111111111111111111111111111111"
+	VoteProgramStr            = "Vote111111111111111111111111111111111111111"
 
 	// USD 计价基础报价币（具有稳定市场价格）
 	SOLMintStr  = "So11111111111111111111111111111111111111111"
@@ -29,16 +30,31 @@ const (
 	RaydiumCLMMProgramStr = "CAMMCzo5YL8w4VFF8KVHrK22GGUsp5VTaW7grrKgrWqK"
 	RaydiumCPMMProgramStr = "CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C"
 
+	// Launchpad: Raydium LaunchLab
+	RaydiumLaunchLabProgramStr = "LanMV9sAd7wArD4vJFi2qDdfnVhFxYSUg6eADduJ3uj"
+
+	// Launchpad: Meteora Dynamic Bonding Curve
+	MeteoraDBCProgramStr = "dbcij3LWUppWqq96dh6gJWwBifmcGfLSB5D4DuSMaqN"
+
 	// DEX: PumpFun
 	PumpFunAMMProgramStr = "pAMMBay6oceH9fJKBRHGP5D4bD4sWpmSwMn52FMfXEA"
 	PumpFunProgramStr    = "6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P"
 
 	// DEX: Meteora
-	MeteoraDLMMProgramStr = "LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo"
+	MeteoraDLMMProgramStr   = "LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo"
+	MeteoraDAMMV1ProgramStr = "Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB"
+	MeteoraDAMMV2ProgramStr = "cpamdpZCGKUy5JxQXB4dcpGPiikHawvSWAd6mEn1sGG"
 
 	// DEX: OrcaWhirlpoolProgram
 	OrcaWhirlpoolProgramStr = "whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc"
 
+	// 订单簿 DEX
+	OpenBookV2ProgramStr = "opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb"
+	PhoenixProgramStr    = "PhoeNiXZ8ByJGLkxNfZRnkUfjvmuYqLR89jG9ooVx6W"
+
+	// 聚合器: Jupiter
+	JupiterV6ProgramStr = "JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4"
+
 	// Known Owner Addresses
 	RaydiumV4AuthorityStr   = "5Q544fKrFoe6tsEbD7S8EmxGTJYAKtTVhAW5Q5pge4j1"
 	RaydiumCPMMAuthorityStr = "GpMZbSM2GgvTKHJirzeGfMFoaZ8UR2X7F4v8vHTvxFbL"
@@ -49,12 +65,25 @@ const (
 	PumpFunAMMFee8Str       = "JCRGumoE9Qi5BBgULTgdgTLjSgkCMSbF62ZZfGs84JeU"
 )
 
+// JitoTipAccountStrs Jito 官方 tip 账户（getTipAccounts），转入这些账户的 SOL 视为 Jito 小费
+var JitoTipAccountStrs = []string{
+	"96gYZGLnJYVFmbjzopPSU6QiEV5fGqZNyN9nmNhvrZU5",
+	"HFqU5x63VTqvQss8hp11i4wVV8bD44PvwucfZ2bU7gRe",
+	"Cw8CFyM9FkoMi7K7Crf6HNQqf4uEMzpKw6QNghXLvLkY",
+	"ADaUMid9yfUytqMBgopwjb2DTLSokTSzL1zt6iGPaS49",
+	"DfXygSm4jCyNCybVYYK6DwvWqjKee8pbDmJGcLWNDXjh",
+	"ADuUkR4vqLUMWXxW9gh6D6L8pMSawimctcNZ5pGwDcEt",
+	"DttWaMuVvTiduZRnguLF7jNxTgiMBZ1hyAumKUiL2KRL",
+}
+
 var (
 	// Programs
 	SystemProgram          = types.PubkeyFromBase58(SystemProgramStr)
 	TokenProgram           = types.PubkeyFromBase58(TokenProgramStr)
 	TokenProgram2022       = types.PubkeyFromBase58(TokenProgram2022Str)
 	AssociatedTokenProgram = types.PubkeyFromBase58(AssociatedTokenProgramStr)
+	VoteProgram            = types.PubkeyFromBase58(VoteProgramStr)
+	ComputeBudgetProgram   = types.PubkeyFromBase58(ComputeBudgetProgramIdStr)
 
 	// 稳定报价币（USD 估值）
 	SOLMint  = types.PubkeyFromBase58(SOLMintStr)
@@ -75,8 +104,21 @@ var (
 	PumpFunProgram       = types.PubkeyFromBase58(PumpFunProgramStr)
 	PumpFunAMMProgram    = types.PubkeyFromBase58(PumpFunAMMProgramStr)
 	MeteoraDLMMProgram   = types.PubkeyFromBase58(MeteoraDLMMProgramStr)
+	MeteoraDAMMV1Program = types.PubkeyFromBase58(MeteoraDAMMV1ProgramStr)
+	MeteoraDAMMV2Program = types.PubkeyFromBase58(MeteoraDAMMV2ProgramStr)
 	OrcaWhirlpoolProgram = types.PubkeyFromBase58(OrcaWhirlpoolProgramStr)
 
+	// 订单簿 DEX Program
+	OpenBookV2Program = types.PubkeyFromBase58(OpenBookV2ProgramStr)
+	PhoenixProgram    = types.PubkeyFromBase58(PhoenixProgramStr)
+
+	// Launchpad Program
+	RaydiumLaunchLabProgram = types.PubkeyFromBase58(RaydiumLaunchLabProgramStr)
+	MeteoraDBCProgram       = types.PubkeyFromBase58(MeteoraDBCProgramStr)
+
+	// 聚合器 Program
+	JupiterV6Program = types.PubkeyFromBase58(JupiterV6ProgramStr)
+
 	// Known Owner
 	RaydiumV4Authority   = types.PubkeyFromBase58(RaydiumV4AuthorityStr)
 	RaydiumCPMMAuthority = types.PubkeyFromBase58(RaydiumCPMMAuthorityStr)
@@ -86,3 +128,12 @@ var (
 	PumpFunAMMFee6       = types.PubkeyFromBase58(PumpFunAMMFee6Str)
 	PumpFunAMMFee8       = types.PubkeyFromBase58(PumpFunAMMFee8Str)
 )
+
+// JitoTipAccounts Jito tip 账户集合
+var JitoTipAccounts = func() map[types.Pubkey]struct{} {
+	m := make(map[types.Pubkey]struct{}, len(JitoTipAccountStrs))
+	for _, s := range JitoTipAccountStrs {
+		m[types.PubkeyFromBase58(s)] = struct{}{}
+	}
+	return m
+}()
@@ -1,24 +1,34 @@
 package consts
 
 const (
-	DexRaydiumV4     = iota + 1 // 1
-	DexRaydiumCLMM              // 2
-	DexPumpfunAMM               // 3
-	DexPumpfun                  // 4
-	DexRaydiumCPMM              // 5
-	DexMeteoraDLMM              // 6
-	DexOrcaWhirlpool            // 7
+	DexRaydiumV4        = iota + 1 // 1
+	DexRaydiumCLMM                 // 2
+	DexPumpfunAMM                  // 3
+	DexPumpfun                     // 4
+	DexRaydiumCPMM                 // 5
+	DexMeteoraDLMM                 // 6
+	DexOrcaWhirlpool               // 7
+	DexMeteoraDAMM                 // 8
+	DexRaydiumLaunchLab            // 9
+	DexMeteoraDBC                  // 10
+	DexOpenBookV2                  // 11
+	DexPhoenix                     // 12
 )
 
 var DexNames = []string{
-	"Unknown",       // 0 (保留)
-	"RaydiumV4",     // 1
-	"RaydiumCLMM",   // 2
-	"PumpfunAMM",    // 3
-	"Pumpfun",       // 4
-	"RaydiumCPMM",   // 5
-	"MeteoraDLMM",   // 6
-	"OrcaWhirlpool", // 7
+	"Unknown",          // 0 (保留)
+	"RaydiumV4",        // 1
+	"RaydiumCLMM",      // 2
+	"PumpfunAMM",       // 3
+	"Pumpfun",          // 4
+	"RaydiumCPMM",      // 5
+	"MeteoraDLMM",      // 6
+	"OrcaWhirlpool",    // 7
+	"MeteoraDAMM",      // 8
+	"RaydiumLaunchLab", // 9
+	"MeteoraDBC",       // 10
+	"OpenBookV2",       // 11
+	"Phoenix",          // 12
 }
 
 func DexName(dex int) string {
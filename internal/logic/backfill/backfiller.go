@@ -0,0 +1,303 @@
+package backfill
+
+import (
+	"context"
+	"dex-indexer-sol/internal/logic/grpc"
+	"dex-indexer-sol/internal/logic/progress"
+	"dex-indexer-sol/internal/logic/txadapter"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/mq"
+	"dex-indexer-sol/internal/svc"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/rpc"
+)
+
+const (
+	getBlocksBatchSize = 1000 // 每批通过 getBlocks 查询的 slot 跨度
+	maxFetchRetries    = 5    // getBlock 最大重试次数
+	maxSendRetries     = 5    // Kafka 发送最大重试次数
+	saveInterval       = 2 * time.Second
+)
+
+// Options 回填参数
+type Options struct {
+	FromSlot     uint64
+	ToSlot       uint64 // 包含
+	Concurrency  int    // 并发拉取/解析的 worker 数
+	RateLimit    int    // 每秒最多发起的 getBlock 请求数，0 表示不限制
+	ProgressFile string // 进度文件路径
+}
+
+// Backfiller 通过 RPC 拉取历史区块，走与实时索引相同的 txadapter → eventparser → jobbuilder 流程（source = RPC），
+// 同步发送 Kafka 并记录进度，中断后可从进度文件继续。
+type Backfiller struct {
+	sc        *svc.GrpcServiceContext
+	client    *rpc.RpcClient
+	processor *grpc.BlockProcessor
+	opts      Options
+	tracker   *progressTracker
+}
+
+func NewBackfiller(sc *svc.GrpcServiceContext, opts Options) (*Backfiller, error) {
+	if opts.ToSlot < opts.FromSlot {
+		return nil, fmt.Errorf("invalid slot range [%d, %d]", opts.FromSlot, opts.ToSlot)
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+
+	tracker, err := loadCheckpoint(opts.ProgressFile, opts.FromSlot, opts.ToSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	client := rpc.NewRpcClient(sc.Config.Grpc.RpcEndpoint)
+	return &Backfiller{
+		sc:        sc,
+		client:    &client,
+		processor: grpc.NewHistoricalBlockProcessor(sc),
+		opts:      opts,
+		tracker:   tracker,
+	}, nil
+}
+
+// Run 执行回填，直到区间处理完成或 ctx 被取消
+func (b *Backfiller) Run(ctx context.Context) error {
+	start := b.tracker.Next()
+	logger.Infof("[Backfill] 回填区间 [%d, %d]，从 slot %d 开始，并发 %d，限速 %d/s",
+		b.opts.FromSlot, b.opts.ToSlot, start, b.opts.Concurrency, b.opts.RateLimit)
+
+	limiter := newRateLimiter(b.opts.RateLimit)
+	defer limiter.Stop()
+
+	// 定期落盘进度
+	saveCtx, stopSave := context.WithCancel(ctx)
+	defer stopSave()
+	go b.saveLoop(saveCtx)
+	defer b.save()
+
+	// 1. 优先重试上次失败的 slot
+	if failed := b.tracker.FailedSlots(); len(failed) > 0 {
+		logger.Infof("[Backfill] 重试上次失败的 %d 个 slot", len(failed))
+		b.processSlots(ctx, limiter, failed)
+	}
+
+	// 2. 按批次回填剩余区间
+	for from := start; from <= b.opts.ToSlot; {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		to := from + getBlocksBatchSize - 1
+		if to > b.opts.ToSlot {
+			to = b.opts.ToSlot
+		}
+
+		slots, err := b.getBlocksWithRetry(ctx, from, to)
+		if err != nil {
+			return fmt.Errorf("getBlocks [%d, %d] error: %w", from, to, err)
+		}
+
+		batchStart := time.Now()
+		b.tracker.BeginBatch(slots, to)
+		b.processSlots(ctx, limiter, slots)
+		if ctx.Err() != nil {
+			return ctx.Err() // 被中断的批次只保留已连续完成的部分
+		}
+		b.tracker.EndBatch(to)
+		logger.Infof("[Backfill] 批次 [%d, %d] 完成，区块 %d 个，耗时 %v", from, to, len(slots), time.Since(batchStart))
+
+		from = to + 1
+	}
+
+	if failed := b.tracker.FailedSlots(); len(failed) > 0 {
+		logger.Errorf("[Backfill] 回填完成，仍有 %d 个 slot 失败，重新执行可重试: %v", len(failed), failed)
+		return nil
+	}
+	logger.Infof("[Backfill] 回填完成 [%d, %d]", b.opts.FromSlot, b.opts.ToSlot)
+	return nil
+}
+
+// processSlots 使用 worker 池并发处理一批 slot，全部完成（或 ctx 取消）后返回
+func (b *Backfiller) processSlots(ctx context.Context, limiter *rateLimiter, slots []uint64) {
+	slotCh := make(chan uint64)
+	var wg sync.WaitGroup
+	for i := 0; i < b.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for slot := range slotCh {
+				if err := b.processSlot(ctx, limiter, slot); err != nil {
+					if ctx.Err() != nil {
+						return // 中断时不记录失败，下次从水位继续
+					}
+					logger.Errorf("[Backfill] slot %d 处理失败: %v", slot, err)
+					b.tracker.MarkDone(slot, true)
+					continue
+				}
+				b.tracker.MarkDone(slot, false)
+			}
+		}()
+	}
+
+loop:
+	for _, slot := range slots {
+		select {
+		case <-ctx.Done():
+			break loop
+		case slotCh <- slot:
+		}
+	}
+	close(slotCh)
+	wg.Wait()
+}
+
+// processSlot 拉取、解析单个区块并同步发送 Kafka；进度中已处理成功的 slot 直接跳过，不重复发布
+func (b *Backfiller) processSlot(ctx context.Context, limiter *rateLimiter, slot uint64) error {
+	if pm := b.sc.ProgressManager; pm != nil {
+		processed, err := pm.IsSlotProcessed(ctx, slot)
+		if err != nil {
+			return fmt.Errorf("check progress error: %w", err)
+		}
+		if processed {
+			logger.Infof("[Backfill] slot %d 已处理过，跳过", slot)
+			return nil
+		}
+	}
+
+	block, err := b.getBlockWithRetry(ctx, limiter, slot)
+	if err != nil {
+		return err
+	}
+
+	update, err := txadapter.ConvertRpcBlock(slot, block)
+	if err != nil {
+		return err
+	}
+
+	source := progress.SourceRpc
+	txCtx, jobs, ok := b.processor.BuildBlockJobs(update, int32(source))
+	if !ok {
+		return errors.New("build kafka jobs failed")
+	}
+	if err = b.sendWithRetry(ctx, jobs); err != nil {
+		return err
+	}
+
+	if pm := b.sc.ProgressManager; pm != nil {
+		if err = pm.MarkSlotStatus(ctx, source, slot, txCtx.BlockTime, progress.SlotProcessed); err != nil {
+			logger.Warnf("[Backfill] slot %d 写入进度失败: %v", slot, err)
+		}
+	}
+	return nil
+}
+
+// sendWithRetry 发送 Kafka 任务，只重试失败的部分
+func (b *Backfiller) sendWithRetry(ctx context.Context, jobs []*mq.KafkaJob) error {
+	sendTimeout := time.Duration(b.sc.Config.TimeConf.EventSendTimeoutMs) * time.Millisecond
+	dispatchTimeout := time.Duration(b.sc.Config.TimeConf.SlotDispatchTimeoutMs) * time.Millisecond
+
+	for attempt := 1; len(jobs) > 0; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, dispatchTimeout)
+		_, failed := mq.SendKafkaJobs(sendCtx, b.sc.Producer, jobs, sendTimeout)
+		cancel()
+		if len(failed) == 0 {
+			return nil
+		}
+		if attempt >= maxSendRetries || ctx.Err() != nil {
+			return fmt.Errorf("kafka send failed %d/%d: %v", len(failed), len(jobs), failed[0].Err)
+		}
+
+		jobs = make([]*mq.KafkaJob, 0, len(failed))
+		for _, f := range failed {
+			jobs = append(jobs, f.Job)
+		}
+		time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
+	}
+	return nil
+}
+
+func (b *Backfiller) getBlockWithRetry(ctx context.Context, limiter *rateLimiter, slot uint64) (*rpc.GetBlock, error) {
+	var (
+		maxVersion = uint8(0)
+		rewards    = false
+	)
+	cfg := rpc.GetBlockConfig{
+		Encoding:                       rpc.GetBlockConfigEncodingBase64,
+		TransactionDetails:             rpc.GetBlockConfigTransactionDetailsFull,
+		Rewards:                        &rewards,
+		Commitment:                     rpc.CommitmentFinalized,
+		MaxSupportedTransactionVersion: &maxVersion,
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxFetchRetries; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		resp, err := b.client.GetBlockWithConfig(reqCtx, slot, cfg)
+		cancel()
+		if err == nil {
+			err = resp.GetError()
+		}
+		if err == nil && resp.Result == nil {
+			err = errors.New("empty result")
+		}
+		if err == nil {
+			return resp.Result, nil
+		}
+
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		time.Sleep(time.Duration(attempt) * 300 * time.Millisecond)
+	}
+	return nil, fmt.Errorf("getBlock failed after %d attempts: %w", maxFetchRetries, lastErr)
+}
+
+func (b *Backfiller) getBlocksWithRetry(ctx context.Context, from, to uint64) ([]uint64, error) {
+	var lastErr error
+	for attempt := 1; attempt <= maxFetchRetries; attempt++ {
+		reqCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		resp, err := b.client.GetBlocks(reqCtx, from, to)
+		cancel()
+		if err == nil {
+			err = resp.GetError()
+		}
+		if err == nil {
+			return resp.Result, nil
+		}
+
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		time.Sleep(time.Duration(attempt) * 300 * time.Millisecond)
+	}
+	return nil, lastErr
+}
+
+func (b *Backfiller) saveLoop(ctx context.Context) {
+	ticker := time.NewTicker(saveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.save()
+		}
+	}
+}
+
+func (b *Backfiller) save() {
+	if err := b.tracker.Save(); err != nil {
+		logger.Errorf("[Backfill] 保存进度失败: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package backfill
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Checkpoint 回填进度，落盘为 json 文件，中断后可从 NextSlot 继续
+type Checkpoint struct {
+	FromSlot    uint64   `json:"from_slot"`
+	ToSlot      uint64   `json:"to_slot"`
+	NextSlot    uint64   `json:"next_slot"`    // 小于该值的 slot 均已处理（或记录在 FailedSlots 中）
+	FailedSlots []uint64 `json:"failed_slots"` // 多次重试仍失败的 slot，下次启动时优先重试
+}
+
+// progressTracker 维护并发处理下的连续完成水位：
+// 区块乱序完成，只有当某个 slot 之前的全部区块都完成后，水位才会推进到它之后。
+type progressTracker struct {
+	mu      sync.Mutex
+	path    string
+	cp      Checkpoint
+	pending []uint64            // 当前批次按顺序排列的 slot
+	done    map[uint64]struct{} // 当前批次已完成但水位尚未推进到的 slot
+	failed  map[uint64]struct{}
+	dirty   bool
+}
+
+// loadCheckpoint 读取进度文件；文件不存在时从 fromSlot 开始，区间与文件不一致时报错（避免误用其他任务的进度）
+func loadCheckpoint(path string, fromSlot, toSlot uint64) (*progressTracker, error) {
+	t := &progressTracker{
+		path:   path,
+		cp:     Checkpoint{FromSlot: fromSlot, ToSlot: toSlot, NextSlot: fromSlot},
+		done:   make(map[uint64]struct{}),
+		failed: make(map[uint64]struct{}),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint %s error: %w", path, err)
+	}
+
+	var cp Checkpoint
+	if err = json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("parse checkpoint %s error: %w", path, err)
+	}
+	if cp.FromSlot != fromSlot || cp.ToSlot != toSlot {
+		return nil, fmt.Errorf("checkpoint %s range [%d, %d] mismatch with [%d, %d], use another progress file",
+			path, cp.FromSlot, cp.ToSlot, fromSlot, toSlot)
+	}
+	t.cp = cp
+	for _, slot := range cp.FailedSlots {
+		t.failed[slot] = struct{}{}
+	}
+	return t, nil
+}
+
+// Next 返回下一个未处理的 slot
+func (t *progressTracker) Next() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cp.NextSlot
+}
+
+// FailedSlots 返回历史失败的 slot（升序）
+func (t *progressTracker) FailedSlots() []uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sortedFailedLocked()
+}
+
+// BeginBatch 登记一批待处理的 slot（升序），batchEnd 为该批次覆盖的最后一个 slot（含跳过的 slot）
+func (t *progressTracker) BeginBatch(slots []uint64, batchEnd uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = slots
+	t.done = make(map[uint64]struct{}, len(slots))
+	if len(slots) == 0 {
+		t.advanceLocked(batchEnd + 1)
+	}
+}
+
+// EndBatch 批次全部完成，水位推进到 batchEnd 之后
+func (t *progressTracker) EndBatch(batchEnd uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = nil
+	t.advanceLocked(batchEnd + 1)
+}
+
+// MarkDone 标记 slot 处理完成；failed 表示多次重试仍失败，记录下来以便下次重试
+func (t *progressTracker) MarkDone(slot uint64, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if failed {
+		t.failed[slot] = struct{}{}
+	} else {
+		delete(t.failed, slot)
+	}
+	t.dirty = true
+	if slot < t.cp.NextSlot {
+		return // 重试历史失败的 slot，水位不变
+	}
+
+	t.done[slot] = struct{}{}
+	for len(t.pending) > 0 {
+		head := t.pending[0]
+		if _, ok := t.done[head]; !ok {
+			break
+		}
+		delete(t.done, head)
+		t.pending = t.pending[1:]
+		t.advanceLocked(head + 1)
+	}
+}
+
+// Save 将进度写入文件（先写临时文件再 rename，避免中断时写坏）
+func (t *progressTracker) Save() error {
+	t.mu.Lock()
+	if !t.dirty {
+		t.mu.Unlock()
+		return nil
+	}
+	cp := t.cp
+	cp.FailedSlots = t.sortedFailedLocked()
+	t.dirty = false
+	t.mu.Unlock()
+
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(t.path); dir != "" {
+		if err = os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmpPath := t.path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, t.path)
+}
+
+func (t *progressTracker) advanceLocked(next uint64) {
+	if next > t.cp.NextSlot {
+		t.cp.NextSlot = next
+		t.dirty = true
+	}
+}
+
+func (t *progressTracker) sortedFailedLocked() []uint64 {
+	slots := make([]uint64, 0, len(t.failed))
+	for slot := range t.failed {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	return slots
+}
@@ -0,0 +1,59 @@
+package backfill
+
+import (
+	"context"
+	"time"
+)
+
+// rateLimiter 简单的令牌桶限速器：每 1/rate 秒补充一个令牌，桶容量为 rate（允许 1 秒的突发）
+type rateLimiter struct {
+	tokens chan struct{}
+	stop   chan struct{}
+}
+
+// newRateLimiter 创建限速器，rate <= 0 表示不限速
+func newRateLimiter(rate int) *rateLimiter {
+	if rate <= 0 {
+		return &rateLimiter{}
+	}
+
+	l := &rateLimiter{
+		tokens: make(chan struct{}, rate),
+		stop:   make(chan struct{}),
+	}
+	go func() {
+		ticker := time.NewTicker(time.Second / time.Duration(rate))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				select {
+				case l.tokens <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return l
+}
+
+// Wait 阻塞直到获取令牌或 ctx 被取消
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l.tokens == nil {
+		return ctx.Err()
+	}
+	select {
+	case <-l.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *rateLimiter) Stop() {
+	if l.stop != nil {
+		close(l.stop)
+	}
+}
@@ -0,0 +1,64 @@
+package backfill
+
+import (
+	"context"
+	"dex-indexer-sol/internal/pkg/logger"
+	"fmt"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/rpc"
+)
+
+const maxSkippedProbe = 32 // 探测 blockTime 时，向后跳过空 slot 的最大数量
+
+// ResolveSlotByTime 二分查找 blockTime >= ts 的第一个 slot，用于将时间区间换算为 slot 区间。
+// 查找范围为 [节点最早可用区块, 当前 slot]。
+func ResolveSlotByTime(ctx context.Context, endpoint string, ts time.Time) (uint64, error) {
+	client := rpc.NewRpcClient(endpoint)
+
+	firstResp, err := client.GetFirstAvailableBlock(ctx)
+	if err == nil {
+		err = firstResp.GetError()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("getFirstAvailableBlock error: %w", err)
+	}
+	slotResp, err := client.GetSlot(ctx)
+	if err == nil {
+		err = slotResp.GetError()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("getSlot error: %w", err)
+	}
+
+	target := ts.Unix()
+	lo, hi := firstResp.Result, slotResp.Result
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		slot, blockTime, err := probeBlockTime(ctx, &client, mid, hi)
+		if err != nil {
+			return 0, err
+		}
+		if blockTime >= target {
+			hi = mid
+		} else {
+			lo = slot + 1
+		}
+	}
+	logger.Infof("[Backfill] 时间 %s 对应 slot %d", ts.Format(time.RFC3339), lo)
+	return lo, nil
+}
+
+// probeBlockTime 获取 slot 的 blockTime，slot 被跳过时向后探测（不超过 limit）
+func probeBlockTime(ctx context.Context, client *rpc.RpcClient, slot, limit uint64) (uint64, int64, error) {
+	for i := uint64(0); i < maxSkippedProbe && slot+i <= limit; i++ {
+		resp, err := client.GetBlockTime(ctx, slot+i)
+		if err == nil && resp.GetError() == nil && resp.Result != nil {
+			return slot + i, *resp.Result, nil
+		}
+		if ctx.Err() != nil {
+			return 0, 0, ctx.Err()
+		}
+	}
+	return 0, 0, fmt.Errorf("no block time found in slots [%d, %d]", slot, slot+maxSkippedProbe-1)
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"dex-indexer-sol/internal/cache"
 	"dex-indexer-sol/internal/consts"
 	"dex-indexer-sol/internal/pkg/logger"
 	"dex-indexer-sol/internal/pkg/types"
@@ -10,19 +11,25 @@ import (
 type TxContext struct {
 	BlockTime   int64      // 区块时间戳（Unix 秒）
 	Slot        uint64     // 当前 Slot（Solana 高度单位）
+	Epoch       uint64     // Slot 所属的 epoch（按配置的 slots_per_epoch 计算，用于 Token-2022 转账费率）
 	ParentSlot  uint64     // 父 Slot（用于分叉检测和回滚）
 	BlockHeight uint64     // 区块高度（辅助比对）
 	BlockHash   types.Hash // 区块哈希（辅助去重与 fork 检测）
+
+	ParentBlockHash types.Hash // 父区块哈希（fork 检测，解析失败为零值）
 }
 
 // AdaptedInstruction 表示一条主指令或 inner 指令，来源于 Solana Transaction 中的 message.instructions 或 innerInstructions。
-// 所有指令在预处理阶段已展平，并补充了位置信息（IxIndex、InnerIndex），以支持顺序遍历与事件定位。
+// 所有指令在预处理阶段已展平，并补充了位置信息（IxIndex、InnerIndex）与 CPI 调用关系（StackHeight、ParentIndex），
+// 以支持顺序遍历、按调用树查找子指令与事件定位。
 type AdaptedInstruction struct {
-	IxIndex    uint16         // 主指令索引（从 0 开始）
-	InnerIndex uint16         // Inner 指令在主指令中的序号，主指令本身为 0，CPI 调用从 1 开始
-	ProgramID  types.Pubkey   // 指令对应的程序 ID
-	Accounts   []types.Pubkey // 指令涉及的账户列表，保持原始顺序
-	Data       []byte         // 指令原始数据，用于 handler 判断指令类型与解析参数
+	IxIndex     uint16         // 主指令索引（从 0 开始）
+	InnerIndex  uint16         // Inner 指令在主指令中的序号，主指令本身为 0，CPI 调用从 1 开始
+	StackHeight uint8          // 调用栈深度：主指令为 1，其直接 CPI 为 2，依次递增；数据源未提供时 inner 指令为 0
+	ParentIndex int            // 父指令（发起 CPI 的指令）在展平列表中的下标，主指令为 -1；StackHeight 未知时为所属主指令
+	ProgramID   types.Pubkey   // 指令对应的程序 ID
+	Accounts    []types.Pubkey // 指令涉及的账户列表，保持原始顺序
+	Data        []byte         // 指令原始数据，用于 handler 判断指令类型与解析参数
 }
 
 // SolBalance 记录某账户在交易中 SOL 余额的变动快照（含执行前后余额）。
@@ -32,6 +39,9 @@ type SolBalance struct {
 	PreBalance  uint64 // 交易执行前余额（最小单位）
 	PostBalance uint64 // 交易执行后余额
 	Account     types.Pubkey
+	Signer      bool // 是否为交易签名者
+	Writable    bool // 是否为可写账户（只读账户的余额不会变化）
+	SystemOwned bool // 交易执行后是否可确定由 System Program 所有（普通钱包，非程序账户）
 }
 
 // TokenBalance 表示某个 SPL Token 账户在交易执行前后的余额信息。
@@ -67,7 +77,7 @@ type AdaptedTx struct {
 	// 每条指令都使用 AdaptedInstruction 表示，并标注其所属主指令位置（IxIndex）与 inner 索引（InnerIndex）。
 	Instructions []*AdaptedInstruction
 
-	// LogMessages 表示交易执行过程中产生的 Program 日志。解析Pump.fun可能会用到
+	// LogMessages 表示交易执行过程中产生的 Program 日志，用于解析通过 sol_log_data 输出的事件（如 OpenBook v2 FillLog）
 	LogMessages []string
 
 	// SolBalances 记录交易中涉及的账户 SOL 余额快照（交易前后余额）。
@@ -82,15 +92,34 @@ type AdaptedTx struct {
 	// 2. 使用切片而非 map 是因为单笔交易涉及的 mint 数量极少（通常 2~3 个），顺序查找在小规模场景下更高效，避免哈希运算与分支预测开销。
 	// 3. 使用值类型而非指针，是因为结构体体积小（Pubkey + uint8），直接存值可减少间接寻址，提高 CPU cache 命中率，加快遍历与查找性能。
 	TokenDecimals []TokenDecimals
+
+	Fee uint64   // 交易手续费（lamports，Meta.Fee = BaseFee + PriorityFee），失败交易同样扣除
+	Err *TxError // 交易执行错误，成功交易为 nil
+
+	BaseFee              uint64 // 基础手续费（lamports，按签名数收取）
+	PriorityFee          uint64 // 优先费（lamports）= ComputeUnitPrice × ComputeUnitLimit / 1e6，向上取整
+	ComputeUnitPrice     uint64 // SetComputeUnitPrice 设置的 CU 单价（micro-lamports），未设置为 0
+	ComputeUnitLimit     uint32 // SetComputeUnitLimit 设置的 CU 上限，未设置时为运行时默认值
+	ComputeUnitsConsumed uint64 // 实际消耗的 CU
+	JitoTip              uint64 // 转入 Jito tip 账户的 SOL（lamports），失败交易为 0
 }
 
+// TxError 表示 Meta.Err 解码后的交易错误。
+type TxError struct {
+	Code       string // TransactionError 名称，如 InstructionError、InsufficientFundsForFee
+	IxIndex    int32  // 出错的主指令索引，非 InstructionError 时为 -1
+	IxCode     string // InstructionError 名称，如 Custom、InvalidAccountData
+	CustomCode uint32 // 程序自定义错误码，仅 IxCode = Custom 时有效
+}
+
+// GetDecimalsByMint 返回 mint 的精度，优先使用当前交易中的数据，缺失时查询全局 mint 注册表
 func (tx *AdaptedTx) GetDecimalsByMint(mint types.Pubkey) (uint8, bool) {
 	for _, v := range tx.TokenDecimals {
 		if v.Token == mint {
 			return v.Decimals, true
 		}
 	}
-	return 0, false
+	return cache.Mints().Decimals(mint)
 }
 
 // AddTokenDecimals 添加一个 mint 和 decimals，重复则跳过
@@ -106,6 +135,24 @@ func (tx *AdaptedTx) AddTokenDecimals(mint types.Pubkey, decimals uint8) {
 	})
 }
 
+// AppendSolBalanceUpdates 将 signer 与执行后属于 System Program 的账户的 SOL 余额变化以 SOLMint 的 TokenBalance 形式写入 Balances，
+// 用于向 balance topic 推送钱包的原生 SOL 余额；池子 vault、PDA 等程序账户，变化量低于 minChange（lamports）的账户与 SPL Token 账户被跳过。
+func (tx *AdaptedTx) AppendSolBalanceUpdates(minChange uint64) {
+	for account, solBalance := range tx.SolBalances {
+		if !solBalance.Signer && !solBalance.SystemOwned {
+			continue
+		}
+		change := max(solBalance.PostBalance, solBalance.PreBalance) - min(solBalance.PostBalance, solBalance.PreBalance)
+		if change == 0 || change < minChange {
+			continue
+		}
+		if balance, ok := tx.Balances[account]; ok && balance.Token != consts.SOLMint {
+			continue // SPL Token 账户的 lamports 只是租金
+		}
+		tx.AppendSolToTokenBalances(solBalance)
+	}
+}
+
 func (tx *AdaptedTx) AppendSolToTokenBalances(solBalance *SolBalance) {
 	account := solBalance.Account
 
@@ -0,0 +1,19 @@
+package core
+
+// DefaultSlotsPerEpoch mainnet-beta 每个 epoch 的 slot 数
+const DefaultSlotsPerEpoch uint64 = 432000
+
+var slotsPerEpoch = DefaultSlotsPerEpoch
+
+// SetSlotsPerEpoch 设置当前网络每个 epoch 的 slot 数（0 表示使用 mainnet 默认值），需在开始处理区块前调用
+func SetSlotsPerEpoch(n uint64) {
+	if n == 0 {
+		n = DefaultSlotsPerEpoch
+	}
+	slotsPerEpoch = n
+}
+
+// EpochOfSlot 返回 slot 所属的 epoch（不考虑 warmup 期，mainnet-beta / devnet 均未启用）
+func EpochOfSlot(slot uint64) uint64 {
+	return slot / slotsPerEpoch
+}
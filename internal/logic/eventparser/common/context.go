@@ -21,6 +21,12 @@ type ParserContext struct {
 
 	Events      []*core.Event
 	PriceEvents []*core.PriceEvent
+	RouteSwaps  []*RouteSwap // 聚合器路由，解析结束后由 LinkRouteSwaps 关联路由内的事件
+
+	Current  int   // 当前 handler 所在的指令下标（instrs 中的位置），由解析循环在调用 handler 前设置
+	eventPos []int // 与 Events 一一对应，记录产生该事件时的 Current，用于按指令范围关联路由
+
+	programData map[int][][]byte // 指令下标 → sol_log_data 输出，由 ProgramData 首次调用时构建
 }
 
 // TxHashString 返回交易签名的 Base58 编码形式。
@@ -31,15 +37,23 @@ func (ctx *ParserContext) TxHashString() string {
 // AddEvent 添加一个事件到当前上下文中。
 func (ctx *ParserContext) AddEvent(event *core.Event) {
 	ctx.Events = append(ctx.Events, event)
+	ctx.eventPos = append(ctx.eventPos, ctx.Current)
 }
 
 // TakeEvents 返回并清空当前上下文中的事件。
 func (ctx *ParserContext) TakeEvents() []*core.Event {
 	events := ctx.Events
 	ctx.Events = nil
+	ctx.eventPos = nil
 	return events
 }
 
+// AddRouteSwap 记录一次聚合器路由，并添加其 RouteSwapEvent 事件。
+func (ctx *ParserContext) AddRouteSwap(route *RouteSwap, event *core.Event) {
+	ctx.RouteSwaps = append(ctx.RouteSwaps, route)
+	ctx.AddEvent(event)
+}
+
 func (ctx *ParserContext) AddPriceEvent(event *core.PriceEvent) {
 	ctx.PriceEvents = append(ctx.PriceEvents, event)
 }
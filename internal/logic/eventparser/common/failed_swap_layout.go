@@ -0,0 +1,139 @@
+package common
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+)
+
+// NoAccount 表示指令中不含该账户（如 swap 指令不带 mint 时，由 SwapIntent 的用户 token 账户推断）
+const NoAccount = -1
+
+// Meteora swap2 的 swap_mode
+const (
+	SwapModeExactIn     = 0
+	SwapModePartialFill = 1
+	SwapModeExactOut    = 2
+)
+
+// FailedSwapLayout 描述一条 swap 指令的账户与参数布局，失败交易中据此还原交易意图。
+// 账户字段为主指令 accounts 列表中的索引；数据字段为 ix.Data 中的字节偏移，0 表示不存在（偏移 0 为方法 ID）。
+type FailedSwapLayout struct {
+	MinAccounts       int  // 最少账户数
+	MaxAccounts       int  // 最多账户数，0 表示不限
+	ShiftUserAccounts bool // 账户数超过 MinAccounts 时，Wallet / UserSource / UserDest 按多出的账户数顺延（如 Raydium V4 的 17/18 个账户）
+
+	Pair       int // 池子账户
+	Wallet     int // 用户钱包
+	UserSource int // 用户支付 token 的账户
+	UserDest   int // 用户接收 token 的账户
+	InputMint  int // 支付 token 的 mint 账户，NoAccount 表示指令中不含
+	OutputMint int // 获得 token 的 mint 账户，NoAccount 表示指令中不含
+
+	InputToken  types.Pubkey // 固定的支付 mint（如 Pump.fun 内盘买入时的 SOL），优先于 InputMint
+	OutputToken types.Pubkey // 固定的获得 mint（如 Pump.fun 内盘卖出时的 SOL），优先于 OutputMint
+
+	AmountIn  int  // 输入数量（ExactOut 时为最大输入）的 u64 偏移
+	AmountOut int  // 输出数量（ExactIn 时为最小输出）的 u64 偏移，0 表示指令不含（AmountOut 记为 0）
+	ExactOut  bool // 是否指定输出数量
+
+	// 以下标志位由指令参数决定方向，命中时互换 AmountIn / AmountOut 并标记 ExactOut
+	ExactInFlag int // is_base_input / amount_specified_is_input 的偏移，为 0 时表示 ExactOut（CLMM / Whirlpool）
+	SwapMode    int // swap2 的 swap_mode 偏移，SwapModeExactOut 时表示 ExactOut（Meteora）
+	AToBFlag    int // a_to_b 的偏移，为 0 时表示 b → a，互换 UserSource / UserDest 与 mint（Whirlpool）
+}
+
+// FailedSwapTable 一个 Program 的全部 swap 指令布局，按方法 ID 索引。
+type FailedSwapTable struct {
+	Dex              int                         // consts.DexXxx
+	DiscriminatorLen int                         // 方法 ID 长度：1（Raydium V4）或 8（Anchor）
+	Layouts          map[uint64]FailedSwapLayout // 方法 ID → 布局
+}
+
+// Extract 按布局表从失败交易的 swap 指令中还原交易意图并构建 FAILED_TRADE 事件，签名与 FailedTradeHandler 一致。
+// 非 swap 指令或账户、数据长度不合法时返回 nil。
+func (t *FailedSwapTable) Extract(ctx *ParserContext, ix *core.AdaptedInstruction) *core.Event {
+	if len(ix.Data) < t.DiscriminatorLen {
+		return nil
+	}
+	var methodID uint64
+	if t.DiscriminatorLen == 1 {
+		methodID = uint64(ix.Data[0])
+	} else {
+		methodID = binary.BigEndian.Uint64(ix.Data[:8])
+	}
+	layout, ok := t.Layouts[methodID]
+	if !ok {
+		return nil
+	}
+
+	accountCount := len(ix.Accounts)
+	if accountCount < layout.MinAccounts || (layout.MaxAccounts > 0 && accountCount > layout.MaxAccounts) {
+		return nil
+	}
+	if len(ix.Data) < layout.dataLen() {
+		return nil
+	}
+
+	shift := 0
+	if layout.ShiftUserAccounts {
+		shift = accountCount - layout.MinAccounts
+	}
+	intent := &SwapIntent{
+		PairAddress: ix.Accounts[layout.Pair],
+		UserWallet:  ix.Accounts[layout.Wallet+shift],
+		UserSource:  ix.Accounts[layout.UserSource+shift],
+		UserDest:    ix.Accounts[layout.UserDest+shift],
+		InputToken:  layout.InputToken,
+		OutputToken: layout.OutputToken,
+		AmountIn:    binary.LittleEndian.Uint64(ix.Data[layout.AmountIn:]),
+		ExactOut:    layout.ExactOut,
+	}
+	if intent.InputToken == (types.Pubkey{}) && layout.InputMint != NoAccount {
+		intent.InputToken = ix.Accounts[layout.InputMint]
+	}
+	if intent.OutputToken == (types.Pubkey{}) && layout.OutputMint != NoAccount {
+		intent.OutputToken = ix.Accounts[layout.OutputMint]
+	}
+	if layout.AmountOut > 0 {
+		intent.AmountOut = binary.LittleEndian.Uint64(ix.Data[layout.AmountOut:])
+	}
+
+	exactOut := false
+	if layout.ExactInFlag > 0 && ix.Data[layout.ExactInFlag] == 0 {
+		exactOut = true
+	}
+	if layout.SwapMode > 0 {
+		switch ix.Data[layout.SwapMode] {
+		case SwapModeExactIn, SwapModePartialFill:
+		case SwapModeExactOut:
+			exactOut = true
+		default:
+			return nil
+		}
+	}
+	if exactOut {
+		intent.AmountIn, intent.AmountOut = intent.AmountOut, intent.AmountIn
+		intent.ExactOut = true
+	}
+	if layout.AToBFlag > 0 && ix.Data[layout.AToBFlag] == 0 {
+		intent.UserSource, intent.UserDest = intent.UserDest, intent.UserSource
+		intent.InputToken, intent.OutputToken = intent.OutputToken, intent.InputToken
+	}
+
+	return BuildFailedTradeEvent(ctx, ix, intent, t.Dex)
+}
+
+// dataLen 布局要求的最小指令数据长度
+func (l *FailedSwapLayout) dataLen() int {
+	n := l.AmountIn + 8
+	if l.AmountOut > 0 && l.AmountOut+8 > n {
+		n = l.AmountOut + 8
+	}
+	for _, flag := range []int{l.ExactInFlag, l.SwapMode, l.AToBFlag} {
+		if flag > 0 && flag+1 > n {
+			n = flag + 1
+		}
+	}
+	return n
+}
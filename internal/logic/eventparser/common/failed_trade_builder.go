@@ -0,0 +1,108 @@
+package common
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/internal/tools"
+	"dex-indexer-sol/pb"
+)
+
+// SwapIntent 表示从 swap 指令参数与账户中解析出的交易意图。
+// 失败交易没有实际转账，只能依赖指令本身还原用户想做的交易。
+type SwapIntent struct {
+	PairAddress types.Pubkey // 池子地址
+	UserWallet  types.Pubkey // 用户钱包
+	UserSource  types.Pubkey // 用户支付 token 的账户（InputToken 未知时用于从 Balances 推断 mint）
+	UserDest    types.Pubkey // 用户接收 token 的账户（OutputToken 未知时用于从 Balances 推断 mint）
+	InputToken  types.Pubkey // 支付的 token mint，指令中不含 mint 时留空
+	OutputToken types.Pubkey // 获得的 token mint，指令中不含 mint 时留空
+	AmountIn    uint64       // 输入数量（ExactOut 时为最大输入数量）
+	AmountOut   uint64       // 输出数量（ExactIn 时为最小输出数量）
+	ExactOut    bool         // 是否指定输出数量
+}
+
+// FailedTradeHandler 定义失败交易中 swap 指令的解析函数签名，返回 FAILED_TRADE 事件；非 swap 指令或数据不合法时返回 nil。
+type FailedTradeHandler func(ctx *ParserContext, ix *core.AdaptedInstruction) *core.Event
+
+// BuildFailedTradeEvent 根据交易意图与交易错误构建 FAILED_TRADE 事件。
+func BuildFailedTradeEvent(ctx *ParserContext, ix *core.AdaptedInstruction, intent *SwapIntent, dex int) *core.Event {
+	inputToken := resolveIntentToken(ctx, intent.InputToken, intent.UserSource)
+	outputToken := resolveIntentToken(ctx, intent.OutputToken, intent.UserDest)
+
+	event := &pb.FailedTradeEvent{
+		Type:         pb.EventType_FAILED_TRADE,
+		EventId:      core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
+		Slot:         ctx.Slot,
+		BlockTime:    ctx.BlockTime,
+		TxHash:       ctx.TxHash,
+		Signers:      ctx.Signers,
+		Dex:          uint32(dex),
+		PairAddress:  intent.PairAddress[:],
+		UserWallet:   intent.UserWallet[:],
+		Side:         failedTradeSide(inputToken, outputToken),
+		AmountIn:     intent.AmountIn,
+		AmountOut:    intent.AmountOut,
+		ExactOut:     intent.ExactOut,
+		Fee:          ctx.Tx.Fee,
+		ErrorIxIndex: -1,
+	}
+	if inputToken != (types.Pubkey{}) {
+		event.InputToken = inputToken[:]
+	}
+	if outputToken != (types.Pubkey{}) {
+		event.OutputToken = outputToken[:]
+	}
+	if txErr := ctx.Tx.Err; txErr != nil {
+		event.ErrorCode = txErr.Code
+		event.ErrorIxIndex = txErr.IxIndex
+		event.ErrorIxCode = txErr.IxCode
+		event.CustomErrorCode = txErr.CustomCode
+	}
+
+	return &core.Event{
+		ID:        event.EventId,
+		EventType: uint32(event.Type),
+		Key:       event.PairAddress,
+		Event: &pb.Event{
+			Event: &pb.Event_FailedTrade{
+				FailedTrade: event,
+			},
+		},
+	}
+}
+
+// resolveIntentToken 优先使用指令中的 mint，否则通过用户 token 账户的余额记录推断
+func resolveIntentToken(ctx *ParserContext, mint types.Pubkey, account types.Pubkey) types.Pubkey {
+	if mint != (types.Pubkey{}) {
+		return mint
+	}
+	if balance, ok := ctx.Balances[account]; ok {
+		return balance.Token
+	}
+	return types.Pubkey{}
+}
+
+// failedTradeSide 支付 quote 为 BUY，获得 quote 为 SELL，无法确定时为 TRADE_UNKNOWN
+func failedTradeSide(inputToken, outputToken types.Pubkey) pb.EventType {
+	var quote types.Pubkey
+	switch {
+	case inputToken == consts.SOLMint || outputToken == consts.SOLMint:
+		quote = consts.SOLMint // Pump.fun 内盘以原生 SOL 计价
+	default:
+		q, ok := tools.ChooseQuote(inputToken, outputToken)
+		if !ok {
+			return pb.EventType_TRADE_UNKNOWN
+		}
+		quote = q
+	}
+
+	switch quote {
+	case inputToken:
+		return pb.EventType_TRADE_BUY
+	case outputToken:
+		return pb.EventType_TRADE_SELL
+	default:
+		return pb.EventType_TRADE_UNKNOWN
+	}
+}
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"iter"
+)
+
+// Children 按执行顺序遍历 instrs[parent] 的直接子指令（即 parent 通过 CPI 直接调用的指令），
+// 产出子指令在展平列表中的下标与指令本身。孙指令（如 Token-2022 transfer hook）不会被遍历。
+//
+// 数据源未提供 stack height 时（inner 指令 StackHeight = 0）无法还原调用树，
+// 退化为遍历 parent 之后同一主指令内的全部 inner 指令（与按 IxIndex 扫描的旧逻辑一致）。
+func Children(instrs []*core.AdaptedInstruction, parent int) iter.Seq2[int, *core.AdaptedInstruction] {
+	return func(yield func(int, *core.AdaptedInstruction) bool) {
+		parentIx := instrs[parent]
+		for i := parent + 1; i < len(instrs); i++ {
+			ix := instrs[i]
+			if ix.IxIndex != parentIx.IxIndex {
+				return // 已离开当前主指令
+			}
+			if ix.StackHeight == 0 {
+				if !yield(i, ix) {
+					return
+				}
+				continue
+			}
+			if ix.StackHeight <= parentIx.StackHeight {
+				return // 已离开 parent 的调用子树
+			}
+			if ix.ParentIndex == parent && !yield(i, ix) {
+				return
+			}
+		}
+	}
+}
+
+// SubtreeEnd 返回 instrs[parent] 调用子树（全部直接与间接子指令）中最后一条指令的下标，无子指令时返回 parent。
+// StackHeight 未知时退化为同一主指令内的最后一条 inner 指令。
+func SubtreeEnd(instrs []*core.AdaptedInstruction, parent int) int {
+	parentIx := instrs[parent]
+	end := parent
+	for i := parent + 1; i < len(instrs); i++ {
+		ix := instrs[i]
+		if ix.IxIndex != parentIx.IxIndex {
+			break
+		}
+		if ix.StackHeight != 0 && ix.StackHeight <= parentIx.StackHeight {
+			break
+		}
+		end = i
+	}
+	return end
+}
+
+// Descendants 按执行顺序遍历 instrs[parent] 调用子树中的全部指令（直接与间接子指令），
+// 用于池子通过其它程序（如 Meteora vault）间接转账的场景。
+func Descendants(instrs []*core.AdaptedInstruction, parent int) iter.Seq2[int, *core.AdaptedInstruction] {
+	return func(yield func(int, *core.AdaptedInstruction) bool) {
+		end := SubtreeEnd(instrs, parent)
+		for i := parent + 1; i <= end; i++ {
+			if !yield(i, instrs[i]) {
+				return
+			}
+		}
+	}
+}
+
+// subInstructions 根据 nested 选择遍历直接子指令（Children）或整个调用子树（Descendants）
+func subInstructions(instrs []*core.AdaptedInstruction, parent int, nested bool) iter.Seq2[int, *core.AdaptedInstruction] {
+	if nested {
+		return Descendants(instrs, parent)
+	}
+	return Children(instrs, parent)
+}
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// treeNode 展平指令的位置信息：主指令索引、栈深度与父指令下标（与 txadapter.buildInstructions 的输出一致）
+type treeNode struct {
+	ixIndex     uint16
+	stackHeight uint8
+	parent      int
+}
+
+func buildTree(nodes []treeNode) []*core.AdaptedInstruction {
+	instrs := make([]*core.AdaptedInstruction, len(nodes))
+	for i, n := range nodes {
+		instrs[i] = &core.AdaptedInstruction{IxIndex: n.ixIndex, StackHeight: n.stackHeight, ParentIndex: n.parent}
+	}
+	return instrs
+}
+
+func collect(seq func(func(int, *core.AdaptedInstruction) bool)) []int {
+	var out []int
+	for i := range seq {
+		out = append(out, i)
+	}
+	return out
+}
+
+func TestChildrenAndSubtreeEnd(t *testing.T) {
+	instrs := buildTree([]treeNode{
+		{0, 1, -1}, // 0: ix0
+		{0, 2, 0},  // 1:   ├─ A
+		{0, 3, 1},  // 2:   │   ├─ A1
+		{0, 3, 1},  // 3:   │   └─ A2
+		{0, 4, 3},  // 4:   │       └─ A2a（如 transfer hook）
+		{0, 2, 0},  // 5:   └─ B
+		{1, 1, -1}, // 6: ix1
+		{1, 2, 6},  // 7:   └─ C
+		{2, 1, -1}, // 8: ix2
+	})
+
+	tests := []struct {
+		parent   int
+		children []int
+		end      int
+	}{
+		{0, []int{1, 5}, 5},
+		{1, []int{2, 3}, 4},
+		{2, nil, 2},
+		{3, []int{4}, 4},
+		{5, nil, 5},
+		{6, []int{7}, 7},
+		{8, nil, 8},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.children, collect(Children(instrs, tt.parent)), "Children(%d)", tt.parent)
+		assert.Equal(t, tt.end, SubtreeEnd(instrs, tt.parent), "SubtreeEnd(%d)", tt.parent)
+	}
+	assert.Equal(t, []int{2, 3, 4}, collect(Descendants(instrs, 1)))
+	assert.Nil(t, collect(Descendants(instrs, 5)), "叶子节点没有子指令")
+	assert.Equal(t, []int{1, 5}, collect(subInstructions(instrs, 0, false)))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, collect(subInstructions(instrs, 0, true)))
+}
+
+// 数据源未提供 stack height 时，退化为同一主指令内的全部 inner 指令
+func TestChildrenWithoutStackHeight(t *testing.T) {
+	instrs := buildTree([]treeNode{{0, 1, -1}, {0, 0, 0}, {0, 0, 0}, {0, 0, 0}, {1, 1, -1}, {1, 0, 4}})
+
+	assert.Equal(t, []int{1, 2, 3}, collect(Children(instrs, 0)))
+	assert.Equal(t, 3, SubtreeEnd(instrs, 0))
+	assert.Equal(t, []int{5}, collect(Children(instrs, 4)))
+	assert.Equal(t, 5, SubtreeEnd(instrs, 4))
+}
+
+func TestChildrenStopsEarly(t *testing.T) {
+	instrs := buildTree([]treeNode{{0, 1, -1}, {0, 2, 0}, {0, 2, 0}, {0, 2, 0}})
+
+	var seen []int
+	for i := range Children(instrs, 0) {
+		seen = append(seen, i)
+		if len(seen) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2}, seen)
+
+	seen = seen[:0]
+	for i := range Descendants(instrs, 0) {
+		seen = append(seen, i)
+		break
+	}
+	assert.Equal(t, []int{1}, seen)
+}
@@ -104,12 +104,14 @@ func buildBaseLiquidityEvent(
 		QuoteTokenAccountOwner: quoteTransfer.DestWallet[:],
 
 		// 资产相关
-		TokenAmount:      baseTransfer.Amount,
-		QuoteTokenAmount: quoteTransfer.Amount,
-		PairTokenBalance: baseTransfer.DestPostBalance,
-		PairQuoteBalance: quoteTransfer.DestPostBalance,
-		UserTokenBalance: baseTransfer.SrcPostBalance,
-		UserQuoteBalance: quoteTransfer.SrcPostBalance,
+		TokenAmount:         baseTransfer.Amount,
+		QuoteTokenAmount:    quoteTransfer.Amount,
+		TokenAmountNet:      baseTransfer.NetAmount(),  // 接收方实际到账的 base
+		QuoteTokenAmountNet: quoteTransfer.NetAmount(), // 接收方实际到账的 quote
+		PairTokenBalance:    baseTransfer.DestPostBalance,
+		PairQuoteBalance:    quoteTransfer.DestPostBalance,
+		UserTokenBalance:    baseTransfer.SrcPostBalance,
+		UserQuoteBalance:    quoteTransfer.SrcPostBalance,
 	}
 
 	// 若 Quote 为 WSOL 且为临时账户（余额为 0），用 SOL 余额补充 Quote 余额。
@@ -167,6 +169,8 @@ func CloneLiquidityEvent(orig *core.Event) *core.Event {
 		QuoteDecimals:          src.QuoteDecimals,
 		TokenAmount:            src.TokenAmount,
 		QuoteTokenAmount:       src.QuoteTokenAmount,
+		TokenAmountNet:         src.TokenAmountNet,
+		QuoteTokenAmountNet:    src.QuoteTokenAmountNet,
 		Token:                  src.Token,
 		QuoteToken:             src.QuoteToken,
 		TokenAccount:           src.TokenAccount,
@@ -34,6 +34,8 @@ type LiquidityLayout struct {
 	PoolToken1AccountIndex int // 必须存在, 不能为-1
 	PoolToken2AccountIndex int // 必须存在, 不能为-1
 	LpMintIndex            int // 可选, -1表示忽略
+
+	IncludeNested bool // 是否匹配间接子指令（池子资金经由其它程序 CPI 转账时使用，如 Meteora DAMM v1 的 vault）
 }
 
 // validateLiquidityInstructionIndex 校验 LiquidityInstructionIndex 中各字段合法性。
@@ -60,10 +62,11 @@ func validateLiquidityInstructionIndex(layout *LiquidityLayout, accountsLen int)
 // 参数说明：
 //   - ctx          : 当前交易解析上下文（包含账户余额、Token 结构等信息）。
 //   - instrs       : 展平后的指令列表（包含主指令和 inner 指令）。
-//   - current      : 当前主指令在 instrs 中的索引（作为匹配起点）。
+//   - current      : 当前指令（主指令或 CPI 调用的 inner 指令）在 instrs 中的索引，
+//     只匹配其直接子指令（layout.IncludeNested 时匹配整个调用子树）。
 //   - layout       : 表示用户提供和池子使用的 Token 账户索引结构，包括 LP Mint（可选）。
-//   - maxLookahead : 向后最多检查的指令数量（不包括主指令本身）；
-//     若为 0，表示不限制，遍历当前主指令的所有 inner 指令（IxIndex 不变）。
+//   - maxLookahead : 最多检查的直接子指令数量；
+//     若为 0，表示不限制，遍历当前指令的全部直接子指令（见 Children）。
 //
 // 返回值：
 // - Token1Transfer : 用户支付的 Token1 的转账记录（用户 → 池子）。
@@ -105,13 +108,7 @@ func FindAddLiquidityTransfers(
 	}
 
 	looked := 0
-	for i := current + 1; i < len(instrs); i++ {
-		ix := instrs[i]
-
-		// 只遍历当前主指令的 inner 指令
-		if ix.IxIndex != mainIx.IxIndex {
-			break
-		}
+	for i, ix := range subInstructions(instrs, current, layout.IncludeNested) {
 		if maxLookahead > 0 {
 			if looked >= maxLookahead {
 				break
@@ -125,7 +122,7 @@ func FindAddLiquidityTransfers(
 		}
 
 		switch ix.Data[0] {
-		case byte(sdktoken.InstructionTransfer), byte(sdktoken.InstructionTransferChecked):
+		case byte(sdktoken.InstructionTransfer), byte(sdktoken.InstructionTransferChecked), InstructionTransferFeeExtension:
 			pt, ok := ParseTransferInstruction(ctx, ix)
 			if !ok {
 				continue
@@ -192,10 +189,11 @@ func FindAddLiquidityTransfers(
 // 参数说明：
 //   - ctx          : 当前交易解析上下文（包含账户余额、Token 结构等信息）。
 //   - instrs       : 展平后的指令列表（包含主指令和 inner 指令）。
-//   - current      : 当前主指令在 instrs 中的索引（作为匹配起点）。
+//   - current      : 当前指令（主指令或 CPI 调用的 inner 指令）在 instrs 中的索引，
+//     只匹配其直接子指令（layout.IncludeNested 时匹配整个调用子树）。
 //   - layout       : 表示用户提供和池子使用的 Token 账户索引结构，包括 LP Mint（可选）。
-//   - maxLookahead : 向后最多检查的指令数量（不包括主指令本身）；
-//     若为 0，表示不限制，遍历当前主指令的所有 inner 指令（IxIndex 不变）。
+//   - maxLookahead : 最多检查的直接子指令数量；
+//     若为 0，表示不限制，遍历当前指令的全部直接子指令（见 Children）。
 //
 // 返回值：
 // - Token1Transfer : 用户收到的 Token1 的转账记录（池子 → 用户）。
@@ -237,13 +235,7 @@ func FindRemoveLiquidityTransfers(
 	}
 
 	looked := 0
-	for i := current + 1; i < len(instrs); i++ {
-		ix := instrs[i]
-
-		// 同一个主指令范围内查找
-		if ix.IxIndex != mainIx.IxIndex {
-			break
-		}
+	for i, ix := range subInstructions(instrs, current, layout.IncludeNested) {
 		if maxLookahead > 0 {
 			if looked >= maxLookahead {
 				break
@@ -257,7 +249,7 @@ func FindRemoveLiquidityTransfers(
 		}
 
 		switch ix.Data[0] {
-		case byte(sdktoken.InstructionTransfer), byte(sdktoken.InstructionTransferChecked):
+		case byte(sdktoken.InstructionTransfer), byte(sdktoken.InstructionTransferChecked), InstructionTransferFeeExtension:
 			pt, ok := ParseTransferInstruction(ctx, ix)
 			if !ok {
 				continue
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/pb"
+	"math/bits"
+)
+
+// OrderbookFill 表示订单簿 DEX 中 taker 订单与单个 maker 订单的一次成交（数量单位为 lots）
+type OrderbookFill struct {
+	Maker           types.Pubkey // maker 钱包，未知时为零值（OpenBook v2 的成交日志只有 OpenOrders 账户）
+	MakerOpenOrders types.Pubkey // maker 的 OpenOrders 账户（仅 OpenBook v2）
+	PriceInTicks    uint64
+	BaseLots        uint64
+	QuoteLots       uint64
+}
+
+// AttachOrderbookFills 将逐笔成交写入 taker 的 TradeEvent。
+// lot size 保存在 market 账户中，交易数据里没有，因此各笔成交的 token 数量按 lots 比例分摊 taker 的实际转账数量：
+// base 按 BaseLots，quote 按 QuoteLots 分摊（quote 因此包含 taker 手续费）。
+func AttachOrderbookFills(event *core.Event, fills []*OrderbookFill) bool {
+	trade := event.Event.GetTrade()
+	if trade == nil || len(fills) == 0 {
+		return false
+	}
+
+	baseLots := make([]uint64, len(fills))
+	quoteLots := make([]uint64, len(fills))
+	for i, fill := range fills {
+		baseLots[i], quoteLots[i] = fill.BaseLots, fill.QuoteLots
+	}
+	tokenAmounts, ok1 := SplitByWeight(trade.TokenAmount, baseLots)
+	quoteAmounts, ok2 := SplitByWeight(trade.QuoteTokenAmount, quoteLots)
+	if !ok1 || !ok2 {
+		return false
+	}
+
+	trade.Fills = make([]*pb.OrderbookFill, len(fills))
+	for i, fill := range fills {
+		trade.Fills[i] = fill.toPb()
+		trade.Fills[i].TokenAmount = tokenAmounts[i]
+		trade.Fills[i].QuoteTokenAmount = quoteAmounts[i]
+	}
+	return true
+}
+
+// BuildOrderbookFillEvent 构建限价单穿价成交的 ORDERBOOK_FILL 事件（只含逐笔成交 lots，不含 token 数量）。
+// takerOpenOrders 为零值时不输出（Phoenix）；side 为 taker 方向（TRADE_BUY / TRADE_SELL）。
+func BuildOrderbookFillEvent(
+	ctx *ParserContext,
+	ix *core.AdaptedInstruction,
+	pairAddress types.Pubkey,
+	userWallet types.Pubkey,
+	takerOpenOrders types.Pubkey,
+	side pb.EventType,
+	fills []*OrderbookFill,
+	dex int,
+) *core.Event {
+	event := &pb.OrderbookFillEvent{
+		Type:        pb.EventType_ORDERBOOK_FILL,
+		EventId:     core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
+		Slot:        ctx.Slot,
+		BlockTime:   ctx.BlockTime,
+		TxHash:      ctx.TxHash,
+		Signers:     ctx.Signers,
+		Dex:         uint32(dex),
+		PairAddress: pairAddress[:],
+		UserWallet:  userWallet[:],
+		Side:        side,
+		Fills:       make([]*pb.OrderbookFill, len(fills)),
+	}
+	if takerOpenOrders != (types.Pubkey{}) {
+		event.TakerOpenOrders = takerOpenOrders[:]
+	}
+	for i, fill := range fills {
+		event.Fills[i] = fill.toPb()
+	}
+
+	return &core.Event{
+		ID:        event.EventId,
+		EventType: uint32(event.Type),
+		Key:       event.PairAddress,
+		Event: &pb.Event{
+			Event: &pb.Event_OrderbookFill{OrderbookFill: event},
+		},
+	}
+}
+
+// toPb 转换为 pb.OrderbookFill，未知的 maker 钱包与 OpenOrders 账户不输出
+func (fill *OrderbookFill) toPb() *pb.OrderbookFill {
+	out := &pb.OrderbookFill{
+		PriceInTicks: fill.PriceInTicks,
+		BaseLots:     fill.BaseLots,
+		QuoteLots:    fill.QuoteLots,
+	}
+	if fill.Maker != (types.Pubkey{}) {
+		out.Maker = fill.Maker[:]
+	}
+	if fill.MakerOpenOrders != (types.Pubkey{}) {
+		out.MakerOpenOrders = fill.MakerOpenOrders[:]
+	}
+	return out
+}
+
+// SplitByWeight 将 total 按 weights 比例拆分（向下取整），最后一份取余数，保证各份之和等于 total。
+// 权重之和为 0 或溢出时返回 false。
+func SplitByWeight(total uint64, weights []uint64) ([]uint64, bool) {
+	var sum, carry uint64
+	for _, w := range weights {
+		sum, carry = bits.Add64(sum, w, 0)
+		if carry != 0 {
+			return nil, false
+		}
+	}
+	if sum == 0 {
+		return nil, false
+	}
+
+	parts := make([]uint64, len(weights))
+	remaining := total
+	for i, w := range weights[:len(weights)-1] {
+		// total * w / sum 不超过 total，128 位中间结果的高位必小于 sum，Div64 不会溢出
+		hi, lo := bits.Mul64(total, w)
+		parts[i], _ = bits.Div64(hi, lo, sum)
+		remaining -= parts[i]
+	}
+	parts[len(parts)-1] = remaining
+	return parts, true
+}
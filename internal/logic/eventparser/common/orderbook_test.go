@@ -0,0 +1,91 @@
+package common
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/pb"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitByWeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   uint64
+		weights []uint64
+		want    []uint64
+	}{
+		{"single", 100, []uint64{7}, []uint64{100}},
+		{"even", 100, []uint64{1, 1, 2}, []uint64{25, 25, 50}},
+		{"remainder goes to last", 10, []uint64{1, 1, 1}, []uint64{3, 3, 4}},
+		{"zero weight", 10, []uint64{0, 5}, []uint64{0, 10}},
+		{"zero total", 0, []uint64{3, 4}, []uint64{0, 0}},
+		{"128-bit intermediate", math.MaxUint64, []uint64{math.MaxUint64 / 2, math.MaxUint64 / 2}, []uint64{math.MaxUint64 / 2, math.MaxUint64/2 + 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SplitByWeight(tt.total, tt.weights)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got)
+
+			var sum uint64
+			for _, v := range got {
+				sum += v
+			}
+			assert.Equal(t, tt.total, sum)
+		})
+	}
+}
+
+func TestSplitByWeight_Invalid(t *testing.T) {
+	_, ok := SplitByWeight(10, []uint64{0, 0})
+	assert.False(t, ok, "权重之和为 0")
+
+	_, ok = SplitByWeight(10, []uint64{math.MaxUint64, 1})
+	assert.False(t, ok, "权重之和溢出")
+}
+
+func TestAttachOrderbookFills(t *testing.T) {
+	maker := types.Pubkey{1}
+	openOrders := types.Pubkey{2}
+	event := &core.Event{Event: &pb.Event{Event: &pb.Event_Trade{Trade: &pb.TradeEvent{
+		TokenAmount:      3000,
+		QuoteTokenAmount: 1001,
+	}}}}
+	fills := []*OrderbookFill{
+		{Maker: maker, PriceInTicks: 10, BaseLots: 1, QuoteLots: 10},
+		{MakerOpenOrders: openOrders, PriceInTicks: 11, BaseLots: 2, QuoteLots: 22},
+	}
+
+	require.True(t, AttachOrderbookFills(event, fills))
+	got := event.Event.GetTrade().Fills
+	require.Len(t, got, 2)
+
+	assert.Equal(t, maker[:], got[0].Maker)
+	assert.Empty(t, got[0].MakerOpenOrders)
+	assert.Equal(t, uint64(1000), got[0].TokenAmount)
+	assert.Equal(t, uint64(312), got[0].QuoteTokenAmount) // 1001 × 10 / 32
+
+	assert.Empty(t, got[1].Maker, "OpenBook v2 的 maker 钱包未知时不输出")
+	assert.Equal(t, openOrders[:], got[1].MakerOpenOrders)
+	assert.Equal(t, uint64(2000), got[1].TokenAmount)
+	assert.Equal(t, uint64(689), got[1].QuoteTokenAmount)
+	assert.Equal(t, uint64(2), got[1].BaseLots)
+	assert.Equal(t, uint64(22), got[1].QuoteLots)
+}
+
+func TestAttachOrderbookFills_NotTrade(t *testing.T) {
+	event := &core.Event{Event: &pb.Event{Event: &pb.Event_Transfer{Transfer: &pb.TransferEvent{}}}}
+	assert.False(t, AttachOrderbookFills(event, []*OrderbookFill{{BaseLots: 1, QuoteLots: 1}}))
+}
+
+// 无法分摊（quote lots 全为 0）时不写入明细
+func TestAttachOrderbookFills_Unsplittable(t *testing.T) {
+	event := &core.Event{Event: &pb.Event{Event: &pb.Event_Trade{Trade: &pb.TradeEvent{TokenAmount: 10, QuoteTokenAmount: 10}}}}
+	assert.False(t, AttachOrderbookFills(event, []*OrderbookFill{{BaseLots: 1}, {BaseLots: 2}}))
+	assert.Empty(t, event.Event.GetTrade().Fills)
+	assert.False(t, AttachOrderbookFills(event, nil))
+}
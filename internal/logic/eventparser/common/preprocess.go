@@ -1,10 +1,12 @@
 package common
 
 import (
+	"dex-indexer-sol/internal/cache"
 	"dex-indexer-sol/internal/logic/core"
 	"dex-indexer-sol/internal/pkg/logger"
 	"dex-indexer-sol/internal/pkg/types"
 	"dex-indexer-sol/internal/tools"
+	"encoding/binary"
 	sdktoken "github.com/blocto/solana-go-sdk/program/token"
 )
 
@@ -27,10 +29,67 @@ func PreScanInitAccountBalances(ctx *ParserContext, instrs []*core.AdaptedInstru
 			byte(sdktoken.InstructionInitializeAccount2),
 			byte(sdktoken.InstructionInitializeAccount3):
 			tryFillBalanceFromInitAccount(ctx, ix)
+
+		case InstructionTransferFeeExtension:
+			tryObserveTransferFeeConfig(ctx, ix)
+		}
+	}
+}
+
+// tryObserveTransferFeeConfig 从 InitializeTransferFeeConfig / SetTransferFee 指令中记录 Token-2022 转账费率，
+// 供后续 TransferChecked 计算手续费使用
+func tryObserveTransferFeeConfig(ctx *ParserContext, ix *core.AdaptedInstruction) {
+	// 失败交易中的费率变更已回滚
+	if ctx.Tx.Err != nil || len(ix.Data) < 2 || len(ix.Accounts) == 0 {
+		return
+	}
+	mint := ix.Accounts[0]
+	epoch := ctx.Tx.TxCtx.Epoch
+
+	switch ix.Data[1] {
+	// InitializeTransferFeeConfig: [0]=26, [1]=0, config_authority COption(1 或 33 字节),
+	// withdraw_authority COption(1 或 33 字节), basis_points(u16), maximum_fee(u64)
+	case TransferFeeInitializeConfig:
+		data, ok := skipPubkeyOption(ix.Data[2:])
+		if ok {
+			data, ok = skipPubkeyOption(data)
+		}
+		if !ok || len(data) < 10 {
+			return
+		}
+		cache.Mints().ObserveTransferFeeConfig(mint, cache.TransferFee{
+			Epoch:       epoch,
+			MaximumFee:  binary.LittleEndian.Uint64(data[2:10]),
+			BasisPoints: binary.LittleEndian.Uint16(data[0:2]),
+		})
+
+	// SetTransferFee: [0]=26, [1]=5, [2:4]=basis_points(u16), [4:12]=maximum_fee(u64)
+	case TransferFeeSetTransferFee:
+		if len(ix.Data) < 12 {
+			return
 		}
+		cache.Mints().ObserveSetTransferFee(mint, epoch,
+			binary.LittleEndian.Uint64(ix.Data[4:12]), binary.LittleEndian.Uint16(ix.Data[2:4]))
 	}
 }
 
+// skipPubkeyOption 跳过 Token-2022 指令中的 COption<Pubkey>：标记 0 为 None（1 字节），1 为 Some（33 字节）
+func skipPubkeyOption(data []byte) ([]byte, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+	switch data[0] {
+	case 0:
+		return data[1:], true
+	case 1:
+		if len(data) < 33 {
+			return nil, false
+		}
+		return data[33:], true
+	}
+	return nil, false
+}
+
 func tryFillMintDecimalsDeFromInitMint(ctx *ParserContext, ix *core.AdaptedInstruction) {
 	// 判断数据长度和账户数是否符合预期
 	if len(ix.Data) < 2 || len(ix.Accounts) == 0 {
@@ -45,6 +104,21 @@ func tryFillMintDecimalsDeFromInitMint(ctx *ParserContext, ix *core.AdaptedInstr
 
 	// 将 mint 和 decimals 加入当前交易的 TokenDecimals 缓存
 	ctx.Tx.AddTokenDecimals(mint, decimals)
+
+	// 失败交易中的 mint 创建已回滚，不记录到全局注册表
+	if ctx.Tx.Err != nil {
+		return
+	}
+
+	// Layout: Data = [ix, decimals, mint_authority(32), freeze_authority COption 标记(1), freeze_authority(32)]
+	var mintAuthority, freezeAuthority types.Pubkey
+	if len(ix.Data) >= 34 {
+		copy(mintAuthority[:], ix.Data[2:34])
+	}
+	if len(ix.Data) >= 67 && ix.Data[34] == 1 {
+		copy(freezeAuthority[:], ix.Data[35:67])
+	}
+	cache.Mints().ObserveInitMint(mint, decimals, ix.ProgramID, mintAuthority, freezeAuthority)
 }
 
 // tryFillBalanceFromInitAccount 尝试从初始化账户指令中提取 TokenAccount → Token (mint) → Owner 映射。
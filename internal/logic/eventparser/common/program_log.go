@@ -0,0 +1,93 @@
+package common
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/base64"
+	"strings"
+)
+
+const (
+	logProgramPrefix   = "Program "
+	logDataPrefix      = "Program data: "
+	logInvokePrefix    = "invoke ["
+	logSuccess         = "success"
+	logFailedPrefix    = "failed: "
+	logTruncatedPrefix = "Log truncated"
+)
+
+// ProgramData 返回 instrs[index] 自身通过 sol_log_data 输出的数据（"Program data:" 日志，已 base64 解码），
+// 不含其 CPI 子指令的输出。部分程序（如 OpenBook v2）不通过 self-CPI 而是直接写日志发出事件。
+//
+// 首次调用时按 invoke / success 日志还原调用栈，将日志归属到展平后的指令下标；
+// 日志被截断（Log truncated）或与指令无法对应时，之后的指令没有数据。
+func (ctx *ParserContext) ProgramData(instrs []*core.AdaptedInstruction, index int) [][]byte {
+	if ctx.programData == nil {
+		ctx.programData = attributeProgramData(ctx.LogMessages, instrs)
+	}
+	return ctx.programData[index]
+}
+
+// attributeProgramData 按执行顺序将 "Program <id> invoke [n]" 日志依次对应到展平后的指令，
+// 并把调用栈顶指令输出的 "Program data:" 归属到该指令。没有 invoke 日志的指令（如预编译程序）会被跳过。
+func attributeProgramData(logs []string, instrs []*core.AdaptedInstruction) map[int][][]byte {
+	result := make(map[int][][]byte)
+	stack := make([]int, 0, 8)
+	next := 0
+
+	for _, line := range logs {
+		switch {
+		case strings.HasPrefix(line, logDataPrefix):
+			if len(stack) == 0 {
+				continue
+			}
+			if data, ok := decodeProgramData(line[len(logDataPrefix):]); ok {
+				top := stack[len(stack)-1]
+				result[top] = append(result[top], data)
+			}
+
+		case strings.HasPrefix(line, logTruncatedPrefix):
+			return result
+
+		case strings.HasPrefix(line, logProgramPrefix):
+			// "Program <id> invoke [n]" / "Program <id> success" / "Program <id> failed: ..."，其余（log、return、consumed）忽略
+			id, tail, ok := strings.Cut(line[len(logProgramPrefix):], " ")
+			if !ok {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(tail, logInvokePrefix):
+				programID, err := types.TryPubkeyFromBase58(id)
+				if err != nil {
+					return result
+				}
+				for next < len(instrs) && instrs[next].ProgramID != programID {
+					next++
+				}
+				if next == len(instrs) {
+					return result // 日志与指令无法对应
+				}
+				stack = append(stack, next)
+				next++
+			case tail == logSuccess || strings.HasPrefix(tail, logFailedPrefix):
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			}
+		}
+	}
+	return result
+}
+
+// decodeProgramData 解码 sol_log_data 输出的一行数据，多个字段以空格分隔，各自 base64 编码，解码后按顺序拼接
+func decodeProgramData(encoded string) ([]byte, bool) {
+	var data []byte
+	for _, field := range strings.Fields(encoded) {
+		decoded, err := base64.StdEncoding.DecodeString(field)
+		if err != nil {
+			return nil, false
+		}
+		data = append(data, decoded...)
+	}
+	return data, len(data) > 0
+}
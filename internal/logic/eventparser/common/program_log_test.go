@@ -0,0 +1,91 @@
+package common
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testProgramA = types.PubkeyFromBase58("opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb")
+	testProgramB = types.PubkeyFromBase58("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	testProgramC = types.PubkeyFromBase58("ComputeBudget111111111111111111111111111111")
+)
+
+func TestAttributeProgramData(t *testing.T) {
+	// 0: C（ComputeBudget）
+	// 1: A
+	// 2:   └─ B
+	// 3: A
+	instrs := []*core.AdaptedInstruction{
+		{ProgramID: testProgramC},
+		{ProgramID: testProgramA, IxIndex: 1},
+		{ProgramID: testProgramB, IxIndex: 1, InnerIndex: 1},
+		{ProgramID: testProgramA, IxIndex: 2},
+	}
+	logs := []string{
+		"Program ComputeBudget111111111111111111111111111111 invoke [1]",
+		"Program ComputeBudget111111111111111111111111111111 success",
+		"Program opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb invoke [1]",
+		"Program log: Instruction: PlaceTakeOrder",
+		"Program data: AQI=",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [2]",
+		"Program data: /w==",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA consumed 4645 of 180000 compute units",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA success",
+		"Program data: AwQ= BQ==", // 多个字段按顺序拼接
+		"Program opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb success",
+		"Program opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb invoke [1]",
+		"Program data: !!!", // 无法解码的数据被忽略
+		"Program data: Bg==",
+		"Program opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb failed: custom program error: 0x1",
+	}
+
+	got := attributeProgramData(logs, instrs)
+	assert.Equal(t, map[int][][]byte{
+		1: {{1, 2}, {3, 4, 5}},
+		2: {{0xff}},
+		3: {{6}},
+	}, got)
+}
+
+// 预编译程序（如 Ed25519）没有 invoke 日志，按 ProgramID 跳过
+func TestAttributeProgramData_SkipsPrecompile(t *testing.T) {
+	instrs := []*core.AdaptedInstruction{
+		{ProgramID: testProgramC},
+		{ProgramID: testProgramA, IxIndex: 1},
+	}
+	logs := []string{
+		"Program opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb invoke [1]",
+		"Program data: AQ==",
+		"Program opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb success",
+	}
+	assert.Equal(t, map[int][][]byte{1: {{1}}}, attributeProgramData(logs, instrs))
+}
+
+// 日志被截断后，之后的指令没有数据
+func TestAttributeProgramData_Truncated(t *testing.T) {
+	instrs := []*core.AdaptedInstruction{
+		{ProgramID: testProgramA},
+		{ProgramID: testProgramA, IxIndex: 1},
+	}
+	logs := []string{
+		"Program opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb invoke [1]",
+		"Program data: AQ==",
+		"Log truncated",
+		"Program data: Ag==",
+	}
+	assert.Equal(t, map[int][][]byte{0: {{1}}}, attributeProgramData(logs, instrs))
+}
+
+// 日志中的 program 与指令无法对应时停止归属
+func TestAttributeProgramData_Mismatch(t *testing.T) {
+	instrs := []*core.AdaptedInstruction{{ProgramID: testProgramA}}
+	logs := []string{
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [1]",
+		"Program data: AQ==",
+	}
+	assert.Empty(t, attributeProgramData(logs, instrs))
+}
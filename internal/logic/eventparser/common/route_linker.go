@@ -0,0 +1,59 @@
+package common
+
+import (
+	"dex-indexer-sol/pb"
+)
+
+// RouteSwap 记录一次聚合器路由覆盖的指令范围。
+// 路由内各 DEX 的 swap 指令是聚合器指令的子指令，由各自的 handler 照常解析，
+// 解析结束后按产生事件的指令下标（而非 event_id，inner 序号超过 255 时 event_id 会溢出到下一条主指令）
+// 将其 TradeEvent / TransferEvent 关联到路由事件。
+type RouteSwap struct {
+	Event   *pb.RouteSwapEvent
+	Start   int   // 路由指令在 instrs 中的下标
+	End     int   // 路由指令调用子树中最后一条指令的下标（见 SubtreeEnd）
+	HopEnds []int // 每个 hop 的 SwapEvent 日志指令下标，hop 的 DEX 指令位于上一个 hop 日志之后、本 hop 日志之前；
+	// 为空时（v2 路由在全部 hop 完成后统一发出 SwapsEvent）按执行顺序依次关联
+
+	nextHop int // HopEnds 为空时下一个待关联的 hop
+}
+
+// LinkRouteSwaps 为路由范围内的 Trade / Transfer 事件设置 parent_event_id，
+// 并将每个 hop 与其范围内的第一个 TradeEvent 关联。
+func LinkRouteSwaps(ctx *ParserContext) {
+	for _, route := range ctx.RouteSwaps {
+		routeID := route.Event.EventId
+		for i, event := range ctx.Events {
+			pos := ctx.eventPos[i]
+			if pos <= route.Start || pos > route.End {
+				continue
+			}
+			switch e := event.Event.Event.(type) {
+			case *pb.Event_Trade:
+				e.Trade.ParentEventId = routeID
+				route.linkHop(pos, e.Trade.EventId)
+			case *pb.Event_Transfer:
+				e.Transfer.ParentEventId = routeID
+			}
+		}
+	}
+}
+
+// linkHop 将 TradeEvent 关联到其所在的 hop（每个 hop 只关联第一个）
+func (route *RouteSwap) linkHop(pos int, tradeID uint64) {
+	if len(route.HopEnds) == 0 {
+		if route.nextHop < len(route.Event.Hops) {
+			route.Event.Hops[route.nextHop].TradeEventId = tradeID
+			route.nextHop++
+		}
+		return
+	}
+	for i, end := range route.HopEnds {
+		if pos < end {
+			if hop := route.Event.Hops[i]; hop.TradeEventId == 0 {
+				hop.TradeEventId = tradeID
+			}
+			return
+		}
+	}
+}
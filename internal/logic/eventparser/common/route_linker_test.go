@@ -0,0 +1,114 @@
+package common
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func addTestEvent(ctx *ParserContext, pos int, event *pb.Event) {
+	ctx.Current = pos
+	ctx.AddEvent(&core.Event{Event: event})
+}
+
+func testTrade(id uint64) *pb.Event {
+	return &pb.Event{Event: &pb.Event_Trade{Trade: &pb.TradeEvent{EventId: id}}}
+}
+
+func testTransfer(id uint64) *pb.Event {
+	return &pb.Event{Event: &pb.Event_Transfer{Transfer: &pb.TransferEvent{EventId: id}}}
+}
+
+func TestLinkRouteSwaps(t *testing.T) {
+	ctx := &ParserContext{}
+	route := &RouteSwap{
+		Event:   &pb.RouteSwapEvent{EventId: 100, Hops: []*pb.RouteHop{{}, {}}},
+		Start:   10,
+		End:     400,
+		HopEnds: []int{200, 400},
+	}
+	ctx.RouteSwaps = append(ctx.RouteSwaps, route)
+
+	before := testTrade(1)
+	self := testTrade(7) // 路由指令本身所在位置的事件不属于路由
+	hop1 := testTrade(2)
+	hop1Extra := testTrade(3)
+	transfer := testTransfer(4)
+	hop2 := testTrade(5) // inner 序号超过 255，event_id 会与下一条主指令重叠，按指令下标仍属于路由
+	after := testTrade(6)
+	addTestEvent(ctx, 5, before)
+	addTestEvent(ctx, 10, self)
+	addTestEvent(ctx, 20, hop1)
+	addTestEvent(ctx, 150, hop1Extra)
+	addTestEvent(ctx, 210, transfer)
+	addTestEvent(ctx, 300, hop2)
+	addTestEvent(ctx, 401, after)
+
+	LinkRouteSwaps(ctx)
+
+	assert.Zero(t, before.GetTrade().ParentEventId)
+	assert.Zero(t, self.GetTrade().ParentEventId)
+	assert.Equal(t, uint64(100), hop1.GetTrade().ParentEventId)
+	assert.Equal(t, uint64(100), hop1Extra.GetTrade().ParentEventId)
+	assert.Equal(t, uint64(100), transfer.GetTransfer().ParentEventId)
+	assert.Equal(t, uint64(100), hop2.GetTrade().ParentEventId)
+	assert.Zero(t, after.GetTrade().ParentEventId)
+
+	// 每个 hop 关联其范围内的第一个 TradeEvent
+	assert.Equal(t, uint64(2), route.Event.Hops[0].TradeEventId)
+	assert.Equal(t, uint64(5), route.Event.Hops[1].TradeEventId)
+}
+
+// v2 路由的 SwapsEvent 在全部 hop 之后发出，按执行顺序依次关联，多出的 TradeEvent 不关联 hop
+func TestLinkRouteSwaps_Sequential(t *testing.T) {
+	ctx := &ParserContext{}
+	route := &RouteSwap{
+		Event: &pb.RouteSwapEvent{EventId: 100, Hops: []*pb.RouteHop{{}, {}}},
+		Start: 0,
+		End:   10,
+	}
+	ctx.RouteSwaps = append(ctx.RouteSwaps, route)
+	extra := testTrade(3)
+	addTestEvent(ctx, 2, testTrade(1))
+	addTestEvent(ctx, 5, testTrade(2))
+	addTestEvent(ctx, 8, extra)
+
+	LinkRouteSwaps(ctx)
+
+	assert.Equal(t, uint64(1), route.Event.Hops[0].TradeEventId)
+	assert.Equal(t, uint64(2), route.Event.Hops[1].TradeEventId)
+	assert.Equal(t, uint64(100), extra.GetTrade().ParentEventId)
+}
+
+// hop 范围内没有 TradeEvent 时保持为 0；同一交易中的多个路由互不影响
+func TestLinkRouteSwaps_EmptyHopAndMultipleRoutes(t *testing.T) {
+	ctx := &ParserContext{}
+	first := &RouteSwap{
+		Event:   &pb.RouteSwapEvent{EventId: 100, Hops: []*pb.RouteHop{{}, {}}},
+		Start:   0,
+		End:     10,
+		HopEnds: []int{4, 10},
+	}
+	second := &RouteSwap{
+		Event:   &pb.RouteSwapEvent{EventId: 200, Hops: []*pb.RouteHop{{}}},
+		Start:   11,
+		End:     20,
+		HopEnds: []int{20},
+	}
+	ctx.RouteSwaps = append(ctx.RouteSwaps, first, second)
+	firstHop := testTrade(1)
+	secondRoute := testTrade(2)
+	addTestEvent(ctx, 6, testTransfer(3))
+	addTestEvent(ctx, 8, firstHop) // 位于第二个 hop 范围
+	addTestEvent(ctx, 15, secondRoute)
+
+	LinkRouteSwaps(ctx)
+
+	assert.Zero(t, first.Event.Hops[0].TradeEventId)
+	assert.Equal(t, uint64(1), first.Event.Hops[1].TradeEventId)
+	assert.Equal(t, uint64(100), firstHop.GetTrade().ParentEventId)
+	assert.Equal(t, uint64(200), secondRoute.GetTrade().ParentEventId)
+	assert.Equal(t, uint64(2), second.Event.Hops[0].TradeEventId)
+}
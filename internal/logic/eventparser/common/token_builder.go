@@ -26,6 +26,9 @@ func BuildTransferEvent(
 		Decimals:         uint32(transfer.Decimals),
 		SrcTokenBalance:  transfer.SrcPostBalance,
 		DestTokenBalance: transfer.DestPostBalance,
+		Fee:              transfer.Fee,
+		WithheldWithdraw: transfer.WithheldWithdraw,
+		Native:           transfer.Native,
 	}
 
 	return &core.Event{
@@ -1,9 +1,12 @@
 package common
 
 import (
+	"dex-indexer-sol/internal/cache"
+	"dex-indexer-sol/internal/consts"
 	"dex-indexer-sol/internal/logic/core"
 	"dex-indexer-sol/internal/pkg/logger"
 	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/internal/tools"
 	"encoding/binary"
 	sdktoken "github.com/blocto/solana-go-sdk/program/token"
 )
@@ -12,19 +15,43 @@ import (
 // SplToken: https://github.com/solana-program/token/blob/main/program/src/instruction.rs
 // Token2022: https://github.com/solana-program/token-2022
 
-// ParsedTransfer 表示一次 SPL Token 的 Transfer 或 TransferChecked 操作。
+// Token-2022 TransferFeeExtension 指令：Data[0] = 26，Data[1] 为子指令
+// 来源：https://github.com/solana-program/token-2022/blob/main/program/src/extension/transfer_fee/instruction.rs
+const (
+	InstructionTransferFeeExtension byte = 26
+
+	TransferFeeInitializeConfig            byte = 0
+	TransferFeeTransferCheckedWithFee      byte = 1
+	TransferFeeWithdrawFromMint            byte = 2
+	TransferFeeWithdrawFromAccounts        byte = 3
+	TransferFeeHarvestWithheldTokensToMint byte = 4
+	TransferFeeSetTransferFee              byte = 5
+)
+
+// ParsedTransfer 表示一次 SPL Token 的 Transfer、TransferChecked 或 TransferCheckedWithFee 操作。
 type ParsedTransfer struct {
-	IxIndex         uint16       // 主指令
-	InnerIndex      uint16       // 内部指令
-	Token           types.Pubkey // Token mint 地址
-	SrcAccount      types.Pubkey // 来源 TokenAccount
-	DestAccount     types.Pubkey // 目标 TokenAccount
-	SrcWallet       types.Pubkey // 来源账户所有者
-	DestWallet      types.Pubkey // 目标账户所有者
-	Amount          uint64       // 转账数量（最小单位）
-	Decimals        uint8        // Token 精度
-	SrcPostBalance  uint64       // 来源账户转账后余额
-	DestPostBalance uint64       // 目标账户转账后余额
+	IxIndex          uint16       // 主指令
+	InnerIndex       uint16       // 内部指令
+	Token            types.Pubkey // Token mint 地址
+	SrcAccount       types.Pubkey // 来源 TokenAccount
+	DestAccount      types.Pubkey // 目标 TokenAccount
+	SrcWallet        types.Pubkey // 来源账户所有者
+	DestWallet       types.Pubkey // 目标账户所有者
+	Amount           uint64       // 转账数量（最小单位）
+	Decimals         uint8        // Token 精度
+	SrcPostBalance   uint64       // 来源账户转账后余额
+	DestPostBalance  uint64       // 目标账户转账后余额
+	Fee              uint64       // Token-2022 转账手续费（由目标账户预扣），目标账户实际到账 Amount - Fee
+	WithheldWithdraw bool         // 是否为提取预扣手续费（WithdrawWithheldTokens），此时 SrcAccount 为 mint
+	Native           bool         // 是否为 System Program 的原生 SOL 转账，此时 Token 为 SOLMint，账户即钱包
+}
+
+// NetAmount 返回扣除 Token-2022 转账手续费后目标账户实际到账的数量
+func (t *ParsedTransfer) NetAmount() uint64 {
+	if t.Fee >= t.Amount {
+		return 0
+	}
+	return t.Amount - t.Fee
 }
 
 // ParsedMintTo 表示一次 SPL Token 的 MintTo 或 MintToChecked 操作。
@@ -99,6 +126,7 @@ func ParseTransferInstruction(ctx *ParserContext, ix *core.AdaptedInstruction) (
 			logger.Errorf("[Token::TransferChecked] tx=%s: mint mismatch, balance.token=%s, ix.mint=%s (account=%s)",
 				ctx.TxHashString(), srcInfo.Token, ix.Accounts[1], ix.Accounts[0])
 		}
+		amount := binary.LittleEndian.Uint64(ix.Data[1:9])
 		return &ParsedTransfer{
 			IxIndex:         ix.IxIndex,
 			InnerIndex:      ix.InnerIndex,
@@ -107,15 +135,159 @@ func ParseTransferInstruction(ctx *ParserContext, ix *core.AdaptedInstruction) (
 			DestAccount:     ix.Accounts[2],
 			SrcWallet:       ix.Accounts[3],
 			DestWallet:      destInfo.PostOwner,
-			Amount:          binary.LittleEndian.Uint64(ix.Data[1:9]),
+			Amount:          amount,
+			Decimals:        srcInfo.Decimals,
+			SrcPostBalance:  srcInfo.PostBalance,
+			DestPostBalance: destInfo.PostBalance,
+			Fee:             estimateTransferFee(ctx, ix, srcInfo.Token, amount, ix.Accounts[2], destInfo),
+		}, true
+
+	// TransferCheckedWithFee: [0]=26, [1]=1, [2:10]=amount, [10]=decimals, [11:19]=fee
+	// accounts = [src_account, mint, dest_account, authority_wallet]
+	case InstructionTransferFeeExtension:
+		if len(ix.Data) < 19 || ix.Data[1] != TransferFeeTransferCheckedWithFee || len(ix.Accounts) < 4 {
+			return nil, false
+		}
+		srcInfo, ok1 := ctx.Balances[ix.Accounts[0]]
+		destInfo, ok2 := ctx.Balances[ix.Accounts[2]]
+		if !ok1 || !ok2 {
+			logger.Errorf("[Token::TransferCheckedWithFee] tx=%s: balance missing src=%s ok=%v dest=%s ok=%v",
+				ctx.TxHashString(), ix.Accounts[0], ok1, ix.Accounts[2], ok2)
+			return nil, false
+		}
+		return &ParsedTransfer{
+			IxIndex:         ix.IxIndex,
+			InnerIndex:      ix.InnerIndex,
+			Token:           srcInfo.Token,
+			SrcAccount:      ix.Accounts[0],
+			DestAccount:     ix.Accounts[2],
+			SrcWallet:       ix.Accounts[3],
+			DestWallet:      destInfo.PostOwner,
+			Amount:          binary.LittleEndian.Uint64(ix.Data[2:10]),
 			Decimals:        srcInfo.Decimals,
 			SrcPostBalance:  srcInfo.PostBalance,
 			DestPostBalance: destInfo.PostBalance,
+			Fee:             binary.LittleEndian.Uint64(ix.Data[11:19]),
 		}, true
 	}
 	return nil, false
 }
 
+// IsTransferInstruction 判断 Token Program 指令是否为 Transfer / TransferChecked / TransferCheckedWithFee
+func IsTransferInstruction(ix *core.AdaptedInstruction) bool {
+	if len(ix.Data) == 0 {
+		return false
+	}
+	switch ix.Data[0] {
+	case byte(sdktoken.InstructionTransfer), byte(sdktoken.InstructionTransferChecked):
+		return true
+	case InstructionTransferFeeExtension:
+		return len(ix.Data) > 1 && ix.Data[1] == TransferFeeTransferCheckedWithFee
+	}
+	return false
+}
+
+// estimateTransferFee 计算 Token-2022 TransferChecked 由目标账户预扣的手续费。
+// 指令本身不含手续费：全局 mint 注册表中已有该 mint 的链上账户或 TransferFeeConfig 时按费率与区块 epoch 计算，
+// 否则按目标账户在交易中的余额变化推算（见 transferFeeFromBalance）。
+func estimateTransferFee(
+	ctx *ParserContext,
+	ix *core.AdaptedInstruction,
+	mint types.Pubkey,
+	amount uint64,
+	dest types.Pubkey,
+	destInfo *core.TokenBalance,
+) uint64 {
+	if ix.ProgramID != consts.TokenProgram2022 {
+		return 0
+	}
+	if info, ok := cache.Mints().Get(mint); ok && (info.HasAccount || info.HasTransferFee) {
+		return info.TransferFeeAt(ctx.Tx.TxCtx.Epoch, amount)
+	}
+	return transferFeeFromBalance(ctx, ix, amount, dest, destInfo)
+}
+
+// transferFeeFromBalance 费率未知时，用目标账户交易前后的余额差推算实际到账数量：
+// 仅当本指令是交易中唯一改变该账户余额的 Token 指令时才可推算，否则按无手续费处理。
+func transferFeeFromBalance(
+	ctx *ParserContext,
+	ix *core.AdaptedInstruction,
+	amount uint64,
+	dest types.Pubkey,
+	destInfo *core.TokenBalance,
+) uint64 {
+	if destInfo.PostBalance < destInfo.PreBalance {
+		return 0
+	}
+	received := destInfo.PostBalance - destInfo.PreBalance
+	if received >= amount {
+		return 0
+	}
+	for _, other := range ctx.Tx.Instructions {
+		if other == ix || !tools.IsSPLTokenPubkey(other.ProgramID) || !changesTokenBalance(other) {
+			continue
+		}
+		for _, account := range other.Accounts {
+			if account == dest {
+				return 0
+			}
+		}
+	}
+	return amount - received
+}
+
+// changesTokenBalance 判断 Token 指令是否会改变 token 账户余额（转账、铸币、销毁、提取预扣手续费）
+func changesTokenBalance(ix *core.AdaptedInstruction) bool {
+	if len(ix.Data) == 0 {
+		return false
+	}
+	switch ix.Data[0] {
+	case byte(sdktoken.InstructionTransfer), byte(sdktoken.InstructionTransferChecked),
+		byte(sdktoken.InstructionMintTo), byte(sdktoken.InstructionMintToChecked),
+		byte(sdktoken.InstructionBurn), byte(sdktoken.InstructionBurnChecked):
+		return true
+	case InstructionTransferFeeExtension:
+		return len(ix.Data) > 1 && (ix.Data[1] == TransferFeeTransferCheckedWithFee ||
+			ix.Data[1] == TransferFeeWithdrawFromMint || ix.Data[1] == TransferFeeWithdrawFromAccounts)
+	}
+	return false
+}
+
+// ParseWithdrawWithheldInstruction 解析 WithdrawWithheldTokensFromMint / FromAccounts 指令，
+// 将预扣的手续费提取到目标账户。指令不含数量，按目标账户在交易中的余额变化计算。
+func ParseWithdrawWithheldInstruction(ctx *ParserContext, ix *core.AdaptedInstruction) (*ParsedTransfer, bool) {
+	// WithdrawWithheldTokensFromMint: [0]=26, [1]=2；accounts = [mint, dest_account, authority, ...]
+	// WithdrawWithheldTokensFromAccounts: [0]=26, [1]=3, [2]=num_token_accounts；accounts = [mint, dest_account, authority, ...signers, ...sources]
+	if len(ix.Data) < 2 || ix.Data[0] != InstructionTransferFeeExtension || len(ix.Accounts) < 3 {
+		return nil, false
+	}
+	if ix.Data[1] != TransferFeeWithdrawFromMint && ix.Data[1] != TransferFeeWithdrawFromAccounts {
+		return nil, false
+	}
+	destInfo, ok := ctx.Balances[ix.Accounts[1]]
+	if !ok {
+		logger.Errorf("[Token::WithdrawWithheld] tx=%s: dest_token_account missing: %s",
+			ctx.TxHashString(), ix.Accounts[1])
+		return nil, false
+	}
+	if destInfo.PostBalance <= destInfo.PreBalance {
+		return nil, false
+	}
+	return &ParsedTransfer{
+		IxIndex:          ix.IxIndex,
+		InnerIndex:       ix.InnerIndex,
+		Token:            ix.Accounts[0],
+		SrcAccount:       ix.Accounts[0],
+		DestAccount:      ix.Accounts[1],
+		SrcWallet:        ix.Accounts[2],
+		DestWallet:       destInfo.PostOwner,
+		Amount:           destInfo.PostBalance - destInfo.PreBalance,
+		Decimals:         destInfo.Decimals,
+		DestPostBalance:  destInfo.PostBalance,
+		WithheldWithdraw: true,
+	}, true
+}
+
 // ParseMintToInstruction 解析 MintTo / MintToChecked 指令
 func ParseMintToInstruction(ctx *ParserContext, ix *core.AdaptedInstruction) (*ParsedMintTo, bool) {
 	// MintTo: [0]=instr, [1:9]=amount, [9]=decimals (可选)
@@ -62,27 +62,29 @@ func buildBuyEvent(
 	dex uint32,
 ) *pb.TradeEvent {
 	event := &pb.TradeEvent{
-		Type:              pb.EventType_TRADE_BUY,
-		EventId:           core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
-		Slot:              ctx.Slot,
-		BlockTime:         ctx.BlockTime,
-		TxHash:            ctx.TxHash,
-		Signers:           ctx.Signers,
-		Dex:               dex,
-		TokenDecimals:     uint32(poolToUser.Decimals), // base 精度
-		QuoteDecimals:     uint32(userToPool.Decimals), // quote 精度
-		TokenAmount:       poolToUser.Amount,           // 获得 base
-		QuoteTokenAmount:  userToPool.Amount,           // 支付 quote
-		Token:             poolToUser.Token[:],
-		QuoteToken:        quote[:],
-		PairAddress:       pairAddress[:],
-		TokenAccount:      poolToUser.SrcAccount[:],
-		QuoteTokenAccount: userToPool.DestAccount[:],
-		UserWallet:        poolToUser.DestWallet[:],
-		PairTokenBalance:  poolToUser.SrcPostBalance,
-		PairQuoteBalance:  userToPool.DestPostBalance,
-		UserTokenBalance:  poolToUser.DestPostBalance,
-		UserQuoteBalance:  userToPool.SrcPostBalance,
+		Type:                pb.EventType_TRADE_BUY,
+		EventId:             core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
+		Slot:                ctx.Slot,
+		BlockTime:           ctx.BlockTime,
+		TxHash:              ctx.TxHash,
+		Signers:             ctx.Signers,
+		Dex:                 dex,
+		TokenDecimals:       uint32(poolToUser.Decimals), // base 精度
+		QuoteDecimals:       uint32(userToPool.Decimals), // quote 精度
+		TokenAmount:         poolToUser.Amount,           // 获得 base
+		QuoteTokenAmount:    userToPool.Amount,           // 支付 quote
+		TokenAmountNet:      poolToUser.NetAmount(),      // 用户实际到账的 base
+		QuoteTokenAmountNet: userToPool.NetAmount(),      // 池子实际到账的 quote
+		Token:               poolToUser.Token[:],
+		QuoteToken:          quote[:],
+		PairAddress:         pairAddress[:],
+		TokenAccount:        poolToUser.SrcAccount[:],
+		QuoteTokenAccount:   userToPool.DestAccount[:],
+		UserWallet:          poolToUser.DestWallet[:],
+		PairTokenBalance:    poolToUser.SrcPostBalance,
+		PairQuoteBalance:    userToPool.DestPostBalance,
+		UserTokenBalance:    poolToUser.DestPostBalance,
+		UserQuoteBalance:    userToPool.SrcPostBalance,
 	}
 
 	// 处理 SOL -> WSOL 的临时账户转账：若 WSOL 账户余额为 0 且为临时创建，则使用用户钱包中的 SOL 补充 quote 余额
@@ -106,27 +108,29 @@ func buildSellEvent(
 	dex uint32,
 ) *pb.TradeEvent {
 	event := &pb.TradeEvent{
-		Type:              pb.EventType_TRADE_SELL,
-		EventId:           core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
-		Slot:              ctx.Slot,
-		BlockTime:         ctx.BlockTime,
-		TxHash:            ctx.TxHash,
-		Signers:           ctx.Signers,
-		Dex:               dex,
-		TokenDecimals:     uint32(userToPool.Decimals),
-		QuoteDecimals:     uint32(poolToUser.Decimals),
-		TokenAmount:       userToPool.Amount, // 卖出的 base
-		QuoteTokenAmount:  poolToUser.Amount, // 获得的 quote
-		Token:             userToPool.Token[:],
-		QuoteToken:        quote[:],
-		PairAddress:       pairAddress[:],
-		TokenAccount:      userToPool.DestAccount[:],
-		QuoteTokenAccount: poolToUser.SrcAccount[:],
-		UserWallet:        poolToUser.DestWallet[:],
-		PairTokenBalance:  userToPool.DestPostBalance,
-		PairQuoteBalance:  poolToUser.SrcPostBalance,
-		UserTokenBalance:  userToPool.SrcPostBalance,
-		UserQuoteBalance:  poolToUser.DestPostBalance,
+		Type:                pb.EventType_TRADE_SELL,
+		EventId:             core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
+		Slot:                ctx.Slot,
+		BlockTime:           ctx.BlockTime,
+		TxHash:              ctx.TxHash,
+		Signers:             ctx.Signers,
+		Dex:                 dex,
+		TokenDecimals:       uint32(userToPool.Decimals),
+		QuoteDecimals:       uint32(poolToUser.Decimals),
+		TokenAmount:         userToPool.Amount,      // 卖出的 base
+		QuoteTokenAmount:    poolToUser.Amount,      // 获得的 quote
+		TokenAmountNet:      userToPool.NetAmount(), // 池子实际到账的 base
+		QuoteTokenAmountNet: poolToUser.NetAmount(), // 用户实际到账的 quote
+		Token:               userToPool.Token[:],
+		QuoteToken:          quote[:],
+		PairAddress:         pairAddress[:],
+		TokenAccount:        userToPool.DestAccount[:],
+		QuoteTokenAccount:   poolToUser.SrcAccount[:],
+		UserWallet:          poolToUser.DestWallet[:],
+		PairTokenBalance:    userToPool.DestPostBalance,
+		PairQuoteBalance:    poolToUser.SrcPostBalance,
+		UserTokenBalance:    userToPool.SrcPostBalance,
+		UserQuoteBalance:    poolToUser.DestPostBalance,
 	}
 
 	// 若 Quote 为 WSOL 且为临时账户（余额为 0），用 SOL 余额补充 Quote 余额。
@@ -3,16 +3,16 @@ package common
 import (
 	"dex-indexer-sol/internal/logic/core"
 	"dex-indexer-sol/internal/tools"
-	sdktoken "github.com/blocto/solana-go-sdk/program/token"
 )
 
 // SwapInstructionIndex 表示 Swap 操作中涉及的关键账户索引。
 // 所有字段对应主指令中的 accounts 列表索引位置。
 type SwapInstructionIndex struct {
-	UserToken1AccountIndex int // 用户提供的 token1 账户索引（可能为支付或接收）
-	UserToken2AccountIndex int // 用户提供的 token2 账户索引（可能为支付或接收）
-	PoolToken1AccountIndex int // 池子的 token1 账户索引
-	PoolToken2AccountIndex int // 池子的 token2 账户索引
+	UserToken1AccountIndex int  // 用户提供的 token1 账户索引（可能为支付或接收）
+	UserToken2AccountIndex int  // 用户提供的 token2 账户索引（可能为支付或接收）
+	PoolToken1AccountIndex int  // 池子的 token1 账户索引
+	PoolToken2AccountIndex int  // 池子的 token2 账户索引
+	IncludeNested          bool // 是否匹配间接子指令（池子资金经由其它程序 CPI 转账时使用，如 Meteora DAMM v1 的 vault）
 }
 
 // SwapTransferResult 表示成功识别出的 Swap 中两个方向的转账记录。
@@ -39,10 +39,11 @@ func validateSwapInstructionIndex(indexes *SwapInstructionIndex, accountsLen int
 // 参数说明：
 //   - ctx          : 当前交易解析上下文（包含账户余额、Token 结构等信息）。
 //   - instrs       : 展平后的指令列表（包含主指令和 inner 指令）。
-//   - current      : 当前主指令在 instrs 中的索引（作为匹配起点）。
+//   - current      : 当前指令（主指令或 CPI 调用的 inner 指令）在 instrs 中的索引，
+//     只匹配其直接子指令（indexes.IncludeNested 时匹配整个调用子树）。
 //   - indexes      : 表示用户和池子之间 Token 账户的索引结构。
-//   - maxLookahead : 向后最多检查的指令数量（不包括主指令本身）；
-//     若为 0，表示不限制，遍历当前主指令的所有 inner 指令（IxIndex 不变）。
+//   - maxLookahead : 最多检查的直接子指令数量；
+//     若为 0，表示不限制，遍历当前指令的全部直接子指令（见 Children）。
 //
 // 返回值：若同时成功匹配两个方向的转账，返回 SwapTransferResult；否则返回 nil。
 func FindSwapTransfersByIndex(
@@ -67,13 +68,7 @@ func FindSwapTransfersByIndex(
 	maxIndex := current
 	looked := 0
 
-	for i := current + 1; i < len(instrs); i++ {
-		ix := instrs[i]
-
-		// 只处理当前主指令的 inner 指令
-		if ix.IxIndex != mainIx.IxIndex {
-			break
-		}
+	for i, ix := range subInstructions(instrs, current, indexes.IncludeNested) {
 		if maxLookahead > 0 {
 			if looked >= maxLookahead {
 				break
@@ -87,8 +82,7 @@ func FindSwapTransfersByIndex(
 		}
 
 		// 仅处理 Transfer 类型的指令
-		if ix.Data[0] != byte(sdktoken.InstructionTransfer) &&
-			ix.Data[0] != byte(sdktoken.InstructionTransferChecked) {
+		if !IsTransferInstruction(ix) {
 			continue
 		}
 
@@ -0,0 +1,49 @@
+package common
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/pb"
+)
+
+// BuildTxFee 将交易的手续费、优先费、CU 与 Jito 小费信息转换为 pb.TxFee。
+func BuildTxFee(tx *core.AdaptedTx) *pb.TxFee {
+	return &pb.TxFee{
+		Fee:                  tx.Fee,
+		BaseFee:              tx.BaseFee,
+		PriorityFee:          tx.PriorityFee,
+		ComputeUnitPrice:     tx.ComputeUnitPrice,
+		ComputeUnitLimit:     tx.ComputeUnitLimit,
+		ComputeUnitsConsumed: tx.ComputeUnitsConsumed,
+		JitoTip:              tx.JitoTip,
+	}
+}
+
+// AttachTxFee 为交易内的全部事件填充手续费信息（同一交易的事件共享同一个 TxFee，只读）。
+func AttachTxFee(events []*core.Event, fee *pb.TxFee) {
+	for _, event := range events {
+		switch e := event.Event.Event.(type) {
+		case *pb.Event_Trade:
+			e.Trade.TxFee = fee
+		case *pb.Event_FailedTrade:
+			e.FailedTrade.TxFee = fee
+		case *pb.Event_Transfer:
+			e.Transfer.TxFee = fee
+		case *pb.Event_Liquidity:
+			e.Liquidity.TxFee = fee
+		case *pb.Event_Mint:
+			e.Mint.TxFee = fee
+		case *pb.Event_Burn:
+			e.Burn.TxFee = fee
+		case *pb.Event_Migrate:
+			e.Migrate.TxFee = fee
+		case *pb.Event_Token:
+			e.Token.TxFee = fee
+		case *pb.Event_Account:
+			e.Account.TxFee = fee
+		case *pb.Event_RouteSwap:
+			e.RouteSwap.TxFee = fee
+		case *pb.Event_OrderbookFill:
+			e.OrderbookFill.TxFee = fee
+		}
+	}
+}
@@ -3,36 +3,76 @@ package eventparser
 import (
 	"dex-indexer-sol/internal/logic/core"
 	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/logic/eventparser/jupiter"
+	"dex-indexer-sol/internal/logic/eventparser/meteoradamm"
+	"dex-indexer-sol/internal/logic/eventparser/meteoradbc"
 	"dex-indexer-sol/internal/logic/eventparser/meteoradlmm"
+	"dex-indexer-sol/internal/logic/eventparser/openbookv2"
 	"dex-indexer-sol/internal/logic/eventparser/oracle"
 	"dex-indexer-sol/internal/logic/eventparser/orcawhirlpool"
+	"dex-indexer-sol/internal/logic/eventparser/phoenix"
 	"dex-indexer-sol/internal/logic/eventparser/pumpfun"
 	"dex-indexer-sol/internal/logic/eventparser/pumpfunamm"
 	"dex-indexer-sol/internal/logic/eventparser/raydiumclmm"
 	"dex-indexer-sol/internal/logic/eventparser/raydiumcpmm"
+	"dex-indexer-sol/internal/logic/eventparser/raydiumlaunchlab"
 	"dex-indexer-sol/internal/logic/eventparser/raydiumv4"
 	"dex-indexer-sol/internal/logic/eventparser/spltoken"
+	"dex-indexer-sol/internal/logic/eventparser/systemprogram"
 	"dex-indexer-sol/internal/pkg/logger"
 	"dex-indexer-sol/internal/pkg/types"
 	"github.com/mr-tron/base58"
 	"runtime/debug"
+	"sort"
 )
 
 // handlers 是 Solana ProgramID → 对应事件解析 handler 的路由表。
 // 所有协议模块通过 RegisterHandlers 注册进该表。
 var handlers = map[types.Pubkey]common.InstructionHandler{}
 
+// failedHandlers 是 ProgramID → 失败交易 Swap 意图解析 handler 的路由表，仅在开启失败交易索引时使用。
+var failedHandlers = map[types.Pubkey]common.FailedTradeHandler{}
+
 // Init 初始化所有 handler 注册器等解析所需状态
 func Init() {
 	spltoken.RegisterHandlers(handlers)
+	systemprogram.RegisterHandlers(handlers)
 	raydiumv4.RegisterHandlers(handlers)
 	raydiumclmm.RegisterHandlers(handlers)
 	raydiumcpmm.RegisterHandlers(handlers)
 	pumpfunamm.RegisterHandlers(handlers)
 	pumpfun.RegisterHandlers(handlers)
+	raydiumlaunchlab.RegisterHandlers(handlers)
+	meteoradbc.RegisterHandlers(handlers)
 	meteoradlmm.RegisterHandlers(handlers)
+	meteoradamm.RegisterHandlers(handlers)
 	orcawhirlpool.RegisterHandlers(handlers)
+	openbookv2.RegisterHandlers(handlers)
+	phoenix.RegisterHandlers(handlers)
 	oracle.RegisterHandlers(handlers)
+	jupiter.RegisterHandlers(handlers)
+
+	raydiumv4.RegisterFailedHandlers(failedHandlers)
+	raydiumclmm.RegisterFailedHandlers(failedHandlers)
+	raydiumcpmm.RegisterFailedHandlers(failedHandlers)
+	pumpfunamm.RegisterFailedHandlers(failedHandlers)
+	pumpfun.RegisterFailedHandlers(failedHandlers)
+	raydiumlaunchlab.RegisterFailedHandlers(failedHandlers)
+	meteoradbc.RegisterFailedHandlers(failedHandlers)
+	meteoradlmm.RegisterFailedHandlers(failedHandlers)
+	meteoradamm.RegisterFailedHandlers(failedHandlers)
+	orcawhirlpool.RegisterFailedHandlers(failedHandlers)
+}
+
+// ProgramIDs 返回已注册 handler 的全部 ProgramID（base58，已排序），需在 Init 之后调用。
+// 用于 gRPC 交易订阅模式下按 program 过滤交易。
+func ProgramIDs() []string {
+	ids := make([]string, 0, len(handlers))
+	for programID := range handlers {
+		ids = append(ids, programID.String())
+	}
+	sort.Strings(ids)
+	return ids
 }
 
 func ExtractEventsFromTx(adaptedTx *core.AdaptedTx) (events []*core.Event, priceEvents []*core.PriceEvent) {
@@ -54,6 +94,7 @@ func ExtractEventsFromTx(adaptedTx *core.AdaptedTx) (events []*core.Event, price
 	for i := 0; i < len(instrs); {
 		ix := instrs[i]
 		if handler, ok := handlers[ix.ProgramID]; ok {
+			ctx.Current = i
 			if next := handler(ctx, instrs, i); next > i {
 				i = next
 				continue
@@ -61,5 +102,45 @@ func ExtractEventsFromTx(adaptedTx *core.AdaptedTx) (events []*core.Event, price
 		}
 		i++
 	}
-	return ctx.TakeEvents(), ctx.TakePriceEvents()
+
+	// 关联聚合器路由内各 DEX 的交易与转账事件
+	common.LinkRouteSwaps(ctx)
+
+	events = ctx.TakeEvents()
+	if len(events) > 0 {
+		common.AttachTxFee(events, common.BuildTxFee(adaptedTx))
+	}
+	return events, ctx.TakePriceEvents()
+}
+
+// ExtractFailedTradesFromTx 解析执行失败的交易，为其中每条可识别的 Swap 指令（含 inner 指令）生成 FAILED_TRADE 事件。
+// 失败交易的状态变更已全部回滚，不产生 Trade/Transfer 等常规事件。
+func ExtractFailedTradesFromTx(adaptedTx *core.AdaptedTx) (events []*core.Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			txHash := base58.Encode(adaptedTx.Signature)
+			logger.Errorf("[eventparser::ExtractFailedTradesFromTx] panic tx=%s: %+v\nstack: %s", txHash, r, debug.Stack())
+			events = nil
+		}
+	}()
+
+	ctx := common.BuildParserContext(adaptedTx)
+	instrs := ctx.Tx.Instructions
+
+	// 用户 token 账户可能在本交易中创建，补全 TokenAccount → Mint 映射用于推断 swap 方向
+	common.PreScanInitAccountBalances(ctx, instrs)
+
+	for _, ix := range instrs {
+		if handler, ok := failedHandlers[ix.ProgramID]; ok {
+			if event := handler(ctx, ix); event != nil {
+				ctx.AddEvent(event)
+			}
+		}
+	}
+
+	events = ctx.TakeEvents()
+	if len(events) > 0 {
+		common.AttachTxFee(events, common.BuildTxFee(adaptedTx))
+	}
+	return events
 }
@@ -0,0 +1,120 @@
+package eventparser
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func extractFailedFixture(t *testing.T, index int) []*pb.FailedTradeEvent {
+	t.Helper()
+	txs := loadFixtureTxs(t, "failed_swap.json")
+	require.NotNil(t, txs[index].Err)
+
+	var out []*pb.FailedTradeEvent
+	for _, e := range ExtractFailedTradesFromTx(txs[index]) {
+		failed := e.Event.GetFailedTrade()
+		require.NotNil(t, failed)
+		assert.Equal(t, uint32(pb.EventType_FAILED_TRADE), e.EventType)
+		assert.Equal(t, failed.PairAddress, e.Key)
+		out = append(out, failed)
+	}
+	return out
+}
+
+func TestFailedTrade_RaydiumV4ShiftedAccounts(t *testing.T) {
+	tx := loadFixtureTxs(t, "failed_swap.json")[0]
+	events := extractFailedFixture(t, 0)
+	require.Len(t, events, 1, "ComputeBudget 指令不产生事件")
+	failed := events[0]
+
+	assert.Equal(t, eventID(tx, 1, 0), failed.EventId)
+	assert.Equal(t, uint32(consts.DexRaydiumV4), failed.Dex)
+	assert.Equal(t, testfixture.Bytes("failed:ray_pool"), failed.PairAddress)
+	// 18 个账户时用户账户顺延一位
+	assert.Equal(t, testfixture.Bytes("failed:wallet"), failed.UserWallet)
+	assert.Equal(t, consts.WSOLMint[:], failed.InputToken, "mint 由用户 token 账户余额推断")
+	assert.Equal(t, testfixture.Bytes("failed:mint"), failed.OutputToken)
+	assert.Equal(t, pb.EventType_TRADE_BUY, failed.Side)
+	assert.Equal(t, uint64(1_000_000_000), failed.AmountIn)
+	assert.Equal(t, uint64(5_000_000), failed.AmountOut)
+	assert.False(t, failed.ExactOut)
+	assert.Equal(t, uint64(5000), failed.Fee)
+
+	assert.Equal(t, "InstructionError", failed.ErrorCode)
+	assert.Equal(t, int32(1), failed.ErrorIxIndex)
+	assert.Equal(t, "Custom", failed.ErrorIxCode)
+	assert.Equal(t, uint32(30), failed.CustomErrorCode)
+}
+
+func TestFailedTrade_InnerSwap(t *testing.T) {
+	tx := loadFixtureTxs(t, "failed_swap.json")[1]
+	events := extractFailedFixture(t, 1)
+	require.Len(t, events, 1)
+	failed := events[0]
+
+	// 报错的是 Jupiter 主指令，事件位置为其中的 Raydium inner 指令
+	assert.Equal(t, eventID(tx, 0, 1), failed.EventId)
+	assert.Equal(t, int32(0), failed.ErrorIxIndex)
+	assert.Equal(t, uint32(6001), failed.CustomErrorCode)
+
+	// 17 个账户不顺延；SwapBaseOut 为指定输出数量
+	assert.Equal(t, testfixture.Bytes("failed:wallet"), failed.UserWallet)
+	assert.Equal(t, testfixture.Bytes("failed:mint"), failed.InputToken)
+	assert.Equal(t, consts.USDCMint[:], failed.OutputToken)
+	assert.Equal(t, pb.EventType_TRADE_SELL, failed.Side)
+	assert.True(t, failed.ExactOut)
+	assert.Equal(t, uint64(8_000_000), failed.AmountIn)
+	assert.Equal(t, uint64(2_500_000), failed.AmountOut)
+}
+
+func TestFailedTrade_WhirlpoolBToAExactOut(t *testing.T) {
+	events := extractFailedFixture(t, 2)
+	require.Len(t, events, 1)
+	failed := events[0]
+
+	assert.Equal(t, uint32(consts.DexOrcaWhirlpool), failed.Dex)
+	assert.Equal(t, testfixture.Bytes("failed:whirlpool"), failed.PairAddress)
+	// a_to_b = 0：用户支付 b（WSOL）获得 a
+	assert.Equal(t, consts.WSOLMint[:], failed.InputToken)
+	assert.Equal(t, testfixture.Bytes("failed:mint"), failed.OutputToken)
+	assert.Equal(t, pb.EventType_TRADE_BUY, failed.Side)
+	// amount_specified_is_input = 0：amount 为输出数量，threshold 为最大输入
+	assert.True(t, failed.ExactOut)
+	assert.Equal(t, uint64(900_000_000), failed.AmountIn)
+	assert.Equal(t, uint64(4_000_000), failed.AmountOut)
+}
+
+func TestFailedTrade_PumpFun(t *testing.T) {
+	buy := extractFailedFixture(t, 3)
+	require.Len(t, buy, 1)
+	assert.Equal(t, uint32(consts.DexPumpfun), buy[0].Dex)
+	assert.Equal(t, testfixture.Bytes("failed:bonding_curve"), buy[0].PairAddress)
+	assert.Equal(t, consts.SOLMint[:], buy[0].InputToken, "内盘以原生 SOL 支付")
+	assert.Equal(t, testfixture.Bytes("failed:mint"), buy[0].OutputToken)
+	assert.Equal(t, pb.EventType_TRADE_BUY, buy[0].Side)
+	assert.True(t, buy[0].ExactOut)
+	assert.Equal(t, uint64(1_100_000_000), buy[0].AmountIn)
+	assert.Equal(t, uint64(30_000_000), buy[0].AmountOut)
+
+	sell := extractFailedFixture(t, 4)
+	require.Len(t, sell, 1)
+	assert.Equal(t, testfixture.Bytes("failed:mint"), sell[0].InputToken)
+	assert.Equal(t, consts.SOLMint[:], sell[0].OutputToken)
+	assert.Equal(t, pb.EventType_TRADE_SELL, sell[0].Side)
+	assert.False(t, sell[0].ExactOut)
+	assert.Equal(t, uint64(30_000_000), sell[0].AmountIn)
+	assert.Equal(t, uint64(900_000_000), sell[0].AmountOut)
+	// 内置错误没有自定义错误码
+	assert.Equal(t, "ProgramFailedToComplete", sell[0].ErrorIxCode)
+	assert.Zero(t, sell[0].CustomErrorCode)
+}
+
+func TestFailedTrade_NotSwap(t *testing.T) {
+	// Deposit、账户数不足的 swap、数据被截断的 swap 均无法还原交易意图
+	assert.Empty(t, extractFailedFixture(t, 5))
+}
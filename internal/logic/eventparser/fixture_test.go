@@ -0,0 +1,74 @@
+package eventparser
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/txadapter"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/pb"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/*.json 为 getBlock（encoding=json）格式的区块，每个文件覆盖一个协议的若干场景，
+// 经 txadapter.AdaptRpcTx 转换后走与线上相同的解析流程。构造的账户地址见 testfixture.Key。
+
+func TestMain(m *testing.M) {
+	Init()
+	os.Exit(m.Run())
+}
+
+// loadFixtureTxs 读取 testdata 下的区块并转换为 AdaptedTx
+func loadFixtureTxs(t *testing.T, name string) []*core.AdaptedTx {
+	t.Helper()
+	block := testfixture.LoadBlock(t, filepath.Join("testdata", name))
+	txCtx, err := txadapter.NewRpcTxContext(block.ParentSlot+1, block)
+	require.NoError(t, err)
+
+	txs := make([]*core.AdaptedTx, len(block.Transactions))
+	for i := range block.Transactions {
+		txs[i], err = txadapter.AdaptRpcTx(txCtx, map[string]types.Pubkey{}, &block.Transactions[i], uint64(i))
+		require.NoError(t, err, "tx %d", i)
+	}
+	return txs
+}
+
+// extractFixture 解析区块中的第 index 笔交易
+func extractFixture(t *testing.T, name string, index int) (*core.AdaptedTx, []*core.Event) {
+	t.Helper()
+	txs := loadFixtureTxs(t, name)
+	require.Less(t, index, len(txs))
+	events, _ := ExtractEventsFromTx(txs[index])
+	return txs[index], events
+}
+
+// eventsOfType 按事件类型筛选
+func eventsOfType(events []*core.Event, types ...pb.EventType) []*core.Event {
+	var out []*core.Event
+	for _, e := range events {
+		for _, typ := range types {
+			if e.EventType == uint32(typ) {
+				out = append(out, e)
+				break
+			}
+		}
+	}
+	return out
+}
+
+func trades(events []*core.Event) []*pb.TradeEvent {
+	var out []*pb.TradeEvent
+	for _, e := range events {
+		if trade := e.Event.GetTrade(); trade != nil {
+			out = append(out, trade)
+		}
+	}
+	return out
+}
+
+func eventID(tx *core.AdaptedTx, ixIndex, innerIndex uint16) uint64 {
+	return core.BuildEventID(tx.TxCtx.Slot, tx.TxIndex, ixIndex, innerIndex)
+}
@@ -0,0 +1,81 @@
+package jupiter
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+)
+
+// Jupiter v6 指令方法ID（anchor discriminator）
+const (
+	Route                              uint64 = 0xe517cb977ae3ad2a
+	RouteWithTokenLedger               uint64 = 0x96564774a75d0e68
+	ExactOutRoute                      uint64 = 0xd033ef977b2bed5c
+	SharedAccountsRoute                uint64 = 0xc1209b3341d69c81
+	SharedAccountsRouteWithTokenLedger uint64 = 0xe6798f50779f6aaa
+	SharedAccountsExactOutRoute        uint64 = 0xb0d169a89a7d453e
+
+	// v2 路由：账户中带 mint 与 token program，全部 hop 完成后统一发出 SwapsEvent
+	RouteV2                       uint64 = 0xbb64facc31c4af14
+	ExactOutRouteV2               uint64 = 0x9d8ab85215f4f324
+	SharedAccountsRouteV2         uint64 = 0xd19853937cfed8e9
+	SharedAccountsExactOutRouteV2 uint64 = 0x3560e5cad8bbfa18
+)
+
+// RegisterHandlers 注册 Jupiter 聚合器的指令解析器
+func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
+	m[consts.JupiterV6Program] = handleInstruction
+}
+
+func handleInstruction(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	// 指令 data 至少应包含 8 字节方法 ID
+	if len(ix.Data) < 8 {
+		return -1
+	}
+
+	switch binary.BigEndian.Uint64(ix.Data[:8]) {
+	case Route, RouteWithTokenLedger, ExactOutRoute:
+		return extractRouteEvent(ctx, instrs, current, &routeAccountIndex{
+			UserWallet:   1,
+			UserSource:   2,
+			UserDest:     3,
+			OptionalDest: 4,
+		})
+
+	case SharedAccountsRoute, SharedAccountsRouteWithTokenLedger, SharedAccountsExactOutRoute:
+		return extractRouteEvent(ctx, instrs, current, &routeAccountIndex{
+			UserWallet:   2,
+			UserSource:   3,
+			UserDest:     6,
+			OptionalDest: -1,
+		})
+
+	case RouteV2, ExactOutRouteV2:
+		return extractRouteEvent(ctx, instrs, current, &routeAccountIndex{
+			UserWallet:   0,
+			UserSource:   1,
+			UserDest:     2,
+			OptionalDest: 7,
+		})
+
+	case SharedAccountsRouteV2, SharedAccountsExactOutRouteV2:
+		return extractRouteEvent(ctx, instrs, current, &routeAccountIndex{
+			UserWallet:   1,
+			UserSource:   2,
+			UserDest:     5,
+			OptionalDest: -1,
+		})
+
+	default:
+		// 其它指令（如 SwapEvent 日志、token ledger 设置）不产生事件
+		return -1
+	}
+}
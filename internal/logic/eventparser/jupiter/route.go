@@ -0,0 +1,235 @@
+package jupiter
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/pb"
+	"encoding/binary"
+)
+
+const (
+	Event      uint64 = 0xe445a52e51cb9a1d // anchor emit_cpi 事件指令前缀
+	SwapEvent  uint64 = 0x40c6cde8260871e2 // SwapEvent 事件 discriminator（v1 路由，每个 hop 一条）
+	SwapsEvent uint64 = 0x982f4eebc0606e6a // SwapsEvent 事件 discriminator（v2 路由，全部 hop 一条）
+
+	swapEventSize     = 16 + 32 + 32 + 8 + 32 + 8
+	swapEventV2Size   = 32 + 8 + 32 + 8 // SwapEventV2：input_mint、input_amount、output_mint、output_amount
+	swapsEventMinSize = 16 + 4          // 前缀 + discriminator + vec 长度
+)
+
+// routeAccountIndex 表示路由指令中用户相关账户的索引
+type routeAccountIndex struct {
+	UserWallet   int // user_transfer_authority
+	UserSource   int // 用户支付的 token 账户
+	UserDest     int // 用户接收的 token 账户
+	OptionalDest int // 可选的 destination_token_account（未指定时为 Jupiter 程序地址），-1 表示无此账户
+}
+
+// parsedSwapEvent 对应 Jupiter 每个 hop 通过 self-CPI 发出的 SwapEvent：
+//
+//	[0:8] Event 前缀  [8:16] SwapEvent discriminator
+//	[16:48] amm  [48:80] input_mint  [80:88] input_amount  [88:120] output_mint  [120:128] output_amount
+//
+// v2 路由改为在全部 hop 完成后发出一条 SwapsEvent，其中每个 SwapEventV2 不含 amm：
+//
+//	[0:8] Event 前缀  [8:16] SwapsEvent discriminator  [16:20] hop 数量（u32）
+//	每个 hop 80 字节：input_mint(32) + input_amount(8) + output_mint(32) + output_amount(8)
+type parsedSwapEvent struct {
+	Index        int // 日志指令在 instrs 中的下标（SwapsEvent 中的各 hop 相同）
+	Amm          types.Pubkey
+	InputMint    types.Pubkey
+	InputAmount  uint64
+	OutputMint   types.Pubkey
+	OutputAmount uint64
+}
+
+// extractRouteEvent 解析 Jupiter v6 路由指令，生成 ROUTE_SWAP 事件。
+// 路由内各 DEX 的 swap 为本指令的子指令，返回 current + 1 使其继续由对应的 handler 解析，
+// 解析结束后由 common.LinkRouteSwaps 将其关联到路由事件。
+//
+// Route / RouteWithTokenLedger / ExactOutRoute 账户结构：
+//
+//	0 - Token Program
+//	1 - User Transfer Authority（用户钱包）
+//	2 - User Source Token Account
+//	3 - User Destination Token Account
+//	4 - Destination Token Account（可选，未指定时为 Jupiter 程序地址）
+//	...
+//
+// SharedAccountsRoute / SharedAccountsRouteWithTokenLedger / SharedAccountsExactOutRoute 账户结构：
+//
+//	0 - Token Program
+//	1 - Program Authority
+//	2 - User Transfer Authority（用户钱包）
+//	3 - Source Token Account
+//	4 - Program Source Token Account
+//	5 - Program Destination Token Account
+//	6 - Destination Token Account
+//	7 - Source Mint
+//	8 - Destination Mint
+//	...
+//
+// RouteV2 / ExactOutRouteV2 账户结构：
+//
+//	0 - User Transfer Authority（用户钱包）
+//	1 - User Source Token Account
+//	2 - User Destination Token Account
+//	3 - Source Mint
+//	4 - Destination Mint
+//	5 - Source Token Program
+//	6 - Destination Token Program
+//	7 - Destination Token Account（可选）
+//	...
+//
+// SharedAccountsRouteV2 / SharedAccountsExactOutRouteV2 账户结构：
+//
+//	0 - Program Authority
+//	1 - User Transfer Authority（用户钱包）
+//	2 - Source Token Account
+//	3 - Program Source Token Account
+//	4 - Program Destination Token Account
+//	5 - Destination Token Account
+//	...
+func extractRouteEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+	indexes *routeAccountIndex,
+) int {
+	ix := instrs[current]
+
+	if len(ix.Accounts) <= max(indexes.UserDest, indexes.OptionalDest) {
+		logger.Errorf("[Jupiter:Route] 账户数量不足: tx=%s, got=%d", ctx.TxHashString(), len(ix.Accounts))
+		return -1
+	}
+
+	swaps, aggregated := findSwapEvents(instrs, current)
+	if len(swaps) == 0 {
+		logger.Errorf("[Jupiter:Route] 未找到 SwapEvent 日志指令: tx=%s, ix=%d, inner=%d",
+			ctx.TxHashString(), ix.IxIndex, ix.InnerIndex)
+		return -1
+	}
+
+	userDest := ix.Accounts[indexes.UserDest]
+	if indexes.OptionalDest >= 0 && ix.Accounts[indexes.OptionalDest] != consts.JupiterV6Program {
+		userDest = ix.Accounts[indexes.OptionalDest]
+	}
+
+	// 首个 hop 的输入为用户支付的 token，最后一个 hop 的输出为用户获得的 token；
+	// 拆单路由中同一 token 可能由多个 hop 并行兑换，数量取全部同 token hop 之和
+	inputMint := swaps[0].InputMint
+	outputMint := swaps[len(swaps)-1].OutputMint
+	var inputAmount, outputAmount uint64
+	hops := make([]*pb.RouteHop, 0, len(swaps))
+	var hopEnds []int
+	for _, swap := range swaps {
+		if swap.InputMint == inputMint {
+			inputAmount += swap.InputAmount
+		}
+		if swap.OutputMint == outputMint {
+			outputAmount += swap.OutputAmount
+		}
+		hop := &pb.RouteHop{
+			InputMint:    swap.InputMint[:],
+			InputAmount:  swap.InputAmount,
+			OutputMint:   swap.OutputMint[:],
+			OutputAmount: swap.OutputAmount,
+		}
+		if !aggregated {
+			hop.Amm = swap.Amm[:]
+			hopEnds = append(hopEnds, swap.Index)
+		}
+		hops = append(hops, hop)
+	}
+
+	inputDecimals, _ := ctx.Tx.GetDecimalsByMint(inputMint)
+	outputDecimals, _ := ctx.Tx.GetDecimalsByMint(outputMint)
+	userWallet := ix.Accounts[indexes.UserWallet]
+	userSource := ix.Accounts[indexes.UserSource]
+
+	event := &pb.RouteSwapEvent{
+		Type:              pb.EventType_ROUTE_SWAP,
+		EventId:           core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
+		Slot:              ctx.Slot,
+		BlockTime:         ctx.BlockTime,
+		TxHash:            ctx.TxHash,
+		Signers:           ctx.Signers,
+		Program:           ix.ProgramID[:],
+		UserWallet:        userWallet[:],
+		UserSourceAccount: userSource[:],
+		UserDestAccount:   userDest[:],
+		InputMint:         inputMint[:],
+		OutputMint:        outputMint[:],
+		InputAmount:       inputAmount,
+		OutputAmount:      outputAmount,
+		InputDecimals:     uint32(inputDecimals),
+		OutputDecimals:    uint32(outputDecimals),
+		Hops:              hops,
+	}
+
+	ctx.AddRouteSwap(&common.RouteSwap{
+		Event:   event,
+		Start:   current,
+		End:     common.SubtreeEnd(instrs, current),
+		HopEnds: hopEnds,
+	}, &core.Event{
+		ID:        event.EventId,
+		EventType: uint32(event.Type),
+		Key:       event.UserWallet,
+		Event: &pb.Event{
+			Event: &pb.Event_RouteSwap{RouteSwap: event},
+		},
+	})
+	return current + 1
+}
+
+// findSwapEvents 按执行顺序查找路由指令发出的 SwapEvent / SwapsEvent（self-CPI，为路由指令的直接子指令），
+// aggregated 表示 hop 来自 v2 路由的 SwapsEvent（日志位于全部 hop 之后，无法按位置划分 hop 范围）
+func findSwapEvents(instrs []*core.AdaptedInstruction, current int) (swaps []parsedSwapEvent, aggregated bool) {
+	for i, ix := range common.Children(instrs, current) {
+		if ix.ProgramID != consts.JupiterV6Program || len(ix.Data) < swapsEventMinSize {
+			continue
+		}
+		if binary.BigEndian.Uint64(ix.Data[0:8]) != Event {
+			continue
+		}
+		switch binary.BigEndian.Uint64(ix.Data[8:16]) {
+		case SwapEvent:
+			if len(ix.Data) < swapEventSize {
+				continue
+			}
+			swap := parsedSwapEvent{
+				Index:        i,
+				InputAmount:  binary.LittleEndian.Uint64(ix.Data[80:88]),
+				OutputAmount: binary.LittleEndian.Uint64(ix.Data[120:128]),
+			}
+			copy(swap.Amm[:], ix.Data[16:48])
+			copy(swap.InputMint[:], ix.Data[48:80])
+			copy(swap.OutputMint[:], ix.Data[88:120])
+			swaps = append(swaps, swap)
+
+		case SwapsEvent:
+			count := int(binary.LittleEndian.Uint32(ix.Data[16:20]))
+			data := ix.Data[swapsEventMinSize:]
+			if count > len(data)/swapEventV2Size {
+				continue
+			}
+			for j := 0; j < count; j++ {
+				item := data[j*swapEventV2Size : (j+1)*swapEventV2Size]
+				swap := parsedSwapEvent{
+					Index:        i,
+					InputAmount:  binary.LittleEndian.Uint64(item[32:40]),
+					OutputAmount: binary.LittleEndian.Uint64(item[72:80]),
+				}
+				copy(swap.InputMint[:], item[0:32])
+				copy(swap.OutputMint[:], item[40:72])
+				swaps = append(swaps, swap)
+			}
+			aggregated = true
+		}
+	}
+	return swaps, aggregated
+}
@@ -0,0 +1,155 @@
+package eventparser
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/jupiter_route.json：Jupiter v6 路由，每跳为 Raydium CPMM swap
+//
+//	tx0：route（v1）USDC → WSOL → X，每个 hop 之后发出 SwapEvent
+//	tx1：route_v2 USDC → WSOL → X，全部 hop 之后发出一条 SwapsEvent
+//	tx2：拆单路由 USDC → X 经两个池子并行兑换，指定 destination_token_account
+//	tx3：第二个 hop 的 AMM 无法解析
+//	tx4：route_v2 的 SwapsEvent 被截断
+
+func routeSwaps(events []*core.Event) []*pb.RouteSwapEvent {
+	var out []*pb.RouteSwapEvent
+	for _, e := range eventsOfType(events, pb.EventType_ROUTE_SWAP) {
+		out = append(out, e.Event.GetRouteSwap())
+	}
+	return out
+}
+
+func TestJupiterRoute(t *testing.T) {
+	tx, events := extractFixture(t, "jupiter_route.json", 0)
+
+	routes := routeSwaps(events)
+	require.Len(t, routes, 1)
+	route := routes[0]
+	assert.Equal(t, eventID(tx, 0, 0), route.EventId)
+	assert.Equal(t, consts.JupiterV6Program[:], route.Program)
+	assert.Equal(t, testfixture.Bytes("jup:wallet"), route.UserWallet)
+	assert.Equal(t, testfixture.Bytes("jup:user_usdc"), route.UserSourceAccount)
+	assert.Equal(t, testfixture.Bytes("jup:user_x"), route.UserDestAccount, "未指定 destination 时为用户的 destination token account")
+	assert.Equal(t, consts.USDCMint[:], route.InputMint)
+	assert.Equal(t, testfixture.Bytes("jup:mint_x"), route.OutputMint)
+	assert.Equal(t, uint64(1_000_000), route.InputAmount)
+	assert.Equal(t, uint64(42_000), route.OutputAmount, "不计中间 hop 的 WSOL")
+	assert.Equal(t, uint32(6), route.InputDecimals)
+	assert.Equal(t, uint32(6), route.OutputDecimals)
+	require.NotNil(t, route.TxFee)
+
+	ts := trades(events)
+	require.Len(t, ts, 2)
+	assert.Equal(t, testfixture.Bytes("jup:pool_a"), ts[0].PairAddress)
+	assert.Equal(t, testfixture.Bytes("jup:pool_b"), ts[1].PairAddress)
+	for _, trade := range ts {
+		assert.Equal(t, route.EventId, trade.ParentEventId)
+		assert.Equal(t, uint32(consts.DexRaydiumCPMM), trade.Dex)
+	}
+	for _, e := range eventsOfType(events, pb.EventType_TRANSFER) {
+		assert.Equal(t, route.EventId, e.Event.GetTransfer().ParentEventId)
+	}
+
+	// 每个 hop 关联其范围内的 TradeEvent（CPMM swap 分别位于 inner 1 与 inner 5）
+	require.Len(t, route.Hops, 2)
+	assert.Equal(t, consts.RaydiumCPMMProgram[:], route.Hops[0].Amm)
+	assert.Equal(t, eventID(tx, 0, 1), route.Hops[0].TradeEventId)
+	assert.Equal(t, ts[0].EventId, route.Hops[0].TradeEventId)
+	assert.Equal(t, consts.WSOLMint[:], route.Hops[0].OutputMint)
+	assert.Equal(t, uint64(5_000_000), route.Hops[0].OutputAmount)
+	assert.Equal(t, eventID(tx, 0, 5), route.Hops[1].TradeEventId)
+	assert.Equal(t, ts[1].EventId, route.Hops[1].TradeEventId)
+}
+
+func TestJupiterRouteV2(t *testing.T) {
+	tx, events := extractFixture(t, "jupiter_route.json", 1)
+
+	routes := routeSwaps(events)
+	require.Len(t, routes, 1)
+	route := routes[0]
+	assert.Equal(t, testfixture.Bytes("jup:wallet"), route.UserWallet)
+	assert.Equal(t, testfixture.Bytes("jup:user_usdc"), route.UserSourceAccount)
+	assert.Equal(t, testfixture.Bytes("jup:user_x"), route.UserDestAccount)
+	assert.Equal(t, consts.USDCMint[:], route.InputMint)
+	assert.Equal(t, testfixture.Bytes("jup:mint_x"), route.OutputMint)
+	assert.Equal(t, uint64(1_000_000), route.InputAmount)
+	assert.Equal(t, uint64(42_000), route.OutputAmount)
+
+	// SwapsEvent 不含 amm，hop 按执行顺序关联 TradeEvent
+	ts := trades(events)
+	require.Len(t, ts, 2)
+	require.Len(t, route.Hops, 2)
+	for i, hop := range route.Hops {
+		assert.Empty(t, hop.Amm)
+		assert.Equal(t, ts[i].EventId, hop.TradeEventId)
+		assert.Equal(t, route.EventId, ts[i].ParentEventId)
+	}
+	assert.Equal(t, eventID(tx, 0, 1), route.Hops[0].TradeEventId)
+	assert.Equal(t, eventID(tx, 0, 4), route.Hops[1].TradeEventId)
+}
+
+func TestJupiterRoute_Split(t *testing.T) {
+	tx, events := extractFixture(t, "jupiter_route.json", 2)
+
+	routes := routeSwaps(events)
+	require.Len(t, routes, 1)
+	route := routes[0]
+	assert.Equal(t, testfixture.Bytes("jup:dest_x"), route.UserDestAccount, "指定 destination_token_account 时使用该账户")
+	// 同一 token 的并行 hop 数量相加
+	assert.Equal(t, uint64(600_000+400_000), route.InputAmount)
+	assert.Equal(t, uint64(25_000+17_000), route.OutputAmount)
+
+	ts := trades(events)
+	require.Len(t, ts, 2)
+	require.Len(t, route.Hops, 2)
+	assert.Equal(t, testfixture.Bytes("jup:pool_c"), ts[0].PairAddress)
+	assert.Equal(t, eventID(tx, 0, 1), route.Hops[0].TradeEventId)
+	assert.Equal(t, testfixture.Bytes("jup:pool_d"), ts[1].PairAddress)
+	assert.Equal(t, eventID(tx, 0, 5), route.Hops[1].TradeEventId)
+}
+
+func TestJupiterRoute_UnknownAmm(t *testing.T) {
+	tx, events := extractFixture(t, "jupiter_route.json", 3)
+
+	routes := routeSwaps(events)
+	require.Len(t, routes, 1)
+	route := routes[0]
+	require.Len(t, route.Hops, 2)
+	assert.Equal(t, eventID(tx, 0, 1), route.Hops[0].TradeEventId)
+	assert.Equal(t, testfixture.Bytes("jup:unknown_amm"), route.Hops[1].Amm)
+	assert.Zero(t, route.Hops[1].TradeEventId, "hop 范围内没有 TradeEvent 时不借用其它 hop 的交易")
+
+	ts := trades(events)
+	require.Len(t, ts, 1)
+	assert.Equal(t, route.EventId, ts[0].ParentEventId)
+
+	// 无法解析的 hop 仍记录其 token 转账，并关联到路由
+	var hopTransfers int
+	for _, e := range eventsOfType(events, pb.EventType_TRANSFER) {
+		transfer := e.Event.GetTransfer()
+		assert.Equal(t, route.EventId, transfer.ParentEventId)
+		if transfer.EventId > eventID(tx, 0, 4) {
+			hopTransfers++
+		}
+	}
+	assert.Equal(t, 2, hopTransfers)
+}
+
+func TestJupiterRouteV2_TruncatedSwapsEvent(t *testing.T) {
+	_, events := extractFixture(t, "jupiter_route.json", 4)
+
+	assert.Empty(t, routeSwaps(events), "SwapsEvent 数据不足时不生成路由事件")
+	ts := trades(events)
+	require.Len(t, ts, 2, "各 hop 的 swap 仍照常解析")
+	for _, trade := range ts {
+		assert.Zero(t, trade.ParentEventId)
+	}
+}
@@ -0,0 +1,124 @@
+package meteoradamm
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+)
+
+// Meteora DAMM v1 - InitializePermissionlessPool / InitializePermissionlessPoolWithFeeTier /
+// InitializeCustomizablePermissionlessConstantProductPool 指令账户布局：
+//
+// #0  - Pool                                  // 池子主账户
+// #1  - LP Mint                               // 池子 LP Token 的 Mint
+// #2  - Token A Mint
+// #3  - Token B Mint
+// #4  - A Vault                               // Token A 的 vault 账户
+// #5  - B Vault                               // Token B 的 vault 账户
+// #6  - A Token Vault                         // vault 中 Token A 的 TokenAccount
+// #7  - B Token Vault                         // vault 中 Token B 的 TokenAccount
+// #8  - A Vault LP Mint
+// #9  - B Vault LP Mint
+// #10 - A Vault LP
+// #11 - B Vault LP
+// #12 - Payer Token A                         // 创建者提供的 Token A 账户
+// #13 - Payer Token B                         // 创建者提供的 Token B 账户
+// #14 - Payer Pool LP                         // 创建者接收 LP Token 的账户
+// #15 - Protocol Token A Fee
+// #16 - Protocol Token B Fee
+// #17 - Payer                                 // 池子创建者（Signer + Fee Payer）
+// ...
+//
+// InitializePermissionlessConstantProductPoolWithConfig / WithConfig2 在 #1 插入 Config 账户，其后账户顺延一位（offset = 1）。
+func extractV1InitializePoolEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+	offset int,
+) int {
+	ix := instrs[current]
+
+	// 实际使用到了 index 0~17（+offset）
+	requiredAccounts := 18 + offset
+	if len(ix.Accounts) < requiredAccounts {
+		logger.Errorf("[MeteoraDAMM:V1InitializePool] 指令账户长度不足: got=%d, expect>=%d, tx=%s",
+			len(ix.Accounts), requiredAccounts, ctx.TxHashString())
+		return -1
+	}
+
+	createPoolEvent := common.ExtractCreatePoolEvent(ctx, ix, consts.DexMeteoraDAMM, "V1InitializePool", &common.CreatePoolLayout{
+		PoolAddressIndex:   0,
+		TokenMint1Index:    2 + offset,
+		TokenMint2Index:    3 + offset,
+		TokenProgram1Index: -1, // v1 仅支持 SPL Token
+		TokenProgram2Index: -1,
+		PoolVault1Index:    6 + offset,
+		PoolVault2Index:    7 + offset,
+		UserWalletIndex:    17 + offset,
+	})
+	if createPoolEvent == nil {
+		return -1
+	}
+
+	ctx.AddEvent(createPoolEvent)
+	return current + 1
+}
+
+// Meteora DAMM v2 - InitializePool 指令账户布局：
+//
+// #0  - Creator                               // 池子创建者
+// #1  - Position NFT Mint
+// #2  - Position NFT Account
+// #3  - Payer                                 // 支付账户（Signer + Fee Payer）
+// #4  - Config                                // 池子配置账户
+// #5  - Pool Authority                        // 池子 Authority PDA
+// #6  - Pool                                  // 池子主账户
+// #7  - Position                              // 创建者的初始流动性头寸
+// #8  - Token A Mint
+// #9  - Token B Mint
+// #10 - Token A Vault                         // 池子 Token A 储备账户
+// #11 - Token B Vault                         // 池子 Token B 储备账户
+// #12 - Payer Token A
+// #13 - Payer Token B
+// #14 - Token A Program
+// #15 - Token B Program
+// ...
+//
+// InitializeCustomizablePool 无 Config 账户（Pool 位于 #5）；
+// InitializePoolWithDynamicConfig 在 Config 前多一个 Pool Creator Authority（Pool 位于 #7）。
+// 三者自 Pool 起的账户顺序一致，poolIndex 为 Pool 账户的位置。
+func extractV2InitializePoolEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+	instructionName string,
+	poolIndex int,
+) int {
+	ix := instrs[current]
+
+	// 实际使用到了 Pool 之后的 9 个账户
+	requiredAccounts := poolIndex + 10
+	if len(ix.Accounts) < requiredAccounts {
+		logger.Errorf("[MeteoraDAMM:%s] 指令账户长度不足: got=%d, expect>=%d, tx=%s",
+			instructionName, len(ix.Accounts), requiredAccounts, ctx.TxHashString())
+		return -1
+	}
+
+	createPoolEvent := common.ExtractCreatePoolEvent(ctx, ix, consts.DexMeteoraDAMM, instructionName, &common.CreatePoolLayout{
+		PoolAddressIndex:   poolIndex,
+		TokenMint1Index:    poolIndex + 2,
+		TokenMint2Index:    poolIndex + 3,
+		TokenProgram1Index: poolIndex + 8,
+		TokenProgram2Index: poolIndex + 9,
+		PoolVault1Index:    poolIndex + 4,
+		PoolVault2Index:    poolIndex + 5,
+		UserWalletIndex:    0,
+	})
+	if createPoolEvent == nil {
+		return -1
+	}
+
+	ctx.AddEvent(createPoolEvent)
+	return current + 1
+}
@@ -0,0 +1,202 @@
+package meteoradamm
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+)
+
+// Meteora DAMM v1 AddBalanceLiquidity / AddImbalanceLiquidity / RemoveBalanceLiquidity 指令账户布局：
+//
+// #0  - Pool                        // 池子主账户
+// #1  - LP Mint                     // 池子 LP Token 的 Mint
+// #2  - User Pool LP                // 用户的 LP Token 账户
+// #3  - A Vault LP                  // 池子持有的 A vault LP
+// #4  - B Vault LP                  // 池子持有的 B vault LP
+// #5  - A Vault                     // Token A 的 vault 账户
+// #6  - B Vault                     // Token B 的 vault 账户
+// #7  - A Vault LP Mint
+// #8  - B Vault LP Mint
+// #9  - A Token Vault               // vault 中 Token A 的 TokenAccount
+// #10 - B Token Vault               // vault 中 Token B 的 TokenAccount
+// #11 - User A Token                // 用户的 Token A 账户
+// #12 - User B Token                // 用户的 Token B 账户
+// #13 - User                        // 用户钱包（Signer）
+// #14 - Vault Program
+// #15 - Token Program
+//
+// 用户与 vault 之间的转账由 vault 程序完成（间接子指令），LP 的铸造 / 销毁由池子程序直接完成。
+var v1LiquidityLayout = common.LiquidityLayout{
+	PoolAddressIndex:       0,
+	TokenMint1Index:        -1, // 无 Mint 信息
+	TokenMint2Index:        -1,
+	UserWalletIndex:        13,
+	UserToken1AccountIndex: 11,
+	UserToken2AccountIndex: 12,
+	UserLpAccountIndex:     2,
+	PoolToken1AccountIndex: 9,
+	PoolToken2AccountIndex: 10,
+	LpMintIndex:            1,
+	IncludeNested:          true,
+}
+
+// extractV1AddLiquidityEvent 解析 DAMM v1 添加流动性（balanced 为双边等比例添加，否则允许单边）
+func extractV1AddLiquidityEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+	instructionName string,
+	balanced bool,
+) int {
+	ix := instrs[current]
+
+	const requiredAccounts = 14
+	if len(ix.Accounts) < requiredAccounts {
+		logger.Errorf("[MeteoraDAMM:%s] 账户数不足: got=%d, expect>=%d, tx=%s",
+			instructionName, len(ix.Accounts), requiredAccounts, ctx.TxHashString())
+		return -1
+	}
+
+	layout := v1LiquidityLayout
+	layout.RequireBothTransfer = balanced
+	liquidityEvent, mintEvent, maxIndex := common.ExtractAddLiquidityEvent(ctx, instrs, current, consts.DexMeteoraDAMM, instructionName, &layout)
+	if liquidityEvent == nil || mintEvent == nil {
+		return -1
+	}
+
+	ctx.AddEvent(liquidityEvent)
+	ctx.AddEvent(mintEvent)
+	return maxIndex + 1
+}
+
+// extractV1RemoveLiquidityEvent 解析 DAMM v1 RemoveBalanceLiquidity（按比例双边移除）
+func extractV1RemoveLiquidityEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	const requiredAccounts = 14
+	if len(ix.Accounts) < requiredAccounts {
+		logger.Errorf("[MeteoraDAMM:RemoveBalanceLiquidity] 账户数不足: got=%d, expect>=%d, tx=%s",
+			len(ix.Accounts), requiredAccounts, ctx.TxHashString())
+		return -1
+	}
+
+	layout := v1LiquidityLayout
+	layout.RequireBothTransfer = true
+	liquidityEvent, burnEvent, maxIndex := common.ExtractRemoveLiquidityEvent(ctx, instrs, current, consts.DexMeteoraDAMM, "RemoveBalanceLiquidity", &layout)
+	if liquidityEvent == nil || burnEvent == nil {
+		return -1
+	}
+
+	ctx.AddEvent(liquidityEvent)
+	ctx.AddEvent(burnEvent)
+	return maxIndex + 1
+}
+
+// Meteora DAMM v2 AddLiquidity 指令账户布局（流动性头寸以 NFT 表示，无 LP Token）：
+//
+// #0  - Pool                        // 池子主账户
+// #1  - Position                    // 用户流动性头寸账户
+// #2  - Token A Account             // 用户的 Token A 账户
+// #3  - Token B Account             // 用户的 Token B 账户
+// #4  - Token A Vault               // 池子 Token A 储备账户
+// #5  - Token B Vault               // 池子 Token B 储备账户
+// #6  - Token A Mint
+// #7  - Token B Mint
+// #8  - Position NFT Account        // 用户持有 Position NFT 的 TokenAccount
+// #9  - Owner                       // 用户钱包（Signer）
+// #10 - Token A Program
+// #11 - Token B Program
+// #12 - Event Authority
+// #13 - Program
+func extractV2AddLiquidityEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	const requiredAccounts = 10
+	if len(ix.Accounts) < requiredAccounts {
+		logger.Errorf("[MeteoraDAMM:AddLiquidity] 账户数不足: got=%d, expect>=%d, tx=%s",
+			len(ix.Accounts), requiredAccounts, ctx.TxHashString())
+		return -1
+	}
+
+	liquidityEvent, _, maxIndex := common.ExtractAddLiquidityEvent(ctx, instrs, current, consts.DexMeteoraDAMM, "AddLiquidity", &common.LiquidityLayout{
+		RequireBothTransfer:    false,
+		PoolAddressIndex:       0,
+		TokenMint1Index:        6,
+		TokenMint2Index:        7,
+		UserWalletIndex:        9,
+		UserToken1AccountIndex: 2,
+		UserToken2AccountIndex: 3,
+		UserLpAccountIndex:     -1, // 无 LP Token
+		PoolToken1AccountIndex: 4,
+		PoolToken2AccountIndex: 5,
+		LpMintIndex:            -1,
+	})
+	if liquidityEvent == nil {
+		return -1
+	}
+
+	ctx.AddEvent(liquidityEvent)
+	return maxIndex + 1
+}
+
+// Meteora DAMM v2 RemoveLiquidity / RemoveAllLiquidity 指令账户布局：
+//
+// #0  - Pool Authority              // 池子 Authority PDA
+// #1  - Pool                        // 池子主账户
+// #2  - Position                    // 用户流动性头寸账户
+// #3  - Token A Account             // 用户接收 Token A 的账户
+// #4  - Token B Account             // 用户接收 Token B 的账户
+// #5  - Token A Vault               // 池子 Token A 储备账户
+// #6  - Token B Vault               // 池子 Token B 储备账户
+// #7  - Token A Mint
+// #8  - Token B Mint
+// #9  - Position NFT Account        // 用户持有 Position NFT 的 TokenAccount
+// #10 - Owner                       // 用户钱包（Signer）
+// #11 - Token A Program
+// #12 - Token B Program
+// #13 - Event Authority
+// #14 - Program
+func extractV2RemoveLiquidityEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+	instructionName string,
+) int {
+	ix := instrs[current]
+
+	const requiredAccounts = 11
+	if len(ix.Accounts) < requiredAccounts {
+		logger.Errorf("[MeteoraDAMM:%s] 账户数不足: got=%d, expect>=%d, tx=%s",
+			instructionName, len(ix.Accounts), requiredAccounts, ctx.TxHashString())
+		return -1
+	}
+
+	liquidityEvent, _, maxIndex := common.ExtractRemoveLiquidityEvent(ctx, instrs, current, consts.DexMeteoraDAMM, instructionName, &common.LiquidityLayout{
+		RequireBothTransfer:    false,
+		PoolAddressIndex:       1,
+		TokenMint1Index:        7,
+		TokenMint2Index:        8,
+		UserWalletIndex:        10,
+		UserToken1AccountIndex: 3,
+		UserToken2AccountIndex: 4,
+		UserLpAccountIndex:     -1, // 无 LP Token
+		PoolToken1AccountIndex: 5,
+		PoolToken2AccountIndex: 6,
+		LpMintIndex:            -1,
+	})
+	if liquidityEvent == nil {
+		return -1
+	}
+
+	ctx.AddEvent(liquidityEvent)
+	return maxIndex + 1
+}
@@ -0,0 +1,168 @@
+package meteoradamm
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+)
+
+// Meteora Dynamic AMM（DAMM v1）指令方法ID
+const (
+	// Swap
+	V1Swap uint64 = 0xf8c69e91e17587c8
+
+	// Create Pool 系列
+	V1InitializePermissionlessPool                            uint64 = 0x76ad299dad486167
+	V1InitializePermissionlessPoolWithFeeTier                 uint64 = 0x06874493e552a971
+	V1InitializeCustomizablePermissionlessConstantProductPool uint64 = 0x9118acc2db7d03be
+	V1InitializePermissionlessConstantProductPoolWithConfig   uint64 = 0x07a68aabceabecf4
+	V1InitializePermissionlessConstantProductPoolWithConfig2  uint64 = 0x3095dc823d0b09b2
+
+	// 添加 / 移除流动性
+	V1AddBalanceLiquidity    uint64 = 0xa8e3323ebdab54b0
+	V1AddImbalanceLiquidity  uint64 = 0x4f237a54ad0f5dbf
+	V1RemoveBalanceLiquidity uint64 = 0x856d2cb338ee7221
+)
+
+// Meteora DAMM v2（cp-amm）指令方法ID
+const (
+	// Swap
+	V2Swap  uint64 = 0xf8c69e91e17587c8
+	V2Swap2 uint64 = 0x414b3f4ceb5b5b88
+
+	// Create Pool 系列
+	V2InitializePool                  uint64 = 0x5fb40aac54aee828
+	V2InitializeCustomizablePool      uint64 = 0x14a1f118bdddb402
+	V2InitializePoolWithDynamicConfig uint64 = 0x955248c5fdfc440f
+
+	// 添加 / 移除流动性
+	V2AddLiquidity       uint64 = 0xb59d59438fb63448
+	V2RemoveLiquidity    uint64 = 0x5055d14818ceb16c
+	V2RemoveAllLiquidity uint64 = 0x0a333d2370691855
+)
+
+// RegisterHandlers 注册 Meteora DAMM v1 / v2 Program 的指令解析器
+func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
+	m[consts.MeteoraDAMMV1Program] = handleV1Instruction
+	m[consts.MeteoraDAMMV2Program] = handleV2Instruction
+}
+
+// RegisterFailedHandlers 注册失败交易中 Swap 指令的意图解析器
+func RegisterFailedHandlers(m map[types.Pubkey]common.FailedTradeHandler) {
+	m[consts.MeteoraDAMMV1Program] = v1FailedSwapTable.Extract
+	m[consts.MeteoraDAMMV2Program] = v2FailedSwapTable.Extract
+}
+
+// v1FailedSwapTable 失败交易中 DAMM v1 Swap 指令的布局（账户见 extractV1SwapEvent）
+// 指令数据：[8:16] in_amount，[16:24] minimum_out_amount
+var v1FailedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexMeteoraDAMM,
+	DiscriminatorLen: 8,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		V1Swap: {
+			MinAccounts: 13,
+			Pair:        0, Wallet: 12, UserSource: 1, UserDest: 2, InputMint: common.NoAccount, OutputMint: common.NoAccount,
+			AmountIn: 8, AmountOut: 16,
+		},
+	},
+}
+
+// v2FailedSwapTable 失败交易中 DAMM v2 Swap / Swap2 指令的布局（账户见 extractV2SwapEvent）
+//   - Swap:  [8:16] amount_in，[16:24] minimum_amount_out
+//   - Swap2: [8:16] amount_0，[16:24] amount_1，[24] swap_mode；
+//     ExactIn / PartialFill 时 amount_0 为输入数量、amount_1 为最小输出，ExactOut 时 amount_0 为输出数量、amount_1 为最大输入
+var v2FailedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexMeteoraDAMM,
+	DiscriminatorLen: 8,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		V2Swap: {
+			MinAccounts: 9,
+			Pair:        1, Wallet: 8, UserSource: 2, UserDest: 3, InputMint: common.NoAccount, OutputMint: common.NoAccount,
+			AmountIn: 8, AmountOut: 16,
+		},
+		V2Swap2: {
+			MinAccounts: 9,
+			Pair:        1, Wallet: 8, UserSource: 2, UserDest: 3, InputMint: common.NoAccount, OutputMint: common.NoAccount,
+			AmountIn: 8, AmountOut: 16, SwapMode: 24,
+		},
+	},
+}
+
+func handleV1Instruction(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	// 指令 data 至少应包含 8 字节方法 ID
+	if len(ix.Data) < 8 {
+		return -1
+	}
+
+	switch binary.BigEndian.Uint64(ix.Data[:8]) {
+	case V1Swap:
+		return extractV1SwapEvent(ctx, instrs, current)
+
+		// Create Pool 系列
+	case V1InitializePermissionlessPool,
+		V1InitializePermissionlessPoolWithFeeTier,
+		V1InitializeCustomizablePermissionlessConstantProductPool:
+		return extractV1InitializePoolEvent(ctx, instrs, current, 0)
+	case V1InitializePermissionlessConstantProductPoolWithConfig,
+		V1InitializePermissionlessConstantProductPoolWithConfig2:
+		return extractV1InitializePoolEvent(ctx, instrs, current, 1) // #1 为 Config 账户，其后账户顺延一位
+
+		// 添加 / 移除流动性
+	case V1AddBalanceLiquidity:
+		return extractV1AddLiquidityEvent(ctx, instrs, current, "AddBalanceLiquidity", true)
+	case V1AddImbalanceLiquidity:
+		return extractV1AddLiquidityEvent(ctx, instrs, current, "AddImbalanceLiquidity", false)
+	case V1RemoveBalanceLiquidity:
+		return extractV1RemoveLiquidityEvent(ctx, instrs, current)
+
+	default:
+		// 未识别的指令（如单边移除流动性、管理类指令），直接跳过
+		return -1
+	}
+}
+
+func handleV2Instruction(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	// 指令 data 至少应包含 8 字节方法 ID
+	if len(ix.Data) < 8 {
+		return -1
+	}
+
+	switch binary.BigEndian.Uint64(ix.Data[:8]) {
+	case V2Swap, V2Swap2:
+		return extractV2SwapEvent(ctx, instrs, current)
+
+		// Create Pool 系列
+	case V2InitializePool:
+		return extractV2InitializePoolEvent(ctx, instrs, current, "InitializePool", 6)
+	case V2InitializeCustomizablePool:
+		return extractV2InitializePoolEvent(ctx, instrs, current, "InitializeCustomizablePool", 5)
+	case V2InitializePoolWithDynamicConfig:
+		return extractV2InitializePoolEvent(ctx, instrs, current, "InitializePoolWithDynamicConfig", 7)
+
+		// 添加 / 移除流动性
+	case V2AddLiquidity:
+		return extractV2AddLiquidityEvent(ctx, instrs, current)
+	case V2RemoveLiquidity:
+		return extractV2RemoveLiquidityEvent(ctx, instrs, current, "RemoveLiquidity")
+	case V2RemoveAllLiquidity:
+		return extractV2RemoveLiquidityEvent(ctx, instrs, current, "RemoveAllLiquidity")
+
+	default:
+		// 未识别的指令（如领取手续费、锁仓），直接跳过
+		return -1
+	}
+}
@@ -0,0 +1,150 @@
+package meteoradamm
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/tools"
+)
+
+// extractV1SwapEvent 解析 Meteora DAMM v1 Swap 交易事件。
+// v1 池子的资金存放在 Meteora 动态 vault 中（同一 mint 的池子共享 vault），
+// 用户与 vault 之间的转账由 vault 程序的 deposit / withdraw 完成，是 swap 指令的间接子指令。
+//
+// 0 - Pool（池子地址）
+// 1 - User Source Token（用户输入的 TokenAccount）
+// 2 - User Destination Token（用户输出的 TokenAccount）
+// 3 - A Vault（Token A 的 vault 账户）
+// 4 - B Vault（Token B 的 vault 账户）
+// 5 - A Token Vault（vault 中 Token A 的 TokenAccount）
+// 6 - B Token Vault（vault 中 Token B 的 TokenAccount）
+// 7 - A Vault LP Mint
+// 8 - B Vault LP Mint
+// 9 - A Vault LP（池子持有的 A vault LP）
+// 10 - B Vault LP（池子持有的 B vault LP）
+// 11 - Protocol Token Fee（协议手续费账户）
+// 12 - User（用户钱包，Signer）
+// 13 - Vault Program
+// 14 - Token Program
+func extractV1SwapEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	// 校验账户数量是否满足预期
+	if len(ix.Accounts) < 13 {
+		logger.Errorf("[MeteoraDAMM:V1Swap] 账户数量不足: tx=%s, accounts=%d", ctx.TxHashString(), len(ix.Accounts))
+		return -1
+	}
+
+	// 查找转账记录，匹配用户与 vault Token 账户
+	result := common.FindSwapTransfersByIndex(ctx, instrs, current, &common.SwapInstructionIndex{
+		UserToken1AccountIndex: 1,
+		UserToken2AccountIndex: 2,
+		PoolToken1AccountIndex: 5,
+		PoolToken2AccountIndex: 6,
+		IncludeNested:          true,
+	}, 0)
+	if result == nil {
+		logger.Errorf("[MeteoraDAMM:V1Swap] 转账结构缺失: tx=%s, ix=%d, inner=%d",
+			ctx.TxHashString(), ix.IxIndex, ix.InnerIndex)
+		return -1
+	}
+
+	// 优先尝试使用自定义优先级的quote token（WSOL、USDC、USDT等）
+	quote, ok := tools.ChooseQuote(result.UserToPool.Token, result.PoolToUser.Token)
+	if !ok {
+		// fallback 使用池子的 Token B 作为 quote
+		if result.UserToPool.DestAccount == ix.Accounts[6] {
+			quote = result.UserToPool.Token
+		} else {
+			quote = result.PoolToUser.Token
+		}
+	}
+
+	// 交易对主池地址
+	pairAddress := ix.Accounts[0]
+
+	// 构建交易事件
+	event := common.BuildTradeEvent(ctx, ix, result.UserToPool, result.PoolToUser, pairAddress, quote, true, consts.DexMeteoraDAMM)
+	if event == nil {
+		return -1
+	}
+
+	ctx.AddEvent(event)
+	return result.MaxIndex + 1
+}
+
+// extractV2SwapEvent 解析 Meteora DAMM v2 Swap / Swap2 交易事件。
+//
+// 0 - Pool Authority（池子 Authority PDA）
+// 1 - Pool（池子地址）
+// 2 - Input Token Account（用户输入的 TokenAccount）
+// 3 - Output Token Account（用户输出的 TokenAccount）
+// 4 - Token A Vault（池子 Token A 的 TokenAccount）
+// 5 - Token B Vault（池子 Token B 的 TokenAccount）
+// 6 - Token A Mint
+// 7 - Token B Mint（通常为 quote）
+// 8 - Payer（用户钱包，Signer）
+// 9 - Token A Program
+// 10 - Token B Program
+// 11 - Referral Token Account（可选）
+// 12 - Event Authority
+// 13 - Program
+func extractV2SwapEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	// 校验账户数量是否满足预期
+	if len(ix.Accounts) < 9 {
+		logger.Errorf("[MeteoraDAMM:V2Swap] 账户数量不足: tx=%s, accounts=%d", ctx.TxHashString(), len(ix.Accounts))
+		return -1
+	}
+
+	// 查找转账记录，匹配用户与池子 Token 账户
+	result := common.FindSwapTransfersByIndex(ctx, instrs, current, &common.SwapInstructionIndex{
+		UserToken1AccountIndex: 2,
+		UserToken2AccountIndex: 3,
+		PoolToken1AccountIndex: 4,
+		PoolToken2AccountIndex: 5,
+	}, 0)
+	if result == nil {
+		logger.Errorf("[MeteoraDAMM:V2Swap] 转账结构缺失: tx=%s, ix=%d, inner=%d",
+			ctx.TxHashString(), ix.IxIndex, ix.InnerIndex)
+		return -1
+	}
+
+	// 严格校验 mint 地址匹配（池子 TokenA/TokenB mint 地址）
+	if !((result.UserToPool.Token == ix.Accounts[6] && result.PoolToUser.Token == ix.Accounts[7]) ||
+		(result.UserToPool.Token == ix.Accounts[7] && result.PoolToUser.Token == ix.Accounts[6])) {
+		logger.Errorf("[MeteoraDAMM:V2Swap] mint 不匹配: tx=%s, userToPool=%s, poolToUser=%s, tokenA=%s, tokenB=%s",
+			ctx.TxHashString(), result.UserToPool.Token, result.PoolToUser.Token, ix.Accounts[6], ix.Accounts[7],
+		)
+		return -1
+	}
+
+	// 优先尝试使用自定义优先级的quote token（WSOL、USDC、USDT等）
+	quote, ok := tools.ChooseQuote(result.UserToPool.Token, result.PoolToUser.Token)
+	if !ok {
+		// fallback 使用池子默认的 Quote Token (Token B Mint)
+		quote = ix.Accounts[7]
+	}
+
+	// 交易对主池地址
+	pairAddress := ix.Accounts[1]
+
+	// 构建交易事件
+	event := common.BuildTradeEvent(ctx, ix, result.UserToPool, result.PoolToUser, pairAddress, quote, true, consts.DexMeteoraDAMM)
+	if event == nil {
+		return -1
+	}
+
+	ctx.AddEvent(event)
+	return result.MaxIndex + 1
+}
@@ -0,0 +1,193 @@
+package eventparser
+
+import (
+	"bytes"
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/meteoradamm.json
+//
+//	tx0：DAMM v1 swap，X 卖出换 WSOL，转账位于 vault 程序 deposit / withdraw 之下
+//	tx1：DAMM v2 swap2（ExactIn），USDC 买入 Y
+//	tx2：DAMM v2 swap2（ExactOut）执行失败
+//	tx3：DAMM v1 AddBalanceLiquidity，双边经 vault deposit 存入并铸造 LP
+//	tx4：DAMM v1 AddBalanceLiquidity 只有一侧转账
+//	tx5：DAMM v2 AddLiquidity 单边存入 Y
+//	tx6：DAMM v2 RemoveAllLiquidity
+//	tx7：DAMM v2 InitializePoolWithDynamicConfig
+//	tx8：DAMM v2 swap2 转账 mint 与池子 mint 不一致
+
+func liquidityEvents(events []*core.Event, typ pb.EventType) []*pb.LiquidityEvent {
+	var out []*pb.LiquidityEvent
+	for _, e := range eventsOfType(events, typ) {
+		out = append(out, e.Event.GetLiquidity())
+	}
+	return out
+}
+
+func TestMeteoraDAMMV1Swap(t *testing.T) {
+	tx, events := extractFixture(t, "meteoradamm.json", 0)
+
+	ts := trades(events)
+	require.Len(t, ts, 1)
+	trade := ts[0]
+	assert.Equal(t, pb.EventType_TRADE_SELL, trade.Type)
+	assert.Equal(t, eventID(tx, 0, 0), trade.EventId)
+	assert.Equal(t, uint32(consts.DexMeteoraDAMM), trade.Dex)
+	assert.Equal(t, testfixture.Bytes("damm:v1_pool"), trade.PairAddress)
+	assert.Equal(t, testfixture.Bytes("damm:wallet"), trade.UserWallet)
+	assert.Equal(t, testfixture.Bytes("damm:mint_x"), trade.Token)
+	assert.Equal(t, consts.WSOLMint[:], trade.QuoteToken)
+	assert.Equal(t, uint64(2_000_000), trade.TokenAmount)
+	assert.Equal(t, uint64(30_000_000), trade.QuoteTokenAmount)
+	assert.Equal(t, uint32(6), trade.TokenDecimals)
+	assert.Equal(t, uint32(9), trade.QuoteDecimals)
+
+	// 池子账户为 vault 中的 TokenAccount，vault LP 的铸造 / 销毁不视为交易转账
+	assert.Equal(t, testfixture.Bytes("damm:a_token_vault"), trade.TokenAccount)
+	assert.Equal(t, testfixture.Bytes("damm:b_token_vault"), trade.QuoteTokenAccount)
+	assert.Equal(t, uint64(82_000_000), trade.PairTokenBalance)
+	assert.Equal(t, uint64(870_000_000), trade.PairQuoteBalance)
+}
+
+func TestMeteoraDAMMV2Swap2(t *testing.T) {
+	tx, events := extractFixture(t, "meteoradamm.json", 1)
+
+	ts := trades(events)
+	require.Len(t, ts, 1)
+	trade := ts[0]
+	assert.Equal(t, pb.EventType_TRADE_BUY, trade.Type)
+	assert.Equal(t, eventID(tx, 0, 0), trade.EventId)
+	assert.Equal(t, uint32(consts.DexMeteoraDAMM), trade.Dex)
+	assert.Equal(t, testfixture.Bytes("damm:v2_pool"), trade.PairAddress)
+	assert.Equal(t, testfixture.Bytes("damm:wallet"), trade.UserWallet)
+	assert.Equal(t, testfixture.Bytes("damm:mint_y"), trade.Token)
+	assert.Equal(t, consts.USDCMint[:], trade.QuoteToken)
+	assert.Equal(t, uint64(7_500_000), trade.TokenAmount)
+	assert.Equal(t, uint64(3_000_000), trade.QuoteTokenAmount)
+	assert.Equal(t, testfixture.Bytes("damm:vault_y"), trade.TokenAccount)
+	assert.Equal(t, testfixture.Bytes("damm:vault_usdc"), trade.QuoteTokenAccount)
+}
+
+func TestMeteoraDAMMV2Swap2_Failed(t *testing.T) {
+	txs := loadFixtureTxs(t, "meteoradamm.json")
+	tx := txs[2]
+	require.NotNil(t, tx.Err)
+
+	events := ExtractFailedTradesFromTx(tx)
+	require.Len(t, events, 1)
+	failed := events[0].Event.GetFailedTrade()
+	require.NotNil(t, failed)
+	assert.Equal(t, eventID(tx, 0, 0), failed.EventId)
+	assert.Equal(t, uint32(consts.DexMeteoraDAMM), failed.Dex)
+	assert.Equal(t, testfixture.Bytes("damm:v2_pool"), failed.PairAddress)
+	assert.Equal(t, testfixture.Bytes("damm:wallet"), failed.UserWallet)
+	assert.Equal(t, pb.EventType_TRADE_BUY, failed.Side)
+
+	// swap_mode = ExactOut：amount_0 为输出数量，amount_1 为最大输入
+	assert.True(t, failed.ExactOut)
+	assert.Equal(t, uint64(3_100_000), failed.AmountIn)
+	assert.Equal(t, uint64(7_500_000), failed.AmountOut)
+	assert.Equal(t, consts.USDCMint[:], failed.InputToken, "mint 由用户 token 账户余额推断")
+	assert.Equal(t, testfixture.Bytes("damm:mint_y"), failed.OutputToken)
+
+	assert.Equal(t, "InstructionError", failed.ErrorCode)
+	assert.Equal(t, int32(0), failed.ErrorIxIndex)
+	assert.Equal(t, "Custom", failed.ErrorIxCode)
+	assert.Equal(t, uint32(6004), failed.CustomErrorCode)
+}
+
+func TestMeteoraDAMMV1AddBalanceLiquidity(t *testing.T) {
+	tx, events := extractFixture(t, "meteoradamm.json", 3)
+
+	adds := liquidityEvents(events, pb.EventType_ADD_LIQUIDITY)
+	require.Len(t, adds, 1)
+	add := adds[0]
+	assert.Equal(t, eventID(tx, 0, 0), add.EventId)
+	assert.Equal(t, uint32(consts.DexMeteoraDAMM), add.Dex)
+	assert.Equal(t, testfixture.Bytes("damm:v1_pool"), add.PairAddress)
+	assert.Equal(t, testfixture.Bytes("damm:mint_x"), add.Token)
+	assert.Equal(t, consts.WSOLMint[:], add.QuoteToken)
+	assert.Equal(t, uint64(1_000_000), add.TokenAmount)
+	assert.Equal(t, uint64(15_000_000), add.QuoteTokenAmount)
+	assert.Equal(t, testfixture.Bytes("damm:a_token_vault"), add.TokenAccount)
+	assert.Equal(t, uint64(915_000_000), add.PairQuoteBalance)
+
+	// 池子铸造的 LP，而非 vault 内部的 LP
+	var lpMints []*pb.MintToEvent
+	for _, e := range eventsOfType(events, pb.EventType_MINT_TO) {
+		if mint := e.Event.GetMint(); bytes.Equal(mint.Token, testfixture.Bytes("damm:lp_mint")) {
+			lpMints = append(lpMints, mint)
+		}
+	}
+	require.Len(t, lpMints, 1)
+	assert.Equal(t, uint64(500_000), lpMints[0].Amount)
+	assert.Equal(t, testfixture.Bytes("damm:user_lp"), lpMints[0].ToTokenAccount)
+}
+
+func TestMeteoraDAMMV1AddBalanceLiquidity_OneSide(t *testing.T) {
+	_, events := extractFixture(t, "meteoradamm.json", 4)
+	assert.Empty(t, liquidityEvents(events, pb.EventType_ADD_LIQUIDITY), "按比例添加要求双边转账")
+}
+
+func TestMeteoraDAMMV2AddLiquidity_OneSide(t *testing.T) {
+	tx, events := extractFixture(t, "meteoradamm.json", 5)
+
+	adds := liquidityEvents(events, pb.EventType_ADD_LIQUIDITY)
+	require.Len(t, adds, 1)
+	add := adds[0]
+	assert.Equal(t, eventID(tx, 0, 0), add.EventId)
+	assert.Equal(t, testfixture.Bytes("damm:v2_pool"), add.PairAddress)
+	assert.Equal(t, testfixture.Bytes("damm:mint_y"), add.Token)
+	assert.Equal(t, consts.USDCMint[:], add.QuoteToken)
+	assert.Equal(t, uint64(2_000_000), add.TokenAmount)
+	assert.Zero(t, add.QuoteTokenAmount, "未转入的一侧补齐为 0")
+	assert.Equal(t, uint64(203_000_000), add.PairQuoteBalance)
+	assert.Empty(t, eventsOfType(events, pb.EventType_MINT_TO), "v2 头寸无 LP Token")
+}
+
+func TestMeteoraDAMMV2RemoveAllLiquidity(t *testing.T) {
+	tx, events := extractFixture(t, "meteoradamm.json", 6)
+
+	removes := liquidityEvents(events, pb.EventType_REMOVE_LIQUIDITY)
+	require.Len(t, removes, 1)
+	remove := removes[0]
+	assert.Equal(t, eventID(tx, 0, 0), remove.EventId)
+	assert.Equal(t, testfixture.Bytes("damm:v2_pool"), remove.PairAddress, "Pool 位于 #1")
+	assert.Equal(t, testfixture.Bytes("damm:wallet"), remove.UserWallet)
+	assert.Equal(t, testfixture.Bytes("damm:mint_y"), remove.Token)
+	assert.Equal(t, uint64(1_000_000), remove.TokenAmount)
+	assert.Equal(t, uint64(400_000), remove.QuoteTokenAmount)
+}
+
+func TestMeteoraDAMMV2InitializePoolWithDynamicConfig(t *testing.T) {
+	tx, events := extractFixture(t, "meteoradamm.json", 7)
+
+	pools := liquidityEvents(events, pb.EventType_CREATE_POOL)
+	require.Len(t, pools, 1)
+	pool := pools[0]
+	assert.Equal(t, eventID(tx, 0, 0), pool.EventId)
+	assert.Equal(t, uint32(consts.DexMeteoraDAMM), pool.Dex)
+	assert.Equal(t, testfixture.Bytes("damm:new_pool"), pool.PairAddress)
+	assert.Equal(t, testfixture.Bytes("damm:wallet"), pool.UserWallet)
+	assert.Equal(t, testfixture.Bytes("damm:mint_new"), pool.Token)
+	assert.Equal(t, consts.USDCMint[:], pool.QuoteToken)
+	assert.Equal(t, testfixture.Bytes("damm:new_vault_a"), pool.TokenAccount)
+	assert.Equal(t, testfixture.Bytes("damm:new_vault_b"), pool.QuoteTokenAccount)
+	assert.Equal(t, uint64(100_000_000), pool.PairTokenBalance)
+	assert.Equal(t, uint64(5_000_000), pool.PairQuoteBalance)
+	assert.Equal(t, pb.TokenProgramType_TOKEN_SPL, pool.TokenProgram)
+}
+
+func TestMeteoraDAMMV2Swap2_MintMismatch(t *testing.T) {
+	_, events := extractFixture(t, "meteoradamm.json", 8)
+	assert.Empty(t, trades(events), "转账 mint 与池子 TokenA / TokenB mint 不一致")
+	assert.Len(t, eventsOfType(events, pb.EventType_TRANSFER), 2, "转账仍单独记录")
+}
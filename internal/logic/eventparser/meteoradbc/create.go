@@ -0,0 +1,252 @@
+package meteoradbc
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/internal/tools"
+	"dex-indexer-sol/pb"
+	"github.com/near/borsh-go"
+	"runtime/debug"
+)
+
+// InitializePoolParameters 为建池指令参数（含 8 字节方法 ID），token 元数据只存在于指令参数中，事件日志不包含
+type InitializePoolParameters struct {
+	Sign   uint64
+	Name   string
+	Symbol string
+	Uri    string
+}
+
+type EvtInitializePool struct {
+	Sign     uint64
+	Pool     types.Pubkey
+	Config   types.Pubkey
+	Creator  types.Pubkey
+	BaseMint types.Pubkey
+}
+
+// createLayout 描述两种建池指令中位置不同的账户索引，其余账户位置相同：
+//
+// #0 - Config（曲线配置）     #1 - Pool Authority（持有 vault 的 PDA）   #2 - Creator
+// #3 - Base Mint（新 token）  #4 - Quote Mint（通常为 WSOL）           #5 - Pool（池子主账户）
+// #6 - Base Vault            #7 - Quote Vault
+type createLayout struct {
+	MinAccounts       int
+	Payer             int
+	QuoteTokenProgram int
+	BaseTokenProgram  int
+	EventAuthority    int
+}
+
+// InitializeVirtualPoolWithSplToken：
+// #8 Mint Metadata, #9 Metadata Program, #10 Payer, #11 Quote Token Program, #12 Token Program,
+// #13 System Program, #14 Event Authority, #15 Program
+var splTokenCreateLayout = createLayout{
+	MinAccounts:       16,
+	Payer:             10,
+	QuoteTokenProgram: 11,
+	BaseTokenProgram:  12,
+	EventAuthority:    14,
+}
+
+// InitializeVirtualPoolWithToken2022（元数据存放在 Token-2022 扩展中，无 Metaplex 账户）：
+// #8 Payer, #9 Quote Token Program, #10 Token Program, #11 System Program, #12 Event Authority, #13 Program
+var token2022CreateLayout = createLayout{
+	MinAccounts:       14,
+	Payer:             8,
+	QuoteTokenProgram: 9,
+	BaseTokenProgram:  10,
+	EventAuthority:    12,
+}
+
+// extractCreateEvent 解析 Meteora DBC 建池指令，输出 CREATE_POOL、LAUNCHPAD_TOKEN 与 ADD_LIQUIDITY 事件。
+func extractCreateEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+	layout *createLayout,
+) (next int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[MeteoraDBC:Create] panic: %v, stack=%s, tx=%s", r, debug.Stack(), ctx.TxHashString())
+			next = -1
+		}
+	}()
+
+	ix := instrs[current]
+
+	// 1. 校验指令结构
+	if len(ix.Accounts) < layout.MinAccounts {
+		logger.Errorf("[MeteoraDBC:Create] 指令账户长度不足: got=%d, expect>=%d, tx=%s",
+			len(ix.Accounts), layout.MinAccounts, ctx.TxHashString())
+		return -1
+	}
+
+	// 2. 解析指令参数中的 token 元数据
+	params := InitializePoolParameters{}
+	if err := borsh.Deserialize(&params, ix.Data); err != nil {
+		logger.Errorf("[MeteoraDBC:Create] 指令参数反序列化失败: %v, tx=%s", err, ctx.TxHashString())
+		return -1
+	}
+
+	// 3. 提取并解析事件
+	eventAuthority := ix.Accounts[layout.EventAuthority]
+	eventIndex := findEventInstruction(instrs, current, eventAuthority, EvtInitializePoolSign)
+	if eventIndex < 0 {
+		logger.Errorf("[MeteoraDBC:Create] 未找到事件日志指令: authority=%s, tx=%s", eventAuthority, ctx.TxHashString())
+		return -1
+	}
+	eventIx := instrs[eventIndex]
+	event := EvtInitializePool{}
+	if err := borsh.Deserialize(&event, eventIx.Data[8:]); err != nil {
+		logger.Errorf("[MeteoraDBC:Create] 事件反序列化失败: %v, tx=%s", err, ctx.TxHashString())
+		return -1
+	}
+
+	// 4. 校验池子、mint 与创建者一致性
+	poolAddress := ix.Accounts[5]
+	baseMint := ix.Accounts[3]
+	if event.Pool != poolAddress || event.BaseMint != baseMint || event.Creator != ix.Accounts[2] {
+		logger.Errorf("[MeteoraDBC:Create] 事件与指令账户不一致: pool=%s/%s, mint=%s/%s, creator=%s/%s, tx=%s",
+			event.Pool, poolAddress, event.BaseMint, baseMint, event.Creator, ix.Accounts[2], ctx.TxHashString())
+		return -1
+	}
+
+	// 5. 校验 Token Program 是否为 SPL Token
+	tokenProgramID := ix.Accounts[layout.BaseTokenProgram]
+	quoteTokenProgramID := ix.Accounts[layout.QuoteTokenProgram]
+	if !tools.IsSPLTokenPubkey(tokenProgramID) || !tools.IsSPLTokenPubkey(quoteTokenProgramID) {
+		logger.Errorf("[MeteoraDBC:Create] Token Program 非 SPL 标准程序: base=%s, quote=%s, tx=%s",
+			tokenProgramID, quoteTokenProgramID, ctx.TxHashString())
+		return -1
+	}
+
+	// 6. 提取池子 vault 账户与余额（Post 状态）
+	poolAuthority := ix.Accounts[1]
+	quoteMint := ix.Accounts[4]
+	baseVault := ix.Accounts[6]
+	quoteVault := ix.Accounts[7]
+
+	baseVaultBalance, ok := ctx.Balances[baseVault]
+	if !ok {
+		logger.Errorf("[MeteoraDBC:Create] 缺失池子 base vault 余额: account=%s, tx=%s", baseVault, ctx.TxHashString())
+		return -1
+	}
+	quoteVaultBalance, ok := ctx.Balances[quoteVault]
+	if !ok {
+		logger.Errorf("[MeteoraDBC:Create] 缺失池子 quote vault 余额: account=%s, tx=%s", quoteVault, ctx.TxHashString())
+		return -1
+	}
+
+	// 7. 校验 vault 的 mint 与所有者（vault 由 Pool Authority PDA 持有）
+	if baseVaultBalance.Token != baseMint || quoteVaultBalance.Token != quoteMint {
+		logger.Errorf("[MeteoraDBC:Create] vault mint 不匹配: base=%s/%s, quote=%s/%s, tx=%s",
+			baseVaultBalance.Token, baseMint, quoteVaultBalance.Token, quoteMint, ctx.TxHashString())
+		return -1
+	}
+	if baseVaultBalance.PostOwner != poolAuthority || quoteVaultBalance.PostOwner != poolAuthority {
+		logger.Errorf("[MeteoraDBC:Create] vault 所有者异常: expected=%s, base=%s, quote=%s, tx=%s",
+			poolAuthority, baseVaultBalance.PostOwner, quoteVaultBalance.PostOwner, ctx.TxHashString())
+		return -1
+	}
+
+	// 8. 构建 CreatePool 类型的 LiquidityEvent
+	userWallet := ix.Accounts[layout.Payer]
+	poolEvent := &pb.LiquidityEvent{
+		Type:      pb.EventType_CREATE_POOL,
+		EventId:   core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
+		Slot:      ctx.Slot,
+		BlockTime: ctx.BlockTime,
+		TxHash:    ctx.TxHash,
+		Signers:   ctx.Signers,
+		Dex:       consts.DexMeteoraDBC,
+
+		UserWallet:  userWallet[:],
+		PairAddress: poolAddress[:],
+
+		TokenDecimals: uint32(baseVaultBalance.Decimals),
+		QuoteDecimals: uint32(quoteVaultBalance.Decimals),
+
+		TokenAmount:      0,
+		QuoteTokenAmount: 0,
+
+		Token:      baseMint[:],
+		QuoteToken: quoteMint[:],
+
+		TokenAccount:      baseVault[:],
+		QuoteTokenAccount: quoteVault[:],
+
+		TokenAccountOwner:      baseVaultBalance.PostOwner[:],
+		QuoteTokenAccountOwner: quoteVaultBalance.PostOwner[:],
+
+		PairTokenBalance: baseVaultBalance.PostBalance,
+		PairQuoteBalance: quoteVaultBalance.PostBalance,
+
+		TokenProgram:      tools.TokenProgramTypeOf(tokenProgramID),
+		QuoteTokenProgram: tools.TokenProgramTypeOf(quoteTokenProgramID),
+	}
+
+	// 9. 构造CreatePool标准事件结构
+	createPool := &core.Event{
+		ID:        poolEvent.EventId,
+		EventType: uint32(poolEvent.Type),
+		Key:       poolEvent.PairAddress,
+		Event: &pb.Event{
+			Event: &pb.Event_Liquidity{Liquidity: poolEvent},
+		},
+	}
+
+	// 10. 衍生出 LaunchpadTokenEvent 事件（建池时全部供应量铸造进 base vault）
+	tokenEvent := &pb.LaunchpadTokenEvent{
+		Type:      pb.EventType_LAUNCHPAD_TOKEN,
+		EventId:   createPool.ID + 1,
+		Slot:      ctx.Slot,
+		BlockTime: ctx.BlockTime,
+		TxHash:    ctx.TxHash,
+		Signers:   ctx.Signers,
+		Dex:       consts.DexMeteoraDBC,
+
+		UserWallet: userWallet[:],
+		Creator:    event.Creator[:],
+
+		Decimals: uint32(baseVaultBalance.Decimals),
+
+		TotalSupply: baseVaultBalance.PostBalance,
+		Token:       baseMint[:],
+		PairAddress: poolAddress[:],
+
+		Symbol: params.Symbol,
+		Name:   params.Name,
+		Uri:    params.Uri,
+
+		TokenProgram: tools.TokenProgramTypeOf(tokenProgramID),
+	}
+	launchpadTokenEvent := &core.Event{
+		ID:        tokenEvent.EventId,
+		EventType: uint32(tokenEvent.Type),
+		Key:       tokenEvent.PairAddress,
+		Event: &pb.Event{
+			Event: &pb.Event_Token{
+				Token: tokenEvent,
+			},
+		},
+	}
+
+	// 11. 衍生出 AddLiquidity 事件（CreatePool 隐含的首笔注入逻辑，不对应链上转账指令）
+	liquidityEvent := common.CloneLiquidityEvent(createPool)
+	liquidityEvent.ID += 2
+	liquidityEvent.Event.GetLiquidity().EventId = liquidityEvent.ID
+	liquidityEvent.EventType = uint32(pb.EventType_ADD_LIQUIDITY)
+	liquidityEvent.Event.GetLiquidity().Type = pb.EventType_ADD_LIQUIDITY
+	liquidityEvent.Event.GetLiquidity().TokenAmount = baseVaultBalance.PostBalance
+	liquidityEvent.Event.GetLiquidity().TokenAmountNet = baseVaultBalance.PostBalance
+	liquidityEvent.Event.GetLiquidity().QuoteTokenAmount = 0 // 建池不注入 quote，quote 注入发生在后续买入
+
+	ctx.AddEvent(createPool)
+	ctx.AddEvent(launchpadTokenEvent)
+	ctx.AddEvent(liquidityEvent)
+	return eventIndex + 1
+}
@@ -0,0 +1,47 @@
+package meteoradbc
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+)
+
+const Event uint64 = 0xe445a52e51cb9a1d
+
+// 事件 discriminator：sha256("event:<Name>")[:8]
+const (
+	EvtInitializePoolSign uint64 = 0xe432f655cb428625
+	EvtSwapSign           uint64 = 0x1b3c15d58aaabb93
+	EvtSwap2Sign          uint64 = 0xbd4233a826507599
+)
+
+// findEventInstruction 查找当前指令通过 self-CPI 发出的指定类型事件日志，data 布局：
+// [0:8] Event 前缀，[8:16] 事件 discriminator，[16:] 事件内容
+func findEventInstruction(
+	instrs []*core.AdaptedInstruction,
+	current int,
+	eventAuthority types.Pubkey,
+	sign uint64,
+) int {
+	for i, ix := range common.Children(instrs, current) {
+		if ix.ProgramID != consts.MeteoraDBCProgram {
+			continue
+		}
+
+		if len(ix.Data) < 16 || len(ix.Accounts) == 0 {
+			continue
+		}
+
+		if binary.BigEndian.Uint64(ix.Data[:8]) != Event || binary.BigEndian.Uint64(ix.Data[8:16]) != sign {
+			continue
+		}
+
+		// 事件日志指令以 eventAuthority 作为第 0 个账户
+		if eventAuthority == ix.Accounts[0] {
+			return i
+		}
+	}
+	return -1
+}
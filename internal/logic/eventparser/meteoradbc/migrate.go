@@ -0,0 +1,217 @@
+package meteoradbc
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/pb"
+	"runtime/debug"
+)
+
+// migrateLayout 描述迁移指令中来源池（bonding curve）与目标 DAMM 池的关键账户索引。
+// 两种迁移指令的前 5 个账户相同：#0 Virtual Pool，#1 Migration Metadata，#2 Config，#3 Pool Authority，#4 DAMM Pool
+type migrateLayout struct {
+	MinAccounts int // 指令账户最少数量
+	Payer       int
+	BaseMint    int
+	QuoteMint   int
+	BaseVault   int // 来源池 base token 账户
+	QuoteVault  int // 来源池 quote token 账户
+
+	DestBaseVault  int // 目标池 base token 账户（token A）
+	DestQuoteVault int // 目标池 quote token 账户（token B）
+}
+
+// MigrateMeteoraDamm 指令账户布局（迁移至 DAMM v1）：
+//
+// #5  - DAMM Config            #6  - LP Mint                #7  - Token A Mint（base）
+// #8  - Token B Mint（quote）  #9  - A Vault                #10 - B Vault
+// #11 - A Token Vault          #12 - B Token Vault          #13 - A Vault LP Mint
+// #14 - B Vault LP Mint        #15 - A Vault LP             #16 - B Vault LP
+// #17 - Base Vault             #18 - Quote Vault            #19 - Virtual Pool LP
+// #20 - Protocol Token A Fee   #21 - Protocol Token B Fee   #22 - Payer
+// #23 - Rent                   #24 - Mint Metadata          #25 - Metadata Program
+// #26 - AMM Program            #27 - Vault Program          #28 - Token Program
+// #29 - Associated Token Prog  #30 - System Program
+//
+// DAMM v1 池子的资金存放在 Meteora Vault 的 token 账户中（同一 mint 的池子共用），余额变化仍只来自本次注入。
+var migrateDammV1Layout = migrateLayout{
+	MinAccounts:    31,
+	Payer:          22,
+	BaseMint:       7,
+	QuoteMint:      8,
+	BaseVault:      17,
+	QuoteVault:     18,
+	DestBaseVault:  11,
+	DestQuoteVault: 12,
+}
+
+// MigrationDammV2 指令账户布局（迁移至 DAMM v2）：
+//
+// #5  - First Position NFT Mint      #6  - First Position NFT Account   #7  - First Position
+// #8  - Second Position NFT Mint     #9  - Second Position NFT Account  #10 - Second Position
+// #11 - DAMM Pool Authority          #12 - AMM Program                  #13 - Base Mint
+// #14 - Quote Mint                   #15 - Token A Vault                #16 - Token B Vault
+// #17 - Base Vault                   #18 - Quote Vault                  #19 - Payer
+// #20 - Base Token Program           #21 - Quote Token Program          #22 - Token 2022 Program
+// #23 - DAMM Event Authority         #24 - System Program
+var migrateDammV2Layout = migrateLayout{
+	MinAccounts:    25,
+	Payer:          19,
+	BaseMint:       13,
+	QuoteMint:      14,
+	BaseVault:      17,
+	QuoteVault:     18,
+	DestBaseVault:  15,
+	DestQuoteVault: 16,
+}
+
+// extractMigrateEvent 解析 bonding curve 毕业迁移（MigrateMeteoraDamm / MigrationDammV2），构造 MigrateEvent。
+// 迁移指令不发出事件日志，迁移数量由来源池与目标池 vault 的余额变化计算：
+//   - TokenAmount / QuoteTokenAmount：目标池 vault 的增加量（实际注入新池的数量）
+//   - MigrationFee：来源池 quote 减少量中未注入新池的部分
+func extractMigrateEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+	layout *migrateLayout,
+) (next int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[MeteoraDBC:Migrate] panic: %v, stack=%s, tx=%s", r, debug.Stack(), ctx.TxHashString())
+			next = -1
+		}
+	}()
+
+	ix := instrs[current]
+
+	// 1. 校验指令结构
+	if len(ix.Accounts) < layout.MinAccounts {
+		logger.Errorf("[MeteoraDBC:Migrate] 指令账户长度不足: got=%d, expect>=%d, tx=%s",
+			len(ix.Accounts), layout.MinAccounts, ctx.TxHashString())
+		return -1
+	}
+
+	// 2. 提取关键账户
+	srcPoolAddress := ix.Accounts[0]
+	poolAuthority := ix.Accounts[3]
+	destPoolAddress := ix.Accounts[4]
+	userWallet := ix.Accounts[layout.Payer]
+	baseMint := ix.Accounts[layout.BaseMint]
+	quoteMint := ix.Accounts[layout.QuoteMint]
+	srcPoolTokenAccount := ix.Accounts[layout.BaseVault]
+	srcPoolQuoteAccount := ix.Accounts[layout.QuoteVault]
+	destPoolTokenAccount := ix.Accounts[layout.DestBaseVault]
+	destPoolQuoteAccount := ix.Accounts[layout.DestQuoteVault]
+
+	// 3. 获取来源池与目标池的 vault 余额
+	srcPoolTokenBalance, ok := ctx.Balances[srcPoolTokenAccount]
+	if !ok {
+		logger.Errorf("[MeteoraDBC:Migrate] 来源池 base token 余额缺失: account=%s, tx=%s", srcPoolTokenAccount, ctx.TxHashString())
+		return -1
+	}
+	srcPoolQuoteBalance, ok := ctx.Balances[srcPoolQuoteAccount]
+	if !ok {
+		logger.Errorf("[MeteoraDBC:Migrate] 来源池 quote token 余额缺失: account=%s, tx=%s", srcPoolQuoteAccount, ctx.TxHashString())
+		return -1
+	}
+	destPoolTokenBalance, ok := ctx.Balances[destPoolTokenAccount]
+	if !ok {
+		logger.Errorf("[MeteoraDBC:Migrate] 目标池 base token 余额缺失: account=%s, tx=%s", destPoolTokenAccount, ctx.TxHashString())
+		return -1
+	}
+	destPoolQuoteBalance, ok := ctx.Balances[destPoolQuoteAccount]
+	if !ok {
+		logger.Errorf("[MeteoraDBC:Migrate] 目标池 quote token 余额缺失: account=%s, tx=%s", destPoolQuoteAccount, ctx.TxHashString())
+		return -1
+	}
+
+	// 4. 校验来源池 vault 所有者为 Pool Authority PDA
+	if srcPoolTokenBalance.PostOwner != poolAuthority || srcPoolQuoteBalance.PostOwner != poolAuthority {
+		logger.Errorf("[MeteoraDBC:Migrate] 来源池 vault 所有者异常: expected=%s, base=%s, quote=%s, tx=%s",
+			poolAuthority, srcPoolTokenBalance.PostOwner, srcPoolQuoteBalance.PostOwner, ctx.TxHashString())
+		return -1
+	}
+
+	// 5. 校验 base / quote token 一致性（来源池、目标池与指令中的 mint 必须相同）
+	if srcPoolTokenBalance.Token != baseMint || destPoolTokenBalance.Token != baseMint {
+		logger.Errorf("[MeteoraDBC:Migrate] base token 不一致: src=%s, dest=%s, mint=%s, tx=%s",
+			srcPoolTokenBalance.Token, destPoolTokenBalance.Token, baseMint, ctx.TxHashString())
+		return -1
+	}
+	if srcPoolQuoteBalance.Token != quoteMint || destPoolQuoteBalance.Token != quoteMint {
+		logger.Errorf("[MeteoraDBC:Migrate] quote token 不一致: src=%s, dest=%s, mint=%s, tx=%s",
+			srcPoolQuoteBalance.Token, destPoolQuoteBalance.Token, quoteMint, ctx.TxHashString())
+		return -1
+	}
+
+	// 6. 根据余额变化计算迁移数量与费用
+	tokenAmount := destPoolTokenBalance.PostBalance - min(destPoolTokenBalance.PreBalance, destPoolTokenBalance.PostBalance)
+	quoteAmount := destPoolQuoteBalance.PostBalance - min(destPoolQuoteBalance.PreBalance, destPoolQuoteBalance.PostBalance)
+	quoteOut := srcPoolQuoteBalance.PreBalance - min(srcPoolQuoteBalance.PreBalance, srcPoolQuoteBalance.PostBalance)
+	var migrationFee uint64
+	if quoteOut > quoteAmount {
+		migrationFee = quoteOut - quoteAmount
+	}
+
+	// 7. 构建MigrateEvent
+	migrateEvent := &pb.MigrateEvent{
+		Type:      pb.EventType_MIGRATE,
+		EventId:   core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
+		Slot:      ctx.Slot,
+		BlockTime: ctx.BlockTime,
+		TxHash:    ctx.TxHash,
+		Signers:   ctx.Signers,
+
+		UserWallet:      userWallet[:],
+		DestPoolCreator: poolAuthority[:],
+
+		SrcDex:  consts.DexMeteoraDBC,
+		DestDex: consts.DexMeteoraDAMM,
+
+		TokenDecimals: uint32(destPoolTokenBalance.Decimals),
+		QuoteDecimals: uint32(destPoolQuoteBalance.Decimals),
+
+		TokenAmount:      tokenAmount,  // 迁移 base token 数量
+		QuoteTokenAmount: quoteAmount,  // 迁移 quote token 数量
+		MigrationFee:     migrationFee, // 迁移费用（quote）
+
+		Token:          baseMint[:],
+		SrcQuoteToken:  quoteMint[:],
+		DestQuoteToken: quoteMint[:],
+
+		SrcPairAddress:  srcPoolAddress[:],
+		DestPairAddress: destPoolAddress[:],
+
+		SrcTokenAccount:  srcPoolTokenAccount[:],
+		DestTokenAccount: destPoolTokenAccount[:],
+
+		SrcQuoteTokenAccount:  srcPoolQuoteAccount[:],
+		DestQuoteTokenAccount: destPoolQuoteAccount[:],
+
+		SrcTokenAccountOwner:  srcPoolTokenBalance.PostOwner[:],
+		DestTokenAccountOwner: destPoolTokenBalance.PostOwner[:],
+
+		SrcQuoteTokenAccountOwner:  srcPoolQuoteBalance.PostOwner[:],
+		DestQuoteTokenAccountOwner: destPoolQuoteBalance.PostOwner[:],
+
+		SrcPairTokenBalance:  srcPoolTokenBalance.PostBalance,
+		DestPairTokenBalance: destPoolTokenBalance.PostBalance,
+		SrcPairQuoteBalance:  srcPoolQuoteBalance.PostBalance,
+		DestPairQuoteBalance: destPoolQuoteBalance.PostBalance,
+	}
+
+	// 8. 添加事件到事件列表
+	ctx.AddEvent(&core.Event{
+		ID:        migrateEvent.EventId,
+		EventType: uint32(migrateEvent.Type),
+		Key:       migrateEvent.SrcPairAddress, // 分区 Key 用旧池 SrcPairAddress 更符合事件语义
+		Event: &pb.Event{
+			Event: &pb.Event_Migrate{Migrate: migrateEvent},
+		},
+	})
+
+	// 保留内部的新池事件（DAMM v1 / v2 的建池与注入流动性）
+	return current + 1
+}
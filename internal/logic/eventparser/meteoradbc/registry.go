@@ -0,0 +1,85 @@
+package meteoradbc
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+)
+
+// Meteora Dynamic Bonding Curve 指令方法ID
+const (
+	// 创建 bonding curve 池子（发币）
+	InitializeVirtualPoolWithSplToken  uint64 = 0x8c55d7b06636684f
+	InitializeVirtualPoolWithToken2022 uint64 = 0xa976334e916edc9b
+
+	// 内盘交易
+	Swap  uint64 = 0xf8c69e91e17587c8
+	Swap2 uint64 = 0x414b3f4ceb5b5b88
+
+	// 毕业迁移
+	MigrateMeteoraDamm uint64 = 0x1b013016b43f76d9 // 迁移至 DAMM v1
+	MigrationDammV2    uint64 = 0x9ca9e66735e45040 // 迁移至 DAMM v2
+)
+
+// RegisterHandlers 注册 Meteora DBC Program 的指令解析器
+func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
+	m[consts.MeteoraDBCProgram] = handleInstruction
+}
+
+// RegisterFailedHandlers 注册失败交易中 Swap 指令的意图解析器
+func RegisterFailedHandlers(m map[types.Pubkey]common.FailedTradeHandler) {
+	m[consts.MeteoraDBCProgram] = failedSwapTable.Extract
+}
+
+// failedSwapTable 失败交易中 Swap / Swap2 指令的布局（账户见 extractSwapEvent）
+//   - Swap:  [8:16] amount_in，[16:24] minimum_amount_out
+//   - Swap2: [8:16] amount_0，[16:24] amount_1，[24] swap_mode；
+//     ExactIn / PartialFill 时 amount_0 为输入数量、amount_1 为最小输出，ExactOut 时 amount_0 为输出数量、amount_1 为最大输入
+var failedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexMeteoraDBC,
+	DiscriminatorLen: 8,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		Swap: {
+			MinAccounts: 10,
+			Pair:        2, Wallet: 9, UserSource: 3, UserDest: 4, InputMint: common.NoAccount, OutputMint: common.NoAccount,
+			AmountIn: 8, AmountOut: 16,
+		},
+		Swap2: {
+			MinAccounts: 10,
+			Pair:        2, Wallet: 9, UserSource: 3, UserDest: 4, InputMint: common.NoAccount, OutputMint: common.NoAccount,
+			AmountIn: 8, AmountOut: 16, SwapMode: 24,
+		},
+	},
+}
+
+func handleInstruction(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	// 指令 data 至少应包含 8 字节方法 ID
+	if len(ix.Data) < 8 {
+		return -1
+	}
+
+	switch binary.BigEndian.Uint64(ix.Data[:8]) {
+	case InitializeVirtualPoolWithSplToken:
+		return extractCreateEvent(ctx, instrs, current, &splTokenCreateLayout)
+	case InitializeVirtualPoolWithToken2022:
+		return extractCreateEvent(ctx, instrs, current, &token2022CreateLayout)
+	case Swap:
+		return extractSwapEvent(ctx, instrs, current, EvtSwapSign)
+	case Swap2:
+		return extractSwapEvent(ctx, instrs, current, EvtSwap2Sign)
+	case MigrateMeteoraDamm:
+		return extractMigrateEvent(ctx, instrs, current, &migrateDammV1Layout)
+	case MigrationDammV2:
+		return extractMigrateEvent(ctx, instrs, current, &migrateDammV2Layout)
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,109 @@
+package meteoradbc
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"github.com/near/borsh-go"
+	"runtime/debug"
+)
+
+// EvtSwapHeader 为 EvtSwap / EvtSwap2 的公共前缀，之后的参数与成交结果两种事件布局不同，成交数量取自实际转账
+type EvtSwapHeader struct {
+	Sign   uint64
+	Pool   types.Pubkey
+	Config types.Pubkey
+}
+
+// extractSwapEvent 解析 Meteora DBC 内盘的 Swap / Swap2 指令，构造标准 TradeEvent（BUY / SELL）。
+//
+// #0  - Pool Authority（持有 vault 的 PDA）
+// #1  - Config（曲线配置）
+// #2  - Pool（池子主账户）
+// #3  - Input Token Account（用户输入 TokenAccount）
+// #4  - Output Token Account（用户输出 TokenAccount）
+// #5  - Base Vault
+// #6  - Quote Vault
+// #7  - Base Mint
+// #8  - Quote Mint
+// #9  - Payer（用户钱包，Signer）
+// #10 - Base Token Program
+// #11 - Quote Token Program
+// #12 - Referral Token Account（可选）
+// #13 - Event Authority
+// #14 - Program
+func extractSwapEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+	eventSign uint64,
+) (next int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[MeteoraDBC:Swap] panic: %v, stack=%s, tx=%s", r, debug.Stack(), ctx.TxHashString())
+			next = -1
+		}
+	}()
+
+	ix := instrs[current]
+
+	// 1. 校验指令结构
+	if len(ix.Accounts) < 15 {
+		logger.Errorf("[MeteoraDBC:Swap] 指令账户长度不足: got=%d, expect>=15, tx=%s",
+			len(ix.Accounts), ctx.TxHashString())
+		return -1
+	}
+
+	// 2. 提取并解析事件
+	eventIndex := findEventInstruction(instrs, current, ix.Accounts[13], eventSign) // Event Authority
+	if eventIndex < 0 {
+		logger.Errorf("[MeteoraDBC:Swap] 未找到事件日志指令: authority=%s, tx=%s", ix.Accounts[13], ctx.TxHashString())
+		return -1
+	}
+	eventIx := instrs[eventIndex]
+	event := EvtSwapHeader{}
+	if err := borsh.Deserialize(&event, eventIx.Data[8:]); err != nil {
+		logger.Errorf("[MeteoraDBC:Swap] 事件反序列化失败: %v, tx=%s", err, ctx.TxHashString())
+		return -1
+	}
+
+	// 3. 校验池子地址一致性
+	pairAddress := ix.Accounts[2]
+	if event.Pool != pairAddress {
+		logger.Errorf("[MeteoraDBC:Swap] Pool 不一致 (expected=%s, got=%s): tx=%s", pairAddress, event.Pool, ctx.TxHashString())
+		return -1
+	}
+
+	// 4. 查找用户与池子 vault 之间的转账
+	result := common.FindSwapTransfersByIndex(ctx, instrs, current, &common.SwapInstructionIndex{
+		UserToken1AccountIndex: 3,
+		UserToken2AccountIndex: 4,
+		PoolToken1AccountIndex: 5,
+		PoolToken2AccountIndex: 6,
+	}, 0)
+	if result == nil {
+		logger.Errorf("[MeteoraDBC:Swap] 转账结构缺失: tx=%s, ix=%d, inner=%d",
+			ctx.TxHashString(), ix.IxIndex, ix.InnerIndex)
+		return -1
+	}
+
+	// 5. 严格校验 mint 地址匹配（池子 base / quote mint）
+	baseMint, quoteMint := ix.Accounts[7], ix.Accounts[8]
+	if !((result.UserToPool.Token == baseMint && result.PoolToUser.Token == quoteMint) ||
+		(result.UserToPool.Token == quoteMint && result.PoolToUser.Token == baseMint)) {
+		logger.Errorf("[MeteoraDBC:Swap] mint 不匹配: tx=%s, userToPool=%s, poolToUser=%s, base=%s, quote=%s",
+			ctx.TxHashString(), result.UserToPool.Token, result.PoolToUser.Token, baseMint, quoteMint)
+		return -1
+	}
+
+	// 6. 构建交易事件（quote 由曲线配置确定）
+	tradeEvent := common.BuildTradeEvent(ctx, ix, result.UserToPool, result.PoolToUser, pairAddress, quoteMint, true, consts.DexMeteoraDBC)
+	if tradeEvent == nil {
+		return -1
+	}
+
+	ctx.AddEvent(tradeEvent)
+	return max(result.MaxIndex, eventIndex) + 1
+}
@@ -0,0 +1,180 @@
+package eventparser
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/meteoradbc.json
+//
+//	tx0：Swap（ExactIn），WSOL 买入
+//	tx1：Swap2（ExactOut），卖出换取指定数量 WSOL
+//	tx2：MigrationDammV2 毕业迁移
+//	tx3：InitializeVirtualPoolWithSplToken 建池发币
+//	tx4：InitializeVirtualPoolWithToken2022 建池发币
+//	tx5：建池事件中的 base mint 与指令不一致
+//	tx6：Swap 事件中的 Pool 与指令不一致
+//	tx7：Swap 转出的 token 与池子 base mint 不一致
+//	tx8：MigrationDammV2，来源池 vault 不由 Pool Authority 持有
+var dbcPoolAuthority = types.PubkeyFromBase58("FhVo3mqL8PW5pH5U2CN4XE33DokiyZnUwuGpH2hmHLuM")
+
+func TestMeteoraDBCSwap(t *testing.T) {
+	tests := []struct {
+		name        string
+		index       int
+		side        pb.EventType
+		tokenAmount uint64
+		quoteAmount uint64
+		pairBase    uint64
+		pairQuote   uint64
+	}{
+		{"swap_buy", 0, pb.EventType_TRADE_BUY, 12_000_000_000_000, 500_000_000, 788_000_000_000_000, 3_500_000_000},
+		{"swap2_sell", 1, pb.EventType_TRADE_SELL, 4_900_000_000_000, 200_000_000, 792_900_000_000_000, 3_300_000_000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, events := extractFixture(t, "meteoradbc.json", tt.index)
+
+			ts := trades(events)
+			require.Len(t, ts, 1)
+			trade := ts[0]
+			assert.Equal(t, tt.side, trade.Type)
+			assert.Equal(t, eventID(tx, 0, 0), trade.EventId)
+			assert.Equal(t, uint32(consts.DexMeteoraDBC), trade.Dex)
+			assert.Equal(t, testfixture.Bytes("dbc:pool"), trade.PairAddress)
+			assert.Equal(t, testfixture.Bytes("dbc:trader"), trade.UserWallet)
+			assert.Equal(t, testfixture.Bytes("dbc:mint"), trade.Token)
+			assert.Equal(t, consts.WSOLMint[:], trade.QuoteToken)
+			assert.Equal(t, tt.tokenAmount, trade.TokenAmount)
+			assert.Equal(t, tt.quoteAmount, trade.QuoteTokenAmount)
+			assert.Equal(t, testfixture.Bytes("dbc:base_vault"), trade.TokenAccount)
+			assert.Equal(t, testfixture.Bytes("dbc:quote_vault"), trade.QuoteTokenAccount)
+			assert.Equal(t, tt.pairBase, trade.PairTokenBalance)
+			assert.Equal(t, tt.pairQuote, trade.PairQuoteBalance)
+			assert.Equal(t, uint32(6), trade.TokenDecimals)
+			assert.Equal(t, uint32(9), trade.QuoteDecimals)
+		})
+	}
+}
+
+func TestMeteoraDBCSwap_Rejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		index int
+	}{
+		{"event_pool_mismatch", 6},
+		{"mint_mismatch", 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, events := extractFixture(t, "meteoradbc.json", tt.index)
+			assert.Empty(t, trades(events))
+			assert.Len(t, eventsOfType(events, pb.EventType_TRANSFER), 2, "转账仍单独记录")
+		})
+	}
+}
+
+func TestMeteoraDBCCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		index        int
+		prefix       string
+		tokenProgram pb.TokenProgramType
+		tokenName    string
+		symbol       string
+		uri          string
+	}{
+		{"spl_token", 3, "dbc:spl_", pb.TokenProgramType_TOKEN_SPL, "Curve Test", "CURVE", "https://example.com/curve.json"},
+		{"token_2022", 4, "dbc:t22_", pb.TokenProgramType_TOKEN_2022, "Curve 2022", "C22", "https://example.com/c22.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, events := extractFixture(t, "meteoradbc.json", tt.index)
+			require.Len(t, events, 3)
+
+			pools := liquidityEvents(events, pb.EventType_CREATE_POOL)
+			require.Len(t, pools, 1)
+			pool := pools[0]
+			assert.Equal(t, eventID(tx, 0, 0), pool.EventId)
+			assert.Equal(t, uint32(consts.DexMeteoraDBC), pool.Dex)
+			assert.Equal(t, testfixture.Bytes("dbc:creator"), pool.UserWallet)
+			assert.Equal(t, testfixture.Bytes(tt.prefix+"pool"), pool.PairAddress)
+			assert.Equal(t, testfixture.Bytes(tt.prefix+"mint"), pool.Token)
+			assert.Equal(t, consts.WSOLMint[:], pool.QuoteToken)
+			assert.Equal(t, testfixture.Bytes(tt.prefix+"base_vault"), pool.TokenAccount)
+			assert.Equal(t, testfixture.Bytes(tt.prefix+"quote_vault"), pool.QuoteTokenAccount)
+			assert.Equal(t, dbcPoolAuthority[:], pool.TokenAccountOwner, "vault 由 Pool Authority PDA 持有")
+			assert.Equal(t, uint64(1_000_000_000_000_000), pool.PairTokenBalance)
+			assert.Zero(t, pool.PairQuoteBalance)
+			assert.Equal(t, tt.tokenProgram, pool.TokenProgram)
+			assert.Equal(t, pb.TokenProgramType_TOKEN_SPL, pool.QuoteTokenProgram)
+
+			tokens := eventsOfType(events, pb.EventType_LAUNCHPAD_TOKEN)
+			require.Len(t, tokens, 1)
+			token := tokens[0].Event.GetToken()
+			assert.Equal(t, pool.EventId+1, token.EventId)
+			assert.Equal(t, testfixture.Bytes("dbc:creator"), token.Creator)
+			assert.Equal(t, testfixture.Bytes(tt.prefix+"mint"), token.Token)
+			assert.Equal(t, uint64(1_000_000_000_000_000), token.TotalSupply)
+			assert.Equal(t, uint32(6), token.Decimals)
+			assert.Equal(t, tt.tokenName, token.Name)
+			assert.Equal(t, tt.symbol, token.Symbol)
+			assert.Equal(t, tt.uri, token.Uri)
+			assert.Equal(t, tt.tokenProgram, token.TokenProgram)
+
+			adds := liquidityEvents(events, pb.EventType_ADD_LIQUIDITY)
+			require.Len(t, adds, 1)
+			assert.Equal(t, pool.EventId+2, adds[0].EventId)
+			assert.Equal(t, uint64(1_000_000_000_000_000), adds[0].TokenAmount)
+			assert.Zero(t, adds[0].QuoteTokenAmount)
+		})
+	}
+}
+
+func TestMeteoraDBCCreate_EventMismatch(t *testing.T) {
+	_, events := extractFixture(t, "meteoradbc.json", 5)
+	assert.Empty(t, events, "事件 base mint 与指令不一致时不生成建池事件")
+}
+
+func TestMeteoraDBCMigrateDammV2(t *testing.T) {
+	tx, events := extractFixture(t, "meteoradbc.json", 2)
+
+	migrates := eventsOfType(events, pb.EventType_MIGRATE)
+	require.Len(t, migrates, 1)
+	migrate := migrates[0].Event.GetMigrate()
+	assert.Equal(t, eventID(tx, 0, 0), migrate.EventId)
+	assert.Equal(t, testfixture.Bytes("dbc:pool"), migrates[0].Key, "分区 Key 为来源池")
+	assert.Equal(t, uint32(consts.DexMeteoraDBC), migrate.SrcDex)
+	assert.Equal(t, uint32(consts.DexMeteoraDAMM), migrate.DestDex)
+	assert.Equal(t, testfixture.Bytes("dbc:migrator"), migrate.UserWallet)
+	assert.Equal(t, dbcPoolAuthority[:], migrate.DestPoolCreator)
+	assert.Equal(t, testfixture.Bytes("dbc:pool"), migrate.SrcPairAddress)
+	assert.Equal(t, testfixture.Bytes("dbc:damm_pool"), migrate.DestPairAddress)
+	assert.Equal(t, testfixture.Bytes("dbc:mint"), migrate.Token)
+	assert.Equal(t, consts.WSOLMint[:], migrate.SrcQuoteToken)
+	assert.Equal(t, consts.WSOLMint[:], migrate.DestQuoteToken)
+	assert.Equal(t, testfixture.Bytes("dbc:base_vault"), migrate.SrcTokenAccount)
+	assert.Equal(t, testfixture.Bytes("dbc:damm_vault_a"), migrate.DestTokenAccount)
+	assert.Equal(t, testfixture.Bytes("dbc:damm_vault_b"), migrate.DestQuoteTokenAccount)
+
+	// 注入数量取目标池 vault 的增加量，来源池 quote 减少量中未注入的部分为迁移费用
+	assert.Equal(t, uint64(200_000_000_000_000), migrate.TokenAmount)
+	assert.Equal(t, uint64(84_000_000_000), migrate.QuoteTokenAmount)
+	assert.Equal(t, uint64(1_000_000_000), migrate.MigrationFee)
+	assert.Zero(t, migrate.SrcPairTokenBalance)
+	assert.Zero(t, migrate.SrcPairQuoteBalance)
+	assert.Equal(t, uint64(84_000_000_000), migrate.DestPairQuoteBalance)
+	assert.Equal(t, uint32(6), migrate.TokenDecimals)
+	assert.Equal(t, uint32(9), migrate.QuoteDecimals)
+}
+
+func TestMeteoraDBCMigrate_VaultOwnerMismatch(t *testing.T) {
+	_, events := extractFixture(t, "meteoradbc.json", 8)
+	assert.Empty(t, eventsOfType(events, pb.EventType_MIGRATE), "来源池 vault 必须由 Pool Authority PDA 持有")
+}
@@ -46,6 +46,35 @@ func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
 	m[consts.MeteoraDLMMProgram] = handleInstruction
 }
 
+// RegisterFailedHandlers 注册失败交易中 Swap 指令的意图解析器
+func RegisterFailedHandlers(m map[types.Pubkey]common.FailedTradeHandler) {
+	m[consts.MeteoraDLMMProgram] = failedSwapTable.Extract
+}
+
+// failedSwapTable 失败交易中 Swap 系列指令的布局（账户 #0~#7 见 extractSwapEvent，#10 为用户钱包）
+//   - Swap / Swap2:                 [8:16] amount_in，[16:24] min_amount_out
+//   - SwapExactOut / SwapExactOut2: [8:16] max_in_amount，[16:24] out_amount
+//   - SwapWithPriceImpact2:         [8:16] amount_in（无最小输出，AmountOut 为 0）
+var failedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexMeteoraDLMM,
+	DiscriminatorLen: 8,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		Swap:                 dlmmFailedSwapLayout(16, false),
+		Swap2:                dlmmFailedSwapLayout(16, false),
+		SwapExactOut:         dlmmFailedSwapLayout(16, true),
+		SwapExactOut2:        dlmmFailedSwapLayout(16, true),
+		SwapWithPriceImpact2: dlmmFailedSwapLayout(0, false),
+	},
+}
+
+func dlmmFailedSwapLayout(amountOut int, exactOut bool) common.FailedSwapLayout {
+	return common.FailedSwapLayout{
+		MinAccounts: 11,
+		Pair:        0, Wallet: 10, UserSource: 4, UserDest: 5, InputMint: common.NoAccount, OutputMint: common.NoAccount,
+		AmountIn: 8, AmountOut: amountOut, ExactOut: exactOut,
+	}
+}
+
 func handleInstruction(
 	ctx *common.ParserContext,
 	instrs []*core.AdaptedInstruction,
@@ -0,0 +1,68 @@
+package openbookv2
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/pb"
+	"runtime/debug"
+)
+
+// placeOrderSideOffset 为 PlaceOrderArgs / PlaceOrderPeggedArgs 中 side 字段的偏移（紧跟 8 字节方法 ID）
+const placeOrderSideOffset = 8
+
+// extractPlaceOrderFills 解析 OpenBook v2 PlaceOrder / PlaceOrderPegged 指令中穿价成交的 FillLog，
+// 输出 ORDERBOOK_FILL 事件；未穿价（只挂单）时没有 FillLog，不输出事件。
+//
+// #0  - Signer（OpenOrders 账户的 owner 或 delegate）
+// #1  - Open Orders Account（taker 的 OpenOrders 账户）
+// #2  - Open Orders Admin（可选）
+// #3  - User Token Account
+// #4  - Market（市场地址，作为 PairAddress）
+// #5  - Bids
+// #6  - Asks
+// #7  - Event Heap
+// #8  - Market Vault
+// #9  - Oracle A（可选）
+// #10 - Oracle B（可选）
+// #11 - Token Program
+func extractPlaceOrderFills(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) (next int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[OpenBookV2:PlaceOrder] panic: %v, stack=%s, tx=%s", r, debug.Stack(), ctx.TxHashString())
+			next = -1
+		}
+	}()
+
+	ix := instrs[current]
+
+	// 1. 校验指令结构
+	if len(ix.Accounts) < 12 || len(ix.Data) <= placeOrderSideOffset {
+		logger.Errorf("[OpenBookV2:PlaceOrder] 指令长度不足: accounts=%d, data=%d, tx=%s", len(ix.Accounts), len(ix.Data), ctx.TxHashString())
+		return -1
+	}
+	takerSide := ix.Data[placeOrderSideOffset]
+	if takerSide != sideBid && takerSide != sideAsk {
+		return -1
+	}
+
+	// 2. 解析逐笔成交
+	pairAddress := ix.Accounts[4]
+	fills := parseFillLogs(ctx.ProgramData(instrs, current), pairAddress, takerSide)
+	if len(fills) == 0 {
+		return -1
+	}
+
+	// 3. 构建成交事件：资金计入 OpenOrders 账户，没有可用于还原总额的转账（子指令中的转账照常解析为 TransferEvent）
+	side := pb.EventType_TRADE_BUY
+	if takerSide == sideAsk {
+		side = pb.EventType_TRADE_SELL
+	}
+	ctx.AddEvent(common.BuildOrderbookFillEvent(ctx, ix, pairAddress, ix.Accounts[0], ix.Accounts[1], side, fills, consts.DexOpenBookV2))
+	return current + 1
+}
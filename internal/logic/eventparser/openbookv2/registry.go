@@ -0,0 +1,46 @@
+package openbookv2
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+)
+
+// OpenBook v2 指令方法ID
+const (
+	PlaceTakeOrder   uint64 = 0x032c47031ac7cb55
+	PlaceOrder       uint64 = 0x33c29baf6d82606a
+	PlaceOrderPegged uint64 = 0x8db9fb3f4a55d291
+)
+
+// RegisterHandlers 注册 OpenBook v2 Program 的指令解析器
+func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
+	m[consts.OpenBookV2Program] = handleInstruction
+}
+
+// handleInstruction 解析 PlaceTakeOrder 与 PlaceOrder / PlaceOrderPegged。
+// PlaceOrder 系列的成交计入 OpenOrders 账户、待 SettleFunds 时才转账，且指令只包含单侧 vault，
+// 无法确定成交数量与另一侧 mint，只根据 FillLog 输出穿价部分的逐笔成交。
+func handleInstruction(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	// 指令 data 至少应包含 8 字节方法 ID
+	if len(ix.Data) < 8 {
+		return -1
+	}
+
+	switch binary.BigEndian.Uint64(ix.Data[:8]) {
+	case PlaceTakeOrder:
+		return extractTakeOrderEvent(ctx, instrs, current)
+	case PlaceOrder, PlaceOrderPegged:
+		return extractPlaceOrderFills(ctx, instrs, current)
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,156 @@
+package openbookv2
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+	"github.com/near/borsh-go"
+	"math/bits"
+	"runtime/debug"
+)
+
+// FillLogSign 为 FillLog 的事件 discriminator：sha256("event:FillLog")[:8]
+const FillLogSign uint64 = 0x9617299498a2d740
+
+const (
+	sideBid = 0
+	sideAsk = 1
+)
+
+// FillLog 由 OpenBook v2 在撮合时通过 sol_log_data 输出（"Program data:" 日志），每笔 maker 成交一条
+type FillLog struct {
+	Sign               uint64
+	Market             types.Pubkey
+	TakerSide          uint8 // taker 方向：0 = Bid（买入 base），1 = Ask（卖出 base）
+	MakerSlot          uint8
+	MakerOut           bool
+	Timestamp          uint64
+	SeqNum             uint64
+	Maker              types.Pubkey // maker 的 OpenOrders 账户
+	MakerClientOrderID uint64
+	MakerFee           uint64
+	MakerTimestamp     uint64
+	Taker              types.Pubkey
+	TakerClientOrderID uint64
+	TakerFeeCeil       uint64
+	Price              int64 // 每 base lot 的 quote lots
+	Quantity           int64 // base lots
+}
+
+// extractTakeOrderEvent 解析 OpenBook v2 PlaceTakeOrder 指令：taker 的转账构成 TradeEvent 的总数量，
+// 指令输出的 FillLog 构成逐笔成交（maker 的 OpenOrders 账户、价格、base lots）。
+//
+// #0  - Signer（taker 钱包）
+// #1  - Penalty Payer
+// #2  - Market（市场地址，作为 PairAddress）
+// #3  - Market Authority
+// #4  - Bids
+// #5  - Asks
+// #6  - Market Base Vault
+// #7  - Market Quote Vault
+// #8  - Event Heap
+// #9  - User Base Account
+// #10 - User Quote Account
+// #11 - Oracle A（可选）
+// #12 - Oracle B（可选）
+// #13 - Token Program
+// #14 - System Program
+// #15 - Open Orders Admin（可选）
+func extractTakeOrderEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) (next int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[OpenBookV2:TakeOrder] panic: %v, stack=%s, tx=%s", r, debug.Stack(), ctx.TxHashString())
+			next = -1
+		}
+	}()
+
+	ix := instrs[current]
+
+	// 1. 校验指令结构
+	if len(ix.Accounts) < 14 {
+		logger.Errorf("[OpenBookV2:TakeOrder] 指令账户长度不足: got=%d, expect>=14, tx=%s", len(ix.Accounts), ctx.TxHashString())
+		return -1
+	}
+
+	// 2. 查找 taker 与 vault 之间的转账（未成交的订单没有转账）
+	result := common.FindSwapTransfersByIndex(ctx, instrs, current, &common.SwapInstructionIndex{
+		UserToken1AccountIndex: 9,
+		UserToken2AccountIndex: 10,
+		PoolToken1AccountIndex: 6,
+		PoolToken2AccountIndex: 7,
+	}, 0)
+	if result == nil {
+		return -1
+	}
+
+	// 3. quote 为市场的 quote vault 持有的 token
+	quoteVault := ix.Accounts[7]
+	quoteVaultBalance, ok := ctx.Balances[quoteVault]
+	if !ok {
+		logger.Errorf("[OpenBookV2:TakeOrder] 缺失 quote vault 余额: account=%s, tx=%s", quoteVault, ctx.TxHashString())
+		return -1
+	}
+	quote := quoteVaultBalance.Token
+	if result.UserToPool.Token != quote && result.PoolToUser.Token != quote {
+		logger.Errorf("[OpenBookV2:TakeOrder] 转账与 quote 不匹配: userToPool=%s, poolToUser=%s, quote=%s, tx=%s",
+			result.UserToPool.Token, result.PoolToUser.Token, quote, ctx.TxHashString())
+		return -1
+	}
+
+	// 4. 构建 taker 的交易事件
+	pairAddress := ix.Accounts[2]
+	event := common.BuildTradeEvent(ctx, ix, result.UserToPool, result.PoolToUser, pairAddress, quote, true, consts.DexOpenBookV2)
+	if event == nil {
+		return -1
+	}
+
+	// 5. 解析逐笔成交；日志缺失（数据源未提供或被截断）时仍输出 taker 的总成交
+	takerSide := uint8(sideAsk)
+	if result.UserToPool.Token == quote {
+		takerSide = sideBid
+	}
+	if fills := parseFillLogs(ctx.ProgramData(instrs, current), pairAddress, takerSide); len(fills) > 0 {
+		if !common.AttachOrderbookFills(event, fills) {
+			logger.Warnf("[OpenBookV2:TakeOrder] 成交明细无法分摊: fills=%d, tx=%s", len(fills), ctx.TxHashString())
+		}
+	}
+
+	ctx.AddEvent(event)
+	return result.MaxIndex + 1
+}
+
+// parseFillLogs 从指令输出的日志中提取属于该市场的 FillLog，任一记录与市场或 taker 方向不一致时放弃全部明细
+func parseFillLogs(logs [][]byte, market types.Pubkey, takerSide uint8) []*common.OrderbookFill {
+	var fills []*common.OrderbookFill
+	for _, data := range logs {
+		if len(data) < 8 || binary.BigEndian.Uint64(data[:8]) != FillLogSign {
+			continue
+		}
+		fillLog := FillLog{}
+		if err := borsh.Deserialize(&fillLog, data); err != nil {
+			return nil
+		}
+		if fillLog.Market != market || fillLog.TakerSide != takerSide || fillLog.Price <= 0 || fillLog.Quantity <= 0 {
+			return nil
+		}
+		hi, quoteLots := bits.Mul64(uint64(fillLog.Price), uint64(fillLog.Quantity))
+		if hi != 0 {
+			return nil
+		}
+		// FillLog 只有 maker 的 OpenOrders 账户，其 owner 保存在账户数据中，交易内无法确定 maker 钱包
+		fills = append(fills, &common.OrderbookFill{
+			MakerOpenOrders: fillLog.Maker,
+			PriceInTicks:    uint64(fillLog.Price),
+			BaseLots:        uint64(fillLog.Quantity),
+			QuoteLots:       quoteLots,
+		})
+	}
+	return fills
+}
@@ -0,0 +1,137 @@
+package eventparser
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/openbookv2.json
+//
+//	tx0：PlaceTakeOrder，taker 以 USDC 买入，与两个 maker 成交（FillLog 位于 "Program data:" 日志）
+//	tx1：PlaceOrder 限价卖单穿价，与一个 maker 成交后剩余部分挂单
+//	tx2：PlaceTakeOrder，其中一条 FillLog 属于其它市场
+//	tx3：PlaceTakeOrder，FillLog 的 taker 方向与转账方向不一致
+//	tx4：PlaceOrder 未穿价，只挂单
+//	tx5：PlaceOrderPegged 穿价，与两个 maker 成交
+
+func TestOpenBookV2PlaceTakeOrder(t *testing.T) {
+	tx, events := extractFixture(t, "openbookv2.json", 0)
+
+	ts := trades(events)
+	require.Len(t, ts, 1)
+	trade := ts[0]
+	assert.Equal(t, pb.EventType_TRADE_BUY, trade.Type)
+	assert.Equal(t, eventID(tx, 0, 0), trade.EventId)
+	assert.Equal(t, uint32(consts.DexOpenBookV2), trade.Dex)
+	assert.Equal(t, testfixture.Bytes("ob:market"), trade.PairAddress)
+	assert.Equal(t, testfixture.Bytes("ob:taker"), trade.UserWallet)
+	assert.Equal(t, testfixture.Bytes("ob:mint"), trade.Token)
+	assert.Equal(t, consts.USDCMint[:], trade.QuoteToken)
+	assert.Equal(t, uint64(5_000_000), trade.TokenAmount)
+	assert.Equal(t, uint64(50_200_000), trade.QuoteTokenAmount)
+	assert.Equal(t, testfixture.Bytes("ob:base_vault"), trade.TokenAccount)
+	assert.Equal(t, testfixture.Bytes("ob:quote_vault"), trade.QuoteTokenAccount)
+
+	// 逐笔成交按 base lots / quote lots 分摊 taker 的总成交；maker 钱包未知，只输出 OpenOrders 账户
+	require.Len(t, trade.Fills, 2)
+	assert.Empty(t, trade.Fills[0].Maker)
+	assert.Equal(t, testfixture.Bytes("ob:maker_oo1"), trade.Fills[0].MakerOpenOrders)
+	assert.Equal(t, uint64(100), trade.Fills[0].PriceInTicks)
+	assert.Equal(t, uint64(3), trade.Fills[0].BaseLots)
+	assert.Equal(t, uint64(300), trade.Fills[0].QuoteLots)
+	assert.Equal(t, uint64(3_000_000), trade.Fills[0].TokenAmount)
+	assert.Equal(t, uint64(30_000_000), trade.Fills[0].QuoteTokenAmount)
+	assert.Empty(t, trade.Fills[1].Maker)
+	assert.Equal(t, testfixture.Bytes("ob:maker_oo2"), trade.Fills[1].MakerOpenOrders)
+	assert.Equal(t, uint64(101), trade.Fills[1].PriceInTicks)
+	assert.Equal(t, uint64(2), trade.Fills[1].BaseLots)
+	assert.Equal(t, uint64(202), trade.Fills[1].QuoteLots)
+	assert.Equal(t, uint64(2_000_000), trade.Fills[1].TokenAmount)
+	assert.Equal(t, uint64(20_200_000), trade.Fills[1].QuoteTokenAmount)
+
+	assert.Empty(t, eventsOfType(events, pb.EventType_ORDERBOOK_FILL))
+}
+
+// FillLog 与市场或 taker 方向不一致时放弃全部明细，仍输出 taker 的总成交
+func TestOpenBookV2PlaceTakeOrder_FillsDropped(t *testing.T) {
+	tests := []struct {
+		name  string
+		index int
+	}{
+		{"other_market", 2},
+		{"side_mismatch", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, events := extractFixture(t, "openbookv2.json", tt.index)
+
+			ts := trades(events)
+			require.Len(t, ts, 1)
+			assert.Equal(t, pb.EventType_TRADE_BUY, ts[0].Type)
+			assert.Equal(t, uint64(5_000_000), ts[0].TokenAmount)
+			assert.Equal(t, uint64(50_200_000), ts[0].QuoteTokenAmount)
+			assert.Empty(t, ts[0].Fills)
+		})
+	}
+}
+
+func TestOpenBookV2PlaceOrderCrossing(t *testing.T) {
+	tx, events := extractFixture(t, "openbookv2.json", 1)
+
+	// 成交计入 OpenOrders 账户，没有可还原总额的转账，不输出 TradeEvent
+	assert.Empty(t, trades(events))
+
+	fillEvents := eventsOfType(events, pb.EventType_ORDERBOOK_FILL)
+	require.Len(t, fillEvents, 1)
+	assert.Equal(t, testfixture.Bytes("ob:market"), fillEvents[0].Key)
+	fill := fillEvents[0].Event.GetOrderbookFill()
+	assert.Equal(t, eventID(tx, 0, 0), fill.EventId)
+	assert.Equal(t, uint32(consts.DexOpenBookV2), fill.Dex)
+	assert.Equal(t, testfixture.Bytes("ob:market"), fill.PairAddress)
+	assert.Equal(t, testfixture.Bytes("ob:taker"), fill.UserWallet)
+	assert.Equal(t, testfixture.Bytes("ob:taker_oo"), fill.TakerOpenOrders)
+	assert.Equal(t, pb.EventType_TRADE_SELL, fill.Side)
+	require.NotNil(t, fill.TxFee)
+
+	require.Len(t, fill.Fills, 1)
+	assert.Empty(t, fill.Fills[0].Maker)
+	assert.Equal(t, testfixture.Bytes("ob:maker_oo1"), fill.Fills[0].MakerOpenOrders)
+	assert.Equal(t, uint64(100), fill.Fills[0].PriceInTicks)
+	assert.Equal(t, uint64(3), fill.Fills[0].BaseLots)
+	assert.Equal(t, uint64(300), fill.Fills[0].QuoteLots)
+	assert.Zero(t, fill.Fills[0].TokenAmount)
+	assert.Zero(t, fill.Fills[0].QuoteTokenAmount)
+
+	// 挂单资金转入 vault 的子指令照常解析为 TransferEvent
+	transfers := eventsOfType(events, pb.EventType_TRANSFER)
+	require.Len(t, transfers, 1)
+	assert.Equal(t, testfixture.Bytes("ob:base_vault"), transfers[0].Event.GetTransfer().DestAccount)
+}
+
+func TestOpenBookV2PlaceOrder_PostOnly(t *testing.T) {
+	_, events := extractFixture(t, "openbookv2.json", 4)
+	assert.Empty(t, eventsOfType(events, pb.EventType_ORDERBOOK_FILL), "未穿价时没有 FillLog")
+	assert.Empty(t, trades(events))
+	assert.Len(t, eventsOfType(events, pb.EventType_TRANSFER), 1)
+}
+
+func TestOpenBookV2PlaceOrderPegged(t *testing.T) {
+	_, events := extractFixture(t, "openbookv2.json", 5)
+
+	fillEvents := eventsOfType(events, pb.EventType_ORDERBOOK_FILL)
+	require.Len(t, fillEvents, 1)
+	fill := fillEvents[0].Event.GetOrderbookFill()
+	assert.Equal(t, pb.EventType_TRADE_SELL, fill.Side)
+	require.Len(t, fill.Fills, 2)
+	assert.Equal(t, testfixture.Bytes("ob:maker_oo1"), fill.Fills[0].MakerOpenOrders)
+	assert.Equal(t, uint64(100), fill.Fills[0].QuoteLots)
+	assert.Equal(t, testfixture.Bytes("ob:maker_oo2"), fill.Fills[1].MakerOpenOrders)
+	assert.Equal(t, uint64(98), fill.Fills[1].PriceInTicks)
+	assert.Equal(t, uint64(2), fill.Fills[1].BaseLots)
+	assert.Equal(t, uint64(196), fill.Fills[1].QuoteLots)
+}
@@ -31,6 +31,30 @@ func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
 	m[consts.OrcaWhirlpoolProgram] = handleInstruction
 }
 
+// RegisterFailedHandlers 注册失败交易中 Swap 指令的意图解析器
+func RegisterFailedHandlers(m map[types.Pubkey]common.FailedTradeHandler) {
+	m[consts.OrcaWhirlpoolProgram] = failedSwapTable.Extract
+}
+
+// failedSwapTable 失败交易中 Swap / Swap2 指令的布局（账户布局见 extractSwapEvent / extractSwap2Event，source / dest 按 a → b 填写）
+// 指令数据：[8:16] amount，[16:24] other_amount_threshold，[24:40] sqrt_price_limit，[40] amount_specified_is_input，[41] a_to_b
+var failedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexOrcaWhirlpool,
+	DiscriminatorLen: 8,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		Swap: {
+			MinAccounts: 7,
+			Pair:        2, Wallet: 1, UserSource: 3, UserDest: 5, InputMint: common.NoAccount, OutputMint: common.NoAccount,
+			AmountIn: 8, AmountOut: 16, ExactInFlag: 40, AToBFlag: 41,
+		},
+		Swap2: {
+			MinAccounts: 11,
+			Pair:        4, Wallet: 3, UserSource: 7, UserDest: 9, InputMint: 5, OutputMint: 6,
+			AmountIn: 8, AmountOut: 16, ExactInFlag: 40, AToBFlag: 41,
+		},
+	},
+}
+
 func handleInstruction(
 	ctx *common.ParserContext,
 	instrs []*core.AdaptedInstruction,
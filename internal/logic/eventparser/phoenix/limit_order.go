@@ -0,0 +1,83 @@
+package phoenix
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/pb"
+	"runtime/debug"
+)
+
+// OrderPacket 布局（borsh 枚举）：[1] 订单类型（0 = PostOnly，1 = Limit，2 = ImmediateOrCancel）  [2] side（0 = Bid，1 = Ask）
+const (
+	orderPacketPostOnly = 0
+	orderPacketSide     = 2
+
+	sideBid = 0
+	sideAsk = 1
+)
+
+// extractLimitOrderFills 解析 Phoenix PlaceLimitOrder / PlaceLimitOrderWithFreeFunds 指令中穿价成交的 Fill 事件，
+// 输出 ORDERBOOK_FILL 事件；PostOnly 或未穿价时没有 Fill，不输出事件。
+// FillSummary 与 Fill 一致时按比例拆分每笔成交的 quote lots，否则 quote lots 为 0。
+//
+// #0 - Phoenix Program
+// #1 - Log Authority（Log 指令的第 0 个账户）
+// #2 - Market（市场地址，作为 PairAddress）
+// #3 - Trader（Signer）
+// #4 - Seat
+// ...（PlaceLimitOrder 另含用户 token 账户、vault 与 Token Program）
+func extractLimitOrderFills(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) (next int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[Phoenix:PlaceLimitOrder] panic: %v, stack=%s, tx=%s", r, debug.Stack(), ctx.TxHashString())
+			next = -1
+		}
+	}()
+
+	ix := instrs[current]
+
+	// 1. 校验指令结构
+	if len(ix.Accounts) < 5 || len(ix.Data) <= orderPacketSide {
+		logger.Errorf("[Phoenix:PlaceLimitOrder] 指令长度不足: accounts=%d, data=%d, tx=%s", len(ix.Accounts), len(ix.Data), ctx.TxHashString())
+		return -1
+	}
+	if ix.Data[1] == orderPacketPostOnly {
+		return -1
+	}
+	var side pb.EventType
+	switch ix.Data[orderPacketSide] {
+	case sideBid:
+		side = pb.EventType_TRADE_BUY
+	case sideAsk:
+		side = pb.EventType_TRADE_SELL
+	default:
+		return -1
+	}
+
+	// 2. 解析 Log 中的逐笔成交
+	events, _ := collectLogEvents(ctx, instrs, current)
+	if len(events.Fills) == 0 {
+		return -1
+	}
+	pairAddress, trader := ix.Accounts[2], ix.Accounts[3]
+	if events.Market != pairAddress || events.Signer != trader {
+		logger.Warnf("[Phoenix:PlaceLimitOrder] Log 事件与指令不一致: market=%s/%s, signer=%s/%s, tx=%s",
+			events.Market, pairAddress, events.Signer, trader, ctx.TxHashString())
+		return -1
+	}
+	if !splitFillQuoteLots(events) {
+		logger.Warnf("[Phoenix:PlaceLimitOrder] 成交 quote lots 无法拆分: fills=%d, summary=%v, tx=%s",
+			len(events.Fills), events.HasSummary, ctx.TxHashString())
+	}
+
+	// 3. 构建成交事件：资金包含挂单部分或来自 seat，没有可用于还原总额的转账（子指令中的转账照常解析为 TransferEvent）
+	ctx.AddEvent(common.BuildOrderbookFillEvent(ctx, ix, pairAddress, trader, types.Pubkey{}, side, events.Fills, consts.DexPhoenix))
+	return current + 1
+}
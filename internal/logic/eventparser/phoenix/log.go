@@ -0,0 +1,123 @@
+package phoenix
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+)
+
+// 市场事件类型（PhoenixMarketEvent 的 borsh 枚举 tag）
+// 来源：https://github.com/Ellipsis-Labs/phoenix-v1/blob/master/src/program/events.rs
+const (
+	eventHeader       = 1
+	eventFill         = 2
+	eventPlace        = 3
+	eventReduce       = 4
+	eventEvict        = 5
+	eventFillSummary  = 6
+	eventFee          = 7
+	eventTimeInForce  = 8
+	eventExpiredOrder = 9
+)
+
+// eventPayloadSizes 为各类型事件去掉 tag 后的定长字节数
+var eventPayloadSizes = map[byte]int{
+	eventFill:         66, // index(2) + maker_id(32) + order_sequence_number(8) + price_in_ticks(8) + base_lots_filled(8) + base_lots_remaining(8)
+	eventPlace:        42, // index(2) + order_sequence_number(8) + client_order_id(16) + price_in_ticks(8) + base_lots_placed(8)
+	eventReduce:       34, // index(2) + order_sequence_number(8) + price_in_ticks(8) + base_lots_removed(8) + base_lots_remaining(8)
+	eventEvict:        58, // index(2) + maker_id(32) + order_sequence_number(8) + price_in_ticks(8) + base_lots_evicted(8)
+	eventFillSummary:  42, // index(2) + client_order_id(16) + total_base_lots_filled(8) + total_quote_lots_filled(8) + total_fee_in_quote_lots(8)
+	eventFee:          10, // index(2) + fees_collected_in_quote_lots(8)
+	eventTimeInForce:  26, // index(2) + order_sequence_number(8) + last_valid_slot(8) + last_valid_unix_timestamp_in_seconds(8)
+	eventExpiredOrder: 58, // index(2) + maker_id(32) + order_sequence_number(8) + price_in_ticks(8) + base_lots_removed(8)
+}
+
+// Log 指令 data 布局：
+//
+//	[0]      Log 指令 tag（15）
+//	[1]      Header 事件 tag（1）
+//	[2:93]   AuditLogHeader：instruction(1) + sequence_number(8) + timestamp(8) + slot(8) + market(32) + signer(32) + total_events(2)
+//	[93:]    依次排列的事件（tag + 定长内容）
+const (
+	logHeaderEnd    = 93
+	logMarketOffset = 27
+	logSignerOffset = 59
+)
+
+// marketEvents 为一次指令输出的市场事件中与成交相关的部分
+type marketEvents struct {
+	Market               types.Pubkey
+	Signer               types.Pubkey
+	Fills                []*common.OrderbookFill
+	TotalBaseLotsFilled  uint64
+	TotalQuoteLotsFilled uint64
+	HasSummary           bool
+}
+
+// collectLogEvents 按执行顺序解析指令通过 self-CPI 输出的全部 Log（第 0 个账户为指令的 Log Authority），
+// 返回累加后的市场事件与最后一条 Log 的下标（没有 Log 时为 current）。任一 Log 解析失败时丢弃全部成交明细。
+func collectLogEvents(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) (*marketEvents, int) {
+	ix := instrs[current]
+	events := &marketEvents{}
+	end := current
+	for i, child := range common.Children(instrs, current) {
+		if child.ProgramID != consts.PhoenixProgram || len(child.Data) == 0 || child.Data[0] != Log ||
+			len(child.Accounts) == 0 || child.Accounts[0] != ix.Accounts[1] {
+			continue
+		}
+		if !parseLogInstruction(child.Data, events) {
+			logger.Warnf("[Phoenix] Log 事件解析失败: tx=%s, ix=%d, inner=%d", ctx.TxHashString(), child.IxIndex, child.InnerIndex)
+			events.Fills = nil
+			break
+		}
+		end = max(end, i)
+	}
+	return events, end
+}
+
+// parseLogInstruction 解析 Log 指令中的事件，将 Fill 与 FillSummary 累加进 events。
+// 事件过多时 Phoenix 会分多次输出 Log，每次都带有 Header。
+func parseLogInstruction(data []byte, events *marketEvents) bool {
+	if len(data) < logHeaderEnd || data[0] != Log || data[1] != eventHeader {
+		return false
+	}
+
+	var market, signer types.Pubkey
+	copy(market[:], data[logMarketOffset:logMarketOffset+32])
+	copy(signer[:], data[logSignerOffset:logSignerOffset+32])
+	if events.Market != (types.Pubkey{}) && (events.Market != market || events.Signer != signer) {
+		return false
+	}
+	events.Market, events.Signer = market, signer
+
+	for rest := data[logHeaderEnd:]; len(rest) > 0; {
+		size, ok := eventPayloadSizes[rest[0]]
+		if !ok || len(rest) < 1+size {
+			return false
+		}
+		payload := rest[1 : 1+size]
+
+		switch rest[0] {
+		case eventFill:
+			fill := &common.OrderbookFill{
+				PriceInTicks: binary.LittleEndian.Uint64(payload[42:50]),
+				BaseLots:     binary.LittleEndian.Uint64(payload[50:58]),
+			}
+			copy(fill.Maker[:], payload[2:34])
+			events.Fills = append(events.Fills, fill)
+		case eventFillSummary:
+			events.TotalBaseLotsFilled += binary.LittleEndian.Uint64(payload[18:26])
+			events.TotalQuoteLotsFilled += binary.LittleEndian.Uint64(payload[26:34])
+			events.HasSummary = true
+		}
+		rest = rest[1+size:]
+	}
+	return true
+}
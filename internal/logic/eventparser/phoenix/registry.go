@@ -0,0 +1,44 @@
+package phoenix
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/types"
+)
+
+// Phoenix 指令（1 字节 tag，非 Anchor 程序）
+const (
+	Swap                         uint8 = 0  // taker 订单（IOC），资金直接在用户 token 账户与 vault 间结算
+	PlaceLimitOrder              uint8 = 2  // 限价单，穿价部分立即成交，其余挂单
+	PlaceLimitOrderWithFreeFunds uint8 = 3  // 使用 seat 中存量资金的限价单
+	Log                          uint8 = 15 // 程序通过 self-CPI 输出的市场事件日志
+)
+
+// RegisterHandlers 注册 Phoenix Program 的指令解析器
+func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
+	m[consts.PhoenixProgram] = handleInstruction
+}
+
+// handleInstruction 解析 Swap 与 PlaceLimitOrder / PlaceLimitOrderWithFreeFunds。
+// PlaceLimitOrder 穿价成交时用户转入的资金还包含挂单部分，WithFreeFunds 使用 seat 中的存量资金不产生转账，
+// 均无法按转账还原成交数量，只根据 Log 中的 Fill 事件输出穿价部分的逐笔成交。
+func handleInstruction(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+	if len(ix.Data) < 1 {
+		return -1
+	}
+
+	switch ix.Data[0] {
+	case Swap:
+		return extractSwapEvent(ctx, instrs, current)
+	case PlaceLimitOrder, PlaceLimitOrderWithFreeFunds:
+		return extractLimitOrderFills(ctx, instrs, current)
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,128 @@
+package phoenix
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"math/bits"
+	"runtime/debug"
+)
+
+// extractSwapEvent 解析 Phoenix Swap 指令：taker 的转账构成 TradeEvent 的总数量，
+// self-CPI Log 中的 Fill 事件构成逐笔成交（maker 钱包、价格 ticks、base lots）。
+//
+// #0 - Phoenix Program
+// #1 - Log Authority（Log 指令的第 0 个账户）
+// #2 - Market（市场地址，作为 PairAddress）
+// #3 - Trader（taker 钱包，Signer）
+// #4 - Trader Base Account
+// #5 - Trader Quote Account
+// #6 - Base Vault
+// #7 - Quote Vault
+// #8 - Token Program
+func extractSwapEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) (next int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[Phoenix:Swap] panic: %v, stack=%s, tx=%s", r, debug.Stack(), ctx.TxHashString())
+			next = -1
+		}
+	}()
+
+	ix := instrs[current]
+
+	// 1. 校验指令结构
+	if len(ix.Accounts) < 9 {
+		logger.Errorf("[Phoenix:Swap] 指令账户长度不足: got=%d, expect>=9, tx=%s", len(ix.Accounts), ctx.TxHashString())
+		return -1
+	}
+
+	// 2. 查找 taker 与 vault 之间的转账（未成交的 IOC 订单没有转账）
+	result := common.FindSwapTransfersByIndex(ctx, instrs, current, &common.SwapInstructionIndex{
+		UserToken1AccountIndex: 4,
+		UserToken2AccountIndex: 5,
+		PoolToken1AccountIndex: 6,
+		PoolToken2AccountIndex: 7,
+	}, 0)
+	if result == nil {
+		return -1
+	}
+
+	// 3. quote 为市场的 quote vault 持有的 token
+	quoteVault := ix.Accounts[7]
+	quoteVaultBalance, ok := ctx.Balances[quoteVault]
+	if !ok {
+		logger.Errorf("[Phoenix:Swap] 缺失 quote vault 余额: account=%s, tx=%s", quoteVault, ctx.TxHashString())
+		return -1
+	}
+	quote := quoteVaultBalance.Token
+	if result.UserToPool.Token != quote && result.PoolToUser.Token != quote {
+		logger.Errorf("[Phoenix:Swap] 转账与 quote 不匹配: userToPool=%s, poolToUser=%s, quote=%s, tx=%s",
+			result.UserToPool.Token, result.PoolToUser.Token, quote, ctx.TxHashString())
+		return -1
+	}
+
+	// 4. 构建 taker 的交易事件
+	pairAddress := ix.Accounts[2]
+	event := common.BuildTradeEvent(ctx, ix, result.UserToPool, result.PoolToUser, pairAddress, quote, true, consts.DexPhoenix)
+	if event == nil {
+		return -1
+	}
+
+	// 5. 解析 Log 中的逐笔成交
+	events, logEnd := collectLogEvents(ctx, instrs, current)
+	end := max(result.MaxIndex, logEnd)
+
+	// 6. 校验后写入成交明细；明细不可用时仍输出 taker 的总成交
+	if events.Market != pairAddress || events.Signer != ix.Accounts[3] {
+		logger.Warnf("[Phoenix:Swap] Log 事件与指令不一致: market=%s/%s, signer=%s/%s, tx=%s",
+			events.Market, pairAddress, events.Signer, ix.Accounts[3], ctx.TxHashString())
+	} else if len(events.Fills) > 0 && !attachFills(event, events) {
+		logger.Warnf("[Phoenix:Swap] 成交明细无法分摊: fills=%d, summary=%v, tx=%s",
+			len(events.Fills), events.HasSummary, ctx.TxHashString())
+	}
+
+	ctx.AddEvent(event)
+	return end + 1
+}
+
+// attachFills 拆分每笔成交的 quote lots 后写入 taker 的 TradeEvent
+func attachFills(event *core.Event, events *marketEvents) bool {
+	if !splitFillQuoteLots(events) {
+		return false
+	}
+	return common.AttachOrderbookFills(event, events.Fills)
+}
+
+// splitFillQuoteLots 按 price_in_ticks × base_lots 的比例拆分 FillSummary 的 quote lots 总数，得到每笔成交的 quote lots
+func splitFillQuoteLots(events *marketEvents) bool {
+	if !events.HasSummary {
+		return false
+	}
+
+	weights := make([]uint64, len(events.Fills))
+	var baseLots uint64
+	for i, fill := range events.Fills {
+		hi, lo := bits.Mul64(fill.PriceInTicks, fill.BaseLots)
+		if hi != 0 {
+			return false
+		}
+		weights[i] = lo
+		baseLots += fill.BaseLots
+	}
+	if baseLots != events.TotalBaseLotsFilled {
+		return false // Fill 与 FillSummary 不一致（如 Log 输出不完整）
+	}
+	quoteLots, ok := common.SplitByWeight(events.TotalQuoteLotsFilled, weights)
+	if !ok {
+		return false
+	}
+	for i, fill := range events.Fills {
+		fill.QuoteLots = quoteLots[i]
+	}
+	return true
+}
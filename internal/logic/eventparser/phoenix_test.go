@@ -0,0 +1,128 @@
+package eventparser
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/phoenix.json
+//
+//	tx0：Swap（IOC 卖单），与两个 maker 成交，Fill 与 FillSummary 分两次 Log 输出
+//	tx1：PlaceLimitOrder 限价买单穿价，与一个 maker 成交后剩余部分挂单
+//	tx2：Swap，FillSummary 的 base lots 与 Fill 之和不一致
+//	tx3：Swap，Log Header 中的 market 与指令不一致
+//	tx4：PlaceLimitOrder（PostOnly）只挂单
+//	tx5：PlaceLimitOrderWithFreeFunds 卖单穿价，与两个 maker 成交，没有转账
+
+func TestPhoenixSwap(t *testing.T) {
+	tx, events := extractFixture(t, "phoenix.json", 0)
+
+	ts := trades(events)
+	require.Len(t, ts, 1)
+	trade := ts[0]
+	assert.Equal(t, pb.EventType_TRADE_SELL, trade.Type)
+	assert.Equal(t, eventID(tx, 0, 0), trade.EventId)
+	assert.Equal(t, uint32(consts.DexPhoenix), trade.Dex)
+	assert.Equal(t, testfixture.Bytes("px:market"), trade.PairAddress)
+	assert.Equal(t, testfixture.Bytes("px:trader"), trade.UserWallet)
+	assert.Equal(t, testfixture.Bytes("px:mint"), trade.Token)
+	assert.Equal(t, consts.USDCMint[:], trade.QuoteToken)
+	assert.Equal(t, uint64(1_000_000), trade.TokenAmount)
+	assert.Equal(t, uint64(99_300), trade.QuoteTokenAmount)
+
+	// FillSummary 的 quote lots 按 price × base lots 拆分，再按比例分摊 taker 的总成交
+	require.Len(t, trade.Fills, 2)
+	assert.Equal(t, testfixture.Bytes("px:maker_a"), trade.Fills[0].Maker)
+	assert.Empty(t, trade.Fills[0].MakerOpenOrders)
+	assert.Equal(t, uint64(1000), trade.Fills[0].PriceInTicks)
+	assert.Equal(t, uint64(4), trade.Fills[0].BaseLots)
+	assert.Equal(t, uint64(4000), trade.Fills[0].QuoteLots)
+	assert.Equal(t, uint64(400_000), trade.Fills[0].TokenAmount)
+	assert.Equal(t, uint64(39_959), trade.Fills[0].QuoteTokenAmount)
+	assert.Equal(t, testfixture.Bytes("px:maker_b"), trade.Fills[1].Maker)
+	assert.Equal(t, uint64(990), trade.Fills[1].PriceInTicks)
+	assert.Equal(t, uint64(6), trade.Fills[1].BaseLots)
+	assert.Equal(t, uint64(5940), trade.Fills[1].QuoteLots)
+	assert.Equal(t, uint64(600_000), trade.Fills[1].TokenAmount)
+	assert.Equal(t, uint64(59_341), trade.Fills[1].QuoteTokenAmount)
+
+	assert.Empty(t, eventsOfType(events, pb.EventType_ORDERBOOK_FILL))
+}
+
+// 成交明细不可用时仍输出 taker 的总成交，不输出明细
+func TestPhoenixSwap_FillsDropped(t *testing.T) {
+	tests := []struct {
+		name  string
+		index int
+	}{
+		{"summary_mismatch", 2},
+		{"other_market", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, events := extractFixture(t, "phoenix.json", tt.index)
+
+			ts := trades(events)
+			require.Len(t, ts, 1)
+			assert.Equal(t, pb.EventType_TRADE_SELL, ts[0].Type)
+			assert.Equal(t, testfixture.Bytes("px:market"), ts[0].PairAddress)
+			assert.Equal(t, uint64(1_000_000), ts[0].TokenAmount)
+			assert.Equal(t, uint64(99_300), ts[0].QuoteTokenAmount)
+			assert.Empty(t, ts[0].Fills)
+		})
+	}
+}
+
+func TestPhoenixPlaceLimitOrderCrossing(t *testing.T) {
+	tx, events := extractFixture(t, "phoenix.json", 1)
+
+	// 转入资金包含挂单部分，不输出 TradeEvent
+	assert.Empty(t, trades(events))
+
+	fillEvents := eventsOfType(events, pb.EventType_ORDERBOOK_FILL)
+	require.Len(t, fillEvents, 1)
+	fill := fillEvents[0].Event.GetOrderbookFill()
+	assert.Equal(t, eventID(tx, 0, 0), fill.EventId)
+	assert.Equal(t, uint32(consts.DexPhoenix), fill.Dex)
+	assert.Equal(t, testfixture.Bytes("px:market"), fill.PairAddress)
+	assert.Equal(t, testfixture.Bytes("px:trader"), fill.UserWallet)
+	assert.Empty(t, fill.TakerOpenOrders)
+	assert.Equal(t, pb.EventType_TRADE_BUY, fill.Side)
+
+	require.Len(t, fill.Fills, 1)
+	assert.Equal(t, testfixture.Bytes("px:maker_a"), fill.Fills[0].Maker)
+	assert.Equal(t, uint64(1000), fill.Fills[0].PriceInTicks)
+	assert.Equal(t, uint64(3), fill.Fills[0].BaseLots)
+	assert.Equal(t, uint64(3000), fill.Fills[0].QuoteLots)
+
+	assert.Len(t, eventsOfType(events, pb.EventType_TRANSFER), 1)
+}
+
+func TestPhoenixPlaceLimitOrder_PostOnly(t *testing.T) {
+	_, events := extractFixture(t, "phoenix.json", 4)
+	assert.Empty(t, eventsOfType(events, pb.EventType_ORDERBOOK_FILL))
+	assert.Empty(t, trades(events))
+	assert.Len(t, eventsOfType(events, pb.EventType_TRANSFER), 1)
+}
+
+func TestPhoenixPlaceLimitOrderWithFreeFunds(t *testing.T) {
+	_, events := extractFixture(t, "phoenix.json", 5)
+
+	fillEvents := eventsOfType(events, pb.EventType_ORDERBOOK_FILL)
+	require.Len(t, fillEvents, 1)
+	require.Len(t, events, 1, "使用 seat 中的资金，没有转账")
+	fill := fillEvents[0].Event.GetOrderbookFill()
+	assert.Equal(t, pb.EventType_TRADE_SELL, fill.Side)
+
+	require.Len(t, fill.Fills, 2)
+	assert.Equal(t, testfixture.Bytes("px:maker_b"), fill.Fills[0].Maker)
+	assert.Equal(t, uint64(1005), fill.Fills[0].PriceInTicks)
+	assert.Equal(t, uint64(2010), fill.Fills[0].QuoteLots)
+	assert.Equal(t, testfixture.Bytes("px:maker_a"), fill.Fills[1].Maker)
+	assert.Equal(t, uint64(1000), fill.Fills[1].QuoteLots)
+}
@@ -236,6 +236,7 @@ func extractCreateEvent(
 	liquidityEvent.EventType = uint32(pb.EventType_ADD_LIQUIDITY)
 	liquidityEvent.Event.GetLiquidity().Type = pb.EventType_ADD_LIQUIDITY
 	liquidityEvent.Event.GetLiquidity().TokenAmount = event.TokenTotalSupply
+	liquidityEvent.Event.GetLiquidity().TokenAmountNet = event.TokenTotalSupply
 	liquidityEvent.Event.GetLiquidity().QuoteTokenAmount = 0 // 首次建池并未注入 SOL，SOL 注入发生在后续 Buy
 
 	ctx.AddEvent(createPool)
@@ -3,6 +3,7 @@ package pumpfun
 import (
 	"dex-indexer-sol/internal/consts"
 	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
 	"dex-indexer-sol/internal/pkg/types"
 	"encoding/binary"
 )
@@ -14,15 +15,8 @@ func findEventInstruction(
 	current int,
 	eventAuthority types.Pubkey,
 ) int {
-	mainIx := instrs[current]
-	for i := current + 1; i < len(instrs); i++ {
-		ix := instrs[i]
-
-		// 只处理当前主指令的 inner 指令
-		if ix.IxIndex != mainIx.IxIndex {
-			return -1
-		}
-
+	// 事件日志由 Pump.fun 通过 self-CPI 发出，是当前指令的直接子指令
+	for i, ix := range common.Children(instrs, current) {
 		if ix.ProgramID != consts.PumpFunProgram {
 			continue
 		}
@@ -20,6 +20,32 @@ func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
 	m[consts.PumpFunProgram] = handleInstruction
 }
 
+// RegisterFailedHandlers 注册失败交易中 Swap 指令的意图解析器
+func RegisterFailedHandlers(m map[types.Pubkey]common.FailedTradeHandler) {
+	m[consts.PumpFunProgram] = failedSwapTable.Extract
+}
+
+// failedSwapTable 失败交易中 Buy / Sell 指令的布局（内盘以原生 SOL 计价，SOL 直接从用户钱包支付）
+// 账户：#2 mint，#3 bonding curve，#5 用户 token 账户，#6 用户钱包
+//   - Buy:  [8:16] token amount（获得），[16:24] max_sol_cost
+//   - Sell: [8:16] token amount（卖出），[16:24] min_sol_output
+var failedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexPumpfun,
+	DiscriminatorLen: 8,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		Buy: {
+			MinAccounts: 7,
+			Pair:        3, Wallet: 6, UserSource: 6, UserDest: 5, InputToken: consts.SOLMint, OutputMint: 2, InputMint: common.NoAccount,
+			AmountIn: 16, AmountOut: 8, ExactOut: true,
+		},
+		Sell: {
+			MinAccounts: 7,
+			Pair:        3, Wallet: 6, UserSource: 5, UserDest: 6, InputMint: 2, OutputToken: consts.SOLMint, OutputMint: common.NoAccount,
+			AmountIn: 8, AmountOut: 16,
+		},
+	},
+}
+
 func handleInstruction(
 	ctx *common.ParserContext,
 	instrs []*core.AdaptedInstruction,
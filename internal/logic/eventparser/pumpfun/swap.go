@@ -196,8 +196,10 @@ func extractSwapEvent(
 		TokenDecimals: uint32(userTokenBalance.Decimals), // 交易 token 的精度
 		QuoteDecimals: 9,                                 // SOL 精度固定为 9
 
-		TokenAmount:      event.TokenAmount, // 实际成交 token 数量
-		QuoteTokenAmount: event.SolAmount,   // 实际成交 SOL 数量（作为 quote）
+		TokenAmount:         event.TokenAmount, // 实际成交 token 数量
+		QuoteTokenAmount:    event.SolAmount,   // 实际成交 SOL 数量（作为 quote）
+		TokenAmountNet:      event.TokenAmount, // bonding curve 阶段无转账手续费，net 与 gross 相同
+		QuoteTokenAmountNet: event.SolAmount,
 
 		Token:      event.Mint[:],     // 被交易的 token（base token）
 		QuoteToken: consts.SOLMint[:], // quote token（SOL）
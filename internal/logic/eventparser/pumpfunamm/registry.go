@@ -21,6 +21,32 @@ func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
 	m[consts.PumpFunAMMProgram] = handleInstruction
 }
 
+// RegisterFailedHandlers 注册失败交易中 Swap 指令的意图解析器
+func RegisterFailedHandlers(m map[types.Pubkey]common.FailedTradeHandler) {
+	m[consts.PumpFunAMMProgram] = failedSwapTable.Extract
+}
+
+// failedSwapTable 失败交易中 Buy / Sell 指令的布局（账户布局见 extractSwapEvent）
+// 账户：#0 pool，#1 用户钱包，#3 base mint，#4 quote mint，#5 用户 base 账户，#6 用户 quote 账户
+//   - Buy:  [8:16] base_amount_out，[16:24] max_quote_amount_in
+//   - Sell: [8:16] base_amount_in，[16:24] min_quote_amount_out
+var failedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexPumpfunAMM,
+	DiscriminatorLen: 8,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		Buy: {
+			MinAccounts: 7,
+			Pair:        0, Wallet: 1, UserSource: 6, UserDest: 5, InputMint: 4, OutputMint: 3,
+			AmountIn: 16, AmountOut: 8, ExactOut: true,
+		},
+		Sell: {
+			MinAccounts: 7,
+			Pair:        0, Wallet: 1, UserSource: 5, UserDest: 6, InputMint: 3, OutputMint: 4,
+			AmountIn: 8, AmountOut: 16,
+		},
+	},
+}
+
 func handleInstruction(
 	ctx *common.ParserContext,
 	instrs []*core.AdaptedInstruction,
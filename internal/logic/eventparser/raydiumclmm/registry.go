@@ -25,6 +25,31 @@ func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
 	m[consts.RaydiumCLMMProgram] = handleInstruction
 }
 
+// RegisterFailedHandlers 注册失败交易中 Swap 指令的意图解析器
+func RegisterFailedHandlers(m map[types.Pubkey]common.FailedTradeHandler) {
+	m[consts.RaydiumCLMMProgram] = failedSwapTable.Extract
+}
+
+// failedSwapTable 失败交易中 Swap 指令的布局
+// 账户：#0 payer，#2 pool_state，#3 input_token_account，#4 output_token_account（SwapV2 的 #11/#12 为 input/output mint）
+// 指令数据：[8:16] amount，[16:24] other_amount_threshold，[24:40] sqrt_price_limit_x64，[40] is_base_input
+var failedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexRaydiumCLMM,
+	DiscriminatorLen: 8,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		Swap: {
+			MinAccounts: 5,
+			Pair:        2, Wallet: 0, UserSource: 3, UserDest: 4, InputMint: common.NoAccount, OutputMint: common.NoAccount,
+			AmountIn: 8, AmountOut: 16, ExactInFlag: 40,
+		},
+		SwapV2: {
+			MinAccounts: 13,
+			Pair:        2, Wallet: 0, UserSource: 3, UserDest: 4, InputMint: 11, OutputMint: 12,
+			AmountIn: 8, AmountOut: 16, ExactInFlag: 40,
+		},
+	},
+}
+
 func handleInstruction(
 	ctx *common.ParserContext,
 	instrs []*core.AdaptedInstruction,
@@ -21,6 +21,32 @@ func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
 	m[consts.RaydiumCPMMProgram] = handleInstruction
 }
 
+// RegisterFailedHandlers 注册失败交易中 Swap 指令的意图解析器
+func RegisterFailedHandlers(m map[types.Pubkey]common.FailedTradeHandler) {
+	m[consts.RaydiumCPMMProgram] = failedSwapTable.Extract
+}
+
+// failedSwapTable 失败交易中 Swap 指令的布局
+// 账户：#0 payer，#3 pool_state，#4 input_token_account，#5 output_token_account，#10 input_token_mint，#11 output_token_mint
+//   - SwapBaseInput:  [8:16] amount_in,     [16:24] minimum_amount_out
+//   - SwapBaseOutput: [8:16] max_amount_in, [16:24] amount_out
+var failedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexRaydiumCPMM,
+	DiscriminatorLen: 8,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		SwapBaseInput: {
+			MinAccounts: 12,
+			Pair:        3, Wallet: 0, UserSource: 4, UserDest: 5, InputMint: 10, OutputMint: 11,
+			AmountIn: 8, AmountOut: 16,
+		},
+		SwapBaseOut: {
+			MinAccounts: 12,
+			Pair:        3, Wallet: 0, UserSource: 4, UserDest: 5, InputMint: 10, OutputMint: 11,
+			AmountIn: 8, AmountOut: 16, ExactOut: true,
+		},
+	},
+}
+
 func handleInstruction(
 	ctx *common.ParserContext,
 	instrs []*core.AdaptedInstruction,
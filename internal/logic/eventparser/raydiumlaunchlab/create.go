@@ -0,0 +1,234 @@
+package raydiumlaunchlab
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/internal/tools"
+	"dex-indexer-sol/pb"
+	"github.com/near/borsh-go"
+	"runtime/debug"
+)
+
+type MintParams struct {
+	Decimals uint8
+	Name     string
+	Symbol   string
+	Uri      string
+}
+
+// LaunchLabPoolCreateEvent 只解析到 curve_param 的 supply 为止：
+// CurveParams 为枚举（Constant / Fixed / Linear），各变体的第一个字段均为 supply，之后的字段按变体不同忽略
+type LaunchLabPoolCreateEvent struct {
+	Sign          uint64
+	PoolState     types.Pubkey
+	Creator       types.Pubkey
+	Config        types.Pubkey
+	BaseMintParam MintParams
+	CurveType     uint8
+	Supply        uint64
+}
+
+// Raydium LaunchLab - Initialize / InitializeV2 指令账户布局：
+//
+// #0  - Payer（交易发起人，Signer）
+// #1  - Creator（项目创建者）
+// #2  - Global Config（全局配置）
+// #3  - Platform Config（发射平台配置，如 bonk.fun）
+// #4  - Authority（Vault 授权 PDA，持有池子 token 账户）
+// #5  - Pool State（池子主账户）
+// #6  - Base Mint（新创建的 Token Mint）
+// #7  - Quote Mint（计价 token，通常为 WSOL）
+// #8  - Base Vault（池子 base token 账户）
+// #9  - Quote Vault（池子 quote token 账户）
+// #10 - Metadata 账户（Metaplex 元数据 PDA）
+// #11 - Base Token Program
+// #12 - Quote Token Program
+// #13 - Metaplex Token Metadata 程序地址
+// #14 - System Program
+// #15 - Rent 账户
+// #16 - Event Authority（事件权限 PDA）
+// #17 - Raydium LaunchLab 程序地址
+func extractCreateEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) (next int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[RaydiumLaunchLab:Create] panic: %v, stack=%s, tx=%s", r, debug.Stack(), ctx.TxHashString())
+			next = -1
+		}
+	}()
+
+	ix := instrs[current]
+
+	// 1. 校验指令结构
+	if len(ix.Accounts) < 18 {
+		logger.Errorf("[RaydiumLaunchLab:Create] 指令账户长度不足: got=%d, expect>=18, tx=%s",
+			len(ix.Accounts), ctx.TxHashString())
+		return -1
+	}
+
+	// 2. 提取并解析事件
+	eventIndex := findEventInstruction(instrs, current, ix.Accounts[16], PoolCreateEventSign) // Event Authority
+	if eventIndex < 0 {
+		logger.Errorf("[RaydiumLaunchLab:Create] 未找到事件日志指令: authority=%s, tx=%s", ix.Accounts[16], ctx.TxHashString())
+		return -1
+	}
+	eventIx := instrs[eventIndex]
+	event := LaunchLabPoolCreateEvent{}
+	if err := borsh.Deserialize(&event, eventIx.Data[8:]); err != nil {
+		logger.Errorf("[RaydiumLaunchLab:Create] 事件反序列化失败: %v, tx=%s", err, ctx.TxHashString())
+		return -1
+	}
+
+	// 3. 校验池子地址与创建者一致性
+	poolAddress := ix.Accounts[5]
+	if event.PoolState != poolAddress {
+		logger.Errorf("[RaydiumLaunchLab:Create] PoolState 不一致 (expected=%s, got=%s): tx=%s", poolAddress, event.PoolState, ctx.TxHashString())
+		return -1
+	}
+	if event.Creator != ix.Accounts[1] {
+		logger.Errorf("[RaydiumLaunchLab:Create] 创建者地址不匹配: expected=%s, got=%s, tx=%s", ix.Accounts[1], event.Creator, ctx.TxHashString())
+		return -1
+	}
+
+	// 4. 校验 Token Program 是否为 SPL Token
+	tokenProgramID := ix.Accounts[11]
+	quoteTokenProgramID := ix.Accounts[12]
+	if !tools.IsSPLTokenPubkey(tokenProgramID) || !tools.IsSPLTokenPubkey(quoteTokenProgramID) {
+		logger.Errorf("[RaydiumLaunchLab:Create] Token Program 非 SPL 标准程序: base=%s, quote=%s, tx=%s",
+			tokenProgramID, quoteTokenProgramID, ctx.TxHashString())
+		return -1
+	}
+
+	// 5. 提取池子 vault 账户与余额（Post 状态）
+	authority := ix.Accounts[4]
+	baseMint := ix.Accounts[6]
+	quoteMint := ix.Accounts[7]
+	baseVault := ix.Accounts[8]
+	quoteVault := ix.Accounts[9]
+
+	baseVaultBalance, ok := ctx.Balances[baseVault]
+	if !ok {
+		logger.Errorf("[RaydiumLaunchLab:Create] 缺失池子 base vault 余额: account=%s, tx=%s", baseVault, ctx.TxHashString())
+		return -1
+	}
+	quoteVaultBalance, ok := ctx.Balances[quoteVault]
+	if !ok {
+		logger.Errorf("[RaydiumLaunchLab:Create] 缺失池子 quote vault 余额: account=%s, tx=%s", quoteVault, ctx.TxHashString())
+		return -1
+	}
+
+	// 6. 校验 vault 的 mint 与所有者（LaunchLab 的 vault 由 Authority PDA 持有，而非池子主账户）
+	if baseVaultBalance.Token != baseMint || quoteVaultBalance.Token != quoteMint {
+		logger.Errorf("[RaydiumLaunchLab:Create] vault mint 不匹配: base=%s/%s, quote=%s/%s, tx=%s",
+			baseVaultBalance.Token, baseMint, quoteVaultBalance.Token, quoteMint, ctx.TxHashString())
+		return -1
+	}
+	if baseVaultBalance.PostOwner != authority || quoteVaultBalance.PostOwner != authority {
+		logger.Errorf("[RaydiumLaunchLab:Create] vault 所有者异常: expected=%s, base=%s, quote=%s, tx=%s",
+			authority, baseVaultBalance.PostOwner, quoteVaultBalance.PostOwner, ctx.TxHashString())
+		return -1
+	}
+
+	// 7. 构建 CreatePool 类型的 LiquidityEvent
+	userWallet := ix.Accounts[0]
+	poolEvent := &pb.LiquidityEvent{
+		Type:      pb.EventType_CREATE_POOL,
+		EventId:   core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
+		Slot:      ctx.Slot,
+		BlockTime: ctx.BlockTime,
+		TxHash:    ctx.TxHash,
+		Signers:   ctx.Signers,
+		Dex:       consts.DexRaydiumLaunchLab,
+
+		UserWallet:  userWallet[:],
+		PairAddress: poolAddress[:],
+
+		TokenDecimals: uint32(baseVaultBalance.Decimals),
+		QuoteDecimals: uint32(quoteVaultBalance.Decimals),
+
+		TokenAmount:      0,
+		QuoteTokenAmount: 0,
+
+		Token:      baseMint[:],
+		QuoteToken: quoteMint[:],
+
+		TokenAccount:      baseVault[:],
+		QuoteTokenAccount: quoteVault[:],
+
+		TokenAccountOwner:      baseVaultBalance.PostOwner[:],
+		QuoteTokenAccountOwner: quoteVaultBalance.PostOwner[:],
+
+		PairTokenBalance: baseVaultBalance.PostBalance,
+		PairQuoteBalance: quoteVaultBalance.PostBalance,
+
+		TokenProgram:      tools.TokenProgramTypeOf(tokenProgramID),
+		QuoteTokenProgram: tools.TokenProgramTypeOf(quoteTokenProgramID),
+	}
+
+	// 8. 构造CreatePool标准事件结构
+	createPool := &core.Event{
+		ID:        poolEvent.EventId,
+		EventType: uint32(poolEvent.Type),
+		Key:       poolEvent.PairAddress,
+		Event: &pb.Event{
+			Event: &pb.Event_Liquidity{Liquidity: poolEvent},
+		},
+	}
+
+	// 9. 衍生出 LaunchpadTokenEvent 事件
+	tokenEvent := &pb.LaunchpadTokenEvent{
+		Type:      pb.EventType_LAUNCHPAD_TOKEN,
+		EventId:   createPool.ID + 1,
+		Slot:      ctx.Slot,
+		BlockTime: ctx.BlockTime,
+		TxHash:    ctx.TxHash,
+		Signers:   ctx.Signers,
+		Dex:       consts.DexRaydiumLaunchLab,
+
+		UserWallet: userWallet[:],
+		Creator:    event.Creator[:],
+
+		Decimals: uint32(event.BaseMintParam.Decimals),
+
+		TotalSupply: event.Supply,
+		Token:       baseMint[:],
+		PairAddress: poolAddress[:],
+
+		Symbol: event.BaseMintParam.Symbol,
+		Name:   event.BaseMintParam.Name,
+		Uri:    event.BaseMintParam.Uri,
+
+		TokenProgram: tools.TokenProgramTypeOf(tokenProgramID),
+	}
+	launchpadTokenEvent := &core.Event{
+		ID:        tokenEvent.EventId,
+		EventType: uint32(tokenEvent.Type),
+		Key:       tokenEvent.PairAddress,
+		Event: &pb.Event{
+			Event: &pb.Event_Token{
+				Token: tokenEvent,
+			},
+		},
+	}
+
+	// 10. 衍生出 AddLiquidity 事件（全部供应量在建池时铸造进 base vault，不对应链上转账指令）
+	liquidityEvent := common.CloneLiquidityEvent(createPool)
+	liquidityEvent.ID += 2
+	liquidityEvent.Event.GetLiquidity().EventId = liquidityEvent.ID
+	liquidityEvent.EventType = uint32(pb.EventType_ADD_LIQUIDITY)
+	liquidityEvent.Event.GetLiquidity().Type = pb.EventType_ADD_LIQUIDITY
+	liquidityEvent.Event.GetLiquidity().TokenAmount = baseVaultBalance.PostBalance
+	liquidityEvent.Event.GetLiquidity().TokenAmountNet = baseVaultBalance.PostBalance
+	liquidityEvent.Event.GetLiquidity().QuoteTokenAmount = 0 // 建池不注入 quote，quote 注入发生在后续 Buy
+
+	ctx.AddEvent(createPool)
+	ctx.AddEvent(launchpadTokenEvent)
+	ctx.AddEvent(liquidityEvent)
+	return eventIndex + 1
+}
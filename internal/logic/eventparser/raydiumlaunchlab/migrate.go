@@ -0,0 +1,223 @@
+package raydiumlaunchlab
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/pb"
+	"runtime/debug"
+)
+
+// migrateLayout 描述迁移指令中来源池（bonding curve）与目标池的关键账户索引
+type migrateLayout struct {
+	MinAccounts int // 指令账户最少数量
+	DestDex     int // 目标 DEX
+
+	Authority  int // LaunchLab Vault 授权 PDA（同时作为目标池创建者）
+	PoolState  int // 来源池主账户
+	BaseVault  int // 来源池 base token 账户
+	QuoteVault int // 来源池 quote token 账户
+
+	DestPool       int // 目标池主账户
+	DestBaseVault  int // 目标池 base token 账户
+	DestQuoteVault int // 目标池 quote token 账户
+}
+
+// MigrateToAmm 指令账户布局（迁移至 Raydium AMM v4）：
+//
+// #0  - Payer                  #1  - Base Mint              #2  - Quote Mint
+// #3  - OpenBook Program       #4  - Market                 #5  - Request Queue
+// #6  - Event Queue            #7  - Bids                   #8  - Asks
+// #9  - Market Vault Signer    #10 - Market Base Vault      #11 - Market Quote Vault
+// #12 - AMM Program            #13 - AMM Pool               #14 - AMM Authority
+// #15 - AMM Open Orders        #16 - AMM LP Mint            #17 - AMM Base Vault
+// #18 - AMM Quote Vault        #19 - AMM Target Orders      #20 - AMM Config
+// #21 - AMM Create Fee Dest    #22 - Authority              #23 - Pool State
+// #24 - Global Config          #25 - Base Vault             #26 - Quote Vault
+// #27 - Pool LP Token          #28 - SPL Token Program      #29 - Associated Token Program
+// #30 - System Program         #31 - Rent
+var migrateToAmmLayout = migrateLayout{
+	MinAccounts:    32,
+	DestDex:        consts.DexRaydiumV4,
+	Authority:      22,
+	PoolState:      23,
+	BaseVault:      25,
+	QuoteVault:     26,
+	DestPool:       13,
+	DestBaseVault:  17,
+	DestQuoteVault: 18,
+}
+
+// MigrateToCpswap 指令账户布局（迁移至 Raydium CPMM）：
+//
+// #0  - Payer                  #1  - Base Mint              #2  - Quote Mint
+// #3  - Platform Config        #4  - CPMM Program           #5  - CPMM Pool
+// #6  - CPMM Authority         #7  - CPMM LP Mint           #8  - CPMM Base Vault
+// #9  - CPMM Quote Vault       #10 - CPMM Config            #11 - CPMM Create Pool Fee
+// #12 - CPMM Observation       #13 - Lock Program           #14 - Lock Authority
+// #15 - Lock LP Vault          #16 - Authority              #17 - Pool State
+// #18 - Global Config          #19 - Base Vault             #20 - Quote Vault
+// #21 - Pool LP Token          #22 - Base Token Program     #23 - Quote Token Program
+// #24 - Associated Token Prog  #25 - System Program         #26 - Rent
+// #27 - Metadata Program
+var migrateToCpswapLayout = migrateLayout{
+	MinAccounts:    28,
+	DestDex:        consts.DexRaydiumCPMM,
+	Authority:      16,
+	PoolState:      17,
+	BaseVault:      19,
+	QuoteVault:     20,
+	DestPool:       5,
+	DestBaseVault:  8,
+	DestQuoteVault: 9,
+}
+
+// extractMigrateEvent 解析 bonding curve 毕业迁移（MigrateToAmm / MigrateToCpswap），构造 MigrateEvent。
+// 迁移指令不发出事件日志，迁移数量由来源池与目标池 vault 的余额变化计算：
+//   - TokenAmount / QuoteTokenAmount：目标池 vault 的增加量（实际注入新池的数量）
+//   - MigrationFee：来源池 quote 减少量中未注入新池的部分
+func extractMigrateEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+	layout *migrateLayout,
+) (next int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[RaydiumLaunchLab:Migrate] panic: %v, stack=%s, tx=%s", r, debug.Stack(), ctx.TxHashString())
+			next = -1
+		}
+	}()
+
+	ix := instrs[current]
+
+	// 1. 校验指令结构
+	if len(ix.Accounts) < layout.MinAccounts {
+		logger.Errorf("[RaydiumLaunchLab:Migrate] 指令账户长度不足: got=%d, expect>=%d, tx=%s",
+			len(ix.Accounts), layout.MinAccounts, ctx.TxHashString())
+		return -1
+	}
+
+	// 2. 提取关键账户
+	userWallet := ix.Accounts[0]
+	baseMint := ix.Accounts[1]
+	quoteMint := ix.Accounts[2]
+	authority := ix.Accounts[layout.Authority]
+	srcPoolAddress := ix.Accounts[layout.PoolState]
+	srcPoolTokenAccount := ix.Accounts[layout.BaseVault]
+	srcPoolQuoteAccount := ix.Accounts[layout.QuoteVault]
+	destPoolAddress := ix.Accounts[layout.DestPool]
+	destPoolTokenAccount := ix.Accounts[layout.DestBaseVault]
+	destPoolQuoteAccount := ix.Accounts[layout.DestQuoteVault]
+
+	// 3. 获取来源池与目标池的 vault 余额
+	srcPoolTokenBalance, ok := ctx.Balances[srcPoolTokenAccount]
+	if !ok {
+		logger.Errorf("[RaydiumLaunchLab:Migrate] 来源池 base token 余额缺失: account=%s, tx=%s", srcPoolTokenAccount, ctx.TxHashString())
+		return -1
+	}
+	srcPoolQuoteBalance, ok := ctx.Balances[srcPoolQuoteAccount]
+	if !ok {
+		logger.Errorf("[RaydiumLaunchLab:Migrate] 来源池 quote token 余额缺失: account=%s, tx=%s", srcPoolQuoteAccount, ctx.TxHashString())
+		return -1
+	}
+	destPoolTokenBalance, ok := ctx.Balances[destPoolTokenAccount]
+	if !ok {
+		logger.Errorf("[RaydiumLaunchLab:Migrate] 目标池 base token 余额缺失: account=%s, tx=%s", destPoolTokenAccount, ctx.TxHashString())
+		return -1
+	}
+	destPoolQuoteBalance, ok := ctx.Balances[destPoolQuoteAccount]
+	if !ok {
+		logger.Errorf("[RaydiumLaunchLab:Migrate] 目标池 quote token 余额缺失: account=%s, tx=%s", destPoolQuoteAccount, ctx.TxHashString())
+		return -1
+	}
+
+	// 4. 校验来源池 vault 所有者为 Authority PDA
+	if srcPoolTokenBalance.PostOwner != authority || srcPoolQuoteBalance.PostOwner != authority {
+		logger.Errorf("[RaydiumLaunchLab:Migrate] 来源池 vault 所有者异常: expected=%s, base=%s, quote=%s, tx=%s",
+			authority, srcPoolTokenBalance.PostOwner, srcPoolQuoteBalance.PostOwner, ctx.TxHashString())
+		return -1
+	}
+
+	// 5. 校验 base / quote token 一致性（来源池、目标池与指令中的 mint 必须相同）
+	if srcPoolTokenBalance.Token != baseMint || destPoolTokenBalance.Token != baseMint {
+		logger.Errorf("[RaydiumLaunchLab:Migrate] base token 不一致: src=%s, dest=%s, mint=%s, tx=%s",
+			srcPoolTokenBalance.Token, destPoolTokenBalance.Token, baseMint, ctx.TxHashString())
+		return -1
+	}
+	if srcPoolQuoteBalance.Token != quoteMint || destPoolQuoteBalance.Token != quoteMint {
+		logger.Errorf("[RaydiumLaunchLab:Migrate] quote token 不一致: src=%s, dest=%s, mint=%s, tx=%s",
+			srcPoolQuoteBalance.Token, destPoolQuoteBalance.Token, quoteMint, ctx.TxHashString())
+		return -1
+	}
+
+	// 6. 根据余额变化计算迁移数量与费用
+	tokenAmount := destPoolTokenBalance.PostBalance - min(destPoolTokenBalance.PreBalance, destPoolTokenBalance.PostBalance)
+	quoteAmount := destPoolQuoteBalance.PostBalance - min(destPoolQuoteBalance.PreBalance, destPoolQuoteBalance.PostBalance)
+	quoteOut := srcPoolQuoteBalance.PreBalance - min(srcPoolQuoteBalance.PreBalance, srcPoolQuoteBalance.PostBalance)
+	var migrationFee uint64
+	if quoteOut > quoteAmount {
+		migrationFee = quoteOut - quoteAmount
+	}
+
+	// 7. 构建MigrateEvent
+	migrateEvent := &pb.MigrateEvent{
+		Type:      pb.EventType_MIGRATE,
+		EventId:   core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
+		Slot:      ctx.Slot,
+		BlockTime: ctx.BlockTime,
+		TxHash:    ctx.TxHash,
+		Signers:   ctx.Signers,
+
+		UserWallet:      userWallet[:],
+		DestPoolCreator: authority[:],
+
+		SrcDex:  consts.DexRaydiumLaunchLab,
+		DestDex: uint32(layout.DestDex),
+
+		TokenDecimals: uint32(destPoolTokenBalance.Decimals),
+		QuoteDecimals: uint32(destPoolQuoteBalance.Decimals),
+
+		TokenAmount:      tokenAmount,  // 迁移 base token 数量
+		QuoteTokenAmount: quoteAmount,  // 迁移 quote token 数量
+		MigrationFee:     migrationFee, // 迁移费用（quote）
+
+		Token:          baseMint[:],
+		SrcQuoteToken:  quoteMint[:],
+		DestQuoteToken: quoteMint[:],
+
+		SrcPairAddress:  srcPoolAddress[:],
+		DestPairAddress: destPoolAddress[:],
+
+		SrcTokenAccount:  srcPoolTokenAccount[:],
+		DestTokenAccount: destPoolTokenAccount[:],
+
+		SrcQuoteTokenAccount:  srcPoolQuoteAccount[:],
+		DestQuoteTokenAccount: destPoolQuoteAccount[:],
+
+		SrcTokenAccountOwner:  srcPoolTokenBalance.PostOwner[:],
+		DestTokenAccountOwner: destPoolTokenBalance.PostOwner[:],
+
+		SrcQuoteTokenAccountOwner:  srcPoolQuoteBalance.PostOwner[:],
+		DestQuoteTokenAccountOwner: destPoolQuoteBalance.PostOwner[:],
+
+		SrcPairTokenBalance:  srcPoolTokenBalance.PostBalance,
+		DestPairTokenBalance: destPoolTokenBalance.PostBalance,
+		SrcPairQuoteBalance:  srcPoolQuoteBalance.PostBalance,
+		DestPairQuoteBalance: destPoolQuoteBalance.PostBalance,
+	}
+
+	// 8. 添加事件到事件列表
+	ctx.AddEvent(&core.Event{
+		ID:        migrateEvent.EventId,
+		EventType: uint32(migrateEvent.Type),
+		Key:       migrateEvent.SrcPairAddress, // 分区 Key 用旧池 SrcPairAddress 更符合事件语义
+		Event: &pb.Event{
+			Event: &pb.Event_Migrate{Migrate: migrateEvent},
+		},
+	})
+
+	// 保留内部的新池事件（Raydium AMM v4 / CPMM 的建池与注入流动性）
+	return current + 1
+}
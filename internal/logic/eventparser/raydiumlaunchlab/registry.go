@@ -0,0 +1,95 @@
+package raydiumlaunchlab
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+)
+
+// Raydium LaunchLab 指令方法ID
+const (
+	// 创建 bonding curve 池子（发币）
+	Initialize   uint64 = 0xafaf6d1f0d989bed
+	InitializeV2 uint64 = 0x4399af27da102620
+
+	// 内盘交易
+	BuyExactIn   uint64 = 0xfaea0d7bd59c13ec
+	BuyExactOut  uint64 = 0x18d3742869039938
+	SellExactIn  uint64 = 0x9527de9bd37c981a
+	SellExactOut uint64 = 0x5fc8472208090ba6
+
+	// 毕业迁移
+	MigrateToAmm    uint64 = 0xcf52c091fecf91df
+	MigrateToCpswap uint64 = 0x885cc8671cda908c
+)
+
+// RegisterHandlers 注册 Raydium LaunchLab Program 的指令解析器
+func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
+	m[consts.RaydiumLaunchLabProgram] = handleInstruction
+}
+
+// RegisterFailedHandlers 注册失败交易中 Swap 指令的意图解析器
+func RegisterFailedHandlers(m map[types.Pubkey]common.FailedTradeHandler) {
+	m[consts.RaydiumLaunchLabProgram] = failedSwapTable.Extract
+}
+
+// failedSwapTable 失败交易中 Buy / Sell 指令的布局
+// 账户：#0 用户钱包，#4 pool state，#5 用户 base token 账户，#6 用户 quote token 账户，#9 base mint，#10 quote mint
+//   - BuyExactIn / SellExactIn:   [8:16] amount_in，[16:24] minimum_amount_out
+//   - BuyExactOut / SellExactOut: [8:16] amount_out，[16:24] maximum_amount_in
+var failedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexRaydiumLaunchLab,
+	DiscriminatorLen: 8,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		BuyExactIn: {
+			MinAccounts: 11,
+			Pair:        4, Wallet: 0, UserSource: 6, UserDest: 5, InputMint: 10, OutputMint: 9,
+			AmountIn: 8, AmountOut: 16,
+		},
+		BuyExactOut: {
+			MinAccounts: 11,
+			Pair:        4, Wallet: 0, UserSource: 6, UserDest: 5, InputMint: 10, OutputMint: 9,
+			AmountIn: 16, AmountOut: 8, ExactOut: true,
+		},
+		SellExactIn: {
+			MinAccounts: 11,
+			Pair:        4, Wallet: 0, UserSource: 5, UserDest: 6, InputMint: 9, OutputMint: 10,
+			AmountIn: 8, AmountOut: 16,
+		},
+		SellExactOut: {
+			MinAccounts: 11,
+			Pair:        4, Wallet: 0, UserSource: 5, UserDest: 6, InputMint: 9, OutputMint: 10,
+			AmountIn: 16, AmountOut: 8, ExactOut: true,
+		},
+	},
+}
+
+func handleInstruction(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	// 指令 data 至少应包含 8 字节方法 ID
+	if len(ix.Data) < 8 {
+		return -1
+	}
+
+	switch binary.BigEndian.Uint64(ix.Data[:8]) {
+	case Initialize, InitializeV2:
+		return extractCreateEvent(ctx, instrs, current)
+	case BuyExactIn, BuyExactOut:
+		return extractSwapEvent(ctx, instrs, current, true)
+	case SellExactIn, SellExactOut:
+		return extractSwapEvent(ctx, instrs, current, false)
+	case MigrateToAmm:
+		return extractMigrateEvent(ctx, instrs, current, &migrateToAmmLayout)
+	case MigrateToCpswap:
+		return extractMigrateEvent(ctx, instrs, current, &migrateToCpswapLayout)
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,123 @@
+package raydiumlaunchlab
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"github.com/near/borsh-go"
+	"runtime/debug"
+)
+
+// LaunchLabTradeEvent 只解析稳定的前缀字段，之后的手续费、方向、池子状态等字段随版本变化，不做解析
+type LaunchLabTradeEvent struct {
+	Sign            uint64
+	PoolState       types.Pubkey
+	TotalBaseSell   uint64
+	VirtualBase     uint64
+	VirtualQuote    uint64
+	RealBaseBefore  uint64
+	RealQuoteBefore uint64
+	RealBaseAfter   uint64
+	RealQuoteAfter  uint64
+	AmountIn        uint64
+	AmountOut       uint64
+}
+
+// extractSwapEvent 解析 Raydium LaunchLab 内盘的 BuyExactIn / BuyExactOut / SellExactIn / SellExactOut 指令，
+// 构造标准 TradeEvent（BUY / SELL）。quote 存放在独立的 quote vault（通常为 WSOL），成交数量取自实际转账。
+//
+// Raydium LaunchLab 交易账户结构：
+//
+// #0  - Payer（用户钱包，Signer）
+// #1  - Authority（Vault 授权 PDA）
+// #2  - Global Config
+// #3  - Platform Config
+// #4  - Pool State（池子主账户）
+// #5  - User Base Token（用户 base token 账户）
+// #6  - User Quote Token（用户 quote token 账户）
+// #7  - Base Vault（池子 base token 账户）
+// #8  - Quote Vault（池子 quote token 账户）
+// #9  - Base Token Mint
+// #10 - Quote Token Mint
+// #11 - Base Token Program
+// #12 - Quote Token Program
+// #13 - Event Authority（事件权限 PDA）
+// #14 - Raydium LaunchLab 程序地址
+func extractSwapEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+	isBuy bool,
+) (next int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[RaydiumLaunchLab:Swap] panic: %v, stack=%s, tx=%s", r, debug.Stack(), ctx.TxHashString())
+			next = -1
+		}
+	}()
+
+	ix := instrs[current]
+
+	// 1. 校验指令结构
+	if len(ix.Accounts) < 15 {
+		logger.Errorf("[RaydiumLaunchLab:Swap] 指令账户长度不足: got=%d, expect>=15, tx=%s",
+			len(ix.Accounts), ctx.TxHashString())
+		return -1
+	}
+
+	// 2. 提取并解析事件
+	eventIndex := findEventInstruction(instrs, current, ix.Accounts[13], TradeEventSign) // Event Authority
+	if eventIndex < 0 {
+		logger.Errorf("[RaydiumLaunchLab:Swap] 未找到事件日志指令: authority=%s, tx=%s", ix.Accounts[13], ctx.TxHashString())
+		return -1
+	}
+	eventIx := instrs[eventIndex]
+	event := LaunchLabTradeEvent{}
+	if err := borsh.Deserialize(&event, eventIx.Data[8:]); err != nil {
+		logger.Errorf("[RaydiumLaunchLab:Swap] 事件反序列化失败: %v, tx=%s", err, ctx.TxHashString())
+		return -1
+	}
+
+	// 3. 校验池子地址一致性
+	pairAddress := ix.Accounts[4]
+	if event.PoolState != pairAddress {
+		logger.Errorf("[RaydiumLaunchLab:Swap] PoolState 不一致 (expected=%s, got=%s): tx=%s", pairAddress, event.PoolState, ctx.TxHashString())
+		return -1
+	}
+
+	// 4. 查找用户与池子 vault 之间的转账
+	result := common.FindSwapTransfersByIndex(ctx, instrs, current, &common.SwapInstructionIndex{
+		UserToken1AccountIndex: 5,
+		UserToken2AccountIndex: 6,
+		PoolToken1AccountIndex: 7,
+		PoolToken2AccountIndex: 8,
+	}, 0)
+	if result == nil {
+		logger.Errorf("[RaydiumLaunchLab:Swap] 转账结构缺失: tx=%s, ix=%d, inner=%d",
+			ctx.TxHashString(), ix.IxIndex, ix.InnerIndex)
+		return -1
+	}
+
+	// 5. 校验 mint 与交易方向：Buy 为用户支付 quote、获得 base，Sell 相反
+	baseMint, quoteMint := ix.Accounts[9], ix.Accounts[10]
+	inputMint, outputMint := baseMint, quoteMint
+	if isBuy {
+		inputMint, outputMint = quoteMint, baseMint
+	}
+	if result.UserToPool.Token != inputMint || result.PoolToUser.Token != outputMint {
+		logger.Errorf("[RaydiumLaunchLab:Swap] mint 或方向不匹配: isBuy=%v, userToPool=%s, poolToUser=%s, base=%s, quote=%s, tx=%s",
+			isBuy, result.UserToPool.Token, result.PoolToUser.Token, baseMint, quoteMint, ctx.TxHashString())
+		return -1
+	}
+
+	// 6. 构建交易事件（quote 由池子配置确定）
+	tradeEvent := common.BuildTradeEvent(ctx, ix, result.UserToPool, result.PoolToUser, pairAddress, quoteMint, true, consts.DexRaydiumLaunchLab)
+	if tradeEvent == nil {
+		return -1
+	}
+
+	ctx.AddEvent(tradeEvent)
+	return max(result.MaxIndex, eventIndex) + 1
+}
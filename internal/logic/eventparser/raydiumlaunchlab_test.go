@@ -0,0 +1,149 @@
+package eventparser
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/raydiumlaunchlab.json
+//
+//	tx0：Initialize 建池发币
+//	tx1：BuyExactIn，WSOL 买入
+//	tx2：SellExactIn，卖出换 WSOL
+//	tx3：MigrateToCpswap 毕业迁移
+//	tx4：MigrateToCpswap，来源池 vault 所有者不是 Authority PDA
+//	tx5：BuyExactIn，事件 PoolState 与指令不一致
+//	tx6：SellExactIn，转账方向为买入
+//	tx7：BuyExactOut
+const launchLabSupply = 1_000_000_000_000_000
+
+func TestRaydiumLaunchLabCreate(t *testing.T) {
+	tx, events := extractFixture(t, "raydiumlaunchlab.json", 0)
+
+	pools := eventsOfType(events, pb.EventType_CREATE_POOL)
+	require.Len(t, pools, 1)
+	pool := pools[0].Event.GetLiquidity()
+	assert.Equal(t, eventID(tx, 0, 0), pool.EventId)
+	assert.Equal(t, uint32(consts.DexRaydiumLaunchLab), pool.Dex)
+	assert.Equal(t, testfixture.Bytes("ll:creator"), pool.UserWallet)
+	assert.Equal(t, testfixture.Bytes("ll:pool"), pool.PairAddress)
+	assert.Equal(t, testfixture.Bytes("ll:mint"), pool.Token)
+	assert.Equal(t, consts.WSOLMint[:], pool.QuoteToken)
+	assert.Equal(t, testfixture.Bytes("ll:base_vault"), pool.TokenAccount)
+	assert.Equal(t, testfixture.Bytes("ll:quote_vault"), pool.QuoteTokenAccount)
+	assert.Equal(t, testfixture.Bytes("ll:authority"), pool.TokenAccountOwner, "vault 由 Authority PDA 持有")
+	assert.Equal(t, uint64(launchLabSupply), pool.PairTokenBalance)
+
+	tokens := eventsOfType(events, pb.EventType_LAUNCHPAD_TOKEN)
+	require.Len(t, tokens, 1)
+	token := tokens[0].Event.GetToken()
+	assert.Equal(t, pool.EventId+1, token.EventId)
+	assert.Equal(t, testfixture.Bytes("ll:creator"), token.Creator)
+	assert.Equal(t, testfixture.Bytes("ll:mint"), token.Token)
+	assert.Equal(t, uint32(6), token.Decimals)
+	assert.Equal(t, uint64(launchLabSupply), token.TotalSupply)
+	assert.Equal(t, "Bonk Test", token.Name)
+	assert.Equal(t, "BTEST", token.Symbol)
+	assert.Equal(t, "https://example.com/btest.json", token.Uri)
+
+	adds := eventsOfType(events, pb.EventType_ADD_LIQUIDITY)
+	require.Len(t, adds, 1)
+	add := adds[0].Event.GetLiquidity()
+	assert.Equal(t, pool.EventId+2, add.EventId)
+	assert.Equal(t, uint64(launchLabSupply), add.TokenAmount)
+	assert.Zero(t, add.QuoteTokenAmount)
+}
+
+func TestRaydiumLaunchLabSwap(t *testing.T) {
+	tests := []struct {
+		name        string
+		index       int
+		side        pb.EventType
+		tokenAmount uint64
+		quoteAmount uint64
+		pairBase    uint64
+		pairQuote   uint64
+	}{
+		{"buy", 1, pb.EventType_TRADE_BUY, 35_000_000_000_000, 990_000_000, launchLabSupply - 35_000_000_000_000, 990_000_000},
+		{"sell", 2, pb.EventType_TRADE_SELL, 17_500_000_000_000, 480_000_000, launchLabSupply - 17_500_000_000_000, 510_000_000},
+		{"buy_exact_out", 7, pb.EventType_TRADE_BUY, 35_000_000_000_000, 990_000_000, launchLabSupply - 35_000_000_000_000, 990_000_000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, events := extractFixture(t, "raydiumlaunchlab.json", tt.index)
+
+			ts := trades(events)
+			require.Len(t, ts, 1)
+			trade := ts[0]
+			assert.Equal(t, tt.side, trade.Type)
+			assert.Equal(t, eventID(tx, 0, 0), trade.EventId)
+			assert.Equal(t, uint32(consts.DexRaydiumLaunchLab), trade.Dex)
+			assert.Equal(t, testfixture.Bytes("ll:pool"), trade.PairAddress)
+			assert.Equal(t, testfixture.Bytes("ll:trader"), trade.UserWallet)
+			assert.Equal(t, testfixture.Bytes("ll:mint"), trade.Token)
+			assert.Equal(t, consts.WSOLMint[:], trade.QuoteToken)
+			assert.Equal(t, tt.tokenAmount, trade.TokenAmount)
+			assert.Equal(t, tt.quoteAmount, trade.QuoteTokenAmount)
+			assert.Equal(t, testfixture.Bytes("ll:base_vault"), trade.TokenAccount)
+			assert.Equal(t, testfixture.Bytes("ll:quote_vault"), trade.QuoteTokenAccount)
+			assert.Equal(t, tt.pairBase, trade.PairTokenBalance)
+			assert.Equal(t, tt.pairQuote, trade.PairQuoteBalance)
+		})
+	}
+}
+
+func TestRaydiumLaunchLabSwap_Rejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		index int
+	}{
+		{"pool_state_mismatch", 5},
+		{"direction_mismatch", 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, events := extractFixture(t, "raydiumlaunchlab.json", tt.index)
+			assert.Empty(t, trades(events))
+			assert.Len(t, eventsOfType(events, pb.EventType_TRANSFER), 2, "转账仍单独记录")
+		})
+	}
+}
+
+func TestRaydiumLaunchLabMigrateToCpswap(t *testing.T) {
+	tx, events := extractFixture(t, "raydiumlaunchlab.json", 3)
+
+	migrates := eventsOfType(events, pb.EventType_MIGRATE)
+	require.Len(t, migrates, 1)
+	assert.Equal(t, testfixture.Bytes("ll:pool"), migrates[0].Key, "按来源池分区")
+	migrate := migrates[0].Event.GetMigrate()
+	assert.Equal(t, eventID(tx, 0, 0), migrate.EventId)
+	assert.Equal(t, uint32(consts.DexRaydiumLaunchLab), migrate.SrcDex)
+	assert.Equal(t, uint32(consts.DexRaydiumCPMM), migrate.DestDex)
+	assert.Equal(t, testfixture.Bytes("ll:creator"), migrate.UserWallet)
+	assert.Equal(t, testfixture.Bytes("ll:authority"), migrate.DestPoolCreator)
+	assert.Equal(t, testfixture.Bytes("ll:mint"), migrate.Token)
+	assert.Equal(t, consts.WSOLMint[:], migrate.DestQuoteToken)
+	assert.Equal(t, testfixture.Bytes("ll:pool"), migrate.SrcPairAddress)
+	assert.Equal(t, testfixture.Bytes("ll:cpmm_pool"), migrate.DestPairAddress)
+	assert.Equal(t, testfixture.Bytes("ll:cpmm_base_vault"), migrate.DestTokenAccount)
+	assert.Equal(t, testfixture.Bytes("ll:cpmm_quote_vault"), migrate.DestQuoteTokenAccount)
+
+	// 数量为目标池 vault 的增加量，来源池 quote 减少量中未注入新池的部分为迁移费用
+	assert.Equal(t, uint64(207_000_000_000_000), migrate.TokenAmount)
+	assert.Equal(t, uint64(84_000_000_000), migrate.QuoteTokenAmount)
+	assert.Equal(t, uint64(1_000_000_000), migrate.MigrationFee)
+	assert.Zero(t, migrate.SrcPairTokenBalance)
+	assert.Equal(t, uint64(84_000_000_000), migrate.DestPairQuoteBalance)
+	assert.Equal(t, uint32(6), migrate.TokenDecimals)
+	assert.Equal(t, uint32(9), migrate.QuoteDecimals)
+}
+
+func TestRaydiumLaunchLabMigrate_VaultOwnerMismatch(t *testing.T) {
+	_, events := extractFixture(t, "raydiumlaunchlab.json", 4)
+	assert.Empty(t, eventsOfType(events, pb.EventType_MIGRATE), "来源池 vault 必须由 Authority PDA 持有")
+}
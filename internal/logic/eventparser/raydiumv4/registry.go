@@ -21,6 +21,31 @@ func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
 	m[consts.RaydiumV4Program] = handleInstruction
 }
 
+// RegisterFailedHandlers 注册失败交易中 Swap 指令的意图解析器
+func RegisterFailedHandlers(m map[types.Pubkey]common.FailedTradeHandler) {
+	m[consts.RaydiumV4Program] = failedSwapTable.Extract
+}
+
+// failedSwapTable 失败交易中 Swap 指令的布局（账户布局见 extractSwapEvent，17/18 个账户，#1 为池子，末尾三个为用户 source / dest / 钱包）
+//   - SwapBaseIn:  [1:9] amount_in,     [9:17] minimum_amount_out
+//   - SwapBaseOut: [1:9] max_amount_in, [9:17] amount_out
+var failedSwapTable = &common.FailedSwapTable{
+	Dex:              consts.DexRaydiumV4,
+	DiscriminatorLen: 1,
+	Layouts: map[uint64]common.FailedSwapLayout{
+		SwapBaseIn: {
+			MinAccounts: 17, MaxAccounts: 18, ShiftUserAccounts: true,
+			Pair: 1, Wallet: 16, UserSource: 14, UserDest: 15, InputMint: common.NoAccount, OutputMint: common.NoAccount,
+			AmountIn: 1, AmountOut: 9,
+		},
+		SwapBaseOut: {
+			MinAccounts: 17, MaxAccounts: 18, ShiftUserAccounts: true,
+			Pair: 1, Wallet: 16, UserSource: 14, UserDest: 15, InputMint: common.NoAccount, OutputMint: common.NoAccount,
+			AmountIn: 1, AmountOut: 9, ExactOut: true,
+		},
+	},
+}
+
 // handleInstruction 是 RaydiumV4 的主分发入口
 func handleInstruction(
 	ctx *common.ParserContext,
@@ -38,8 +38,37 @@ func handleTokenInstruction(
 		byte(sdktoken.InstructionBurnChecked):
 		return extractTokenBurnEvent(ctx, instrs, current)
 
+	case common.InstructionTransferFeeExtension:
+		return handleTransferFeeInstruction(ctx, instrs, current)
+
 	default:
 		// 忽略非关心的 TokenProgram 指令
 		return -1
 	}
 }
+
+// handleTransferFeeInstruction 分派 Token-2022 TransferFeeExtension 子指令
+func handleTransferFeeInstruction(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+	if len(ix.Data) < 2 {
+		return -1
+	}
+
+	switch ix.Data[1] {
+	case common.TransferFeeTransferCheckedWithFee:
+		return extractTokenTransferEvent(ctx, instrs, current)
+
+	case common.TransferFeeWithdrawFromMint,
+		common.TransferFeeWithdrawFromAccounts:
+		return extractWithdrawWithheldEvent(ctx, instrs, current)
+
+	default:
+		// InitializeTransferFeeConfig / SetTransferFee 已在预扫描阶段处理，
+		// HarvestWithheldTokensToMint 只在账户与 mint 间移动预扣手续费，不产生事件
+		return -1
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"dex-indexer-sol/internal/logic/eventparser/common"
 )
 
-// extractTokenTransferEvent 尝试将当前指令解析为 SPL Token 的 Transfer 或 TransferChecked 事件。
+// extractTokenTransferEvent 尝试将当前指令解析为 SPL Token 的 Transfer、TransferChecked 或 TransferCheckedWithFee 事件。
 // 若解析成功，则构造 TransferEvent 并添加至上下文。
 func extractTokenTransferEvent(
 	ctx *common.ParserContext,
@@ -14,7 +14,7 @@ func extractTokenTransferEvent(
 ) int {
 	ix := instrs[current]
 
-	// 解析 Transfer / TransferChecked 指令
+	// 解析 Transfer / TransferChecked / TransferCheckedWithFee 指令
 	parsedTransfer, ok := common.ParseTransferInstruction(ctx, ix)
 	if !ok {
 		return -1
@@ -23,3 +23,21 @@ func extractTokenTransferEvent(
 	ctx.AddEvent(common.BuildTransferEvent(ctx, parsedTransfer))
 	return current + 1
 }
+
+// extractWithdrawWithheldEvent 将 Token-2022 WithdrawWithheldTokens 指令解析为 TransferEvent，
+// 来源账户为 mint，数量为提取到目标账户的预扣手续费。
+func extractWithdrawWithheldEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	parsedTransfer, ok := common.ParseWithdrawWithheldInstruction(ctx, ix)
+	if !ok {
+		return -1
+	}
+
+	ctx.AddEvent(common.BuildTransferEvent(ctx, parsedTransfer))
+	return current + 1
+}
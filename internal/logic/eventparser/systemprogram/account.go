@@ -0,0 +1,105 @@
+package systemprogram
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/pb"
+	"encoding/binary"
+)
+
+// accountChange 表示一次账户创建或回收
+type accountChange struct {
+	Closed       bool         // true 为回收，false 为创建
+	Account      types.Pubkey // 被创建 / 回收的账户
+	Counterparty types.Pubkey // 创建时为出资账户，回收时为接收剩余 SOL 的账户
+	Owner        types.Pubkey // 创建时分配的所属程序
+	Lamports     uint64       // 创建时存入 / 回收时转出的 lamports
+	Space        uint64       // 创建时分配的数据空间（字节）
+}
+
+// parseCreateAccount 解析 CreateAccount / CreateAccountWithSeed 指令
+func parseCreateAccount(ix *core.AdaptedInstruction) (*accountChange, bool) {
+	if len(ix.Data) < 4 || len(ix.Accounts) < 2 {
+		return nil, false
+	}
+
+	// accounts = [funder, new_account, (base)]
+	change := &accountChange{
+		Account:      ix.Accounts[1],
+		Counterparty: ix.Accounts[0],
+	}
+
+	// CreateAccount: [0:4]=instr, [4:12]=lamports, [12:20]=space, [20:52]=owner
+	data := ix.Data[4:]
+	if binary.LittleEndian.Uint32(ix.Data[:4]) == CreateAccountWithSeed {
+		// CreateAccountWithSeed: [4:36]=base, [36:44]=seed 长度, seed, lamports, space, owner
+		if len(data) < 40 {
+			return nil, false
+		}
+		seedLen := binary.LittleEndian.Uint64(data[32:40])
+		if seedLen > uint64(len(data)-40) {
+			return nil, false
+		}
+		data = data[40+seedLen:]
+	}
+	if len(data) < 48 {
+		return nil, false
+	}
+
+	change.Lamports = binary.LittleEndian.Uint64(data[0:8])
+	change.Space = binary.LittleEndian.Uint64(data[8:16])
+	copy(change.Owner[:], data[16:48])
+	return change, true
+}
+
+// extractCreateAccountEvent 将 CreateAccount / CreateAccountWithSeed 解析为 ACCOUNT_CREATE 事件
+func extractCreateAccountEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	change, ok := parseCreateAccount(ix)
+	if !ok {
+		return -1
+	}
+
+	ctx.AddEvent(buildAccountEvent(ctx, ix, change))
+	return current + 1
+}
+
+// buildAccountEvent 构造 AccountEvent 并封装为 core.Event，按账户地址分区
+func buildAccountEvent(
+	ctx *common.ParserContext,
+	ix *core.AdaptedInstruction,
+	change *accountChange,
+) *core.Event {
+	event := pb.AccountEvent{
+		Type:         pb.EventType_ACCOUNT_CREATE,
+		EventId:      core.BuildEventID(ctx.Slot, ctx.TxIndex, ix.IxIndex, ix.InnerIndex),
+		Slot:         ctx.Slot,
+		BlockTime:    ctx.BlockTime,
+		TxHash:       ctx.TxHash,
+		Signers:      ctx.Signers,
+		Account:      change.Account[:],
+		Counterparty: change.Counterparty[:],
+		Lamports:     change.Lamports,
+		Space:        change.Space,
+	}
+	if change.Closed {
+		event.Type = pb.EventType_ACCOUNT_CLOSE
+	} else {
+		event.Owner = change.Owner[:]
+	}
+
+	return &core.Event{
+		ID:        event.EventId,
+		EventType: uint32(event.Type),
+		Key:       event.Account,
+		Event: &pb.Event{
+			Event: &pb.Event_Account{Account: &event},
+		},
+	}
+}
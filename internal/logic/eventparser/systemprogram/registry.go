@@ -0,0 +1,46 @@
+package systemprogram
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+)
+
+// 合约源代码: https://github.com/anza-xyz/solana-sdk/blob/master/system-interface/src/instruction.rs
+// 指令序号为 u32（bincode 编码）
+const (
+	CreateAccount         uint32 = 0
+	Transfer              uint32 = 2
+	CreateAccountWithSeed uint32 = 3
+	WithdrawNonceAccount  uint32 = 5
+	TransferWithSeed      uint32 = 11
+)
+
+// RegisterHandlers 注册 System Program 的指令解析器
+func RegisterHandlers(m map[types.Pubkey]common.InstructionHandler) {
+	m[consts.SystemProgram] = handleInstruction
+}
+
+func handleInstruction(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	// 指令 data 至少应包含 4 字节指令序号
+	if len(ix.Data) < 4 {
+		return -1
+	}
+
+	switch binary.LittleEndian.Uint32(ix.Data[:4]) {
+	case Transfer, TransferWithSeed, WithdrawNonceAccount:
+		return extractTransferEvent(ctx, instrs, current)
+	case CreateAccount, CreateAccountWithSeed:
+		return extractCreateAccountEvent(ctx, instrs, current)
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,121 @@
+package systemprogram
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/eventparser/common"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/internal/tools"
+	"encoding/binary"
+)
+
+// parseTransfer 解析 Transfer / TransferWithSeed 指令，返回来源账户、目标账户与转账数量（lamports）
+func parseTransfer(ix *core.AdaptedInstruction) (from, to types.Pubkey, lamports uint64, ok bool) {
+	if len(ix.Data) < 12 {
+		return from, to, 0, false
+	}
+
+	switch binary.LittleEndian.Uint32(ix.Data[:4]) {
+	// Transfer: [0:4]=instr, [4:12]=lamports
+	// accounts = [from, to]
+	case Transfer:
+		if len(ix.Accounts) < 2 {
+			return from, to, 0, false
+		}
+		from, to = ix.Accounts[0], ix.Accounts[1]
+
+	// TransferWithSeed: [0:4]=instr, [4:12]=lamports, 之后为 from_seed 与 from_owner
+	// accounts = [from（派生账户）, base, to]
+	case TransferWithSeed:
+		if len(ix.Accounts) < 3 {
+			return from, to, 0, false
+		}
+		from, to = ix.Accounts[0], ix.Accounts[2]
+
+	// WithdrawNonceAccount: [0:4]=instr, [4:12]=lamports
+	// accounts = [nonce_account, to, recent_blockhashes_sysvar, rent_sysvar, nonce_authority]
+	case WithdrawNonceAccount:
+		if len(ix.Accounts) < 2 {
+			return from, to, 0, false
+		}
+		from, to = ix.Accounts[0], ix.Accounts[1]
+
+	default:
+		return from, to, 0, false
+	}
+	return from, to, binary.LittleEndian.Uint64(ix.Data[4:12]), true
+}
+
+// extractTransferEvent 将 System Program 的 SOL 转账（含 nonce 账户提取）解析为 native = true、token = SOLMint 的 TransferEvent。
+// 原生 SOL 没有 token account，来源/目标账户与钱包相同，转账后余额取自 AdaptedTx.SolBalances。
+// 普通钱包转空余额不视为回收；nonce 账户带有状态数据，全部提取后账户被回收，此时额外生成 ACCOUNT_CLOSE 事件。
+func extractTransferEvent(
+	ctx *common.ParserContext,
+	instrs []*core.AdaptedInstruction,
+	current int,
+) int {
+	ix := instrs[current]
+
+	from, to, lamports, ok := parseTransfer(ix)
+	if !ok {
+		return -1
+	}
+	if lamports == 0 {
+		return current + 1 // 0 lamports 转账没有实际资金变动
+	}
+
+	srcBalance, ok1 := ctx.Tx.SolBalances[from]
+	destBalance, ok2 := ctx.Tx.SolBalances[to]
+	if !ok1 || !ok2 {
+		logger.Errorf("[SystemProgram::Transfer] tx=%s: sol balance missing from=%s ok=%v to=%s ok=%v",
+			ctx.TxHashString(), from, ok1, to, ok2)
+		return -1
+	}
+
+	event := common.BuildTransferEvent(ctx, &common.ParsedTransfer{
+		IxIndex:         ix.IxIndex,
+		InnerIndex:      ix.InnerIndex,
+		Token:           consts.SOLMint,
+		SrcAccount:      from,
+		DestAccount:     to,
+		SrcWallet:       from,
+		DestWallet:      to,
+		Amount:          lamports,
+		Decimals:        tools.SOLDecimals,
+		SrcPostBalance:  srcBalance.PostBalance,
+		DestPostBalance: destBalance.PostBalance,
+		Native:          true,
+	})
+	// SOL 转账数量远多于其它 token，按来源账户而非 token 分区，避免集中到同一个分区
+	event.Key = event.Event.GetTransfer().SrcAccount
+	ctx.AddEvent(event)
+
+	if binary.LittleEndian.Uint32(ix.Data[:4]) == WithdrawNonceAccount &&
+		srcBalance.PostBalance == 0 && isLastTransferFrom(instrs, current, from) {
+		closeEvent := buildAccountEvent(ctx, ix, &accountChange{
+			Closed:       true,
+			Account:      from,
+			Counterparty: to,
+			Lamports:     lamports,
+		})
+		// 与 SOL 转账事件来自同一条指令，ID 顺延一位以保持唯一
+		closeEvent.ID = event.ID + 1
+		closeEvent.Event.GetAccount().EventId = closeEvent.ID
+		ctx.AddEvent(closeEvent)
+	}
+	return current + 1
+}
+
+// isLastTransferFrom 判断 instrs[current] 之后是否不再有从 account 转出 SOL 的指令
+func isLastTransferFrom(instrs []*core.AdaptedInstruction, current int, account types.Pubkey) bool {
+	for _, ix := range instrs[current+1:] {
+		if ix.ProgramID != consts.SystemProgram {
+			continue
+		}
+		if from, _, lamports, ok := parseTransfer(ix); ok && lamports > 0 && from == account {
+			return false
+		}
+	}
+	return true
+}
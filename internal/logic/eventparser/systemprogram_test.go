@@ -0,0 +1,94 @@
+package eventparser
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemProgram_Transfers(t *testing.T) {
+	tx, events := extractFixture(t, "systemprogram.json", 0)
+	alice, bob, derived := testfixture.Bytes("sys:alice"), testfixture.Bytes("sys:bob"), testfixture.Bytes("sys:derived")
+
+	transfers := eventsOfType(events, pb.EventType_TRANSFER)
+	require.Len(t, transfers, 2, "0 lamports 与数据被截断的转账不产生事件")
+
+	first := transfers[0].Event.GetTransfer()
+	assert.Equal(t, eventID(tx, 0, 0), first.EventId)
+	assert.True(t, first.Native)
+	assert.Equal(t, consts.SOLMint[:], first.Token)
+	assert.NotEqual(t, consts.WSOLMint[:], first.Token, "原生 SOL 与 WSOL 区分")
+	assert.Equal(t, uint32(9), first.Decimals)
+	assert.Equal(t, alice, first.SrcAccount)
+	assert.Equal(t, alice, first.SrcWallet)
+	assert.Equal(t, bob, first.DestAccount)
+	assert.Equal(t, bob, first.DestWallet)
+	assert.Equal(t, uint64(1_500_000_000), first.Amount)
+	// 余额为交易结束后的 lamports
+	assert.Equal(t, uint64(5_000_000_000-5000-1_500_000_000-2_039_280), first.SrcTokenBalance)
+	assert.Equal(t, uint64(10_000_000+1_500_000_000+700_000), first.DestTokenBalance)
+	assert.Equal(t, alice, transfers[0].Key, "按来源账户分区")
+
+	// TransferWithSeed：来源为派生账户，base 账户只签名
+	seeded := transfers[1].Event.GetTransfer()
+	assert.Equal(t, eventID(tx, 2, 0), seeded.EventId)
+	assert.True(t, seeded.Native)
+	assert.Equal(t, derived, seeded.SrcAccount)
+	assert.Equal(t, bob, seeded.DestAccount)
+	assert.Equal(t, uint64(700_000), seeded.Amount)
+	assert.Equal(t, uint64(200_000), seeded.SrcTokenBalance)
+
+	created := eventsOfType(events, pb.EventType_ACCOUNT_CREATE)
+	require.Len(t, created, 1)
+	account := created[0].Event.GetAccount()
+	assert.Equal(t, eventID(tx, 1, 0), account.EventId)
+	assert.Equal(t, testfixture.Bytes("sys:token_account"), account.Account)
+	assert.Equal(t, alice, account.Counterparty)
+	assert.Equal(t, consts.TokenProgram[:], account.Owner)
+	assert.Equal(t, uint64(2_039_280), account.Lamports)
+	assert.Equal(t, uint64(165), account.Space)
+	assert.Empty(t, eventsOfType(events, pb.EventType_ACCOUNT_CLOSE))
+}
+
+func TestSystemProgram_NonceWithdrawAll(t *testing.T) {
+	tx, events := extractFixture(t, "systemprogram.json", 1)
+
+	created := eventsOfType(events, pb.EventType_ACCOUNT_CREATE)
+	require.Len(t, created, 1)
+	account := created[0].Event.GetAccount()
+	assert.Equal(t, testfixture.Bytes("sys:derived"), account.Account, "CreateAccountWithSeed 跳过 seed 解析 lamports / owner")
+	assert.Equal(t, uint64(1_000_000), account.Lamports)
+	assert.Zero(t, account.Space)
+	assert.Equal(t, consts.SystemProgram[:], account.Owner)
+
+	transfers := eventsOfType(events, pb.EventType_TRANSFER)
+	require.Len(t, transfers, 1)
+	withdraw := transfers[0].Event.GetTransfer()
+	assert.True(t, withdraw.Native)
+	assert.Equal(t, testfixture.Bytes("sys:nonce"), withdraw.SrcAccount)
+	assert.Equal(t, uint64(1_447_680), withdraw.Amount)
+	assert.Zero(t, withdraw.SrcTokenBalance)
+
+	// nonce 账户全部提取后被回收，回收事件 ID 顺延一位
+	closed := eventsOfType(events, pb.EventType_ACCOUNT_CLOSE)
+	require.Len(t, closed, 1)
+	closeEvent := closed[0].Event.GetAccount()
+	assert.Equal(t, eventID(tx, 1, 0)+1, closeEvent.EventId)
+	assert.Equal(t, closeEvent.EventId, closed[0].ID)
+	assert.Equal(t, testfixture.Bytes("sys:nonce"), closeEvent.Account)
+	assert.Equal(t, testfixture.Bytes("sys:bob"), closeEvent.Counterparty)
+	assert.Equal(t, uint64(1_447_680), closeEvent.Lamports)
+	assert.Empty(t, closeEvent.Owner)
+}
+
+func TestSystemProgram_NonceWithdrawPartial(t *testing.T) {
+	_, events := extractFixture(t, "systemprogram.json", 2)
+	transfers := eventsOfType(events, pb.EventType_TRANSFER)
+	require.Len(t, transfers, 1)
+	assert.Equal(t, uint64(1_500_000), transfers[0].Event.GetTransfer().SrcTokenBalance)
+	assert.Empty(t, eventsOfType(events, pb.EventType_ACCOUNT_CLOSE), "账户仍有余额，不视为回收")
+}
@@ -0,0 +1,118 @@
+package eventparser
+
+import (
+	"dex-indexer-sol/internal/cache"
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transferEvents 解析区块中的第 index 笔交易，返回其中的 TransferEvent
+func transferEvents(t *testing.T, name string, index int) []*pb.TransferEvent {
+	t.Helper()
+	_, events := extractFixture(t, name, index)
+	var out []*pb.TransferEvent
+	for _, e := range eventsOfType(events, pb.EventType_TRANSFER) {
+		out = append(out, e.Event.GetTransfer())
+	}
+	return out
+}
+
+func TestToken2022_InitConfigThenTransfer(t *testing.T) {
+	transfers := transferEvents(t, "token2022_fee.json", 0)
+	require.Len(t, transfers, 3)
+
+	// 同一交易内 InitializeTransferFeeConfig 设置的费率：100 bps，上限 5_000
+	assert.Equal(t, uint64(100_000), transfers[0].Amount)
+	assert.Equal(t, uint64(1_000), transfers[0].Fee)
+	assert.Equal(t, uint64(1_000_000), transfers[1].Amount)
+	assert.Equal(t, uint64(5_000), transfers[1].Fee, "不超过 maximum_fee")
+	// TransferCheckedWithFee 使用指令中的手续费
+	assert.Equal(t, uint64(200_000), transfers[2].Amount)
+	assert.Equal(t, uint64(2_000), transfers[2].Fee)
+	assert.Equal(t, testfixture.Bytes("fee:wallet"), transfers[2].SrcWallet)
+	assert.Equal(t, testfixture.Bytes("fee:friend"), transfers[2].DestWallet)
+	for _, transfer := range transfers {
+		assert.False(t, transfer.WithheldWithdraw)
+		assert.False(t, transfer.Native)
+	}
+
+	// 同一交易内的 SetTransferFee 两个 epoch 后才生效
+	info, ok := cache.Mints().Get(testfixture.Key("fee:mint_new"))
+	require.True(t, ok)
+	epoch := uint64(344_000_000 / 432_000)
+	assert.Equal(t, cache.TransferFee{Epoch: epoch, MaximumFee: 5_000, BasisPoints: 100}, info.OlderTransferFee)
+	assert.Equal(t, cache.TransferFee{Epoch: epoch + 2, MaximumFee: 50_000, BasisPoints: 300}, info.NewerTransferFee)
+}
+
+func TestToken2022_FeeFromBalance(t *testing.T) {
+	transfers := transferEvents(t, "token2022_fee.json", 1)
+	require.Len(t, transfers, 3)
+
+	// 费率未知：目标账户到账 49_500，手续费 500
+	assert.Equal(t, testfixture.Bytes("fee:mint_unknown"), transfers[0].Token)
+	assert.Equal(t, uint64(50_000), transfers[0].Amount)
+	assert.Equal(t, uint64(500), transfers[0].Fee)
+
+	// 同一目标账户有多笔转入，无法推算
+	assert.Zero(t, transfers[1].Fee)
+	assert.Zero(t, transfers[2].Fee)
+}
+
+func TestToken2022_WithdrawWithheld(t *testing.T) {
+	transfers := transferEvents(t, "token2022_fee.json", 2)
+	require.Len(t, transfers, 1)
+
+	withdraw := transfers[0]
+	assert.True(t, withdraw.WithheldWithdraw)
+	assert.Equal(t, testfixture.Bytes("fee:mint_new"), withdraw.Token)
+	assert.Equal(t, testfixture.Bytes("fee:mint_new"), withdraw.SrcAccount, "来源为 mint")
+	assert.Equal(t, testfixture.Bytes("fee:withheld_dst"), withdraw.DestAccount)
+	assert.Equal(t, uint64(3_000), withdraw.Amount, "按目标账户余额变化计算")
+	assert.Equal(t, uint64(3_010), withdraw.DestTokenBalance)
+	assert.Zero(t, withdraw.Fee)
+}
+
+func TestToken2022_TradeGrossAndNet(t *testing.T) {
+	mint := testfixture.Key("fee:mint_swap")
+	cache.Mints().Put(mint, cache.MintInfo{
+		Decimals:         6,
+		TokenProgram:     consts.TokenProgram2022,
+		HasAccount:       true,
+		HasTransferFee:   true,
+		OlderTransferFee: cache.TransferFee{MaximumFee: 1_000_000, BasisPoints: 100},
+		NewerTransferFee: cache.TransferFee{MaximumFee: 1_000_000, BasisPoints: 100},
+	})
+
+	_, events := extractFixture(t, "token2022_fee.json", 3)
+	sells := trades(events)
+	require.Len(t, sells, 1)
+	sell := sells[0]
+	assert.Equal(t, pb.EventType_TRADE_SELL, sell.Type)
+	assert.Equal(t, mint[:], sell.Token)
+	assert.Equal(t, uint64(1_000_000), sell.TokenAmount, "用户支付的数量")
+	assert.Equal(t, uint64(990_000), sell.TokenAmountNet, "池子实际到账")
+	assert.Equal(t, uint64(400_000), sell.QuoteTokenAmount)
+	assert.Equal(t, sell.QuoteTokenAmount, sell.QuoteTokenAmountNet, "USDC 无转账手续费")
+
+	_, events = extractFixture(t, "token2022_fee.json", 4)
+	buys := trades(events)
+	require.Len(t, buys, 1)
+	buy := buys[0]
+	assert.Equal(t, pb.EventType_TRADE_BUY, buy.Type)
+	assert.Equal(t, uint64(2_000_000), buy.TokenAmount, "池子转出的数量")
+	assert.Equal(t, uint64(1_980_000), buy.TokenAmountNet, "用户实际到账")
+	assert.Equal(t, uint64(800_000), buy.QuoteTokenAmount)
+	assert.Equal(t, uint64(800_000), buy.QuoteTokenAmountNet)
+
+	// 失败交易中的 SetTransferFee 已回滚，不更新费率
+	_, events = extractFixture(t, "token2022_fee.json", 5)
+	assert.Empty(t, events)
+	info, ok := cache.Mints().Get(mint)
+	require.True(t, ok)
+	assert.Equal(t, uint16(100), info.NewerTransferFee.BasisPoints)
+}
@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"dex-indexer-sol/internal/config"
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/eventparser"
+	"dex-indexer-sol/internal/pkg/types"
+	"fmt"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+)
+
+// AccountFilter 订阅的账户过滤条件。
+// Include / Exclude / Required 为 base58 地址，用于构造 SubscribeRequest；
+// Yellowstone 的区块过滤器只支持 include，block 模式下 exclude / required 由 filterBlock 在本地过滤。
+type AccountFilter struct {
+	Include  []string
+	Exclude  []string
+	Required []string
+
+	exclude  map[types.Pubkey]struct{}
+	required map[types.Pubkey]struct{}
+}
+
+// NewAccountFilter 根据配置构造过滤条件；include 为空时，block 模式使用 consts.GrpcAccountInclude，
+// transaction 模式使用已注册 handler 的 ProgramID（需在 eventparser.Init 之后调用）。
+func NewAccountFilter(conf config.AccountFilterConfig, txMode bool) (*AccountFilter, error) {
+	include := conf.Include
+	if conf.AutoPrograms {
+		include = append(append([]string{}, include...), eventparser.ProgramIDs()...)
+	}
+	if len(include) == 0 {
+		if txMode {
+			include = eventparser.ProgramIDs()
+		} else {
+			include = consts.GrpcAccountInclude
+		}
+	}
+	if txMode && len(include) == 0 {
+		return nil, fmt.Errorf("transaction subscribe mode requires account include list or registered program ids, call eventparser.Init first")
+	}
+
+	f := &AccountFilter{}
+	var err error
+	if f.Include, _, err = parseAccounts(include); err != nil {
+		return nil, fmt.Errorf("invalid include account: %w", err)
+	}
+	if f.Exclude, f.exclude, err = parseAccounts(conf.Exclude); err != nil {
+		return nil, fmt.Errorf("invalid exclude account: %w", err)
+	}
+	if f.Required, f.required, err = parseAccounts(conf.Required); err != nil {
+		return nil, fmt.Errorf("invalid required account: %w", err)
+	}
+	return f, nil
+}
+
+func (f *AccountFilter) String() string {
+	return fmt.Sprintf("include=%d, exclude=%d, required=%d", len(f.Include), len(f.Exclude), len(f.Required))
+}
+
+// filterBlock 在本地按 exclude / required 过滤区块中的交易（原地修改），未配置时直接返回
+func (f *AccountFilter) filterBlock(block *pb.SubscribeUpdateBlock) {
+	if len(f.exclude) == 0 && len(f.required) == 0 {
+		return
+	}
+	kept := block.Transactions[:0]
+	for _, tx := range block.Transactions {
+		if f.matchTx(tx) {
+			kept = append(kept, tx)
+		}
+	}
+	block.Transactions = kept
+}
+
+func (f *AccountFilter) matchTx(tx *pb.SubscribeUpdateTransactionInfo) bool {
+	matched := 0
+	check := func(keys [][]byte) bool {
+		for _, key := range keys {
+			if len(key) != 32 {
+				continue
+			}
+			pk := types.Pubkey(key)
+			if _, ok := f.exclude[pk]; ok {
+				return false
+			}
+			if _, ok := f.required[pk]; ok {
+				matched++
+			}
+		}
+		return true
+	}
+
+	if !check(tx.GetTransaction().GetMessage().GetAccountKeys()) ||
+		!check(tx.GetMeta().GetLoadedWritableAddresses()) ||
+		!check(tx.GetMeta().GetLoadedReadonlyAddresses()) {
+		return false
+	}
+	// 同一账户在一笔交易中只会出现一次，命中数等于 required 数量即全部命中
+	return matched == len(f.required)
+}
+
+// parseAccounts 校验并去重 base58 地址，保持原有顺序
+func parseAccounts(accounts []string) ([]string, map[types.Pubkey]struct{}, error) {
+	list := make([]string, 0, len(accounts))
+	set := make(map[types.Pubkey]struct{}, len(accounts))
+	for _, account := range accounts {
+		pk, err := types.TryPubkeyFromBase58(account)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := set[pk]; ok {
+			continue
+		}
+		set[pk] = struct{}{}
+		list = append(list, account)
+	}
+	return list, set, nil
+}
@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"dex-indexer-sol/internal/config"
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/eventparser"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"testing"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func filterTestTx(index uint64, static []string, loadedWritable []string) *pb.SubscribeUpdateTransactionInfo {
+	keys := make([][]byte, 0, len(static))
+	for _, name := range static {
+		keys = append(keys, testfixture.Bytes(name))
+	}
+	loaded := make([][]byte, 0, len(loadedWritable))
+	for _, name := range loadedWritable {
+		loaded = append(loaded, testfixture.Bytes(name))
+	}
+	return &pb.SubscribeUpdateTransactionInfo{
+		Index:       index,
+		Transaction: &pb.Transaction{Message: &pb.Message{AccountKeys: keys}},
+		Meta:        &pb.TransactionStatusMeta{LoadedWritableAddresses: loaded},
+	}
+}
+
+func blockTxIndexes(block *pb.SubscribeUpdateBlock) []uint64 {
+	indexes := make([]uint64, 0, len(block.Transactions))
+	for _, tx := range block.Transactions {
+		indexes = append(indexes, tx.Index)
+	}
+	return indexes
+}
+
+func TestNewAccountFilter_DefaultsAndDedup(t *testing.T) {
+	f, err := NewAccountFilter(config.AccountFilterConfig{}, false)
+	require.NoError(t, err)
+	assert.NotEmpty(t, f.Include)
+	assert.Subset(t, consts.GrpcAccountInclude, f.Include, "block 模式默认使用内置列表")
+
+	a, b := testfixture.Key("filter:a").String(), testfixture.Key("filter:b").String()
+	f, err = NewAccountFilter(config.AccountFilterConfig{
+		Include: []string{b, a, b},
+		Exclude: []string{a, a},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{b, a}, f.Include, "去重并保持原有顺序")
+	assert.Equal(t, []string{a}, f.Exclude)
+	assert.Len(t, f.exclude, 1)
+	assert.Equal(t, "include=2, exclude=1, required=0", f.String())
+}
+
+func TestNewAccountFilter_InvalidAccount(t *testing.T) {
+	_, err := NewAccountFilter(config.AccountFilterConfig{Include: []string{"0OIl"}}, false)
+	assert.ErrorContains(t, err, "invalid include account")
+	_, err = NewAccountFilter(config.AccountFilterConfig{Exclude: []string{"short"}}, false)
+	assert.ErrorContains(t, err, "invalid exclude account")
+	_, err = NewAccountFilter(config.AccountFilterConfig{Required: []string{""}}, false)
+	assert.ErrorContains(t, err, "invalid required account")
+}
+
+func TestNewAccountFilter_AutoPrograms(t *testing.T) {
+	eventparser.Init()
+	programs := eventparser.ProgramIDs()
+	require.NotEmpty(t, programs)
+
+	// transaction 模式未配置 include 时订阅全部已注册的程序
+	f, err := NewAccountFilter(config.AccountFilterConfig{}, true)
+	require.NoError(t, err)
+	assert.Equal(t, programs, f.Include)
+
+	// auto_programs：配置的 include 在前，已注册的程序追加在后
+	extra := testfixture.Key("filter:extra").String()
+	f, err = NewAccountFilter(config.AccountFilterConfig{AutoPrograms: true, Include: []string{extra, programs[0]}}, false)
+	require.NoError(t, err)
+	assert.Equal(t, append([]string{extra}, programs...), f.Include)
+}
+
+func TestAccountFilter_FilterBlock(t *testing.T) {
+	f, err := NewAccountFilter(config.AccountFilterConfig{
+		Exclude:  []string{testfixture.Key("filter:spam").String()},
+		Required: []string{testfixture.Key("filter:pool").String(), testfixture.Key("filter:mint").String()},
+	}, false)
+	require.NoError(t, err)
+
+	block := &pb.SubscribeUpdateBlock{Transactions: []*pb.SubscribeUpdateTransactionInfo{
+		filterTestTx(0, []string{"filter:user", "filter:pool", "filter:mint"}, nil),
+		filterTestTx(1, []string{"filter:user", "filter:pool"}, nil),                     // 缺少 mint
+		filterTestTx(2, []string{"filter:user", "filter:pool"}, []string{"filter:mint"}), // mint 由 ALT 加载
+		filterTestTx(3, []string{"filter:user", "filter:pool", "filter:mint", "filter:spam"}, nil),
+		filterTestTx(4, []string{"filter:pool", "filter:mint"}, []string{"filter:spam"}),                 // spam 由 ALT 加载
+		{Index: 5, Transaction: &pb.Transaction{Message: &pb.Message{AccountKeys: [][]byte{{1, 2, 3}}}}}, // 非法长度的 key 忽略
+	}}
+	f.filterBlock(block)
+	assert.Equal(t, []uint64{0, 2}, blockTxIndexes(block))
+}
+
+func TestAccountFilter_FilterBlockWithoutLocalRules(t *testing.T) {
+	f, err := NewAccountFilter(config.AccountFilterConfig{Include: []string{testfixture.Key("filter:pool").String()}}, false)
+	require.NoError(t, err)
+
+	block := &pb.SubscribeUpdateBlock{Transactions: []*pb.SubscribeUpdateTransactionInfo{
+		filterTestTx(0, []string{"filter:user"}, nil),
+		filterTestTx(1, []string{"filter:spam"}, nil),
+	}}
+	f.filterBlock(block)
+	assert.Equal(t, []uint64{0, 1}, blockTxIndexes(block), "include 由服务端过滤，本地不处理")
+}
@@ -0,0 +1,219 @@
+package grpc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"dex-indexer-sol/internal/config"
+	"dex-indexer-sol/internal/pkg/logger"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	captureFilePrefix    = "blocks-"
+	captureFileExt       = ".pb.gz"
+	captureQueueSize     = 256
+	captureFlushInterval = time.Second
+)
+
+// BlockCapture 将收到的原始区块写入磁盘，用于线上问题的离线复现（见 BlockReplaySource）。
+// 文件格式：gzip 压缩的 length-delimited protobuf 流，每条记录为一个 SubscribeUpdate：
+// UpdateOneof 为区块本身，Filters 为来源 endpoint，CreatedAt 为收到区块的本地时间（用于按原速回放）。
+// 单个文件超过大小上限后滚动，超过文件数上限时删除最旧的文件。
+type BlockCapture struct {
+	dir      string
+	maxBytes int64
+	maxFiles int
+	queue    chan *pb.SubscribeUpdate
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	dropped  atomic.Int64
+
+	file    *os.File
+	counter *countingWriter
+	gz      *gzip.Writer
+	buf     *bufio.Writer
+}
+
+func NewBlockCapture(conf config.CaptureConfig) (*BlockCapture, error) {
+	if err := os.MkdirAll(conf.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create capture dir %s error: %w", conf.Dir, err)
+	}
+	return &BlockCapture{
+		dir:      conf.Dir,
+		maxBytes: int64(conf.MaxFileSizeMB) << 20,
+		maxFiles: conf.MaxFiles,
+		queue:    make(chan *pb.SubscribeUpdate, captureQueueSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (c *BlockCapture) Start() {
+	go c.writeLoop()
+}
+
+func (c *BlockCapture) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		<-c.done
+	})
+}
+
+// Write 异步写入区块，队列满时丢弃（抓包不能影响实时处理）
+func (c *BlockCapture) Write(source string, block *pb.SubscribeUpdateBlock) {
+	update := &pb.SubscribeUpdate{
+		Filters:     []string{source},
+		UpdateOneof: &pb.SubscribeUpdate_Block{Block: block},
+		CreatedAt:   timestamppb.Now(),
+	}
+	select {
+	case c.queue <- update:
+	default:
+		if dropped := c.dropped.Add(1); dropped%100 == 1 {
+			logger.Warnf("[BlockCapture] 写入队列已满，丢弃 slot %d（累计丢弃 %d）", block.Slot, dropped)
+		}
+	}
+}
+
+func (c *BlockCapture) writeLoop() {
+	defer close(c.done)
+	defer c.closeFile()
+
+	ticker := time.NewTicker(captureFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			// 写完队列中剩余的区块后退出
+			for {
+				select {
+				case update := <-c.queue:
+					c.writeOrLog(update)
+				default:
+					return
+				}
+			}
+		case update := <-c.queue:
+			c.writeOrLog(update)
+		case <-ticker.C:
+			// 定期 flush，进程异常退出时最多丢失 1 秒的数据
+			if c.gz != nil {
+				if err := c.buf.Flush(); err == nil {
+					_ = c.gz.Flush()
+				}
+			}
+		}
+	}
+}
+
+func (c *BlockCapture) writeOrLog(update *pb.SubscribeUpdate) {
+	if err := c.write(update); err != nil {
+		logger.Errorf("[BlockCapture] 写入 slot %d 失败: %v", update.GetBlock().GetSlot(), err)
+		c.closeFile()
+	}
+}
+
+func (c *BlockCapture) write(update *pb.SubscribeUpdate) error {
+	if c.file == nil {
+		if err := c.openFile(update.GetBlock().GetSlot()); err != nil {
+			return err
+		}
+	}
+	if _, err := protodelim.MarshalTo(c.buf, update); err != nil {
+		return err
+	}
+	if c.maxBytes > 0 && c.counter.n >= c.maxBytes {
+		c.closeFile() // 下一条记录写入新文件
+	}
+	return nil
+}
+
+func (c *BlockCapture) openFile(firstSlot uint64) error {
+	name := fmt.Sprintf("%s%s-%d%s", captureFilePrefix, time.Now().Format("20060102-150405"), firstSlot, captureFileExt)
+	file, err := os.Create(filepath.Join(c.dir, name))
+	if err != nil {
+		return err
+	}
+	c.file = file
+	c.counter = &countingWriter{w: file}
+	c.gz = gzip.NewWriter(c.counter)
+	c.buf = bufio.NewWriterSize(c.gz, 1<<20)
+	logger.Infof("[BlockCapture] 开始写入文件 %s", name)
+
+	c.cleanup()
+	return nil
+}
+
+func (c *BlockCapture) closeFile() {
+	if c.file == nil {
+		return
+	}
+	if err := c.buf.Flush(); err != nil {
+		logger.Warnf("[BlockCapture] flush 失败: %v", err)
+	}
+	if err := c.gz.Close(); err != nil {
+		logger.Warnf("[BlockCapture] gzip close 失败: %v", err)
+	}
+	if err := c.file.Close(); err != nil {
+		logger.Warnf("[BlockCapture] 文件关闭失败: %v", err)
+	}
+	c.file, c.counter, c.gz, c.buf = nil, nil, nil, nil
+}
+
+// cleanup 删除超出数量上限的最旧文件（文件名以时间开头，按名称排序即按时间排序）
+func (c *BlockCapture) cleanup() {
+	if c.maxFiles <= 0 {
+		return
+	}
+	files, err := listCaptureFiles(c.dir)
+	if err != nil || len(files) <= c.maxFiles {
+		return
+	}
+	for _, path := range files[:len(files)-c.maxFiles] {
+		if err = os.Remove(path); err != nil {
+			logger.Warnf("[BlockCapture] 删除旧文件失败: %s, err=%v", path, err)
+		}
+	}
+}
+
+// listCaptureFiles 返回目录下的全部抓包文件（按名称升序，即按写入时间升序）
+func listCaptureFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, captureFilePrefix) && strings.HasSuffix(name, captureFileExt) {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// countingWriter 统计写入底层文件的字节数（压缩后大小），用于按大小滚动
+type countingWriter struct {
+	w *os.File
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
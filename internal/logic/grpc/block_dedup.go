@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"dex-indexer-sol/internal/pkg/logger"
+	"sync"
+	"time"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+)
+
+const (
+	dedupWindowSlots = 3000 // 去重窗口（约 20 分钟），早于 highestSlot - window 的区块视为过期直接丢弃
+	dedupStatsStep   = 1000 // 每下发多少个 slot 打印一次各 endpoint 的领先统计
+)
+
+// BlockDeduper 汇聚多个 gRPC 流的区块：每个 slot+blockhash 只下发最先到达的一份，
+// 并基于已下发的 slot 检测缺口（某个流丢块但其他流补上时不算缺口）。
+type BlockDeduper struct {
+	mu          sync.Mutex
+	blockChan   chan *pb.SubscribeUpdateBlock // 下游区块通道
+	slotChecker *SlotChecker                  // 缺口检测与 RPC 补块
+	spill       *BlockSpillQueue              // blockChan 写满时的磁盘溢出队列，未配置时为 nil
+	capture     *BlockCapture                 // 原始区块抓包，未配置时为 nil
+	seen        map[uint64][]string           // slot → 已下发的 blockhash 列表（分叉时同一 slot 可能有多个）
+	highestSlot uint64                        // 已下发的最大 slot
+	wins        map[string]int                // endpoint → 抢先下发的 slot 数
+	arrivals    map[string]time.Time          // endpoint → 最近一次收到区块的时间（含重复区块），用于判断来源是否存活
+	forwarded   int                           // 累计下发的区块数
+}
+
+func NewBlockDeduper(
+	blockChan chan *pb.SubscribeUpdateBlock,
+	slotChecker *SlotChecker,
+	spill *BlockSpillQueue,
+	capture *BlockCapture,
+) *BlockDeduper {
+	return &BlockDeduper{
+		blockChan:   blockChan,
+		slotChecker: slotChecker,
+		spill:       spill,
+		capture:     capture,
+		seen:        make(map[uint64][]string, dedupWindowSlots*2),
+		wins:        make(map[string]int),
+		arrivals:    make(map[string]time.Time),
+	}
+}
+
+// Forward 尝试下发区块，返回是否为首次到达（重复或过期的区块返回 false）。
+// 下发路径（blockChan / 溢出队列）与溢出队列的位置在锁内决定，保证多个流并发下发时与溢出队列的写回顺序一致；
+// 落盘（序列化 + 写文件）在锁外执行，避免阻塞其他流。两者都写入失败时释放该 slot 的去重记录并提交补块检测。
+func (d *BlockDeduper) Forward(source string, block *pb.SubscribeUpdateBlock) bool {
+	d.mu.Lock()
+	d.arrivals[source] = time.Now()
+	if !d.markLocked(source, block) {
+		d.mu.Unlock()
+		return false
+	}
+
+	if d.capture != nil {
+		d.capture.Write(source, block)
+	}
+
+	reservation, route := d.routeLocked(block)
+	if route == routeDiscard {
+		// 未下发成功的 slot 不能保留去重记录，否则 SlotChecker 会认为已送达而跳过补块
+		d.releaseLocked(block.Slot)
+	}
+	d.mu.Unlock()
+
+	if route == routeSpill && !d.spill.Write(reservation, block) {
+		d.Release(block.Slot)
+	}
+	return true
+}
+
+// dedupRoute 区块的下发路径
+type dedupRoute int
+
+const (
+	routeChan    dedupRoute = iota // 已写入 blockChan
+	routeSpill                     // 已在溢出队列中占位，待锁外落盘
+	routeDiscard                   // 无法下发
+)
+
+// routeLocked 决定区块的下发路径：写入 blockChan（非阻塞），或在溢出队列中占位。
+// 溢出队列仍有积压（含已占位、尚未落盘的区块）时排在队列之后，保证按 slot 顺序写回。
+func (d *BlockDeduper) routeLocked(block *pb.SubscribeUpdateBlock) (spillReservation, dedupRoute) {
+	if d.spill == nil || d.spill.Depth() == 0 {
+		select {
+		case d.blockChan <- block:
+			return spillReservation{}, routeChan
+		default:
+		}
+	}
+	if d.spill == nil {
+		logger.Warnf("[BlockDeduper] blockChan is full, discard block at slot %v, 已提交补块检测", block.Slot)
+		return spillReservation{}, routeDiscard
+	}
+	if reservation, ok := d.spill.Reserve(block.Slot); ok {
+		return reservation, routeSpill
+	}
+	return spillReservation{}, routeDiscard
+}
+
+// Release 释放已下发但未能成功分发的 slot：从去重记录中移除（续传回放或迟到的同一区块可再次下发），
+// 并提交给 SlotChecker，若之后仍未重新送达则通过 RPC 补块。
+func (d *BlockDeduper) Release(slot uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.releaseLocked(slot)
+}
+
+func (d *BlockDeduper) releaseLocked(slot uint64) {
+	delete(d.seen, slot)
+	if d.slotChecker != nil {
+		d.slotChecker.Submit(slot, slot)
+	}
+}
+
+// Seen 判断 slot 是否已经下发过（任意 blockhash），供 SlotChecker 补块前复核。
+func (d *BlockDeduper) Seen(slot uint64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.seen[slot]
+	return ok
+}
+
+// LastArrival 返回指定来源中最近一次收到区块的时间，均未收到过时返回零值。
+func (d *BlockDeduper) LastArrival(sources []string) time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var last time.Time
+	for _, source := range sources {
+		if t := d.arrivals[source]; t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+func (d *BlockDeduper) markLocked(source string, block *pb.SubscribeUpdateBlock) bool {
+	slot := block.Slot
+	if d.highestSlot > dedupWindowSlots && slot < d.highestSlot-dedupWindowSlots {
+		return false // 已超出去重窗口，视为过期数据
+	}
+
+	hashes := d.seen[slot]
+	for _, h := range hashes {
+		if h == block.Blockhash {
+			return false
+		}
+	}
+	d.seen[slot] = append(hashes, block.Blockhash)
+
+	// 检查是否丢失 slot：只以已下发的最大 slot 为基准，迟到的 slot 不会重复提交
+	if d.highestSlot != 0 && slot > d.highestSlot+1 && d.slotChecker != nil {
+		d.slotChecker.Submit(d.highestSlot+1, slot-1)
+	}
+	if slot > d.highestSlot {
+		d.highestSlot = slot
+	}
+
+	d.wins[source]++
+	d.forwarded++
+	if d.forwarded%dedupStatsStep == 0 {
+		logger.Infof("[BlockDeduper] 已下发 %d 个区块, 各 endpoint 领先次数: %v", d.forwarded, d.wins)
+	}
+
+	d.evictLocked()
+	return true
+}
+
+// evictLocked 清理去重窗口之外的 slot，避免 map 无限增长
+func (d *BlockDeduper) evictLocked() {
+	if len(d.seen) < dedupWindowSlots*2 || d.highestSlot <= dedupWindowSlots {
+		return
+	}
+	minSlot := d.highestSlot - dedupWindowSlots
+	for slot := range d.seen {
+		if slot < minSlot {
+			delete(d.seen, slot)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"dex-indexer-sol/internal/config"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/rpc"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBlock(slot uint64, hash string) *pb.SubscribeUpdateBlock {
+	return &pb.SubscribeUpdateBlock{Slot: slot, Blockhash: hash, ParentSlot: slot - 1}
+}
+
+// newTestDeduper 创建不启动后台协程的去重器，SlotChecker 提交的缺口范围可从 checker.rangeCh 读取
+func newTestDeduper(t *testing.T, chanSize int, spill *BlockSpillQueue) (*BlockDeduper, chan *pb.SubscribeUpdateBlock, *SlotChecker) {
+	t.Helper()
+	blockChan := make(chan *pb.SubscribeUpdateBlock, chanSize)
+	checker := NewSlotChecker("http://127.0.0.1:0", rpc.CommitmentConfirmed, nil)
+	t.Cleanup(checker.Stop)
+	return NewBlockDeduper(blockChan, checker, spill, nil), blockChan, checker
+}
+
+func TestBlockDeduper_ForwardsFirstArrivalOnly(t *testing.T) {
+	d, blockChan, _ := newTestDeduper(t, 10, nil)
+
+	assert.True(t, d.Forward("a", testBlock(100, "h100")))
+	assert.False(t, d.Forward("b", testBlock(100, "h100")), "同一 slot+blockhash 只下发一次")
+	// 分叉：同一 slot 的另一个 blockhash 照常下发
+	assert.True(t, d.Forward("b", testBlock(100, "h100-fork")))
+
+	require.Len(t, blockChan, 2)
+	assert.Equal(t, "h100", (<-blockChan).Blockhash)
+	assert.Equal(t, "h100-fork", (<-blockChan).Blockhash)
+	assert.True(t, d.Seen(100))
+	assert.False(t, d.Seen(101))
+	assert.Equal(t, map[string]int{"a": 1, "b": 1}, d.wins)
+	assert.False(t, d.LastArrival([]string{"a", "b"}).IsZero())
+	assert.True(t, d.LastArrival([]string{"c"}).IsZero())
+}
+
+func TestBlockDeduper_DropsExpiredSlots(t *testing.T) {
+	d, blockChan, _ := newTestDeduper(t, 10, nil)
+
+	require.True(t, d.Forward("a", testBlock(10_000, "h")))
+	assert.False(t, d.Forward("a", testBlock(10_000-dedupWindowSlots-1, "old")), "早于去重窗口的区块视为过期")
+	assert.True(t, d.Forward("a", testBlock(10_000-dedupWindowSlots, "edge")), "窗口边界内的迟到区块照常下发")
+	assert.Len(t, blockChan, 2)
+}
+
+func TestBlockDeduper_SubmitsGaps(t *testing.T) {
+	d, _, checker := newTestDeduper(t, 10, nil)
+
+	d.Forward("a", testBlock(100, "h100"))
+	d.Forward("a", testBlock(104, "h104"))
+	require.Len(t, checker.rangeCh, 1)
+	r := <-checker.rangeCh
+	assert.Equal(t, [2]uint64{101, 103}, [2]uint64{r.From, r.To})
+
+	// 迟到的 slot 不重复提交缺口
+	d.Forward("b", testBlock(102, "h102"))
+	assert.Empty(t, checker.rangeCh)
+}
+
+func TestBlockDeduper_ReleasesWhenChanFullWithoutSpill(t *testing.T) {
+	d, blockChan, checker := newTestDeduper(t, 1, nil)
+
+	require.True(t, d.Forward("a", testBlock(100, "h100")))
+	// blockChan 已满且未配置溢出队列：仍视为首次到达，但释放去重记录并提交补块
+	assert.True(t, d.Forward("a", testBlock(101, "h101")))
+	assert.False(t, d.Seen(101))
+	require.Len(t, checker.rangeCh, 1)
+	r := <-checker.rangeCh
+	assert.Equal(t, [2]uint64{101, 101}, [2]uint64{r.From, r.To})
+
+	// 释放后同一区块可再次下发
+	<-blockChan
+	assert.True(t, d.Forward("b", testBlock(101, "h101")))
+	assert.True(t, d.Seen(101))
+}
+
+func TestBlockDeduper_SpillKeepsOrder(t *testing.T) {
+	spill, err := NewBlockSpillQueue(config.SpillQueueConfig{Dir: t.TempDir()}, nil)
+	require.NoError(t, err)
+	d, blockChan, _ := newTestDeduper(t, 1, spill)
+
+	d.Forward("a", testBlock(100, "h100"))
+	d.Forward("a", testBlock(101, "h101")) // blockChan 已满，落盘
+	assert.Equal(t, 1, spill.Depth())
+
+	// blockChan 空出位置后，溢出队列仍有积压，新区块排在其后落盘
+	<-blockChan
+	d.Forward("a", testBlock(102, "h102"))
+	assert.Empty(t, blockChan)
+	assert.Equal(t, 2, spill.Depth())
+	assert.True(t, d.Seen(101))
+	assert.True(t, d.Seen(102))
+}
+
+func TestBlockDeduper_ReleasesWhenSpillFull(t *testing.T) {
+	spill, err := NewBlockSpillQueue(config.SpillQueueConfig{Dir: t.TempDir(), MaxBlocks: 1}, nil)
+	require.NoError(t, err)
+	d, _, checker := newTestDeduper(t, 1, spill)
+
+	d.Forward("a", testBlock(100, "h100"))
+	d.Forward("a", testBlock(101, "h101"))
+	assert.True(t, d.Forward("a", testBlock(102, "h102")))
+	assert.True(t, d.Seen(101))
+	assert.False(t, d.Seen(102), "溢出队列已满时释放去重记录")
+	require.Len(t, checker.rangeCh, 1)
+	assert.Equal(t, uint64(102), (<-checker.rangeCh).From)
+}
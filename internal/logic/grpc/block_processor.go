@@ -19,6 +19,7 @@ import (
 	"runtime"
 
 	"errors"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -36,34 +37,66 @@ const (
 var workerCount = consts.CpuCount + 2
 
 type BlockProcessor struct {
-	sc        *svc.GrpcServiceContext
-	blockChan chan *pb.SubscribeUpdateBlock // 接收 block 的 channel
-	ctx       context.Context
-	cancel    func(err error)
+	sc           *svc.GrpcServiceContext
+	blockChan    chan *pb.SubscribeUpdateBlock // 接收 block 的 channel
+	rpcBlockChan chan *pb.SubscribeUpdateBlock // 接收 RPC 补块的 channel（SlotChecker 写入）
+	ctx          context.Context
+	cancel       func(err error)
 
 	startTime             time.Time
 	activeSlotDispatch    int64 // 当前活跃的 slot dispatch goroutine 数（用于限流发事件 + 同步进度）
 	lastBlockChanWarnTime int64
+	forkTracker           *ForkTracker // 分叉检测，未开启时为 nil
+	historical            bool         // 历史回填：不读写实时价格缓存，事件不带 USD 估值与 quote 价格
+	dryRun                bool         // 只解析与构建任务，不发送 Kafka（离线回放）
+
+	// 实时（gRPC）slot 的分发进度，用于断线续传：dispatch 并发执行，只有低于全部在途 slot 的位置才是连续完成的
+	dispatchMu     sync.Mutex
+	inflight       map[uint64]struct{} // 已开始分发、尚未结束的实时 slot
+	maxDispatched  uint64              // 已成功分发的最大实时 slot
+	dispatchFailed func(slot uint64)   // 实时 slot 已去重下发但未能成功分发时的回调（交给去重器释放并补块），可为 nil
 }
 
 func NewBlockProcessor(sc *svc.GrpcServiceContext, blockChan chan *pb.SubscribeUpdateBlock) *BlockProcessor {
 	ctx, cancel := context.WithCancelCause(context.Background())
-	return &BlockProcessor{
-		sc:        sc,
-		blockChan: blockChan,
-		ctx:       ctx,
-		cancel:    cancel,
+	p := &BlockProcessor{
+		sc:           sc,
+		blockChan:    blockChan,
+		rpcBlockChan: make(chan *pb.SubscribeUpdateBlock, 16),
+		ctx:          ctx,
+		cancel:       cancel,
+		inflight:     make(map[uint64]struct{}, maxSlotDispatch),
 	}
+	// finalized 订阅下区块不会再分叉，无需跟踪
+	grpcConf := sc.Config.Grpc
+	if (grpcConf.EnableForkDetection || grpcConf.EmitFinalizedMarker) &&
+		parseCommitment(grpcConf.Commitment) != pb.CommitmentLevel_FINALIZED {
+		p.forkTracker = NewForkTracker(sc, grpcConf.EnableForkDetection, grpcConf.EmitFinalizedMarker)
+	}
+	return p
+}
+
+// NewHistoricalBlockProcessor 创建历史回填（cmd/backfill）使用的处理器，只通过 BuildBlockJobs 构建任务。
+// PriceCache 只有当前价格，无法还原历史价格，且并发回填的区块乱序，因此不更新价格缓存、不补全 USD 估值。
+func NewHistoricalBlockProcessor(sc *svc.GrpcServiceContext) *BlockProcessor {
+	p := NewBlockProcessor(sc, nil)
+	p.historical = true
+	return p
 }
 
 func (p *BlockProcessor) Start() {
 	p.startTime = time.Now()
+	if p.forkTracker != nil {
+		p.forkTracker.Start()
+	}
 	for {
 		select {
 		case <-p.ctx.Done():
 			return // 退出
+		case block := <-p.rpcBlockChan:
+			p.procBlock(block, int32(progress.SourceRpc))
 		case block := <-p.blockChan:
-			p.procBlock(block)
+			p.procBlock(block, sourceGrpc)
 			if len(p.blockChan) > 10 {
 				now := time.Now().Unix()
 				if now-p.lastBlockChanWarnTime >= 10 {
@@ -77,38 +110,136 @@ func (p *BlockProcessor) Start() {
 
 func (p *BlockProcessor) Stop() {
 	p.cancel(errors.New("service stop"))
+	if p.forkTracker != nil {
+		p.forkTracker.Stop()
+	}
+}
+
+// SubmitRpcBlock 提交一个通过 RPC 补回的区块，与实时区块走同一套处理流程（source = RPC）。
+// 阻塞直到写入成功、超时或服务停止，用于对 SlotChecker 形成背压。
+func (p *BlockProcessor) SubmitRpcBlock(block *pb.SubscribeUpdateBlock, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case p.rpcBlockChan <- block:
+		return true
+	case <-timer.C:
+		return false
+	case <-p.ctx.Done():
+		return false
+	}
+}
+
+// SetDryRun 开启后只解析区块并构建 Kafka 任务，不发送、不写进度（用于离线回放排查解析问题）
+func (p *BlockProcessor) SetDryRun(dryRun bool) {
+	p.dryRun = dryRun
+}
+
+// SetDispatchFailedFunc 设置实时 slot 分发失败（构建任务失败、dispatch 限流、Kafka 发送失败）时的回调
+func (p *BlockProcessor) SetDispatchFailedFunc(fn func(slot uint64)) {
+	p.dispatchFailed = fn
+}
+
+// ResumeSlot 返回断线续传的起始 slot：存在在途 slot 时为其中最小的一个，否则为已成功分发的最大 slot + 1；
+// 尚未分发过时返回 0。低于该位置的实时 slot 均已分发结束（成功，或失败后已交给补块流程）。
+func (p *BlockProcessor) ResumeSlot() uint64 {
+	p.dispatchMu.Lock()
+	defer p.dispatchMu.Unlock()
+
+	resume := uint64(0)
+	if p.maxDispatched > 0 {
+		resume = p.maxDispatched + 1
+	}
+	for slot := range p.inflight {
+		if resume == 0 || slot < resume {
+			resume = slot
+		}
+	}
+	return resume
 }
 
-func (p *BlockProcessor) procBlock(block *pb.SubscribeUpdateBlock) {
+// beginDispatch 记录开始分发的实时 slot
+func (p *BlockProcessor) beginDispatch(slot uint64) {
+	p.dispatchMu.Lock()
+	p.inflight[slot] = struct{}{}
+	p.dispatchMu.Unlock()
+}
+
+// endDispatch 记录实时 slot 分发结束；失败时通知去重器释放该 slot 并补块
+func (p *BlockProcessor) endDispatch(slot uint64, ok bool) {
+	p.dispatchMu.Lock()
+	delete(p.inflight, slot)
+	if ok && slot > p.maxDispatched {
+		p.maxDispatched = slot
+	}
+	p.dispatchMu.Unlock()
+
+	if !ok {
+		p.onDispatchFailed(slot)
+	}
+}
+
+func (p *BlockProcessor) onDispatchFailed(slot uint64) {
+	if p.dispatchFailed != nil {
+		p.dispatchFailed(slot)
+	}
+}
+
+func (p *BlockProcessor) procBlock(block *pb.SubscribeUpdateBlock, source int32) {
 	startTime := time.Now()
 	defer func() {
 		if r := recover(); r != nil {
 			logger.Errorf("[BlockProcessor] procBlock panic: %v (slot: %d)", r, block.Slot)
 		}
-		logger.Infof("[BlockProcessor] 区块处理总耗时: %v, slot: %d", time.Since(startTime), block.Slot)
+		logger.Infof("[BlockProcessor] 区块处理总耗时: %v, slot: %d, source: %d", time.Since(startTime), block.Slot, source)
 	}()
 
+	txCtx := p.buildTxContext(block)
+
+	// 记录 slot → blockhash/parent，检测分叉（已分发的 slot 被孤立时广播回滚消息）；
+	// 区块本身是迟到的孤立区块时，不再更新价格与分发事件
+	if p.forkTracker != nil && p.forkTracker.OnBlock(txCtx) {
+		logger.Warnf("[BlockProcessor] slot %d 为孤立区块，跳过分发, source: %d", block.Slot, source)
+		return
+	}
+
+	mqJobs, ok := p.buildBlockJobs(block, txCtx, source)
+	if !ok {
+		if source == sourceGrpc {
+			p.onDispatchFailed(block.Slot)
+		}
+		return
+	}
+
+	// 7. 分发任务（Kafka 推送 + 写进度）
+	dispatchStart := time.Now()
+	p.dispatchSlot(txCtx.Slot, txCtx.BlockTime, int16(source), mqJobs)
+	logger.Infof("[BlockProcessor] 任务分发耗时: %v", time.Since(dispatchStart))
+}
+
+// BuildBlockJobs 解析区块内的全部交易并构建 Kafka 任务（不发送），
+// 供实时处理与历史回填（cmd/backfill）共用。获取报价失败时返回 ok=false。
+func (p *BlockProcessor) BuildBlockJobs(block *pb.SubscribeUpdateBlock, source int32) (*core.TxContext, []*mq.KafkaJob, bool) {
+	txCtx := p.buildTxContext(block)
+	mqJobs, ok := p.buildBlockJobs(block, txCtx, source)
+	return txCtx, mqJobs, ok
+}
+
+func (p *BlockProcessor) buildBlockJobs(block *pb.SubscribeUpdateBlock, txCtx *core.TxContext, source int32) ([]*mq.KafkaJob, bool) {
 	// 1. 过滤合法交易
 	filterStart := time.Now()
+	includeFailed := p.sc.Config.FailedTxConf.Enable
 	validTxs := make([]*pb.SubscribeUpdateTransactionInfo, 0, len(block.Transactions))
 	for _, tx := range block.Transactions {
-		if IsValidGrpcTx(tx) {
+		if IsValidGrpcTx(tx) || (includeFailed && IsFailedGrpcTx(tx)) {
 			validTxs = append(validTxs, tx)
 		}
 	}
 	logger.Infof("[BlockProcessor] 交易过滤耗时: %v, 总交易数: %d, 有效交易数: %d",
 		time.Since(filterStart), len(block.Transactions), len(validTxs))
 
-	// 2. 构造上下文
-	ctxStart := time.Now()
-	txCtx := p.buildTxContext(block)
-	if txCtx == nil {
-		logger.Errorf("[BlockProcessor] 构造 txCtx 失败, slot: %d", block.Slot)
-		return
-	}
-	logger.Infof("[BlockProcessor] 上下文构造耗时: %v", time.Since(ctxStart))
-
-	// 3. 并发解析所有交易，构造 ParsedTxResult 列表。
+	// 2. 并发解析所有交易，构造 ParsedTxResult 列表。
 	parseStart := time.Now()
 	results := utils.ParallelMap(
 		validTxs,
@@ -122,23 +253,26 @@ func (p *BlockProcessor) procBlock(block *pb.SubscribeUpdateBlock) {
 		})
 	logger.Infof("[BlockProcessor] 事件解析耗时: %v", time.Since(parseStart))
 
-	// 4. 更新价格缓存，并补全 USD 估值
-	usdStart := time.Now()
-	p.updatePriceCacheFromEvents(results)                      // 更新 token 最新价格至 PriceCache
-	quotePrices := p.loadQuotePricesFromCache(txCtx.BlockTime) // 从 PriceCache 读取 quote token 价格（SOL/USDC/USDT）
-	if quotePrices == nil {
-		logger.Errorf("[BlockProcessor] 获取 quotePrices 失败, slot: %d", block.Slot)
-		return
+	// 3. 更新价格缓存，并补全 USD 估值（历史回填时 PriceCache 中只有当前价格，USD 估值留空）
+	var quotePrices []*pb2.TokenPrice
+	if !p.historical {
+		usdStart := time.Now()
+		p.updatePriceCacheFromEvents(results)                     // 更新 token 最新价格至 PriceCache
+		quotePrices = p.loadQuotePricesFromCache(txCtx.BlockTime) // 从 PriceCache 读取 quote token 价格（SOL/USDC/USDT）
+		if quotePrices == nil {
+			logger.Errorf("[BlockProcessor] 获取 quotePrices 失败, slot: %d", block.Slot)
+			return nil, false
+		}
+		fillUsdAmountForEvents(results, quotePrices) // 用 quotePrices 填充所有 TradeEvent 的 USD 金额
+		logger.Infof("[BlockProcessor] 补全 USD 估值完成, 耗时: %v", time.Since(usdStart))
 	}
-	fillUsdAmountForEvents(results, quotePrices) // 用 quotePrices 填充所有 TradeEvent 的 USD 金额
-	logger.Infof("[BlockProcessor] 补全 USD 估值完成, 耗时: %v", time.Since(usdStart))
 
-	// 5. 构建事件类 Kafka 任务
+	// 4. 构建事件类 Kafka 任务
 	eventStart := time.Now()
 	eventJobs, eventCount, tradeCount, validTradeCount, transferCount := jobbuilder.BuildEventKafkaJobs(
 		txCtx,
 		quotePrices,
-		sourceGrpc,
+		source,
 		p.sc.Config.KafkaProducerConf.Topics.Event,
 		p.sc.Config.KafkaProducerConf.Partitions.Event,
 		results,
@@ -147,12 +281,12 @@ func (p *BlockProcessor) procBlock(block *pb.SubscribeUpdateBlock) {
 	logger.Infof("[BlockProcessor] Kafka事件：事件 %d 条（trade %d，有效trade %d，transfer %d）, 耗时 %s",
 		eventCount, tradeCount, validTradeCount, transferCount, eventDuration)
 
-	// 6. 构建余额类 Kafka 任务
+	// 5. 构建余额类 Kafka 任务
 	balanceStart := time.Now()
 	balanceJobs, balanceCount := jobbuilder.BuildBalanceKafkaJobs(
 		txCtx,
 		quotePrices,
-		sourceGrpc,
+		source,
 		p.sc.Config.KafkaProducerConf.Topics.Balance,
 		p.sc.Config.KafkaProducerConf.Partitions.Balance,
 		results,
@@ -160,26 +294,30 @@ func (p *BlockProcessor) procBlock(block *pb.SubscribeUpdateBlock) {
 	balanceDuration := time.Since(balanceStart)
 	logger.Infof("[BlockProcessor] Kafka余额事件：事件 %d 条, 耗时 %s", balanceCount, balanceDuration)
 
-	// 7. 合并 Kafka 任务
+	// 6. 合并 Kafka 任务
 	mqJobs := make([]*mq.KafkaJob, 0, len(eventJobs)+len(balanceJobs))
 	mqJobs = append(mqJobs, eventJobs...)
 	mqJobs = append(mqJobs, balanceJobs...)
-
-	// 8. 分发任务（Kafka 推送 + 写进度）
-	dispatchStart := time.Now()
-	p.dispatchSlot(txCtx.Slot, txCtx.BlockTime, mqJobs)
-	logger.Infof("[BlockProcessor] 任务分发耗时: %v", time.Since(dispatchStart))
+	return mqJobs, true
 }
 
 func IsValidGrpcTx(tx *pb.SubscribeUpdateTransactionInfo) bool {
+	return isWellFormedGrpcTx(tx) && tx.Meta.Err == nil // - transaction execution failed
+}
+
+// IsFailedGrpcTx 结构完整但执行失败的非投票交易（开启 failed_tx 时解析为 FAILED_TRADE 事件）
+func IsFailedGrpcTx(tx *pb.SubscribeUpdateTransactionInfo) bool {
+	return isWellFormedGrpcTx(tx) && tx.Meta.Err != nil
+}
+
+func isWellFormedGrpcTx(tx *pb.SubscribeUpdateTransactionInfo) bool {
 	if tx == nil || // - nil transaction info
 		tx.Transaction == nil || // - missing Transaction field
 		tx.Transaction.Message == nil || // - missing Message field in transaction
 		len(tx.Transaction.Signatures) == 0 || // - missing transaction signature
 		len(tx.Transaction.Signatures[0]) != 64 || // - invalid transaction signature length
 		tx.IsVote || // - vote transaction skipped
-		tx.Meta == nil || // - missing transaction meta data
-		tx.Meta.Err != nil { // - transaction execution failed
+		tx.Meta == nil { // - missing transaction meta data
 		return false
 	}
 	return true
@@ -193,11 +331,18 @@ func (p *BlockProcessor) buildTxContext(block *pb.SubscribeUpdateBlock) *core.Tx
 			block.Slot, block.Blockhash, err)
 	}
 
+	var parentBlockHash types.Hash
+	if block.ParentBlockhash != "" {
+		parentBlockHash, _ = types.HashFromBase58(block.ParentBlockhash)
+	}
+
 	return &core.TxContext{
-		BlockTime:  block.BlockTime.Timestamp,
-		Slot:       block.Slot,
-		BlockHash:  blockHash, // 若解析失败为零值
-		ParentSlot: block.ParentSlot,
+		BlockTime:       block.BlockTime.Timestamp,
+		Slot:            block.Slot,
+		Epoch:           core.EpochOfSlot(block.Slot),
+		BlockHash:       blockHash, // 若解析失败为零值
+		ParentSlot:      block.ParentSlot,
+		ParentBlockHash: parentBlockHash,
 	}
 }
 
@@ -207,7 +352,25 @@ func (p *BlockProcessor) parseTx(txCtx *core.TxContext, ownerCache map[string]ty
 		return core.ParsedTxResult{}
 	}
 
+	solBalanceConf := &p.sc.Config.SolBalanceConf
+
+	// 失败交易的余额变更已回滚，只产生 FAILED_TRADE 事件；手续费仍会扣除，开启 SOL 余额推送时保留 SOL 余额变化
+	if adaptedTx.Err != nil {
+		result := core.ParsedTxResult{
+			Events: eventparser.ExtractFailedTradesFromTx(adaptedTx),
+		}
+		if solBalanceConf.Enable {
+			adaptedTx.Balances = make(map[types.Pubkey]*core.TokenBalance, 1)
+			adaptedTx.AppendSolBalanceUpdates(solBalanceConf.MinChangeLamports)
+			result.Balances = adaptedTx.Balances
+		}
+		return result
+	}
+
 	events, priceEvents := eventparser.ExtractEventsFromTx(adaptedTx)
+	if solBalanceConf.Enable {
+		adaptedTx.AppendSolBalanceUpdates(solBalanceConf.MinChangeLamports)
+	}
 	return core.ParsedTxResult{
 		Balances:    adaptedTx.Balances,
 		Events:      events,
@@ -322,10 +485,27 @@ func fillUsdAmountForEvents(results []core.ParsedTxResult, quotePrices []*pb2.To
 	}
 }
 
-func (p *BlockProcessor) dispatchSlot(slotID uint64, blockTime int64, jobs []*mq.KafkaJob) {
+func (p *BlockProcessor) dispatchSlot(slotID uint64, blockTime int64, source int16, jobs []*mq.KafkaJob) {
+	if p.dryRun {
+		logger.Infof("[BlockProcessor] dry run, slot %d 构建 Kafka 任务 %d 个，未发送", slotID, len(jobs))
+		return
+	}
+
 	if p.sc.ProgressManager != nil {
-		should, _ := p.sc.ProgressManager.ShouldProcessSlot(p.ctx, slotID, blockTime)
+		// 只跳过已处理成功的 slot（RPC 补块、续传回放等迟到区块不会被丢弃）
+		should, err := p.sc.ProgressManager.ShouldProcessSlot(p.ctx, slotID, blockTime)
+		if err != nil {
+			logger.Warnf("[BlockProcessor] slot %d 查询进度失败，继续处理: %v", slotID, err)
+		}
 		if !should {
+			logger.Infof("[BlockProcessor] slot %d 已处理过，跳过分发, source: %d", slotID, source)
+			if source == sourceGrpc {
+				// 已处理过等同于分发成功，推进续传水位
+				p.endDispatch(slotID, true)
+			}
+			if p.forkTracker != nil {
+				p.forkTracker.OnDispatched(slotID)
+			}
 			return
 		}
 	}
@@ -333,14 +513,24 @@ func (p *BlockProcessor) dispatchSlot(slotID uint64, blockTime int64, jobs []*mq
 	if atomic.AddInt64(&p.activeSlotDispatch, 1) > maxSlotDispatch {
 		atomic.AddInt64(&p.activeSlotDispatch, -1)
 		logger.Errorf("[BlockProcessor] slot %d 被丢弃：活跃 dispatch 数超过上限", slotID)
+		if source == sourceGrpc {
+			p.onDispatchFailed(slotID)
+		}
 		return
 	}
 
+	if source == sourceGrpc {
+		p.beginDispatch(slotID)
+	}
 	go func() {
+		dispatched := false
 		defer func() {
 			if r := recover(); r != nil {
 				logger.Errorf("[BlockProcessor] dispatchSlot panic: %v", r)
 			}
+			if source == sourceGrpc {
+				p.endDispatch(slotID, dispatched)
+			}
 			atomic.AddInt64(&p.activeSlotDispatch, -1)
 		}()
 
@@ -369,10 +559,15 @@ func (p *BlockProcessor) dispatchSlot(slotID uint64, blockTime int64, jobs []*mq
 		}
 
 		if len(failedJobs) == 0 {
+			dispatched = true
+			if p.forkTracker != nil {
+				p.forkTracker.OnDispatched(slotID)
+			}
+
 			// Kafka 发送成功，写入进度
 			progressStartTime := time.Now()
 			if p.sc.ProgressManager != nil {
-				err := p.sc.ProgressManager.MarkSlotStatus(ctx, progress.SourceGrpc, slotID, blockTime, progress.SlotProcessed)
+				err := p.sc.ProgressManager.MarkSlotStatus(ctx, source, slotID, blockTime, progress.SlotProcessed)
 				if err != nil {
 					logger.Errorf("[BlockProcessor] slot %d 写入进度失败: %v", slotID, err)
 				}
@@ -0,0 +1,153 @@
+package grpc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"dex-indexer-sol/internal/pkg/logger"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+const maxReplayPause = 10 * time.Second // 按原速回放时，两个区块之间的最长等待（跳过抓包中断的空档）
+
+// BlockReplaySource 读取 BlockCapture 写入的文件，将区块按顺序写入 blockChan，代替 GrpcStreamGroup 作为区块来源，
+// 用于离线复现线上解析问题。originalPace 为 true 时按抓包时的到达间隔回放，否则全速回放。
+type BlockReplaySource struct {
+	path         string // 单个抓包文件，或包含抓包文件的目录
+	originalPace bool
+	blockChan    chan *pb.SubscribeUpdateBlock
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+func NewBlockReplaySource(path string, originalPace bool, blockChan chan *pb.SubscribeUpdateBlock) (*BlockReplaySource, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("replay path %s error: %w", path, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BlockReplaySource{
+		path:         path,
+		originalPace: originalPace,
+		blockChan:    blockChan,
+		ctx:          ctx,
+		cancel:       cancel,
+	}, nil
+}
+
+func (r *BlockReplaySource) Start() {
+	files, err := r.files()
+	if err != nil {
+		logger.Errorf("[BlockReplay] 读取回放文件失败: %v", err)
+		return
+	}
+
+	logger.Infof("[BlockReplay] 开始回放 %d 个文件, originalPace=%v", len(files), r.originalPace)
+	start := time.Now()
+	total := 0
+	for _, file := range files {
+		count, err := r.replayFile(file)
+		total += count
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		if err != nil {
+			logger.Errorf("[BlockReplay] 回放文件 %s 出错（已回放 %d 个区块）: %v", file, count, err)
+			continue
+		}
+		logger.Infof("[BlockReplay] 文件 %s 回放完成, 区块 %d 个", file, count)
+	}
+	logger.Infof("[BlockReplay] 全部回放完成, 区块 %d 个, 耗时 %v", total, time.Since(start))
+}
+
+func (r *BlockReplaySource) Stop() {
+	r.cancel()
+}
+
+func (r *BlockReplaySource) files() ([]string, error) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{r.path}, nil
+	}
+	return listCaptureFiles(r.path)
+}
+
+// replayFile 回放单个文件，返回回放的区块数
+func (r *BlockReplaySource) replayFile(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+	reader := bufio.NewReaderSize(gz, 1<<20)
+
+	var lastCreated time.Time
+	count := 0
+	for {
+		update := &pb.SubscribeUpdate{}
+		if err = protodelim.UnmarshalFrom(reader, update); err != nil {
+			if errors.Is(err, io.EOF) {
+				return count, nil
+			}
+			// 进程异常退出时文件尾部可能不完整
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				logger.Warnf("[BlockReplay] 文件 %s 尾部不完整, 已忽略", path)
+				return count, nil
+			}
+			return count, err
+		}
+		block := update.GetBlock()
+		if block == nil {
+			continue
+		}
+
+		if r.originalPace && update.CreatedAt != nil {
+			created := update.CreatedAt.AsTime()
+			if !lastCreated.IsZero() {
+				if err = r.sleep(created.Sub(lastCreated)); err != nil {
+					return count, err
+				}
+			}
+			lastCreated = created
+		}
+
+		select {
+		case r.blockChan <- block:
+			count++
+		case <-r.ctx.Done():
+			return count, context.Canceled
+		}
+	}
+}
+
+func (r *BlockReplaySource) sleep(d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	if d > maxReplayPause {
+		d = maxReplayPause
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-r.ctx.Done():
+		return context.Canceled
+	}
+}
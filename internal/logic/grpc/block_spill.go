@@ -0,0 +1,345 @@
+package grpc
+
+import (
+	"container/heap"
+	"context"
+	"dex-indexer-sol/internal/config"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/monitor"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	spillFileExt      = ".blk"
+	spillTmpExt       = ".tmp"
+	spillLogDepthStep = 100 // 每积压多少个区块打印一次
+)
+
+// spillItem 磁盘上的一个区块文件
+type spillItem struct {
+	slot uint64
+	path string
+	size int64
+}
+
+// spillHeap 按 slot 升序的最小堆
+type spillHeap []*spillItem
+
+func (h spillHeap) Len() int           { return len(h) }
+func (h spillHeap) Less(i, j int) bool { return h[i].slot < h[j].slot }
+func (h spillHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *spillHeap) Push(x any)        { *h = append(*h, x.(*spillItem)) }
+func (h *spillHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// BlockSpillQueue 是 blockChan 的磁盘溢出队列：blockChan 写满时，区块序列化后落盘，
+// 待处理速度追上后按 slot 顺序重新写回 blockChan。队列有上限（区块数、字节数），
+// 超限时才会丢弃并打印错误日志；进程重启后会自动恢复磁盘上尚未处理的区块。
+type BlockSpillQueue struct {
+	mu            sync.Mutex
+	dir           string
+	maxBlocks     int
+	maxBytes      int64
+	items         spillHeap
+	bytes         int64  // 磁盘上的区块总字节数
+	inflight      int    // 已出队、尚未写入 blockChan 的区块数
+	reserved      int    // 已占位、尚未落盘的区块数
+	reservedBytes int64  // 正在落盘的区块字节数
+	seq           uint64 // 文件名序号，避免同一 slot 多个 blockhash 时冲突
+	dropped       int    // 超限丢弃的区块数
+	blockChan     chan *pb.SubscribeUpdateBlock
+	notify        chan struct{}
+	ctx           context.Context
+	cancel        context.CancelFunc
+}
+
+func NewBlockSpillQueue(conf config.SpillQueueConfig, blockChan chan *pb.SubscribeUpdateBlock) (*BlockSpillQueue, error) {
+	if err := os.MkdirAll(conf.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create spill dir %s error: %w", conf.Dir, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &BlockSpillQueue{
+		dir:       conf.Dir,
+		maxBlocks: conf.MaxBlocks,
+		maxBytes:  int64(conf.MaxSizeMB) << 20,
+		blockChan: blockChan,
+		notify:    make(chan struct{}, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	if err := q.restore(); err != nil {
+		cancel()
+		return nil, err
+	}
+	q.registerMetrics()
+	return q, nil
+}
+
+func (q *BlockSpillQueue) Start() {
+	go q.drainLoop()
+}
+
+func (q *BlockSpillQueue) Stop() {
+	q.cancel()
+	logger.Infof("[BlockSpillQueue] stopped, 剩余 %d 个区块保留在磁盘，下次启动时恢复", q.Depth())
+}
+
+// Depth 返回积压的区块数（含已占位待落盘、正在写回 blockChan 的区块）
+func (q *BlockSpillQueue) Depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items) + q.reserved + q.inflight
+}
+
+// spillReservation 溢出队列中已占位、尚未落盘的区块
+type spillReservation struct {
+	slot uint64
+	seq  uint64
+}
+
+// Reserve 为区块预占队列位置与文件序号，只更新内存计数，可在调用方的锁内执行；
+// 占位后 Depth 即包含该区块，之后必须调用 Write。队列区块数已满时返回 false。
+func (q *BlockSpillQueue) Reserve(slot uint64) (spillReservation, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.maxBlocks > 0 && len(q.items)+q.reserved >= q.maxBlocks {
+		q.dropped++
+		logger.Errorf("[BlockSpillQueue] 溢出队列已满（%d 个区块, %d MB），丢弃 slot %d，累计丢弃 %d 个",
+			len(q.items)+q.reserved, q.bytes>>20, slot, q.dropped)
+		return spillReservation{}, false
+	}
+	q.seq++
+	q.reserved++
+	return spillReservation{slot: slot, seq: q.seq}, true
+}
+
+// Write 将已占位的区块落盘，返回是否成功（超出字节上限或写盘失败时返回 false）；无论成功与否都会释放占位。
+// 序列化与写文件在锁外执行，只在检查字节上限与入堆时加锁，避免大区块写盘阻塞 Depth / drainLoop。
+func (q *BlockSpillQueue) Write(r spillReservation, block *pb.SubscribeUpdateBlock) bool {
+	data, err := proto.Marshal(block)
+	if err != nil {
+		logger.Errorf("[BlockSpillQueue] slot %d 序列化失败: %v", r.slot, err)
+		q.release(0)
+		return false
+	}
+	size := int64(len(data))
+
+	// 1. 预占字节数，超限时丢弃
+	q.mu.Lock()
+	if q.maxBytes > 0 && q.bytes+q.reservedBytes+size > q.maxBytes {
+		q.reserved--
+		q.dropped++
+		logger.Errorf("[BlockSpillQueue] 溢出队列已满（%d 个区块, %d MB），丢弃 slot %d，累计丢弃 %d 个",
+			len(q.items)+q.reserved, (q.bytes+q.reservedBytes)>>20, r.slot, q.dropped)
+		q.mu.Unlock()
+		return false
+	}
+	q.reservedBytes += size
+	q.mu.Unlock()
+
+	// 2. 锁外写盘
+	path := filepath.Join(q.dir, fmt.Sprintf("%020d-%d%s", r.slot, r.seq, spillFileExt))
+	if err = writeSpillFile(path, data); err != nil {
+		logger.Errorf("[BlockSpillQueue] slot %d 写盘失败: %v", r.slot, err)
+		q.release(size)
+		return false
+	}
+
+	// 3. 重新加锁入堆
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.reserved--
+	q.reservedBytes -= size
+	heap.Push(&q.items, &spillItem{slot: r.slot, path: path, size: size})
+	q.bytes += size
+	if len(q.items)%spillLogDepthStep == 1 {
+		logger.Warnf("[BlockSpillQueue] blockChan 已满，区块落盘, slot=%d, 积压 %d 个区块（%d MB）",
+			r.slot, len(q.items), q.bytes>>20)
+	}
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// release 释放未能落盘的占位（size 为已预占的字节数）
+func (q *BlockSpillQueue) release(size int64) {
+	q.mu.Lock()
+	q.reserved--
+	q.reservedBytes -= size
+	q.mu.Unlock()
+}
+
+// drainLoop 按 slot 顺序将磁盘上的区块写回 blockChan（阻塞写入，处理速度即为回放速度）
+func (q *BlockSpillQueue) drainLoop() {
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-q.notify:
+		}
+
+		delivered := 0
+		for {
+			item := q.pop()
+			if item == nil {
+				break
+			}
+			if !q.deliver(item) {
+				return // 服务停止，未写回的区块保留在磁盘
+			}
+			delivered++
+		}
+		if delivered > 0 {
+			logger.Infof("[BlockSpillQueue] 积压区块已全部写回（本轮 %d 个）", delivered)
+		}
+	}
+}
+
+func (q *BlockSpillQueue) pop() *spillItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		return nil
+	}
+	item := heap.Pop(&q.items).(*spillItem)
+	q.bytes -= item.size
+	q.inflight++
+	return item
+}
+
+// deliver 读取区块并写入 blockChan，服务停止时返回 false
+func (q *BlockSpillQueue) deliver(item *spillItem) bool {
+	defer func() {
+		q.mu.Lock()
+		q.inflight--
+		q.mu.Unlock()
+	}()
+
+	block, err := readSpillFile(item.path)
+	if err != nil {
+		logger.Errorf("[BlockSpillQueue] slot %d 读取失败，已丢弃: %v", item.slot, err)
+		_ = os.Remove(item.path)
+		return true
+	}
+
+	select {
+	case q.blockChan <- block:
+	case <-q.ctx.Done():
+		return false
+	}
+	if err = os.Remove(item.path); err != nil {
+		logger.Warnf("[BlockSpillQueue] 删除区块文件失败: %s, err=%v", item.path, err)
+	}
+	return true
+}
+
+// restore 加载磁盘上残留的区块（上次退出时尚未处理完），并清理未写完的临时文件
+func (q *BlockSpillQueue) restore() error {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return fmt.Errorf("read spill dir %s error: %w", q.dir, err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(q.dir, name)
+		if strings.HasSuffix(name, spillTmpExt) {
+			_ = os.Remove(path)
+			continue
+		}
+		if entry.IsDir() || !strings.HasSuffix(name, spillFileExt) {
+			continue
+		}
+
+		slotStr, seqStr, ok := strings.Cut(strings.TrimSuffix(name, spillFileExt), "-")
+		if !ok {
+			continue
+		}
+		slot, err1 := strconv.ParseUint(slotStr, 10, 64)
+		seq, err2 := strconv.ParseUint(seqStr, 10, 64)
+		info, err3 := entry.Info()
+		if err1 != nil || err2 != nil || err3 != nil {
+			logger.Warnf("[BlockSpillQueue] 无法识别的区块文件: %s", name)
+			continue
+		}
+
+		heap.Push(&q.items, &spillItem{slot: slot, path: path, size: info.Size()})
+		q.bytes += info.Size()
+		if seq > q.seq {
+			q.seq = seq
+		}
+	}
+
+	if len(q.items) > 0 {
+		logger.Infof("[BlockSpillQueue] 从磁盘恢复 %d 个未处理区块（%d MB）", len(q.items), q.bytes>>20)
+		q.notify <- struct{}{}
+	}
+	return nil
+}
+
+func (q *BlockSpillQueue) registerMetrics() {
+	collectors := []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "dex_indexer_spill_queue_depth",
+			Help: "Number of blocks buffered in the on-disk spill queue.",
+		}, func() float64 { return float64(q.Depth()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "dex_indexer_spill_queue_bytes",
+			Help: "Total size in bytes of blocks buffered in the on-disk spill queue.",
+		}, func() float64 {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			return float64(q.bytes)
+		}),
+	}
+	for _, c := range collectors {
+		if err := monitor.Registry.Register(c); err != nil {
+			logger.Warnf("[BlockSpillQueue] 注册监控指标失败: %v", err)
+		}
+	}
+}
+
+// writeSpillFile 先写临时文件再重命名，避免进程退出时留下不完整的区块文件
+func writeSpillFile(path string, data []byte) error {
+	tmpPath := path + spillTmpExt
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+func readSpillFile(path string) (*pb.SubscribeUpdateBlock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block := &pb.SubscribeUpdateBlock{}
+	if err = proto.Unmarshal(data, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
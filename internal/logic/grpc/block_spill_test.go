@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"dex-indexer-sol/internal/config"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestSpill(t *testing.T, dir string, maxBlocks int, blockChan chan *pb.SubscribeUpdateBlock) *BlockSpillQueue {
+	t.Helper()
+	q, err := NewBlockSpillQueue(config.SpillQueueConfig{Dir: dir, MaxBlocks: maxBlocks}, blockChan)
+	require.NoError(t, err)
+	t.Cleanup(q.Stop)
+	return q
+}
+
+func spillBlock(t *testing.T, q *BlockSpillQueue, block *pb.SubscribeUpdateBlock) bool {
+	t.Helper()
+	r, ok := q.Reserve(block.Slot)
+	if !ok {
+		return false
+	}
+	return q.Write(r, block)
+}
+
+func mustMarshal(t *testing.T, block *pb.SubscribeUpdateBlock) []byte {
+	t.Helper()
+	data, err := proto.Marshal(block)
+	require.NoError(t, err)
+	return data
+}
+
+func spillFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestBlockSpillQueue_DrainsInSlotOrder(t *testing.T) {
+	dir := t.TempDir()
+	blockChan := make(chan *pb.SubscribeUpdateBlock, 10)
+	q := newTestSpill(t, dir, 0, blockChan)
+
+	for _, slot := range []uint64{103, 101, 102} {
+		require.True(t, spillBlock(t, q, testBlock(slot, "h")))
+	}
+	// 同一 slot 的分叉区块使用不同序号，不会覆盖
+	require.True(t, spillBlock(t, q, testBlock(101, "h-fork")))
+	assert.Equal(t, 4, q.Depth())
+	assert.Len(t, spillFiles(t, dir), 4)
+
+	q.Start()
+	var slots []uint64
+	for i := 0; i < 4; i++ {
+		select {
+		case block := <-blockChan:
+			slots = append(slots, block.Slot)
+		case <-time.After(time.Second):
+			t.Fatal("积压区块未写回 blockChan")
+		}
+	}
+	assert.Equal(t, []uint64{101, 101, 102, 103}, slots)
+	require.Eventually(t, func() bool { return q.Depth() == 0 && len(spillFiles(t, dir)) == 0 },
+		time.Second, time.Millisecond, "写回后删除区块文件")
+}
+
+func TestBlockSpillQueue_ReservationCountsTowardsLimits(t *testing.T) {
+	q := newTestSpill(t, t.TempDir(), 2, nil)
+
+	r1, ok := q.Reserve(100)
+	require.True(t, ok)
+	assert.Equal(t, 1, q.Depth(), "占位后即计入积压")
+	r2, ok := q.Reserve(101)
+	require.True(t, ok)
+	_, ok = q.Reserve(102)
+	assert.False(t, ok, "占位计入区块数上限")
+
+	require.True(t, q.Write(r2, testBlock(101, "h")))
+	require.True(t, q.Write(r1, testBlock(100, "h")))
+	assert.Equal(t, 2, q.Depth())
+	assert.Equal(t, 1, q.dropped)
+}
+
+func TestBlockSpillQueue_ByteLimit(t *testing.T) {
+	q := newTestSpill(t, t.TempDir(), 0, nil)
+	block := testBlock(100, "h")
+	q.maxBytes = int64(len(mustMarshal(t, block)))
+
+	require.True(t, spillBlock(t, q, block))
+	assert.False(t, spillBlock(t, q, testBlock(101, "h")), "超出字节上限时丢弃")
+	assert.Equal(t, 1, q.Depth(), "丢弃后释放占位")
+	assert.Equal(t, q.maxBytes, q.bytes)
+	assert.Zero(t, q.reservedBytes)
+}
+
+func TestBlockSpillQueue_WriteFailureReleasesReservation(t *testing.T) {
+	dir := t.TempDir()
+	q := newTestSpill(t, dir, 0, nil)
+	r, ok := q.Reserve(100)
+	require.True(t, ok)
+
+	require.NoError(t, os.RemoveAll(dir))
+	assert.False(t, q.Write(r, testBlock(100, "h")))
+	assert.Equal(t, 0, q.Depth())
+	assert.Zero(t, q.reservedBytes)
+}
+
+func TestBlockSpillQueue_ConcurrentWrites(t *testing.T) {
+	q := newTestSpill(t, t.TempDir(), 0, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(slot uint64) {
+			defer wg.Done()
+			assert.True(t, spillBlock(t, q, testBlock(slot, "h")))
+		}(uint64(100 + i))
+		q.Depth()
+	}
+	wg.Wait()
+	assert.Equal(t, 32, q.Depth())
+	assert.Zero(t, q.reserved)
+	assert.Equal(t, 100, int(q.items[0].slot))
+}
+
+func TestBlockSpillQueue_Restore(t *testing.T) {
+	dir := t.TempDir()
+	q := newTestSpill(t, dir, 0, nil)
+	require.True(t, spillBlock(t, q, testBlock(102, "h")))
+	require.True(t, spillBlock(t, q, testBlock(101, "h")))
+	q.Stop()
+
+	// 未写完的临时文件清理，无法识别的文件忽略
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000103-9.blk.tmp"), []byte("x"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unknown.blk"), []byte("x"), 0o644))
+
+	blockChan := make(chan *pb.SubscribeUpdateBlock, 10)
+	restored := newTestSpill(t, dir, 0, blockChan)
+	assert.Equal(t, 2, restored.Depth())
+	assert.Equal(t, uint64(2), restored.seq, "序号从磁盘上的最大值继续")
+	assert.NotContains(t, spillFiles(t, dir), "00000000000000000103-9.blk.tmp")
+
+	restored.Start()
+	assert.Equal(t, uint64(101), (<-blockChan).Slot)
+	assert.Equal(t, uint64(102), (<-blockChan).Slot)
+}
@@ -0,0 +1,556 @@
+package grpc
+
+import (
+	"context"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/jobbuilder"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/mq"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/internal/svc"
+	pb2 "dex-indexer-sol/pb"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+)
+
+const (
+	forkTrackWindow    = 3000 // 最多跟踪的 slot 跨度（未收到 finalized 通知时的兜底清理）
+	noticeQueueSize    = 1024
+	noticeTimeout      = 10 * time.Second
+	resolveInterval    = 5 * time.Second // 通过 RPC 判定待定 slot 的间隔
+	maxResolveAttempts = 5               // RPC 判定失败的最大重试轮数
+	maxResolveRange    = 10000           // 单次 getBlocks 查询的最大 slot 跨度
+	resolveTailSlots   = 64              // 查询区间向后多取的 slot 数，用于判定区间末尾的 slot
+)
+
+// slotNode 记录一个已处理区块在链上的位置
+type slotNode struct {
+	blockHash  types.Hash
+	parentSlot uint64
+	blockTime  int64
+	dispatched bool // 事件是否已成功分发
+	canonical  bool // 是否已确认在 finalized 规范链上
+}
+
+// forkNotice 待广播的通知，rollback 与 finalized 二选一
+type forkNotice struct {
+	rollback  *jobbuilder.RollbackInfo
+	finalized *jobbuilder.FinalizedInfo
+}
+
+// ForkTracker 跟踪最近已处理的 slot → blockhash/parent 链，检测分叉：
+//   - 父链不一致：新区块的 ParentSlot 跳过了已处理的 slot，或父哈希与已处理区块不一致；
+//   - finalized 校验：slot finalized 时沿父链回溯，不在规范链上的已处理 slot 视为被孤立；
+//   - dead slot：服务端通知 slot dead。
+//
+// 被孤立的 slot 会广播 SLOT_ROLLBACK 消息，消费方据此撤销该 slot 的全部事件；
+// 开启 finalized 标记时，规范链上的已处理 slot 在 finalized 且事件分发成功后广播 SLOT_FINALIZED 消息。
+// 父链信息不足以判定的 slot（迟到的区块、回溯链断开、长时间未 finalized 被清理的 slot）
+// 通过 RPC getBlocks（finalized）判定：在列表中即为规范链，否则视为被孤立。
+type ForkTracker struct {
+	mu            sync.Mutex
+	sc            *svc.GrpcServiceContext
+	ctx           context.Context
+	cancel        context.CancelFunc
+	detectFork    bool                 // 是否广播回滚消息
+	emitFinalized bool                 // 是否广播 finalized 标记
+	nodes         map[uint64]*slotNode // 已处理的区块（尚未 finalized）
+	parents       map[uint64]uint64    // slot 更新中携带的父 slot，用于补全回溯链
+	finalized     uint64               // 已确认 finalized 的最大 slot
+	highestSlot   uint64               // 已处理的最大 slot
+	rolledBack    map[uint64]types.Hash
+	undispatched  map[uint64]*jobbuilder.RollbackInfo // 被孤立时尚未分发的 slot：事件未发出无需回滚，若之后分发成功再广播
+	awaiting      map[uint64]*slotNode                // 已离开跟踪窗口、等待 RPC 判定或等待分发后再广播 finalized 标记的 slot
+	unresolved    map[uint64]int                      // 等待 RPC 判定是否在规范链上的 slot → 已失败的查询轮数
+	client        *rpc.RpcClient                      // 未配置 rpc_endpoint 时为 nil，待定 slot 无法判定
+	noticeCh      chan *forkNotice
+	outbox        []*forkNotice // 锁内产生、待释放锁后写入 noticeCh 的通知
+}
+
+func NewForkTracker(sc *svc.GrpcServiceContext, detectFork, emitFinalized bool) *ForkTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &ForkTracker{
+		sc:            sc,
+		ctx:           ctx,
+		cancel:        cancel,
+		detectFork:    detectFork,
+		emitFinalized: emitFinalized,
+		nodes:         make(map[uint64]*slotNode, 256),
+		parents:       make(map[uint64]uint64, 256),
+		rolledBack:    make(map[uint64]types.Hash, 16),
+		undispatched:  make(map[uint64]*jobbuilder.RollbackInfo, 16),
+		awaiting:      make(map[uint64]*slotNode, 16),
+		unresolved:    make(map[uint64]int, 16),
+		noticeCh:      make(chan *forkNotice, noticeQueueSize),
+	}
+	if endpoint := sc.Config.Grpc.RpcEndpoint; endpoint != "" {
+		client := rpc.NewRpcClient(endpoint)
+		t.client = &client
+	}
+	return t
+}
+
+func (t *ForkTracker) Start() {
+	go t.sendLoop()
+	go t.resolveLoop()
+}
+
+func (t *ForkTracker) Stop() {
+	t.cancel()
+}
+
+// OnBlock 记录一个待分发的区块，并做父链一致性检查；返回该区块本身是否为孤立区块（调用方不应再分发）
+func (t *ForkTracker) OnBlock(txCtx *core.TxContext) (orphan bool) {
+	t.mu.Lock()
+	defer t.unlock()
+
+	slot := txCtx.Slot
+	if slot <= t.finalized {
+		// 迟到的区块（如 RPC 补块）已低于 finalized slot，父链回溯已结束，通过 RPC 判定是否在规范链上
+		t.awaitLocked(slot, &slotNode{
+			blockHash:  txCtx.BlockHash,
+			parentSlot: txCtx.ParentSlot,
+			blockTime:  txCtx.BlockTime,
+		})
+		return false
+	}
+
+	// 1. 父哈希不一致：父 slot 上我们处理的是另一个区块
+	if parent, ok := t.nodes[txCtx.ParentSlot]; ok && txCtx.ParentBlockHash != (types.Hash{}) &&
+		parent.blockHash != txCtx.ParentBlockHash {
+		t.rollbackLocked(txCtx.ParentSlot, parent, slot, pb2.RollbackReason_ROLLBACK_PARENT_MISMATCH)
+	}
+
+	// 2. 新区块的父链跳过了已处理的 slot：(ParentSlot, Slot) 之间的已处理 slot 不在新区块所在的链上
+	for s, node := range t.nodes {
+		if s > txCtx.ParentSlot && s < slot {
+			t.rollbackLocked(s, node, slot, pb2.RollbackReason_ROLLBACK_PARENT_MISMATCH)
+		}
+	}
+
+	// 3. 迟到的区块（如 RPC 补块）落在已处理区块的跳过区间内，本身就是孤立的：尚未分发，直接丢弃即可，无需广播回滚
+	if slot < t.highestSlot {
+		for s, node := range t.nodes {
+			if s > slot && node.parentSlot < slot {
+				logger.Warnf("[ForkTracker] slot %d 落在 slot %d 的父链跳过区间 (%d, %d) 内，为孤立区块",
+					slot, s, node.parentSlot, s)
+				return true
+			}
+		}
+	}
+
+	t.nodes[slot] = &slotNode{
+		blockHash:  txCtx.BlockHash,
+		parentSlot: txCtx.ParentSlot,
+		blockTime:  txCtx.BlockTime,
+	}
+	if slot > t.highestSlot {
+		t.highestSlot = slot
+	}
+	t.pruneLocked()
+	return false
+}
+
+// OnDispatched 记录 slot 的事件已成功分发；已确认在规范链上的 slot 此时才广播 finalized 标记，保证标记晚于事件
+func (t *ForkTracker) OnDispatched(slot uint64) {
+	t.mu.Lock()
+	defer t.unlock()
+
+	if node, ok := t.nodes[slot]; ok {
+		node.dispatched = true
+		return
+	}
+	if node, ok := t.awaiting[slot]; ok {
+		node.dispatched = true
+		if node.canonical {
+			t.finalizeNodeLocked(slot, node)
+		}
+		return
+	}
+	// 分发过程中被孤立的 slot：事件已发出，补发回滚消息
+	if info, ok := t.undispatched[slot]; ok {
+		delete(t.undispatched, slot)
+		logger.Errorf("[ForkTracker] 已孤立的 slot %d 分发完成，补发回滚消息", slot)
+		t.enqueueLocked(&forkNotice{rollback: info})
+	}
+}
+
+// OnSlotUpdate 处理 gRPC 推送的 slot 状态更新（多个 endpoint 会重复推送，需幂等）
+func (t *ForkTracker) OnSlotUpdate(u *pb.SubscribeUpdateSlot) {
+	t.mu.Lock()
+	defer t.unlock()
+
+	if u.Parent != nil && u.Slot > t.finalized {
+		t.parents[u.Slot] = *u.Parent
+	}
+
+	switch u.Status {
+	case pb.SlotStatus_SLOT_DEAD:
+		if node, ok := t.nodes[u.Slot]; ok {
+			t.rollbackLocked(u.Slot, node, u.Slot, pb2.RollbackReason_ROLLBACK_DEAD_SLOT)
+		}
+	case pb.SlotStatus_SLOT_FINALIZED:
+		if u.Slot > t.finalized {
+			t.finalizeLocked(u.Slot)
+		}
+	}
+}
+
+// finalizeLocked 从 finalized slot 沿父链回溯到上一个 finalized slot，
+// 回溯区间内不在链上的已处理 slot 即为被孤立的 slot。
+// 父链信息缺失时回溯提前终止，更早的已处理 slot 无法由父链判定，交给 RPC 判定。
+func (t *ForkTracker) finalizeLocked(finalizedSlot uint64) {
+	canonical := make(map[uint64]struct{}, 64)
+	lowest := finalizedSlot
+	complete := false // 是否回溯到了上一个 finalized slot
+	for s := finalizedSlot; ; {
+		canonical[s] = struct{}{}
+		lowest = s
+		parent, ok := t.parentOfLocked(s)
+		if !ok {
+			break
+		}
+		if parent <= t.finalized {
+			complete = true
+			break
+		}
+		s = parent
+	}
+
+	// 按 slot 升序处理，保证消费方看到的 finalized 标记有序
+	slots := make([]uint64, 0, len(t.nodes))
+	for s := range t.nodes {
+		if s <= finalizedSlot {
+			slots = append(slots, s)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+
+	for _, s := range slots {
+		node := t.nodes[s]
+		switch _, ok := canonical[s]; {
+		case ok:
+			node.canonical = true
+			delete(t.nodes, s)
+			if node.dispatched {
+				t.finalizeNodeLocked(s, node)
+			} else {
+				t.awaiting[s] = node // 分发成功后再广播标记
+			}
+		case s > lowest || complete:
+			t.rollbackLocked(s, node, finalizedSlot, pb2.RollbackReason_ROLLBACK_NOT_FINALIZED)
+		default:
+			delete(t.nodes, s)
+			t.awaitLocked(s, node)
+		}
+	}
+
+	// 清理已 finalized 的部分
+	t.finalized = finalizedSlot
+	for s := range t.parents {
+		if s <= finalizedSlot {
+			delete(t.parents, s)
+		}
+	}
+	for s := range t.rolledBack {
+		if s <= finalizedSlot {
+			delete(t.rolledBack, s)
+		}
+	}
+	for s := range t.undispatched {
+		if s <= finalizedSlot {
+			delete(t.undispatched, s)
+		}
+	}
+}
+
+// finalizeNodeLocked 广播规范链上已分发 slot 的 finalized 标记
+func (t *ForkTracker) finalizeNodeLocked(slot uint64, node *slotNode) {
+	delete(t.awaiting, slot)
+	if !t.emitFinalized {
+		return
+	}
+	t.enqueueLocked(&forkNotice{finalized: &jobbuilder.FinalizedInfo{
+		Slot:       slot,
+		BlockHash:  node.blockHash,
+		ParentSlot: node.parentSlot,
+		BlockTime:  node.blockTime,
+	}})
+}
+
+// awaitLocked 将无法由父链判定的 slot 交给 RPC 判定
+func (t *ForkTracker) awaitLocked(slot uint64, node *slotNode) {
+	if old, ok := t.awaiting[slot]; ok && old.blockHash == node.blockHash {
+		return // 同一区块重复送达（如补块），保留原有状态
+	}
+	t.awaiting[slot] = node
+	t.unresolved[slot] = 0
+}
+
+func (t *ForkTracker) parentOfLocked(slot uint64) (uint64, bool) {
+	if node, ok := t.nodes[slot]; ok {
+		return node.parentSlot, true
+	}
+	parent, ok := t.parents[slot]
+	return parent, ok
+}
+
+// rollbackLocked 移除孤立的 slot，并异步广播回滚消息（同一 slot+blockhash 只广播一次）。
+// 只有事件已分发的 slot 才需要广播；尚未分发的 slot 记录下来，分发成功后再补发。
+func (t *ForkTracker) rollbackLocked(slot uint64, node *slotNode, canonicalSlot uint64, reason pb2.RollbackReason) {
+	delete(t.nodes, slot)
+	delete(t.awaiting, slot)
+	delete(t.unresolved, slot)
+	if hash, ok := t.rolledBack[slot]; ok && hash == node.blockHash {
+		return
+	}
+	t.rolledBack[slot] = node.blockHash
+
+	if !t.detectFork {
+		logger.Warnf("[ForkTracker] slot %d 被孤立（%s），canonical slot = %d，未开启分叉检测，不广播回滚消息",
+			slot, reason.String(), canonicalSlot)
+		return
+	}
+
+	info := &jobbuilder.RollbackInfo{
+		Slot:          slot,
+		BlockHash:     node.blockHash,
+		ParentSlot:    node.parentSlot,
+		BlockTime:     node.blockTime,
+		CanonicalSlot: canonicalSlot,
+		Reason:        reason,
+	}
+	if !node.dispatched {
+		logger.Warnf("[ForkTracker] slot %d 被孤立（%s），canonical slot = %d，事件尚未分发，暂不广播回滚消息",
+			slot, reason.String(), canonicalSlot)
+		t.undispatched[slot] = info
+		return
+	}
+	logger.Errorf("[ForkTracker] slot %d 被孤立（%s），canonical slot = %d，广播回滚消息",
+		slot, reason.String(), canonicalSlot)
+	t.enqueueLocked(&forkNotice{rollback: info})
+}
+
+// enqueueLocked 暂存通知，由 unlock 在释放锁后写入发送队列
+func (t *ForkTracker) enqueueLocked(notice *forkNotice) {
+	t.outbox = append(t.outbox, notice)
+}
+
+// unlock 释放锁，并按产生顺序将锁内暂存的通知写入发送队列。
+// 队列已满时阻塞等待（对区块处理形成背压），不丢弃通知；等待期间不持有锁，不影响其他调用方与 RPC 判定。
+func (t *ForkTracker) unlock() {
+	notices := t.outbox
+	t.outbox = nil
+	t.mu.Unlock()
+
+	for _, notice := range notices {
+		t.enqueue(notice)
+	}
+}
+
+func (t *ForkTracker) enqueue(notice *forkNotice) {
+	select {
+	case t.noticeCh <- notice:
+		return
+	default:
+	}
+
+	slot := notice.slot()
+	logger.Warnf("[ForkTracker] 通知队列已满，等待发送 slot %d 的通知", slot)
+	select {
+	case t.noticeCh <- notice:
+	case <-t.ctx.Done():
+		logger.Errorf("[ForkTracker] 服务停止，slot %d 的通知未能发送", slot)
+	}
+}
+
+func (n *forkNotice) slot() uint64 {
+	if n.rollback != nil {
+		return n.rollback.Slot
+	}
+	return n.finalized.Slot
+}
+
+// pruneLocked 兜底清理：长时间收不到 finalized 通知时，避免 map 无限增长；
+// 被清理的已处理 slot 交给 RPC 判定，长时间未能分发的待定 slot 不再广播标记
+func (t *ForkTracker) pruneLocked() {
+	if t.highestSlot <= forkTrackWindow {
+		return
+	}
+	minSlot := t.highestSlot - forkTrackWindow
+
+	if len(t.nodes) >= forkTrackWindow {
+		for s, node := range t.nodes {
+			if s < minSlot {
+				delete(t.nodes, s)
+				t.awaitLocked(s, node)
+			}
+		}
+		for s := range t.parents {
+			if s < minSlot {
+				delete(t.parents, s)
+			}
+		}
+	}
+
+	if len(t.awaiting) >= forkTrackWindow && minSlot > forkTrackWindow {
+		for s, node := range t.awaiting {
+			if s < minSlot-forkTrackWindow {
+				delete(t.awaiting, s)
+				delete(t.unresolved, s)
+				logger.Errorf("[ForkTracker] slot %d 长时间未能完成分发/判定（dispatched=%v, canonical=%v），不再广播 finalized 标记",
+					s, node.dispatched, node.canonical)
+			}
+		}
+	}
+}
+
+// resolveLoop 定期通过 RPC getBlocks（finalized）判定待定 slot 是否在规范链上
+func (t *ForkTracker) resolveLoop() {
+	ticker := time.NewTicker(resolveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-ticker.C:
+			t.resolvePending()
+		}
+	}
+}
+
+func (t *ForkTracker) resolvePending() {
+	t.mu.Lock()
+	slots := make([]uint64, 0, len(t.unresolved))
+	for s := range t.unresolved {
+		slots = append(slots, s)
+	}
+	t.mu.Unlock()
+	if len(slots) == 0 {
+		return
+	}
+	if t.client == nil {
+		logger.Errorf("[ForkTracker] 未配置 rpc_endpoint，%d 个 slot 无法判定是否 finalized", len(slots))
+		return
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	for start := 0; start < len(slots); {
+		end := start
+		for end < len(slots) && slots[end]-slots[start] < maxResolveRange {
+			end++
+		}
+		t.resolveRange(slots[start:end])
+		start = end
+	}
+}
+
+// resolveRange 查询 slots 所在区间的 finalized 区块：在列表中的 slot 为规范链；
+// 不在列表中、但其后已有 finalized 区块的 slot 被孤立；其余 slot 尚未 finalized，留到下一轮判定
+func (t *ForkTracker) resolveRange(slots []uint64) {
+	from, to := slots[0], slots[len(slots)-1]+resolveTailSlots
+	ctx, cancel := context.WithTimeout(t.ctx, 15*time.Second)
+	resp, err := t.client.GetBlocksWithConfig(ctx, from, to, rpc.GetBlocksConfig{Commitment: rpc.CommitmentFinalized})
+	cancel()
+	if err == nil {
+		err = resp.GetError()
+	}
+
+	t.mu.Lock()
+	defer t.unlock()
+
+	if err != nil {
+		logger.Warnf("[ForkTracker] getBlocks [%d, %d] 失败: %v", from, to, err)
+		for _, s := range slots {
+			if attempts, ok := t.unresolved[s]; ok {
+				if attempts+1 >= maxResolveAttempts {
+					delete(t.unresolved, s)
+					delete(t.awaiting, s)
+					logger.Errorf("[ForkTracker] slot %d 多次无法判定是否 finalized，放弃广播标记", s)
+				} else {
+					t.unresolved[s] = attempts + 1
+				}
+			}
+		}
+		return
+	}
+
+	finalizedSet := make(map[uint64]struct{}, len(resp.Result))
+	maxFinalized := uint64(0)
+	for _, s := range resp.Result {
+		finalizedSet[s] = struct{}{}
+		if s > maxFinalized {
+			maxFinalized = s
+		}
+	}
+
+	for _, s := range slots {
+		node, ok := t.awaiting[s]
+		if _, pending := t.unresolved[s]; !ok || !pending {
+			continue
+		}
+		if _, ok = finalizedSet[s]; ok {
+			delete(t.unresolved, s)
+			node.canonical = true
+			if node.dispatched {
+				t.finalizeNodeLocked(s, node)
+			}
+		} else if s < maxFinalized {
+			t.rollbackLocked(s, node, maxFinalized, pb2.RollbackReason_ROLLBACK_NOT_FINALIZED)
+		}
+	}
+}
+
+func (t *ForkTracker) sendLoop() {
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case notice := <-t.noticeCh:
+			if notice.rollback != nil {
+				t.sendRollback(notice.rollback)
+			} else {
+				t.sendFinalized(notice.finalized)
+			}
+		}
+	}
+}
+
+// sendRollback 将回滚消息广播到 event 和 balance topic 的全部分区
+func (t *ForkTracker) sendRollback(info *jobbuilder.RollbackInfo) {
+	conf := t.sc.Config.KafkaProducerConf
+	jobs := jobbuilder.BuildRollbackKafkaJobs(info, sourceGrpc, conf.Topics.Event, conf.Partitions.Event)
+	jobs = append(jobs, jobbuilder.BuildRollbackKafkaJobs(info, sourceGrpc, conf.Topics.Balance, conf.Partitions.Balance)...)
+
+	if failed := t.send(jobs); len(failed) > 0 {
+		logger.Errorf("[ForkTracker] slot %d 回滚消息发送失败 %d/%d 条: %v",
+			info.Slot, len(failed), len(jobs), failed[0].Err)
+		return
+	}
+	logger.Infof("[ForkTracker] slot %d 回滚消息已发送（%d 条）", info.Slot, len(jobs))
+}
+
+// sendFinalized 将 finalized 标记广播到 event topic 的全部分区
+func (t *ForkTracker) sendFinalized(info *jobbuilder.FinalizedInfo) {
+	conf := t.sc.Config.KafkaProducerConf
+	jobs := jobbuilder.BuildFinalizedKafkaJobs(info, sourceGrpc, conf.Topics.Event, conf.Partitions.Event)
+	if failed := t.send(jobs); len(failed) > 0 {
+		logger.Errorf("[ForkTracker] slot %d finalized 标记发送失败 %d/%d 条: %v",
+			info.Slot, len(failed), len(jobs), failed[0].Err)
+		return
+	}
+	logger.Debugf("[ForkTracker] slot %d finalized 标记已发送", info.Slot)
+}
+
+func (t *ForkTracker) send(jobs []*mq.KafkaJob) []mq.KafkaSendResult {
+	ctx, cancel := context.WithTimeout(t.ctx, noticeTimeout)
+	defer cancel()
+
+	sendTimeout := time.Duration(t.sc.Config.TimeConf.EventSendTimeoutMs) * time.Millisecond
+	_, failed := mq.SendKafkaJobs(ctx, t.sc.Producer, jobs, sendTimeout)
+	return failed
+}
@@ -0,0 +1,205 @@
+package grpc
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/logic/jobbuilder"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/internal/svc"
+	pb2 "dex-indexer-sol/pb"
+	"testing"
+	"time"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testHash(slot uint64, fork byte) types.Hash {
+	var h types.Hash
+	h[0] = fork
+	h[1], h[2], h[3] = byte(slot>>16), byte(slot>>8), byte(slot)
+	return h
+}
+
+// newTestForkTracker 创建不启动发送协程的 ForkTracker，待广播的通知可从 t.noticeCh 读取
+func newTestForkTracker(t *testing.T, emitFinalized bool) *ForkTracker {
+	t.Helper()
+	tracker := NewForkTracker(&svc.GrpcServiceContext{}, true, emitFinalized)
+	t.Cleanup(tracker.Stop)
+	return tracker
+}
+
+// processBlock 模拟 BlockProcessor：记录区块，非孤立且 dispatch 为 true 时标记为已分发
+func processBlock(tracker *ForkTracker, slot, parent uint64, fork byte, dispatch bool) bool {
+	orphan := tracker.OnBlock(&core.TxContext{
+		Slot:            slot,
+		ParentSlot:      parent,
+		BlockHash:       testHash(slot, fork),
+		ParentBlockHash: testHash(parent, 0),
+		BlockTime:       int64(slot),
+	})
+	if !orphan && dispatch {
+		tracker.OnDispatched(slot)
+	}
+	return orphan
+}
+
+func drainNotices(tracker *ForkTracker) []*forkNotice {
+	var notices []*forkNotice
+	for len(tracker.noticeCh) > 0 {
+		notices = append(notices, <-tracker.noticeCh)
+	}
+	return notices
+}
+
+func TestForkTracker_RollbackSkippedDispatchedSlot(t *testing.T) {
+	tracker := newTestForkTracker(t, false)
+	processBlock(tracker, 100, 99, 0, true)
+	processBlock(tracker, 101, 100, 0, true)
+
+	// 102 的父 slot 为 100，跳过了已分发的 101
+	processBlock(tracker, 102, 100, 0, true)
+	notices := drainNotices(tracker)
+	require.Len(t, notices, 1)
+	info := notices[0].rollback
+	require.NotNil(t, info)
+	assert.Equal(t, uint64(101), info.Slot)
+	assert.Equal(t, testHash(101, 0), info.BlockHash)
+	assert.Equal(t, uint64(100), info.ParentSlot)
+	assert.Equal(t, uint64(102), info.CanonicalSlot)
+	assert.Equal(t, pb2.RollbackReason_ROLLBACK_PARENT_MISMATCH, info.Reason)
+
+	// 同一 slot+blockhash 只广播一次
+	tracker.OnSlotUpdate(&pb.SubscribeUpdateSlot{Slot: 101, Status: pb.SlotStatus_SLOT_DEAD})
+	assert.Empty(t, drainNotices(tracker))
+}
+
+func TestForkTracker_ParentHashMismatch(t *testing.T) {
+	tracker := newTestForkTracker(t, false)
+	processBlock(tracker, 100, 99, 1, true) // 处理的是分叉上的 100
+
+	processBlock(tracker, 101, 100, 0, true) // 父哈希指向另一个 100
+	notices := drainNotices(tracker)
+	require.Len(t, notices, 1)
+	assert.Equal(t, uint64(100), notices[0].rollback.Slot)
+	assert.Equal(t, testHash(100, 1), notices[0].rollback.BlockHash)
+}
+
+func TestForkTracker_UndispatchedRollbackDeferred(t *testing.T) {
+	tracker := newTestForkTracker(t, false)
+	processBlock(tracker, 100, 99, 0, true)
+	processBlock(tracker, 101, 100, 0, false) // 仍在分发中
+
+	tracker.OnSlotUpdate(&pb.SubscribeUpdateSlot{Slot: 101, Status: pb.SlotStatus_SLOT_DEAD})
+	assert.Empty(t, drainNotices(tracker), "事件尚未分发的 slot 不广播回滚")
+
+	// 分发完成后补发
+	tracker.OnDispatched(101)
+	notices := drainNotices(tracker)
+	require.Len(t, notices, 1)
+	assert.Equal(t, uint64(101), notices[0].rollback.Slot)
+	assert.Equal(t, pb2.RollbackReason_ROLLBACK_DEAD_SLOT, notices[0].rollback.Reason)
+
+	tracker.OnDispatched(101)
+	assert.Empty(t, drainNotices(tracker), "只补发一次")
+}
+
+func TestForkTracker_UndispatchedRollbackNeverDispatched(t *testing.T) {
+	tracker := newTestForkTracker(t, false)
+	processBlock(tracker, 100, 99, 0, false)
+	tracker.OnSlotUpdate(&pb.SubscribeUpdateSlot{Slot: 100, Status: pb.SlotStatus_SLOT_DEAD})
+
+	// finalized 后清理，之后不会再补发
+	tracker.OnSlotUpdate(&pb.SubscribeUpdateSlot{Slot: 105, Status: pb.SlotStatus_SLOT_FINALIZED})
+	tracker.OnDispatched(100)
+	assert.Empty(t, drainNotices(tracker))
+}
+
+func TestForkTracker_LateBlockInSkippedRangeIsOrphan(t *testing.T) {
+	tracker := newTestForkTracker(t, false)
+	processBlock(tracker, 100, 99, 0, true)
+	processBlock(tracker, 103, 100, 0, true)
+
+	assert.True(t, processBlock(tracker, 101, 100, 0, false), "落在 103 跳过区间内的迟到区块为孤立区块")
+	assert.False(t, processBlock(tracker, 104, 103, 0, true))
+	assert.Empty(t, drainNotices(tracker))
+}
+
+func TestForkTracker_FinalizedMarkers(t *testing.T) {
+	tracker := newTestForkTracker(t, true)
+	parent := func(slot, parent uint64) {
+		tracker.OnSlotUpdate(&pb.SubscribeUpdateSlot{Slot: slot, Parent: &parent, Status: pb.SlotStatus_SLOT_CONFIRMED})
+	}
+	processBlock(tracker, 100, 99, 0, true)
+	processBlock(tracker, 101, 100, 0, false) // 尚未分发
+	processBlock(tracker, 102, 101, 0, true)
+	parent(103, 101) // 103 跳过 102，未经本服务处理
+	parent(104, 103)
+	require.Empty(t, drainNotices(tracker))
+
+	// 104 finalized，规范链为 104 → 103 → 101 → 100：102 被孤立，101 待分发后再广播标记
+	tracker.OnSlotUpdate(&pb.SubscribeUpdateSlot{Slot: 104, Status: pb.SlotStatus_SLOT_FINALIZED})
+	notices := drainNotices(tracker)
+	require.Len(t, notices, 2)
+	require.NotNil(t, notices[0].finalized)
+	assert.Equal(t, uint64(100), notices[0].finalized.Slot)
+	require.NotNil(t, notices[1].rollback)
+	assert.Equal(t, uint64(102), notices[1].rollback.Slot)
+	assert.Equal(t, pb2.RollbackReason_ROLLBACK_NOT_FINALIZED, notices[1].rollback.Reason)
+
+	tracker.OnDispatched(101)
+	notices = drainNotices(tracker)
+	require.Len(t, notices, 1)
+	require.NotNil(t, notices[0].finalized)
+	assert.Equal(t, uint64(101), notices[0].finalized.Slot)
+	assert.Equal(t, testHash(101, 0), notices[0].finalized.BlockHash)
+}
+
+func TestForkTracker_FullNoticeQueueDoesNotHoldLock(t *testing.T) {
+	tracker := newTestForkTracker(t, false)
+	for i := 0; i < noticeQueueSize; i++ {
+		tracker.noticeCh <- &forkNotice{finalized: &jobbuilder.FinalizedInfo{Slot: uint64(i)}}
+	}
+	processBlock(tracker, 100, 99, 0, true)
+
+	// 队列已满：回滚通知阻塞等待发送
+	blocked := make(chan struct{})
+	go func() {
+		defer close(blocked)
+		tracker.OnSlotUpdate(&pb.SubscribeUpdateSlot{Slot: 100, Status: pb.SlotStatus_SLOT_DEAD})
+	}()
+
+	// 等待回滚完成、通知进入发送等待
+	require.Eventually(t, func() bool {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		_, ok := tracker.nodes[100]
+		return !ok
+	}, time.Second, time.Millisecond)
+
+	// 等待期间不持有锁，区块处理不受影响
+	processed := make(chan struct{})
+	go func() {
+		defer close(processed)
+		processBlock(tracker, 101, 100, 1, true)
+	}()
+	select {
+	case <-processed:
+	case <-time.After(time.Second):
+		t.Fatal("通知队列已满时 OnBlock 被阻塞")
+	}
+	select {
+	case <-blocked:
+		t.Fatal("通知队列已满时应等待发送而不是丢弃")
+	default:
+	}
+
+	// 队列空出位置后通知写入队尾
+	<-tracker.noticeCh
+	<-blocked
+	notices := drainNotices(tracker)
+	require.Len(t, notices, noticeQueueSize)
+	last := notices[len(notices)-1]
+	require.NotNil(t, last.rollback)
+	assert.Equal(t, uint64(100), last.rollback.Slot)
+}
@@ -2,23 +2,25 @@ package grpc
 
 import (
 	"context"
-	"crypto/tls"
-	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/config"
 	"dex-indexer-sol/internal/pkg/logger"
 	"dex-indexer-sol/internal/svc"
 	"errors"
 	"fmt"
 	"google.golang.org/grpc/metadata"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 )
 
+// GrpcStreamManager 管理单个 endpoint 的订阅流，收到的区块统一交给 BlockDeduper 去重后下发
 type GrpcStreamManager struct {
+	name                  string                        // endpoint 名称，用于日志
 	mu                    sync.Mutex                    // 互斥锁，保护并发安全
 	conn                  *grpc.ClientConn              // gRPC 连接对象
 	client                pb.GeyserClient               // gRPC 客户端
@@ -27,7 +29,7 @@ type GrpcStreamManager struct {
 	reconnectAttempts     int                           // 已重连次数
 	xToken                string                        // 认证用的 x-token
 	streamPingIntervalSec int                           // Stream心跳包发送间隔（秒）
-	blockChan             chan *pb.SubscribeUpdateBlock // 区块数据通道
+	deduper               *BlockDeduper                 // 多流去重后写入区块数据通道
 	connCtx               context.Context               // 当前连接的 context
 	connCancel            context.CancelFunc            // 当前连接的 cancel 函数
 	reconnectInterval     time.Duration                 // 每次重连之间的最小间隔（秒）
@@ -35,14 +37,49 @@ type GrpcStreamManager struct {
 	recvTimeoutSec        int                           // gRPC Recv 超时时间（秒）
 	maxLatencyWarnMs      int                           // 区块延迟超 3 秒打 warning
 	maxLatencyDropMs      int                           // 区块延迟超 5 秒断流重连
-	slotChecker           *SlotChecker
+	processor             *BlockProcessor               // 用于获取最近一次成功分发的 slot（FromSlot 续传起点）
+	supportsFromSlot      bool                          // 当前 endpoint 是否支持 FromSlot 续传
+	fromSlotRejected      atomic.Bool                   // 上次 FromSlot 续传被服务端拒绝，下次连接退化为 RPC 补块
+	forkTracker           *ForkTracker                  // 接收 slot 状态更新，未开启分叉检测时为 nil
+	commitment            pb.CommitmentLevel            // 订阅的 commitment 级别
+	txMode                bool                          // 交易订阅模式：按账户过滤订阅交易，由 assembler 按 slot 组装为区块
+	includeFailed         bool                          // 交易订阅模式下是否订阅失败交易（开启 failed_tx 时）
+	filter                atomic.Pointer[AccountFilter] // 账户过滤条件，可通过 UpdateAccountFilter 热更新
+	sendMu                sync.Mutex                    // 串行化 stream.Send（ping 与过滤条件更新可能并发发送）
 }
 
-func NewGrpcStreamManager(sc *svc.GrpcServiceContext, blockChan chan *pb.SubscribeUpdateBlock) (*GrpcStreamManager, error) {
+const (
+	subscribeModeBlock       = "block"
+	subscribeModeTransaction = "transaction"
+
+	maxReplayDuration = 2 * time.Minute // FromSlot 续传追平的最长时间，期间不触发延迟断连
+)
+
+func NewGrpcStreamManager(
+	sc *svc.GrpcServiceContext,
+	ep config.GrpcEndpointConfig,
+	deduper *BlockDeduper,
+	processor *BlockProcessor,
+) (*GrpcStreamManager, error) {
 	grpcConf := sc.Config.Grpc
 
-	configTls := &tls.Config{
-		InsecureSkipVerify: true,
+	txMode := isTransactionMode(grpcConf.SubscribeMode)
+	filter, err := NewAccountFilter(grpcConf.AccountFilter, txMode)
+	if err != nil {
+		return nil, err
+	}
+	if txMode {
+		logger.Infof("[GrpcStream] [%s] 使用交易订阅模式, 账户过滤: %s", ep.Name, filter)
+	} else {
+		logger.Infof("[GrpcStream] [%s] 使用区块订阅模式, 账户过滤: %s", ep.Name, filter)
+	}
+
+	creds, err := buildTransportCredentials(ep.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint %s tls config error: %w", ep.Name, err)
+	}
+	if mode := strings.ToLower(ep.TLS.Mode); mode == tlsModeInsecure || mode == tlsModePlaintext {
+		logger.Warnf("[GrpcStream] [%s] 未校验服务端证书（tls mode = %s），仅用于本地/测试环境", ep.Name, mode)
 	}
 
 	dialCtx, cancel := context.WithTimeout(context.Background(), time.Duration(grpcConf.ConnectTimeoutSec)*time.Second)
@@ -50,8 +87,8 @@ func NewGrpcStreamManager(sc *svc.GrpcServiceContext, blockChan chan *pb.Subscri
 
 	conn, err := grpc.DialContext(
 		dialCtx,
-		grpcConf.Endpoint,
-		grpc.WithTransportCredentials(credentials.NewTLS(configTls)),
+		ep.Endpoint,
+		grpc.WithTransportCredentials(creds),
 		grpc.WithInitialWindowSize(int32(grpcConf.InitialWindowSize)),
 		grpc.WithInitialConnWindowSize(int32(grpcConf.InitialConnWindowSize)),
 		grpc.WithDefaultCallOptions(
@@ -66,28 +103,35 @@ func NewGrpcStreamManager(sc *svc.GrpcServiceContext, blockChan chan *pb.Subscri
 		}),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect: %v", err)
+		return nil, fmt.Errorf("failed to connect %s: %v", ep.Endpoint, err)
 	}
 
-	return &GrpcStreamManager{
+	m := &GrpcStreamManager{
+		name:                  ep.Name,
 		conn:                  conn,
 		client:                pb.NewGeyserClient(conn),
 		reconnectAttempts:     0,
 		reconnectInterval:     time.Duration(grpcConf.ReconnectIntervalSec) * time.Second,
-		xToken:                grpcConf.XToken,
+		xToken:                ep.XToken,
 		streamPingIntervalSec: grpcConf.StreamPingIntervalSec,
-		blockChan:             blockChan,
+		deduper:               deduper,
 		recvTimeoutSec:        grpcConf.RecvTimeoutSec,
 		sendTimeoutSec:        grpcConf.SendTimeoutSec,
 		maxLatencyWarnMs:      grpcConf.MaxLatencyWarnMs,
 		maxLatencyDropMs:      grpcConf.MaxLatencyDropMs,
-		slotChecker:           NewSlotChecker(grpcConf.RpcEndpoint),
-	}, nil
+		processor:             processor,
+		supportsFromSlot:      ep.SupportsFromSlot,
+		forkTracker:           processor.forkTracker,
+		commitment:            parseCommitment(grpcConf.Commitment),
+		txMode:                txMode,
+		includeFailed:         sc.Config.FailedTxConf.Enable,
+	}
+	m.filter.Store(filter)
+	return m, nil
 }
 
 func (m *GrpcStreamManager) Start() {
-	m.slotChecker.Start()
-	m.mustConnect(0, 0)
+	m.mustConnect()
 }
 
 func (m *GrpcStreamManager) Stop() {
@@ -96,8 +140,6 @@ func (m *GrpcStreamManager) Stop() {
 
 	m.stopped = true // 标记已停止，必须在 cancel 之前设置，防止重入
 
-	m.slotChecker.Stop()
-
 	// 先 cancel context，通知所有 goroutine 退出（如 pingLoop, blockRecvLoop）
 	if m.connCancel != nil {
 		m.connCancel()
@@ -107,7 +149,7 @@ func (m *GrpcStreamManager) Stop() {
 	// 再关闭 stream，确保没有 goroutine 在调用 Send()
 	if m.stream != nil {
 		if err := m.stream.CloseSend(); err != nil {
-			logger.Warnf("[GrpcStream] CloseSend failed: %v", err)
+			logger.Warnf("[GrpcStream] [%s] CloseSend failed: %v", m.name, err)
 		}
 		m.stream = nil
 	}
@@ -115,14 +157,14 @@ func (m *GrpcStreamManager) Stop() {
 	// 最后关闭连接
 	if m.conn != nil {
 		if err := m.conn.Close(); err != nil {
-			logger.Warnf("[GrpcStream] conn.Close failed: %v", err)
+			logger.Warnf("[GrpcStream] [%s] conn.Close failed: %v", m.name, err)
 		}
 		m.conn = nil
 	}
 }
 
 // 内部循环直到连接成功
-func (m *GrpcStreamManager) mustConnect(lastBlockNumber int64, lastSlot uint64) {
+func (m *GrpcStreamManager) mustConnect() {
 	for {
 		m.mu.Lock()
 		if m.stopped {
@@ -138,42 +180,155 @@ func (m *GrpcStreamManager) mustConnect(lastBlockNumber int64, lastSlot uint64)
 				time.Sleep(m.reconnectInterval)
 			}
 		}
-		logger.Infof("[GrpcStream] Connecting... Attempt %d", m.reconnectAttempts+1)
+		logger.Infof("[GrpcStream] [%s] Connecting... Attempt %d", m.name, m.reconnectAttempts+1)
 		m.reconnectAttempts++
-		err := m.connect(lastBlockNumber, lastSlot)
+		err := m.connect()
 		if err == nil {
 			return // 连接成功
 		}
-		logger.Errorf("[GrpcStream] Connect failed: %v, will retry...", err)
+		logger.Errorf("[GrpcStream] [%s] Connect failed: %v, will retry...", m.name, err)
+	}
+}
+
+// parseCommitment 解析配置中的 commitment 级别，未配置或无法识别时使用 confirmed
+func parseCommitment(s string) pb.CommitmentLevel {
+	switch strings.ToLower(s) {
+	case "processed":
+		return pb.CommitmentLevel_PROCESSED
+	case "finalized":
+		return pb.CommitmentLevel_FINALIZED
+	case "", "confirmed":
+		return pb.CommitmentLevel_CONFIRMED
+	default:
+		logger.Warnf("[GrpcStream] unknown commitment %q, fallback to confirmed", s)
+		return pb.CommitmentLevel_CONFIRMED
+	}
+}
+
+// isTransactionMode 解析订阅模式，未配置或无法识别时使用全量区块模式
+func isTransactionMode(mode string) bool {
+	switch strings.ToLower(mode) {
+	case subscribeModeTransaction:
+		return true
+	case "", subscribeModeBlock:
+		return false
+	default:
+		logger.Warnf("[GrpcStream] unknown subscribe mode %q, fallback to block", mode)
+		return false
+	}
+}
+
+// buildTxSubscribeRequest 构造交易订阅模式的请求：
+// 按账户过滤订阅非投票交易（includeFailed 为 false 时只订阅成功交易），并订阅 BlockMeta（区块元信息）与 slot 状态（判断 slot 是否完整）。
+func buildTxSubscribeRequest(fromSlot *uint64, commitment pb.CommitmentLevel, filter *AccountFilter, includeFailed bool) *pb.SubscribeRequest {
+	var failed *bool // nil 表示成功与失败交易都推送
+	if !includeFailed {
+		failed = boolPtr(false)
+	}
+	return &pb.SubscribeRequest{
+		Transactions: map[string]*pb.SubscribeRequestFilterTransactions{
+			"txs": {
+				Vote:            boolPtr(false),
+				Failed:          failed,
+				AccountInclude:  filter.Include,
+				AccountExclude:  filter.Exclude,
+				AccountRequired: filter.Required,
+			},
+		},
+		BlocksMeta: map[string]*pb.SubscribeRequestFilterBlocksMeta{
+			"blocks_meta": {},
+		},
+		Slots: map[string]*pb.SubscribeRequestFilterSlots{
+			"slots": {FilterByCommitment: boolPtr(false)},
+		},
+		Commitment: &commitment,
+		FromSlot:   fromSlot,
 	}
 }
 
-func buildSubscribeRequest(baseBlockTime int64, baseSlot uint64) *pb.SubscribeRequest {
+func buildSubscribeRequest(fromSlot *uint64, commitment pb.CommitmentLevel, withSlots bool, filter *AccountFilter) *pb.SubscribeRequest {
 	blocks := make(map[string]*pb.SubscribeRequestFilterBlocks)
 	blocks["blocks"] = &pb.SubscribeRequestFilterBlocks{
-		AccountInclude:      consts.GrpcAccountInclude,
+		AccountInclude:      filter.Include,
 		IncludeTransactions: boolPtr(true),  // ✅ 保留转 SOL、swap、transfer 等交易
 		IncludeAccounts:     boolPtr(false), // 不再收账户余额变化的单独 AccountUpdate（vote 省了）
 		IncludeEntries:      boolPtr(false), // IncludeEntries 是 Solana 底层的日志，普通业务基本没用。
 	}
-	commitment := pb.CommitmentLevel_CONFIRMED
-
-	var fromSlot *uint64
-	if baseBlockTime > 0 && baseSlot > 0 {
-		// quickNode 不支持 FromSlot 参数，启用后会报错：
-		//slot := estimateCurrentSlot(baseBlockTime, baseSlot)
-		//fromSlot = &slot
+	// 分叉检测需要全部状态（含 finalized / dead）的 slot 更新，不按 commitment 过滤
+	var slots map[string]*pb.SubscribeRequestFilterSlots
+	if withSlots {
+		slots = map[string]*pb.SubscribeRequestFilterSlots{
+			"slots": {FilterByCommitment: boolPtr(false)},
+		}
 	}
 
 	return &pb.SubscribeRequest{
 		Blocks:     blocks,
+		Slots:      slots,
 		Commitment: &commitment,
 		FromSlot:   fromSlot,
 	}
 }
 
+// buildRequest 按当前订阅模式与账户过滤条件构造订阅请求
+func (m *GrpcStreamManager) buildRequest(fromSlot *uint64) *pb.SubscribeRequest {
+	filter := m.filter.Load()
+	if m.txMode {
+		return buildTxSubscribeRequest(fromSlot, m.commitment, filter, m.includeFailed)
+	}
+	return buildSubscribeRequest(fromSlot, m.commitment, m.forkTracker != nil, filter)
+}
+
+// UpdateAccountFilter 更新账户过滤条件：当前有活跃的订阅流时直接发送新的 SubscribeRequest（服务端替换订阅条件，无需重连），
+// 否则在下次连接时生效。
+func (m *GrpcStreamManager) UpdateAccountFilter(filter *AccountFilter) error {
+	m.filter.Store(filter)
+
+	m.mu.Lock()
+	stream, ctx := m.stream, m.connCtx
+	m.mu.Unlock()
+	if stream == nil {
+		return nil
+	}
+	// 不带 FromSlot，避免服务端重放历史区块
+	if err := m.send(ctx, stream, m.buildRequest(nil)); err != nil {
+		return fmt.Errorf("endpoint %s: %w", m.name, err)
+	}
+	logger.Infof("[GrpcStream] [%s] 账户过滤已更新: %s", m.name, filter)
+	return nil
+}
+
+// send 在超时时间内发送请求；gRPC stream 不支持并发 Send，由 sendMu 串行化
+func (m *GrpcStreamManager) send(ctx context.Context, stream pb.Geyser_SubscribeClient, req *pb.SubscribeRequest) error {
+	return sendWithTimeout(ctx, func(r *pb.SubscribeRequest) error {
+		m.sendMu.Lock()
+		defer m.sendMu.Unlock()
+		return stream.Send(r)
+	}, req, time.Duration(m.sendTimeoutSec)*time.Second)
+}
+
+// resumeFromSlot 计算本次连接的 FromSlot：
+//   - endpoint 不支持 FromSlot（如 quickNode，启用后会报错）或上次续传被拒绝时返回 nil，断连缺口交由 SlotChecker 通过 RPC 补块；
+//   - 否则从分发进度的连续低水位（最小的在途 slot，或已分发的最大 slot + 1）开始续传（而不是按时间估算，避免估算误差导致漏块）。
+//     回放的区块中已下发过的部分由 BlockDeduper 按 slot+blockhash 去重，分发失败的 slot 已由去重器释放，会被重新下发。
+func (m *GrpcStreamManager) resumeFromSlot() *uint64 {
+	if !m.supportsFromSlot || m.processor == nil {
+		return nil
+	}
+	if m.fromSlotRejected.Swap(false) {
+		logger.Warnf("[GrpcStream] [%s] 上次 FromSlot 续传失败，本次连接不使用 FromSlot，缺口由 RPC 补块", m.name)
+		return nil
+	}
+
+	slot := m.processor.ResumeSlot()
+	if slot == 0 {
+		return nil // 首次启动，没有可续传的位置
+	}
+	return &slot
+}
+
 // connect 只尝试一次连接
-func (m *GrpcStreamManager) connect(lastBlockNumber int64, lastSlot uint64) error {
+func (m *GrpcStreamManager) connect() error {
 	m.mu.Lock()
 	if m.stopped {
 		m.mu.Unlock()
@@ -189,7 +344,7 @@ func (m *GrpcStreamManager) connect(lastBlockNumber int64, lastSlot uint64) erro
 	// 再关闭 stream
 	if m.stream != nil {
 		if err := m.stream.CloseSend(); err != nil {
-			logger.Warnf("[GrpcStream] CloseSend failed: %v", err)
+			logger.Warnf("[GrpcStream] [%s] CloseSend failed: %v", m.name, err)
 		}
 		m.stream = nil
 	}
@@ -197,7 +352,7 @@ func (m *GrpcStreamManager) connect(lastBlockNumber int64, lastSlot uint64) erro
 	// 开始建立新的stream
 	m.connCtx, m.connCancel = context.WithCancel(context.Background())
 
-	logger.Infof("[GrpcStream] Attempting to connect...")
+	logger.Infof("[GrpcStream] [%s] Attempting to connect...", m.name)
 
 	metaCtx := metadata.NewOutgoingContext(
 		m.connCtx,
@@ -205,34 +360,41 @@ func (m *GrpcStreamManager) connect(lastBlockNumber int64, lastSlot uint64) erro
 	)
 	stream, err := m.client.Subscribe(metaCtx)
 	if err != nil {
-		logger.Errorf("[GrpcStream] Failed to subscribe: %v", err)
+		logger.Errorf("[GrpcStream] [%s] Failed to subscribe: %v", m.name, err)
 		return err // 只返回错误
 	}
 
-	req := buildSubscribeRequest(lastBlockNumber, lastSlot)
-	err = sendWithTimeout(m.connCtx, stream.Send, req, time.Duration(m.sendTimeoutSec)*time.Second)
+	fromSlot := m.resumeFromSlot()
+	err = m.send(m.connCtx, stream, m.buildRequest(fromSlot))
 	if err != nil {
-		logger.Errorf("[GrpcStream] Failed to send request: %v", err)
+		logger.Errorf("[GrpcStream] [%s] Failed to send request: %v", m.name, err)
+		if fromSlot != nil {
+			m.fromSlotRejected.Store(true)
+		}
 		return err // 只返回错误
 	}
+	if fromSlot != nil {
+		logger.Infof("[GrpcStream] [%s] 使用 FromSlot 续传, from_slot = %d", m.name, *fromSlot)
+	}
 
 	m.stream = stream
 	m.reconnectAttempts = 0
-	logger.Infof("[GrpcStream] Connection established")
+	logger.Infof("[GrpcStream] [%s] Connection established", m.name)
 
 	// 启动 ping 协程
-	go m.pingLoop(m.connCtx)
+	go m.pingLoop(m.connCtx, stream)
 	// 启动 block 监听协程
-	go m.blockRecvLoop(m.connCtx)
+	go m.blockRecvLoop(m.connCtx, fromSlot != nil)
 
 	return nil
 }
 
-func (m *GrpcStreamManager) blockRecvLoop(ctx context.Context) {
+// blockRecvLoop 接收区块（交易订阅模式下为按 slot 组装出的区块）；replaying 表示本次连接使用了 FromSlot 续传，
+// 追平之前的历史区块延迟必然偏高，不触发延迟断连；超过 maxReplayDuration 仍未追平时恢复延迟断连检测。
+func (m *GrpcStreamManager) blockRecvLoop(ctx context.Context, replaying bool) {
 	const warnSlotStep = 50
-	var lastSlot uint64 = 0
-	var lastBlockTime int64 = 0
 	var lastWarnSlot uint64 = 0
+	var received = false
 	var totalLatency int64 = 0
 	var count int64 = 0
 
@@ -240,6 +402,13 @@ func (m *GrpcStreamManager) blockRecvLoop(ctx context.Context) {
 	warnThreshold := int64(m.maxLatencyWarnMs)
 	dropThreshold := int64(m.maxLatencyDropMs)
 	recvTimeout := time.Duration(m.recvTimeoutSec) * time.Second
+	replayDeadline := last.Add(maxReplayDuration)
+
+	// 交易订阅模式：每个连接使用独立的组装器，断连时未完整的 slot 直接丢弃（由 SlotChecker 补块）
+	var assembler *SlotAssembler
+	if m.txMode {
+		assembler = NewSlotAssembler(m.name, m.commitment)
+	}
 
 	for {
 		select {
@@ -249,48 +418,74 @@ func (m *GrpcStreamManager) blockRecvLoop(ctx context.Context) {
 			update, err := recvWithTimeout[*pb.SubscribeUpdate](ctx, m.stream.Recv, recvTimeout)
 			now := time.Now()
 			if err != nil {
-				logger.Errorf("[GrpcStream] reconnecting, stream error: %v", err)
-				m.reconnect(lastBlockTime, lastSlot)
+				if replaying && !received {
+					// 续传连接还没收到任何区块就出错，视为服务端无法回放该 FromSlot
+					m.fromSlotRejected.Store(true)
+				}
+				logger.Errorf("[GrpcStream] [%s] reconnecting, stream error: %v", m.name, err)
+				m.reconnect()
 				return
 			}
 
+			var block *pb.SubscribeUpdateBlock
 			switch u := (*update).GetUpdateOneof().(type) {
-			case *pb.SubscribeUpdate_Block:
-				// 检查是否丢失slot
-				if lastSlot != 0 && u.Block.Slot != lastSlot+1 {
-					m.slotChecker.Submit(lastSlot+1, u.Block.Slot-1)
+			case *pb.SubscribeUpdate_Slot:
+				if m.forkTracker != nil {
+					m.forkTracker.OnSlotUpdate(u.Slot)
+				}
+				if assembler != nil {
+					block = assembler.OnSlotStatus(u.Slot)
 				}
-				lastSlot = u.Block.Slot
-				lastBlockTime = u.Block.BlockTime.Timestamp * 1000
+			case *pb.SubscribeUpdate_Transaction:
+				if assembler != nil {
+					assembler.OnTransaction(u.Transaction)
+				}
+			case *pb.SubscribeUpdate_BlockMeta:
+				if assembler != nil {
+					block = assembler.OnBlockMeta(u.BlockMeta)
+				}
+			case *pb.SubscribeUpdate_Block:
+				block = u.Block
+				m.filter.Load().filterBlock(block)
+			}
+
+			if block != nil {
+				received = true
+				lastBlockTime := block.BlockTime.GetTimestamp() * 1000
 
 				interval := now.UnixMilli() - lastBlockTime // 算出收到这个区块时的延迟（ms）
 				totalLatency += interval
 				count++
 				avgLatency := totalLatency / count
-				logger.Infof("[GrpcStream] slot = %d, latency = %d ms, avg = %d ms (count = %d)", u.Block.Slot, interval, avgLatency, count)
+				logger.Infof("[GrpcStream] [%s] slot = %d, latency = %d ms, avg = %d ms (count = %d)", m.name, block.Slot, interval, avgLatency, count)
 
-				select {
-				case m.blockChan <- u.Block:
-					// 成功写入，无事发生
-				default:
-					//logger.Warnf("[GrpcStream] blockChan is full, discard block at slot %v", u.Block.Slot)
-				}
+				// 去重后下发；缺口检测（含断连期间的缺口）由 deduper 基于已下发的 slot 统一处理
+				m.deduper.Forward(m.name, block)
 
 				//无论是否写入成功，都要更新 last
 				last = now
-				if interval > dropThreshold {
-					logger.Errorf("[GrpcStream] reconnecting, slot=%d, latency too high: %dms > %dms", u.Block.Slot, interval, dropThreshold)
-					m.reconnect(lastBlockTime, lastSlot)
+				if replaying {
+					if interval <= warnThreshold {
+						replaying = false
+						logger.Infof("[GrpcStream] [%s] FromSlot 续传已追平, slot=%d, latency=%dms", m.name, block.Slot, interval)
+					} else if now.After(replayDeadline) {
+						replaying = false
+						logger.Warnf("[GrpcStream] [%s] FromSlot 续传 %v 仍未追平, slot=%d, latency=%dms，恢复延迟断连检测",
+							m.name, maxReplayDuration, block.Slot, interval)
+					}
+				} else if interval > dropThreshold {
+					logger.Errorf("[GrpcStream] [%s] reconnecting, slot=%d, latency too high: %dms > %dms", m.name, block.Slot, interval, dropThreshold)
+					m.reconnect()
 					return
-				} else if interval > warnThreshold && lastSlot-lastWarnSlot >= warnSlotStep {
-					logger.Warnf("[GrpcStream] latency too high, slot=%d, latency: %dms > %dms", u.Block.Slot, interval, warnThreshold)
-					lastWarnSlot = lastSlot
+				} else if interval > warnThreshold && block.Slot-lastWarnSlot >= warnSlotStep {
+					logger.Warnf("[GrpcStream] [%s] latency too high, slot=%d, latency: %dms > %dms", m.name, block.Slot, interval, warnThreshold)
+					lastWarnSlot = block.Slot
 				}
 			}
 
 			if time.Since(last) > recvTimeout {
-				logger.Errorf("[GrpcStream] reconnecting, %v未收到block，触发重连", recvTimeout)
-				m.reconnect(lastBlockTime, lastSlot)
+				logger.Errorf("[GrpcStream] [%s] reconnecting, %v未收到block，触发重连", m.name, recvTimeout)
+				m.reconnect()
 				return
 			}
 		}
@@ -408,7 +603,7 @@ func recvWithTimeout[T any](
 }
 
 // 心跳检测
-func (m *GrpcStreamManager) pingLoop(ctx context.Context) {
+func (m *GrpcStreamManager) pingLoop(ctx context.Context, stream pb.Geyser_SubscribeClient) {
 	ticker := time.NewTicker(time.Duration(m.streamPingIntervalSec) * time.Second)
 	defer ticker.Stop()
 	for {
@@ -419,16 +614,16 @@ func (m *GrpcStreamManager) pingLoop(ctx context.Context) {
 			pingReq := &pb.SubscribeRequest{
 				Ping: &pb.SubscribeRequestPing{Id: 1},
 			}
-			err := sendWithTimeout(ctx, m.stream.Send, pingReq, time.Duration(m.sendTimeoutSec)*time.Second)
+			err := m.send(ctx, stream, pingReq)
 			if err != nil {
-				logger.Warnf("[GrpcStream] Ping failed (non-critical): %v", err)
+				logger.Warnf("[GrpcStream] [%s] Ping failed (non-critical): %v", m.name, err)
 				continue // ✅ 安全！由 recvLoop 兜底 reconnect
 			}
 		}
 	}
 }
 
-func (m *GrpcStreamManager) reconnect(lastBlockNumber int64, lastSlot uint64) {
+func (m *GrpcStreamManager) reconnect() {
 	m.mu.Lock()
 	if m.stopped {
 		m.mu.Unlock()
@@ -440,23 +635,9 @@ func (m *GrpcStreamManager) reconnect(lastBlockNumber int64, lastSlot uint64) {
 	}
 	m.mu.Unlock()
 
-	go m.mustConnect(lastBlockNumber, lastSlot)
+	go m.mustConnect()
 }
 
 func boolPtr(b bool) *bool {
 	return &b
 }
-
-// estimateCurrentSlot 推算当前应处于哪个 slot（以指定 blockTime/slot 为基准）
-// slotOffset 表示从估算值中回退的 slot 数，防止错过早期推送
-func estimateCurrentSlot(baseBlockTime int64, baseSlot uint64) uint64 {
-	const slotsPerSecond = 2.5
-	const slotOffset = 4
-
-	delta := time.Now().Unix() - baseBlockTime
-	estimated := baseSlot + uint64(float64(delta)*slotsPerSecond) - slotOffset
-	if estimated > baseSlot {
-		return estimated
-	}
-	return baseSlot
-}
@@ -0,0 +1,297 @@
+package grpc
+
+import (
+	"dex-indexer-sol/internal/config"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/svc"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+)
+
+const (
+	blockSourceGrpc      = "grpc"      // 只使用 gRPC 订阅
+	blockSourceWebsocket = "websocket" // 只使用 RPC websocket blockSubscribe
+	blockSourceFailover  = "failover"  // 默认使用 gRPC，gRPC 全部中断时自动启用 websocket
+
+	defaultFailoverAfter  = 10 * time.Second // 未配置 failover_after_sec 时的切换阈值
+	failoverCheckInterval = time.Second
+	grpcAliveWindow       = 3 * time.Second // 最近该时间内收到过 gRPC 区块即视为 gRPC 正常
+)
+
+// BlockSource 区块来源（gRPC 订阅流、websocket 订阅），收到的区块统一交给 BlockDeduper 去重后下发
+type BlockSource interface {
+	Start()
+	Stop()
+}
+
+// GrpcStreamGroup 同时订阅多个 gRPC endpoint，每个 slot 只下发最先到达的一份，
+// 单个服务端的延迟抖动或故障不再影响下游延迟。
+// failover 模式下，全部 gRPC 流中断超过阈值时启用 websocket 备用来源，gRPC 恢复后再停用。
+type GrpcStreamGroup struct {
+	sc          *svc.GrpcServiceContext
+	sources     []BlockSource // 常驻来源（gRPC 流，或 websocket 模式下的 websocket 订阅）
+	grpcNames   []string      // gRPC 流的名称，用于判断 gRPC 是否存活
+	deduper     *BlockDeduper
+	slotChecker *SlotChecker
+	spill       *BlockSpillQueue // 未配置落盘目录时为 nil
+	capture     *BlockCapture    // 未配置抓包目录时为 nil
+
+	failover      bool          // 是否开启自动切换
+	failoverAfter time.Duration // gRPC 中断多久后切换到 websocket
+	standbyMu     sync.Mutex
+	standby       *WsBlockSource // failover 模式下当前启用的 websocket 来源，未启用时为 nil
+	stopCh        chan struct{}
+	stopOnce      sync.Once
+}
+
+func NewGrpcStreamGroup(
+	sc *svc.GrpcServiceContext,
+	blockChan chan *pb.SubscribeUpdateBlock,
+	processor *BlockProcessor, // 用于 SlotChecker 补块、FromSlot 续传
+) (*GrpcStreamGroup, error) {
+	var spill *BlockSpillQueue
+	if spillConf := sc.Config.SpillQueueConf; spillConf.Dir != "" {
+		var err error
+		spill, err = NewBlockSpillQueue(spillConf, blockChan)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var capture *BlockCapture
+	if captureConf := sc.Config.CaptureConf; captureConf.Dir != "" {
+		var err error
+		capture, err = NewBlockCapture(captureConf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mode := parseBlockSourceMode(sc.Config.Grpc.Source)
+	partial := wsMentionFiltered(sc.Config.Grpc.Websocket.Mention)
+	if mode == blockSourceFailover && partial {
+		// mention 过滤后的区块只含部分交易，与 gRPC 全量区块共用去重会导致同一 slot 的全量区块被丢弃
+		return nil, fmt.Errorf("failover mode does not support grpc.websocket.mention, leave it empty")
+	}
+
+	slotChecker := NewSlotChecker(sc.Config.Grpc.RpcEndpoint, rpcCommitment(sc.Config.Grpc.Commitment), processor)
+	gapChecker := slotChecker
+	if mode == blockSourceWebsocket && partial {
+		// 没有匹配交易的 slot 不会推送，缺口属于正常现象，且 RPC 补回的是全量区块，不做缺口检测
+		logger.Warnf("[GrpcStreamGroup] websocket mention=%s 只推送部分区块, 已关闭缺口检测与 RPC 补块", sc.Config.Grpc.Websocket.Mention)
+		gapChecker = nil
+	}
+	deduper := NewBlockDeduper(blockChan, gapChecker, spill, capture)
+	slotChecker.SetReceivedFunc(deduper.Seen)
+	processor.SetDispatchFailedFunc(deduper.Release)
+
+	g := &GrpcStreamGroup{
+		sc:          sc,
+		deduper:     deduper,
+		slotChecker: slotChecker,
+		spill:       spill,
+		capture:     capture,
+		stopCh:      make(chan struct{}),
+	}
+
+	if mode == blockSourceWebsocket {
+		ws, err := NewWsBlockSource(sc, deduper)
+		if err != nil {
+			return nil, err
+		}
+		g.sources = append(g.sources, ws)
+		logger.Infof("[GrpcStreamGroup] 使用 websocket blockSubscribe 作为区块来源")
+		return g, nil
+	}
+
+	if mode == blockSourceFailover {
+		if sc.Config.Grpc.Websocket.Endpoint == "" {
+			return nil, fmt.Errorf("failover mode requires grpc.websocket.endpoint")
+		}
+		g.failover = true
+		g.failoverAfter = time.Duration(sc.Config.Grpc.Websocket.FailoverAfterSec) * time.Second
+		if g.failoverAfter <= 0 {
+			g.failoverAfter = defaultFailoverAfter
+		}
+	}
+
+	endpoints := sc.Config.GrpcEndpoints()
+	for i, ep := range endpoints {
+		if ep.Name == "" {
+			ep.Name = fmt.Sprintf("endpoint-%d", i)
+		}
+		stream, err := NewGrpcStreamManager(sc, ep, deduper, processor)
+		if err != nil {
+			// 多 endpoint 时单个不可用不影响启动
+			logger.Errorf("[GrpcStreamGroup] endpoint %s 初始化失败, 已跳过: %v", ep.Name, err)
+			continue
+		}
+		g.sources = append(g.sources, stream)
+		g.grpcNames = append(g.grpcNames, ep.Name)
+	}
+	// failover 模式下 gRPC 全部不可用时仍可启动，由 websocket 兜底
+	if len(g.sources) == 0 && !g.failover {
+		return nil, fmt.Errorf("no available grpc endpoint (total %d)", len(endpoints))
+	}
+
+	logger.Infof("[GrpcStreamGroup] 已初始化 %d/%d 个 endpoint, failover=%v", len(g.sources), len(endpoints), g.failover)
+	return g, nil
+}
+
+func (g *GrpcStreamGroup) Start() {
+	if g.spill != nil {
+		g.spill.Start()
+	}
+	if g.capture != nil {
+		g.capture.Start()
+	}
+	g.slotChecker.Start()
+
+	if g.failover {
+		go g.failoverLoop()
+	}
+
+	var wg sync.WaitGroup
+	for _, source := range g.sources {
+		wg.Add(1)
+		go func(s BlockSource) {
+			defer wg.Done()
+			s.Start()
+		}(source)
+	}
+	wg.Wait()
+}
+
+func (g *GrpcStreamGroup) Stop() {
+	g.stopOnce.Do(func() { close(g.stopCh) })
+	g.deactivateStandby()
+	for _, source := range g.sources {
+		source.Stop()
+	}
+	g.slotChecker.Stop()
+	if g.spill != nil {
+		g.spill.Stop()
+	}
+	if g.capture != nil {
+		g.capture.Stop()
+	}
+}
+
+// UpdateAccountFilter 热更新全部 gRPC 流的账户过滤条件（websocket 来源不受影响）
+func (g *GrpcStreamGroup) UpdateAccountFilter(conf config.AccountFilterConfig) error {
+	filter, err := NewAccountFilter(conf, isTransactionMode(g.sc.Config.Grpc.SubscribeMode))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	updated := 0
+	for _, source := range g.sources {
+		if stream, ok := source.(*GrpcStreamManager); ok {
+			if err = stream.UpdateAccountFilter(filter); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			updated++
+		}
+	}
+	if updated == 0 && len(errs) == 0 {
+		// websocket 模式下没有 gRPC 订阅流，blockSubscribe 的过滤条件不支持热更新
+		logger.Warnf("[GrpcStreamGroup] 没有可热更新的 gRPC 订阅流, 账户过滤条件未生效")
+	}
+	return errors.Join(errs...)
+}
+
+// failoverLoop 定期检查 gRPC 是否存活：全部 gRPC 流超过 failoverAfter 未收到区块时启用 websocket，
+// gRPC 连续正常 failoverAfter 后停用 websocket。切换期间两路来源同时运行，重复区块由 BlockDeduper 去重。
+func (g *GrpcStreamGroup) failoverLoop() {
+	ticker := time.NewTicker(failoverCheckInterval)
+	defer ticker.Stop()
+
+	started := time.Now()
+	var healthySince time.Time
+	for {
+		select {
+		case <-g.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		last := g.deduper.LastArrival(g.grpcNames)
+		if last.IsZero() {
+			last = started // 启动后尚未收到过 gRPC 区块，从启动时间开始计算
+		}
+		idle := now.Sub(last)
+
+		if idle > grpcAliveWindow {
+			healthySince = time.Time{}
+		} else if healthySince.IsZero() {
+			healthySince = now
+		}
+
+		active := g.standbyActive()
+		switch {
+		case !active && idle > g.failoverAfter:
+			logger.Warnf("[GrpcStreamGroup] gRPC 已 %v 未收到区块，切换到 websocket 备用来源", idle.Truncate(time.Second))
+			g.activateStandby()
+		case active && !healthySince.IsZero() && now.Sub(healthySince) >= g.failoverAfter:
+			logger.Infof("[GrpcStreamGroup] gRPC 已恢复 %v，停用 websocket 备用来源", now.Sub(healthySince).Truncate(time.Second))
+			g.deactivateStandby()
+		}
+	}
+}
+
+func (g *GrpcStreamGroup) standbyActive() bool {
+	g.standbyMu.Lock()
+	defer g.standbyMu.Unlock()
+	return g.standby != nil
+}
+
+func (g *GrpcStreamGroup) activateStandby() {
+	g.standbyMu.Lock()
+	defer g.standbyMu.Unlock()
+
+	select {
+	case <-g.stopCh:
+		return
+	default:
+	}
+	if g.standby != nil {
+		return
+	}
+	ws, err := NewWsBlockSource(g.sc, g.deduper)
+	if err != nil {
+		logger.Errorf("[GrpcStreamGroup] websocket 备用来源初始化失败: %v", err)
+		return
+	}
+	ws.Start()
+	g.standby = ws
+}
+
+func (g *GrpcStreamGroup) deactivateStandby() {
+	g.standbyMu.Lock()
+	defer g.standbyMu.Unlock()
+	if g.standby != nil {
+		g.standby.Stop()
+		g.standby = nil
+	}
+}
+
+// parseBlockSourceMode 解析区块来源，未配置或无法识别时只使用 gRPC
+func parseBlockSourceMode(mode string) string {
+	switch m := strings.ToLower(mode); m {
+	case blockSourceWebsocket, blockSourceFailover:
+		return m
+	case "", blockSourceGrpc:
+		return blockSourceGrpc
+	default:
+		logger.Warnf("[GrpcStreamGroup] unknown block source %q, fallback to grpc", mode)
+		return blockSourceGrpc
+	}
+}
@@ -0,0 +1,151 @@
+package grpc
+
+import (
+	"dex-indexer-sol/internal/pkg/logger"
+	"sort"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+)
+
+const assembleWindowSlots = 150 // 未组装完成的 slot 最多保留的跨度，超出视为不完整直接丢弃（缺口由 SlotChecker 补块）
+
+// pendingSlot 正在组装的 slot
+type pendingSlot struct {
+	txs     []*pb.SubscribeUpdateTransactionInfo
+	meta    *pb.SubscribeUpdateBlockMeta
+	reached bool // slot 状态已达到订阅的 commitment
+	done    bool // 已组装输出，保留到离开窗口，忽略之后的重复更新（如 finalized 状态）
+}
+
+// SlotAssembler 在交易订阅模式下将同一 slot 的交易重新组装为区块。
+// 同一 slot 的交易先于 BlockMeta 推送，因此收到 BlockMeta 且 slot 状态达到 commitment 后，即可认为该 slot 已完整。
+// 组装出的区块只包含命中过滤条件的交易，后续处理流程与全量区块一致。
+// 只在单个流的接收协程中使用，无需加锁。
+type SlotAssembler struct {
+	name        string // endpoint 名称，用于日志
+	commitment  pb.CommitmentLevel
+	pending     map[uint64]*pendingSlot
+	highestSlot uint64 // 收到过的最大 slot
+}
+
+func NewSlotAssembler(name string, commitment pb.CommitmentLevel) *SlotAssembler {
+	return &SlotAssembler{
+		name:       name,
+		commitment: commitment,
+		pending:    make(map[uint64]*pendingSlot, 64),
+	}
+}
+
+// OnTransaction 缓存一笔交易
+func (a *SlotAssembler) OnTransaction(u *pb.SubscribeUpdateTransaction) {
+	if u.Transaction == nil || a.expired(u.Slot) {
+		return
+	}
+	ps := a.slotOf(u.Slot)
+	if ps.done {
+		logger.Warnf("[SlotAssembler] [%s] slot %d 已组装，忽略迟到的交易 %d", a.name, u.Slot, u.Transaction.Index)
+		return
+	}
+	ps.txs = append(ps.txs, u.Transaction)
+}
+
+// OnBlockMeta 记录区块元信息，slot 已完整时返回组装好的区块
+func (a *SlotAssembler) OnBlockMeta(meta *pb.SubscribeUpdateBlockMeta) *pb.SubscribeUpdateBlock {
+	if a.expired(meta.Slot) {
+		return nil
+	}
+	ps := a.slotOf(meta.Slot)
+	if ps.done {
+		return nil
+	}
+	ps.meta = meta
+	return a.tryAssemble(ps)
+}
+
+// OnSlotStatus 处理 slot 状态更新，slot 已完整时返回组装好的区块
+func (a *SlotAssembler) OnSlotStatus(u *pb.SubscribeUpdateSlot) *pb.SubscribeUpdateBlock {
+	if u.Status == pb.SlotStatus_SLOT_DEAD {
+		if ps, ok := a.pending[u.Slot]; ok {
+			logger.Warnf("[SlotAssembler] [%s] slot %d dead, 丢弃已缓存的 %d 笔交易", a.name, u.Slot, len(ps.txs))
+			delete(a.pending, u.Slot)
+		}
+		return nil
+	}
+	if !reachedCommitment(u.Status, a.commitment) || a.expired(u.Slot) {
+		return nil
+	}
+	ps := a.slotOf(u.Slot)
+	ps.reached = true
+	return a.tryAssemble(ps)
+}
+
+func (a *SlotAssembler) slotOf(slot uint64) *pendingSlot {
+	ps, ok := a.pending[slot]
+	if !ok {
+		ps = &pendingSlot{}
+		a.pending[slot] = ps
+	}
+	if slot > a.highestSlot {
+		a.highestSlot = slot
+		a.evict()
+	}
+	return ps
+}
+
+func (a *SlotAssembler) expired(slot uint64) bool {
+	return a.highestSlot > assembleWindowSlots && slot < a.highestSlot-assembleWindowSlots
+}
+
+func (a *SlotAssembler) tryAssemble(ps *pendingSlot) *pb.SubscribeUpdateBlock {
+	if ps.done || ps.meta == nil || !ps.reached {
+		return nil
+	}
+
+	// 交易按在区块中的位置排序，保证事件 ID 与全量区块模式一致
+	sort.Slice(ps.txs, func(i, j int) bool { return ps.txs[i].Index < ps.txs[j].Index })
+
+	meta, txs := ps.meta, ps.txs
+	*ps = pendingSlot{done: true}
+	return &pb.SubscribeUpdateBlock{
+		Slot:                     meta.Slot,
+		Blockhash:                meta.Blockhash,
+		BlockTime:                meta.BlockTime,
+		BlockHeight:              meta.BlockHeight,
+		ParentSlot:               meta.ParentSlot,
+		ParentBlockhash:          meta.ParentBlockhash,
+		ExecutedTransactionCount: meta.ExecutedTransactionCount,
+		Transactions:             txs,
+	}
+}
+
+// evict 丢弃超出窗口仍未组装完成的 slot
+func (a *SlotAssembler) evict() {
+	if a.highestSlot <= assembleWindowSlots {
+		return
+	}
+	minSlot := a.highestSlot - assembleWindowSlots
+	for slot, ps := range a.pending {
+		if slot >= minSlot {
+			continue
+		}
+		if ps.meta != nil || len(ps.txs) > 0 {
+			logger.Warnf("[SlotAssembler] [%s] slot %d 组装超时（meta=%v, reached=%v, txs=%d），丢弃",
+				a.name, slot, ps.meta != nil, ps.reached, len(ps.txs))
+		}
+		delete(a.pending, slot)
+	}
+}
+
+// reachedCommitment 判断 slot 状态是否已达到订阅的 commitment 级别
+func reachedCommitment(status pb.SlotStatus, commitment pb.CommitmentLevel) bool {
+	switch commitment {
+	case pb.CommitmentLevel_PROCESSED:
+		return status == pb.SlotStatus_SLOT_PROCESSED ||
+			status == pb.SlotStatus_SLOT_CONFIRMED ||
+			status == pb.SlotStatus_SLOT_FINALIZED
+	case pb.CommitmentLevel_FINALIZED:
+		return status == pb.SlotStatus_SLOT_FINALIZED
+	default:
+		return status == pb.SlotStatus_SLOT_CONFIRMED || status == pb.SlotStatus_SLOT_FINALIZED
+	}
+}
@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"testing"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTxUpdate(slot, index uint64) *pb.SubscribeUpdateTransaction {
+	return &pb.SubscribeUpdateTransaction{
+		Slot:        slot,
+		Transaction: &pb.SubscribeUpdateTransactionInfo{Index: index},
+	}
+}
+
+func testBlockMeta(slot uint64) *pb.SubscribeUpdateBlockMeta {
+	return &pb.SubscribeUpdateBlockMeta{
+		Slot:                     slot,
+		Blockhash:                "hash",
+		ParentSlot:               slot - 1,
+		ParentBlockhash:          "parent",
+		BlockTime:                &pb.UnixTimestamp{Timestamp: 1760000000},
+		BlockHeight:              &pb.BlockHeight{BlockHeight: slot - 1000},
+		ExecutedTransactionCount: 1200,
+	}
+}
+
+func slotStatus(slot uint64, status pb.SlotStatus) *pb.SubscribeUpdateSlot {
+	return &pb.SubscribeUpdateSlot{Slot: slot, Status: status}
+}
+
+func TestSlotAssembler_AssemblesAfterMetaAndCommitment(t *testing.T) {
+	a := NewSlotAssembler("test", pb.CommitmentLevel_CONFIRMED)
+	a.OnTransaction(testTxUpdate(100, 7))
+	a.OnTransaction(testTxUpdate(100, 2))
+	a.OnTransaction(&pb.SubscribeUpdateTransaction{Slot: 100}) // 空交易忽略
+
+	// processed 未达到 confirmed，meta 到达前也不组装
+	assert.Nil(t, a.OnSlotStatus(slotStatus(100, pb.SlotStatus_SLOT_PROCESSED)))
+	assert.Nil(t, a.OnSlotStatus(slotStatus(100, pb.SlotStatus_SLOT_CONFIRMED)))
+
+	block := a.OnBlockMeta(testBlockMeta(100))
+	require.NotNil(t, block)
+	assert.Equal(t, uint64(100), block.Slot)
+	assert.Equal(t, "hash", block.Blockhash)
+	assert.Equal(t, uint64(99), block.ParentSlot)
+	assert.Equal(t, "parent", block.ParentBlockhash)
+	assert.Equal(t, int64(1760000000), block.BlockTime.Timestamp)
+	assert.Equal(t, uint64(1200), block.ExecutedTransactionCount)
+	// 交易按区块内位置排序
+	require.Len(t, block.Transactions, 2)
+	assert.Equal(t, uint64(2), block.Transactions[0].Index)
+	assert.Equal(t, uint64(7), block.Transactions[1].Index)
+
+	// 已组装的 slot 不会再次输出，迟到的交易与重复的 meta 被忽略
+	assert.Nil(t, a.OnSlotStatus(slotStatus(100, pb.SlotStatus_SLOT_FINALIZED)))
+	a.OnTransaction(testTxUpdate(100, 9))
+	assert.Nil(t, a.OnBlockMeta(testBlockMeta(100)))
+	require.Contains(t, a.pending, uint64(100))
+	assert.True(t, a.pending[100].done)
+	assert.Empty(t, a.pending[100].txs)
+	assert.Nil(t, a.pending[100].meta)
+}
+
+func TestSlotAssembler_MetaBeforeStatus(t *testing.T) {
+	a := NewSlotAssembler("test", pb.CommitmentLevel_FINALIZED)
+	a.OnTransaction(testTxUpdate(100, 1))
+	assert.Nil(t, a.OnBlockMeta(testBlockMeta(100)))
+	assert.Nil(t, a.OnSlotStatus(slotStatus(100, pb.SlotStatus_SLOT_CONFIRMED)), "finalized 订阅下 confirmed 不算完整")
+
+	block := a.OnSlotStatus(slotStatus(100, pb.SlotStatus_SLOT_FINALIZED))
+	require.NotNil(t, block)
+	assert.Len(t, block.Transactions, 1)
+}
+
+func TestSlotAssembler_EmptySlot(t *testing.T) {
+	a := NewSlotAssembler("test", pb.CommitmentLevel_PROCESSED)
+	assert.Nil(t, a.OnBlockMeta(testBlockMeta(100)))
+	block := a.OnSlotStatus(slotStatus(100, pb.SlotStatus_SLOT_PROCESSED))
+	require.NotNil(t, block, "没有命中过滤条件的交易时仍输出空区块")
+	assert.Empty(t, block.Transactions)
+}
+
+func TestSlotAssembler_DeadSlotDropped(t *testing.T) {
+	a := NewSlotAssembler("test", pb.CommitmentLevel_CONFIRMED)
+	a.OnTransaction(testTxUpdate(100, 1))
+	assert.Nil(t, a.OnSlotStatus(slotStatus(100, pb.SlotStatus_SLOT_DEAD)))
+	assert.Empty(t, a.pending)
+
+	// dead slot 之后只收到 meta 与状态：之前的交易已丢弃
+	a.OnBlockMeta(testBlockMeta(100))
+	block := a.OnSlotStatus(slotStatus(100, pb.SlotStatus_SLOT_CONFIRMED))
+	require.NotNil(t, block)
+	assert.Empty(t, block.Transactions)
+}
+
+func TestSlotAssembler_EvictsIncompleteSlots(t *testing.T) {
+	a := NewSlotAssembler("test", pb.CommitmentLevel_CONFIRMED)
+	a.OnTransaction(testTxUpdate(100, 1))
+	a.OnBlockMeta(testBlockMeta(101))
+
+	// 超出窗口后未完成的 slot 被清理，迟到的更新直接忽略
+	high := uint64(101 + assembleWindowSlots + 1)
+	a.OnTransaction(testTxUpdate(high, 1))
+	assert.NotContains(t, a.pending, uint64(100))
+	assert.NotContains(t, a.pending, uint64(101))
+
+	assert.Nil(t, a.OnSlotStatus(slotStatus(101, pb.SlotStatus_SLOT_CONFIRMED)))
+	a.OnTransaction(testTxUpdate(100, 2))
+	assert.NotContains(t, a.pending, uint64(100))
+	assert.Len(t, a.pending, 1)
+}
+
+func TestReachedCommitment(t *testing.T) {
+	cases := []struct {
+		commitment pb.CommitmentLevel
+		status     pb.SlotStatus
+		want       bool
+	}{
+		{pb.CommitmentLevel_PROCESSED, pb.SlotStatus_SLOT_PROCESSED, true},
+		{pb.CommitmentLevel_PROCESSED, pb.SlotStatus_SLOT_FIRST_SHRED_RECEIVED, false},
+		{pb.CommitmentLevel_CONFIRMED, pb.SlotStatus_SLOT_PROCESSED, false},
+		{pb.CommitmentLevel_CONFIRMED, pb.SlotStatus_SLOT_CONFIRMED, true},
+		{pb.CommitmentLevel_CONFIRMED, pb.SlotStatus_SLOT_FINALIZED, true},
+		{pb.CommitmentLevel_FINALIZED, pb.SlotStatus_SLOT_CONFIRMED, false},
+		{pb.CommitmentLevel_FINALIZED, pb.SlotStatus_SLOT_FINALIZED, true},
+		{pb.CommitmentLevel_FINALIZED, pb.SlotStatus_SLOT_DEAD, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, reachedCommitment(c.status, c.commitment), "%s / %s", c.commitment, c.status)
+	}
+}
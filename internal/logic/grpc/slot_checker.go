@@ -2,12 +2,21 @@ package grpc
 
 import (
 	"context"
+	"dex-indexer-sol/internal/logic/txadapter"
 	"dex-indexer-sol/internal/pkg/logger"
+	"fmt"
 	"github.com/blocto/solana-go-sdk/rpc"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
 	"sort"
 	"time"
 )
 
+const (
+	backfillSubmitTimeout = 30 * time.Second // 补块写入 BlockProcessor 的最大等待时间
+	maxBackfillPerRound   = 500              // 单轮最多补块数量，防止长时间阻塞检测循环
+	maxBackfillAttempts   = 3                // 单个 slot 补块失败（getBlock / 转换 / 提交）后的最多尝试轮数
+)
+
 type SlotRange struct {
 	From     uint64
 	To       uint64
@@ -15,25 +24,37 @@ type SlotRange struct {
 }
 
 type SlotChecker struct {
-	client   *rpc.RpcClient
-	rangeCh  chan SlotRange
-	ctx      context.Context
-	cancel   context.CancelFunc
-	endpoint string
+	client     *rpc.RpcClient
+	rangeCh    chan SlotRange
+	ctx        context.Context
+	cancel     context.CancelFunc
+	endpoint   string
+	commitment rpc.Commitment         // getBlocks / getBlock 使用的 commitment，与订阅级别一致
+	processor  *BlockProcessor        // 补块处理器，为 nil 时只检测不补块
+	received   func(slot uint64) bool // 补块前复核：slot 是否已经由其他流迟到送达
+	attempts   map[uint64]int         // 补块失败的 slot 已尝试的轮数，只在 run 协程中访问
 }
 
-func NewSlotChecker(endpoint string) *SlotChecker {
+func NewSlotChecker(endpoint string, commitment rpc.Commitment, processor *BlockProcessor) *SlotChecker {
 	ctx, cancel := context.WithCancel(context.Background())
 	client := rpc.NewRpcClient(endpoint)
 	return &SlotChecker{
-		client:   &client,
-		rangeCh:  make(chan SlotRange, 300),
-		ctx:      ctx,
-		cancel:   cancel,
-		endpoint: endpoint,
+		client:     &client,
+		rangeCh:    make(chan SlotRange, 300),
+		ctx:        ctx,
+		cancel:     cancel,
+		endpoint:   endpoint,
+		commitment: commitment,
+		processor:  processor,
+		attempts:   make(map[uint64]int),
 	}
 }
 
+// SetReceivedFunc 设置补块前的复核函数（多流竞速时，某个流的缺口可能已由其他流补上）
+func (s *SlotChecker) SetReceivedFunc(fn func(slot uint64) bool) {
+	s.received = fn
+}
+
 func (s *SlotChecker) Start() {
 	go s.run()
 }
@@ -111,12 +132,26 @@ func (s *SlotChecker) run() {
 			}
 
 			// 串行执行，防止 goroutine 累积
-			s.checkSlotRanges(ready)
+			remaining := s.checkSlotRanges(ready)
 			ranges = pending
+
+			// 超出单轮上限的漏扫 slot 放回待检测队列，下一轮直接补块（不受 maxPendingRanges 限制，避免丢失）
+			for _, r := range slotsToRanges(remaining) {
+				r.SubmitAt = now.Add(-delayBeforeCheck)
+				ranges = append(ranges, r)
+			}
 		}
 	}
 }
 
+// rpcCommitment 将订阅的 commitment 转换为 RPC 查询使用的 commitment（getBlock 不支持 processed，降级为 confirmed）
+func rpcCommitment(s string) rpc.Commitment {
+	if parseCommitment(s) == pb.CommitmentLevel_FINALIZED {
+		return rpc.CommitmentFinalized
+	}
+	return rpc.CommitmentConfirmed
+}
+
 func drainTicker(t *time.Ticker) {
 	for {
 		select {
@@ -128,10 +163,11 @@ func drainTicker(t *time.Ticker) {
 	}
 }
 
-func (s *SlotChecker) checkSlotRanges(ranges []SlotRange) {
+// checkSlotRanges 检测范围内漏扫的 slot 并补块，返回超出单轮补块上限、留待下一轮处理的 slot
+func (s *SlotChecker) checkSlotRanges(ranges []SlotRange) []uint64 {
 	merged := mergeRanges(ranges)
 	if len(merged) == 0 {
-		return
+		return nil
 	}
 
 	maxEmptySlots := 0
@@ -147,7 +183,7 @@ func (s *SlotChecker) checkSlotRanges(ranges []SlotRange) {
 		select {
 		case <-s.ctx.Done():
 			logger.Infof("[SlotChecker] stopped while checking slot range [%d, %d]", r.From, r.To)
-			return
+			return nil
 		default:
 		}
 
@@ -161,6 +197,7 @@ func (s *SlotChecker) checkSlotRanges(ranges []SlotRange) {
 		fillEmptySlots(r.From, r.To, blocks, confirmedEmptySlots)
 	}
 
+	missingSlots := make([]uint64, 0)
 	for _, r := range ranges {
 		for slot := r.From; slot <= r.To; slot++ {
 			// merge后的range不会有交集, 并且是排好序的,所以可以直接用二分查找
@@ -170,11 +207,103 @@ func (s *SlotChecker) checkSlotRanges(ranges []SlotRange) {
 
 			if _, ok := confirmedEmptySlots[slot]; ok {
 				logger.Infof("[SlotChecker] slot %d is confirmed empty", slot)
+				delete(s.attempts, slot)
+			} else if s.received != nil && s.received(slot) {
+				logger.Infof("[SlotChecker] slot %d 已迟到送达，无需补块", slot)
+				delete(s.attempts, slot)
+			} else {
+				logger.Errorf("[SlotChecker] slot %d is missing，疑似漏扫，准备 RPC 补块", slot)
+				missingSlots = append(missingSlots, slot)
+			}
+		}
+	}
+
+	return s.backfillSlots(missingSlots)
+}
+
+// backfillSlots 通过 RPC getBlock 拉取漏扫的区块，交给 BlockProcessor 按 source = RPC 重新处理。
+// 串行执行：补块频率低，且需避免与实时流争抢解析与 Kafka 资源。
+// 单轮最多补 maxBackfillPerRound 个，返回其余未处理的 slot（升序）。
+func (s *SlotChecker) backfillSlots(slots []uint64) []uint64 {
+	if s.processor == nil || len(slots) == 0 {
+		return nil
+	}
+
+	// ranges 可能有重叠，去重后按 slot 升序补块
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	uniq := slots[:0]
+	for i, slot := range slots {
+		if i == 0 || slot != slots[i-1] {
+			uniq = append(uniq, slot)
+		}
+	}
+	var remaining []uint64
+	if len(uniq) > maxBackfillPerRound {
+		logger.Warnf("[SlotChecker] 漏扫 slot 过多(%d)，本轮补前 %d 个，其余 [%d, %d] 留待下一轮",
+			len(uniq), maxBackfillPerRound, uniq[maxBackfillPerRound], uniq[len(uniq)-1])
+		remaining = append([]uint64(nil), uniq[maxBackfillPerRound:]...)
+		uniq = uniq[:maxBackfillPerRound]
+	}
+
+	success := 0
+	for _, slot := range uniq {
+		select {
+		case <-s.ctx.Done():
+			logger.Infof("[SlotChecker] stopped while backfilling slot %d", slot)
+			return nil
+		default:
+		}
+
+		if err := s.backfillSlot(slot); err != nil {
+			s.attempts[slot]++
+			if s.attempts[slot] < maxBackfillAttempts {
+				logger.Errorf("[SlotChecker] slot %d 补块失败（第 %d 次），下一轮重试: %v", slot, s.attempts[slot], err)
+				remaining = append(remaining, slot)
 			} else {
-				logger.Errorf("[SlotChecker] slot %d is missing，疑似漏扫", slot)
+				logger.Errorf("[SlotChecker] slot %d 补块失败（第 %d 次），放弃: %v", slot, s.attempts[slot], err)
+				delete(s.attempts, slot)
 			}
+			continue
+		}
+		delete(s.attempts, slot)
+		success++
+	}
+
+	logger.Infof("[SlotChecker] 本轮补块完成: 成功 %d / 共 %d", success, len(uniq))
+	sort.Slice(remaining, func(i, j int) bool { return remaining[i] < remaining[j] })
+	return remaining
+}
+
+// backfillSlot 拉取单个区块并提交 BlockProcessor
+func (s *SlotChecker) backfillSlot(slot uint64) error {
+	block, err := s.getBlockWithRetry(slot, 3)
+	if err != nil {
+		return fmt.Errorf("getBlock error: %w", err)
+	}
+
+	update, err := txadapter.ConvertRpcBlock(slot, block)
+	if err != nil {
+		return fmt.Errorf("convert error: %w", err)
+	}
+
+	if !s.processor.SubmitRpcBlock(update, backfillSubmitTimeout) {
+		return fmt.Errorf("提交 BlockProcessor 超时")
+	}
+	logger.Infof("[SlotChecker] slot %d 已通过 RPC 补块, 交易数: %d", slot, len(update.Transactions))
+	return nil
+}
+
+// slotsToRanges 将升序 slot 列表合并为连续的闭区间
+func slotsToRanges(slots []uint64) []SlotRange {
+	var ranges []SlotRange
+	for _, slot := range slots {
+		if n := len(ranges); n > 0 && ranges[n-1].To+1 == slot {
+			ranges[n-1].To = slot
+			continue
 		}
+		ranges = append(ranges, SlotRange{From: slot, To: slot})
 	}
+	return ranges
 }
 
 func slotInFailedRanges(slot uint64, failedRanges []SlotRange) bool {
@@ -210,7 +339,7 @@ func (s *SlotChecker) getBlocksWithRetry(from, to uint64, maxRetries int) ([]uin
 		}
 
 		ctx, cancel := context.WithTimeout(s.ctx, 6*time.Second)
-		blocks, err := s.client.GetBlocks(ctx, from, to)
+		blocks, err := s.client.GetBlocksWithConfig(ctx, from, to, rpc.GetBlocksConfig{Commitment: s.commitment})
 		cancel()
 
 		if err == nil {
@@ -226,6 +355,59 @@ func (s *SlotChecker) getBlocksWithRetry(from, to uint64, maxRetries int) ([]uin
 	}
 }
 
+func (s *SlotChecker) getBlockWithRetry(slot uint64, maxRetries int) (_ *rpc.GetBlock, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[SlotChecker] panic during getBlock: %v", r)
+			err = fmt.Errorf("getBlock panic: %v", r)
+		}
+	}()
+
+	var (
+		delay      = 300 * time.Millisecond
+		maxVersion = uint8(0)
+		rewards    = false
+		attempt    int
+	)
+
+	cfg := rpc.GetBlockConfig{
+		Encoding:                       rpc.GetBlockConfigEncodingBase64,
+		TransactionDetails:             rpc.GetBlockConfigTransactionDetailsFull,
+		Rewards:                        &rewards,
+		Commitment:                     s.commitment,
+		MaxSupportedTransactionVersion: &maxVersion,
+	}
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return nil, context.Canceled
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, 15*time.Second)
+		resp, err := s.client.GetBlockWithConfig(ctx, slot, cfg)
+		cancel()
+
+		if err == nil {
+			err = resp.GetError()
+		}
+		if err == nil && resp.Result == nil {
+			err = fmt.Errorf("empty result")
+		}
+		if err == nil {
+			return resp.Result, nil
+		}
+
+		attempt++
+		if attempt >= maxRetries {
+			return nil, err
+		}
+
+		time.Sleep(delay)
+	}
+}
+
 // mergeRanges 拆分并合并 SlotRange，使每段长度不超过 maxRangeSize，且尽可能合并相邻段。
 // 处理流程：
 // 1. 拆分：对于每个输入 SlotRange，按 maxRangeSize 拆成多个小段（每段长度 ≤ maxRangeSize）。
@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/rpc"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSlotChecker 创建连接本地假 RPC 的 SlotChecker：badSlots 中的 slot 返回含无法解析交易（缺少 meta）的区块
+func newTestSlotChecker(t *testing.T, badSlots map[uint64]bool) (*SlotChecker, chan *pb.SubscribeUpdateBlock) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+			Params []any  `json:"params"`
+		}
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) || !assert.Equal(t, "getBlock", req.Method) {
+			return
+		}
+		slot := uint64(req.Params[0].(float64))
+		txs := []any{}
+		if badSlots[slot] {
+			txs = append(txs, map[string]any{"transaction": []string{"", "base64"}, "meta": nil})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result": map[string]any{
+				"blockhash":         "11111111111111111111111111111111",
+				"previousBlockhash": "11111111111111111111111111111111",
+				"parentSlot":        slot - 1,
+				"blockTime":         1760000000,
+				"blockHeight":       slot,
+				"transactions":      txs,
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	t.Cleanup(func() { cancel(nil) })
+	blocks := make(chan *pb.SubscribeUpdateBlock, 2*maxBackfillPerRound)
+	processor := &BlockProcessor{rpcBlockChan: blocks, ctx: ctx, cancel: cancel}
+
+	checker := NewSlotChecker(server.URL, rpc.CommitmentConfirmed, processor)
+	t.Cleanup(checker.Stop)
+	return checker, blocks
+}
+
+func TestSlotChecker_BackfillSlotsCarriesOverflow(t *testing.T) {
+	checker, blocks := newTestSlotChecker(t, nil)
+
+	// 乱序且有重复，去重后共 maxBackfillPerRound + 2 个
+	slots := []uint64{1_000 + maxBackfillPerRound + 1, 1_000}
+	for slot := uint64(1_000); slot <= 1_000+maxBackfillPerRound+1; slot++ {
+		slots = append(slots, slot)
+	}
+
+	remaining := checker.backfillSlots(slots)
+	assert.Equal(t, []uint64{1_000 + maxBackfillPerRound, 1_000 + maxBackfillPerRound + 1}, remaining)
+	require.Len(t, blocks, maxBackfillPerRound)
+	first := <-blocks
+	assert.Equal(t, uint64(1_000), first.Slot)
+	assert.Equal(t, uint64(999), first.ParentSlot)
+
+	// 下一轮补上剩余部分
+	assert.Empty(t, checker.backfillSlots(remaining))
+	assert.Len(t, blocks, maxBackfillPerRound-1+2)
+}
+
+func TestSlotChecker_BackfillSlotsRetriesUnconvertibleBlock(t *testing.T) {
+	checker, blocks := newTestSlotChecker(t, map[uint64]bool{101: true})
+
+	// 含无法解析交易的区块不提交，留待下一轮重试，直到达到 maxBackfillAttempts
+	slots := []uint64{100, 101, 102}
+	for attempt := 1; attempt < maxBackfillAttempts; attempt++ {
+		slots = checker.backfillSlots(slots)
+		assert.Equal(t, []uint64{101}, slots, "attempt %d", attempt)
+		assert.Equal(t, attempt, checker.attempts[101])
+	}
+	assert.Empty(t, checker.backfillSlots(slots))
+	assert.NotContains(t, checker.attempts, uint64(101))
+
+	require.Len(t, blocks, 2)
+	assert.Equal(t, uint64(100), (<-blocks).Slot)
+	assert.Equal(t, uint64(102), (<-blocks).Slot)
+}
+
+func TestSlotChecker_BackfillSlotsWithoutProcessor(t *testing.T) {
+	checker := NewSlotChecker("http://127.0.0.1:0", rpc.CommitmentConfirmed, nil)
+	defer checker.Stop()
+	assert.Nil(t, checker.backfillSlots([]uint64{1, 2, 3}))
+}
+
+func TestSlotsToRanges(t *testing.T) {
+	assert.Nil(t, slotsToRanges(nil))
+	assert.Equal(t, []SlotRange{{From: 5, To: 5}}, slotsToRanges([]uint64{5}))
+	assert.Equal(t,
+		[]SlotRange{{From: 1, To: 3}, {From: 7, To: 7}, {From: 9, To: 10}},
+		slotsToRanges([]uint64{1, 2, 3, 7, 9, 10}))
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"dex-indexer-sol/internal/config"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	tlsModeVerify    = "verify"    // 校验服务端证书（系统 CA 或自定义 CA）
+	tlsModeInsecure  = "insecure"  // TLS 加密但不校验证书
+	tlsModePlaintext = "plaintext" // 不加密，仅用于本地/测试服务
+)
+
+// buildTransportCredentials 根据配置构造 gRPC 传输凭证
+func buildTransportCredentials(conf config.TLSConfig) (credentials.TransportCredentials, error) {
+	mode := strings.ToLower(conf.Mode)
+	switch mode {
+	case tlsModePlaintext:
+		return insecure.NewCredentials(), nil
+	case "", tlsModeVerify, tlsModeInsecure:
+	default:
+		return nil, fmt.Errorf("unknown tls mode %q", conf.Mode)
+	}
+
+	tlsConf := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         conf.ServerName,
+		InsecureSkipVerify: mode == tlsModeInsecure,
+	}
+
+	// 自定义 CA：只信任该 CA 签发的证书（证书固定）；未配置时 RootCAs 为 nil，使用系统 CA
+	if conf.CAFile != "" {
+		pem, err := os.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca file %s error: %w", conf.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificate in ca file %s", conf.CAFile)
+		}
+		tlsConf.RootCAs = pool
+	}
+
+	// mTLS 客户端证书
+	if conf.CertFile != "" || conf.KeyFile != "" {
+		if conf.CertFile == "" || conf.KeyFile == "" {
+			return nil, fmt.Errorf("mtls requires both cert_file and key_file")
+		}
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate error: %w", err)
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConf), nil
+}
@@ -0,0 +1,249 @@
+package grpc
+
+import (
+	"context"
+	"dex-indexer-sol/internal/logic/txadapter"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/svc"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/rpc"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	wsSourceName         = "websocket"
+	wsMaxPayloadBytes    = 256 << 20 // 单条 blockNotification 最大字节数（全量区块 base64 编码后可达上百 MB）
+	wsDefaultRecvTimeout = 30 * time.Second
+	wsSubscribeRequestID = 1
+	wsBlockNotification  = "blockNotification"
+	wsMentionAll         = "all"
+)
+
+// WsBlockSource 通过 Solana JSON-RPC websocket 的 blockSubscribe 订阅区块，作为 Yellowstone gRPC 不可用时的备用来源。
+// 收到的区块经 txadapter.ConvertRpcBlock 转换为 gRPC 区块格式后交给 BlockDeduper，与 gRPC 流共用去重、补块与处理流程。
+// 注意：blockSubscribe 需要节点开启 --rpc-pubsub-enable-block-subscription，mentionsAccountOrProgram 只支持单个地址，
+// 且过滤后的区块只含部分交易，只能在 websocket 模式下使用（failover 模式下会与 gRPC 全量区块冲突）。
+type WsBlockSource struct {
+	endpoint          string
+	mention           string
+	commitment        string
+	recvTimeout       time.Duration
+	reconnectInterval time.Duration
+	deduper           *BlockDeduper
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	stopped bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func NewWsBlockSource(sc *svc.GrpcServiceContext, deduper *BlockDeduper) (*WsBlockSource, error) {
+	grpcConf := sc.Config.Grpc
+	wsConf := grpcConf.Websocket
+	if wsConf.Endpoint == "" {
+		return nil, errors.New("websocket endpoint is empty")
+	}
+
+	mention := wsConf.Mention
+	if mention == "" {
+		mention = wsMentionAll
+	}
+	recvTimeout := time.Duration(wsConf.RecvTimeoutSec) * time.Second
+	if recvTimeout <= 0 {
+		recvTimeout = wsDefaultRecvTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WsBlockSource{
+		endpoint:          wsConf.Endpoint,
+		mention:           mention,
+		commitment:        wsCommitment(grpcConf.Commitment),
+		recvTimeout:       recvTimeout,
+		reconnectInterval: time.Duration(grpcConf.ReconnectIntervalSec) * time.Second,
+		deduper:           deduper,
+		ctx:               ctx,
+		cancel:            cancel,
+	}, nil
+}
+
+// Start 启动订阅协程后立即返回，断连后自动重连直到 Stop
+func (s *WsBlockSource) Start() {
+	logger.Infof("[WsBlockSource] 启动 blockSubscribe, endpoint=%s, mention=%s, commitment=%s", s.endpoint, s.mention, s.commitment)
+	go s.run()
+}
+
+func (s *WsBlockSource) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	s.cancel()
+	// 关闭连接以打断阻塞中的 Receive
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+func (s *WsBlockSource) run() {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(s.reconnectInterval):
+			}
+		}
+		err := s.subscribe()
+		if s.ctx.Err() != nil {
+			return
+		}
+		logger.Errorf("[WsBlockSource] reconnecting, subscribe error: %v", err)
+	}
+}
+
+// subscribe 建立一次连接并持续接收区块，连接出错时返回
+func (s *WsBlockSource) subscribe() error {
+	wsConf, err := websocket.NewConfig(s.endpoint, "http://localhost/")
+	if err != nil {
+		return err
+	}
+	dialCtx, cancel := context.WithTimeout(s.ctx, s.recvTimeout)
+	conn, err := wsConf.DialContext(dialCtx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("dial %s error: %w", s.endpoint, err)
+	}
+	conn.MaxPayloadBytes = wsMaxPayloadBytes
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		_ = conn.Close()
+		return context.Canceled
+	}
+	s.conn = conn
+	s.mu.Unlock()
+	defer s.closeConn(conn)
+
+	if err = websocket.JSON.Send(conn, s.subscribeRequest()); err != nil {
+		return fmt.Errorf("send blockSubscribe error: %w", err)
+	}
+
+	var count, totalLatency int64
+	for {
+		if err = conn.SetReadDeadline(time.Now().Add(s.recvTimeout)); err != nil {
+			return err
+		}
+		var data []byte
+		if err = websocket.Message.Receive(conn, &data); err != nil {
+			return fmt.Errorf("receive error: %w", err)
+		}
+
+		var msg wsMessage
+		if err = json.Unmarshal(data, &msg); err != nil {
+			logger.Warnf("[WsBlockSource] 消息解析失败: %v", err)
+			continue
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("rpc error %d: %s", msg.Error.Code, msg.Error.Message)
+		}
+		if msg.ID != nil {
+			logger.Infof("[WsBlockSource] 订阅成功, subscription=%s", string(msg.Result))
+			continue
+		}
+		if msg.Method != wsBlockNotification || msg.Params == nil {
+			continue
+		}
+
+		value := msg.Params.Result.Value
+		if value.Err != nil || value.Block == nil {
+			logger.Warnf("[WsBlockSource] slot %d 区块不可用: %v", value.Slot, value.Err)
+			continue
+		}
+		block, err := txadapter.ConvertRpcBlock(value.Slot, value.Block)
+		if err != nil {
+			// 不转发不完整的区块，由漏块检测通过 RPC 补块
+			logger.Errorf("[WsBlockSource] slot %d 区块转换失败: %v", value.Slot, err)
+			continue
+		}
+
+		latency := time.Now().UnixMilli() - block.BlockTime.GetTimestamp()*1000
+		totalLatency += latency
+		count++
+		logger.Infof("[WsBlockSource] slot = %d, latency = %d ms, avg = %d ms (count = %d)", block.Slot, latency, totalLatency/count, count)
+
+		s.deduper.Forward(wsSourceName, block)
+	}
+}
+
+func (s *WsBlockSource) closeConn(conn *websocket.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == conn {
+		_ = conn.Close()
+		s.conn = nil
+	}
+}
+
+func (s *WsBlockSource) subscribeRequest() map[string]any {
+	var filter any = wsMentionAll
+	if s.mention != wsMentionAll {
+		filter = map[string]string{"mentionsAccountOrProgram": s.mention}
+	}
+	return map[string]any{
+		"jsonrpc": "2.0",
+		"id":      wsSubscribeRequestID,
+		"method":  "blockSubscribe",
+		"params": []any{
+			filter,
+			map[string]any{
+				"commitment":                     s.commitment,
+				"encoding":                       "base64",
+				"transactionDetails":             "full",
+				"maxSupportedTransactionVersion": 0,
+				"showRewards":                    false,
+			},
+		},
+	}
+}
+
+// wsMentionFiltered 判断是否配置了 mentionsAccountOrProgram 过滤（此时推送的区块只含部分交易）
+func wsMentionFiltered(mention string) bool {
+	return mention != "" && mention != wsMentionAll
+}
+
+// wsCommitment blockSubscribe 不支持 processed，统一降级为 confirmed
+func wsCommitment(s string) string {
+	if parseCommitment(s) == pb.CommitmentLevel_FINALIZED {
+		return "finalized"
+	}
+	return "confirmed"
+}
+
+// wsMessage websocket 消息：订阅响应（id + result/error）或区块通知（method + params）
+type wsMessage struct {
+	ID     *int            `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Method string `json:"method"`
+	Params *struct {
+		Result struct {
+			Value struct {
+				Slot  uint64        `json:"slot"`
+				Block *rpc.GetBlock `json:"block"`
+				Err   any           `json:"err"`
+			} `json:"value"`
+		} `json:"result"`
+	} `json:"params"`
+}
@@ -0,0 +1,55 @@
+package jobbuilder
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/mq"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/pb"
+)
+
+// FinalizedInfo 描述一个已 finalized 的 slot
+type FinalizedInfo struct {
+	Slot       uint64
+	BlockHash  types.Hash
+	ParentSlot uint64
+	BlockTime  int64
+}
+
+// BuildFinalizedKafkaJobs 构造 slot finalized 标记消息，广播到 topic 的每个分区。
+func BuildFinalizedKafkaJobs(
+	info *FinalizedInfo,
+	source int32,
+	topic string,
+	partitions int,
+) []*mq.KafkaJob {
+	if partitions <= 0 {
+		partitions = 1
+	}
+
+	jobs := make([]*mq.KafkaJob, 0, partitions)
+	for pid := 0; pid < partitions; pid++ {
+		jobs = append(jobs, &mq.KafkaJob{
+			Topic:     topic,
+			Partition: int32(pid),
+			Msg: &pb.Events{
+				Version: 1,
+				ChainId: consts.ChainIDSolana,
+				Slot:    info.Slot,
+				Source:  source,
+				Events: []*pb.Event{{
+					Event: &pb.Event_Finalized{
+						Finalized: &pb.SlotFinalizedEvent{
+							Type:       pb.EventType_SLOT_FINALIZED,
+							Slot:       info.Slot,
+							BlockHash:  info.BlockHash[:],
+							ParentSlot: info.ParentSlot,
+							BlockTime:  info.BlockTime,
+						},
+					},
+				}},
+				BlockHash: info.BlockHash[:],
+			},
+		})
+	}
+	return jobs
+}
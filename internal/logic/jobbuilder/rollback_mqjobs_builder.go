@@ -0,0 +1,60 @@
+package jobbuilder
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/mq"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/pb"
+)
+
+// RollbackInfo 描述一个需要回滚的 slot
+type RollbackInfo struct {
+	Slot          uint64
+	BlockHash     types.Hash
+	ParentSlot    uint64
+	BlockTime     int64
+	CanonicalSlot uint64
+	Reason        pb.RollbackReason
+}
+
+// BuildRollbackKafkaJobs 构造 slot 回滚消息。
+// 同一 slot 的事件按 Key 分散在各个分区，因此回滚消息需要广播到 topic 的每个分区。
+func BuildRollbackKafkaJobs(
+	info *RollbackInfo,
+	source int32,
+	topic string,
+	partitions int,
+) []*mq.KafkaJob {
+	if partitions <= 0 {
+		partitions = 1
+	}
+
+	jobs := make([]*mq.KafkaJob, 0, partitions)
+	for pid := 0; pid < partitions; pid++ {
+		jobs = append(jobs, &mq.KafkaJob{
+			Topic:     topic,
+			Partition: int32(pid),
+			Msg: &pb.Events{
+				Version: 1,
+				ChainId: consts.ChainIDSolana,
+				Slot:    info.Slot,
+				Source:  source,
+				Events: []*pb.Event{{
+					Event: &pb.Event_Rollback{
+						Rollback: &pb.SlotRollbackEvent{
+							Type:          pb.EventType_SLOT_ROLLBACK,
+							Slot:          info.Slot,
+							BlockHash:     info.BlockHash[:],
+							ParentSlot:    info.ParentSlot,
+							BlockTime:     info.BlockTime,
+							CanonicalSlot: info.CanonicalSlot,
+							Reason:        info.Reason,
+						},
+					},
+				}},
+				BlockHash: info.BlockHash[:],
+			},
+		})
+	}
+	return jobs
+}
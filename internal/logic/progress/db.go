@@ -32,6 +32,20 @@ func (d *DBProgressStore) CheckSlotExists(ctx context.Context, slot uint64) (boo
 	return true, nil
 }
 
+// CheckSlotProcessed 判定某 slot 是否已在 DB 中标记为处理成功
+func (d *DBProgressStore) CheckSlotProcessed(ctx context.Context, slot uint64) (bool, error) {
+	query := `SELECT 1 FROM progress_slot WHERE slot = $1 AND status = $2`
+	var dummy int
+	err := d.db.QueryRowContext(ctx, query, slot, SlotProcessed).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check slot processed error: %w", err)
+	}
+	return true, nil
+}
+
 // InsertOrUpdateSlot 插入或更新单个 slot 的处理状态
 func (d *DBProgressStore) InsertOrUpdateSlot(ctx context.Context, slot *SlotRecord) error {
 	query := `
@@ -5,12 +5,14 @@ import (
 	"time"
 )
 
+// ProgressManager 管理 slot 处理进度：Redis 负责高频判重，DB 持久化全部历史进度（可选，未配置时只使用 Redis）
 type ProgressManager struct {
 	redis           *RedisProgressStore
-	db              *DBProgressStore
+	db              *DBProgressStore // 可为 nil
 	recentThreshold time.Duration
 }
 
+// NewProgressManager 创建进度管理器，db 为 nil 时只使用 Redis（判重范围受 Redis 记录的 TTL 限制）
 func NewProgressManager(redis *RedisProgressStore, db *DBProgressStore, recentThresholdSec int) *ProgressManager {
 	return &ProgressManager{
 		redis:           redis,
@@ -20,33 +22,43 @@ func NewProgressManager(redis *RedisProgressStore, db *DBProgressStore, recentTh
 }
 
 // ShouldProcessSlot 判断是否需要处理该 slot：
-// - 近期 block 永远处理（不判重）
-// - 否则查 Redis + fallback DB 判重
+//   - 近期 block 永远处理（不判重）
+//   - 否则查 Redis + fallback DB，只跳过已处理成功的 slot；
+//     查不到记录的 slot（RPC 补块、断线续传、溢出队列、离线回放等迟到区块）照常处理
 func (pm *ProgressManager) ShouldProcessSlot(ctx context.Context, slot uint64, blockTime int64) (bool, error) {
 	if time.Since(time.Unix(blockTime, 0)) <= pm.recentThreshold {
 		return true, nil
 	}
+	processed, err := pm.IsSlotProcessed(ctx, slot)
+	if err != nil {
+		return true, err // 判重失败时宁可重复处理，也不丢数据
+	}
+	return !processed, nil
+}
 
+// IsSlotProcessed 判断 slot 是否已处理成功（先查 Redis，未命中时 fallback DB 并回写 Redis）
+func (pm *ProgressManager) IsSlotProcessed(ctx context.Context, slot uint64) (bool, error) {
 	// Step 1: Redis
 	status, err := pm.redis.GetSlotStatus(ctx, slot)
 	if err != nil {
 		return false, err
 	}
-	if status == SlotProcessed || status == SlotInvalid {
-		return false, nil
+	if status == SlotProcessed {
+		return true, nil
 	}
 
 	// Step 2: fallback DB
-	exists, err := pm.db.CheckSlotExists(ctx, slot)
+	if pm.db == nil {
+		return false, nil
+	}
+	processed, err := pm.db.CheckSlotProcessed(ctx, slot)
 	if err != nil {
 		return false, err
 	}
-	if exists {
+	if processed {
 		_ = pm.redis.MarkSlotProcessed(ctx, slot)
-		return false, nil
 	}
-	_ = pm.redis.MarkSlotInvalid(ctx, slot)
-	return false, nil
+	return processed, nil
 }
 
 // MarkSlotStatus 持久化 slot 状态（支持 SlotProcessed / SlotInvalid）
@@ -62,14 +74,16 @@ func (pm *ProgressManager) MarkSlotStatus(
 	}
 
 	// Step 1: 落库 DB（Insert or Update）
-	err := pm.db.InsertOrUpdateSlot(ctx, &SlotRecord{
-		Slot:      slot,
-		Source:    source,
-		BlockTime: blockTime,
-		Status:    status,
-	})
-	if err != nil {
-		return err
+	if pm.db != nil {
+		err := pm.db.InsertOrUpdateSlot(ctx, &SlotRecord{
+			Slot:      slot,
+			Source:    source,
+			BlockTime: blockTime,
+			Status:    status,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	// Step 2: Redis 标记状态（幂等）
@@ -85,6 +99,9 @@ func (pm *ProgressManager) MarkSlotStatus(
 
 // StartGCLoop 启动后台 GC 清理历史进度（单表清理）
 func (pm *ProgressManager) StartGCLoop(ctx context.Context, interval time.Duration) {
+	if pm.db == nil {
+		return
+	}
 	ticker := time.NewTicker(interval)
 	go func() {
 		defer ticker.Stop()
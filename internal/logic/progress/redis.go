@@ -16,11 +16,15 @@ const (
 // RedisProgressStore 管理 Redis 中的 slot 状态记录（幂等控制）
 type RedisProgressStore struct {
 	rdb *redis.Client
+	ttl time.Duration
 }
 
-// NewRedisProgressStore 创建 Redis 判重管理器
-func NewRedisProgressStore(rdb *redis.Client) *RedisProgressStore {
-	return &RedisProgressStore{rdb: rdb}
+// NewRedisProgressStore 创建 Redis 判重管理器，ttl <= 0 时使用默认的 24 小时
+func NewRedisProgressStore(rdb *redis.Client, ttl time.Duration) *RedisProgressStore {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &RedisProgressStore{rdb: rdb, ttl: ttl}
 }
 
 func (r *RedisProgressStore) getKey(slot uint64) string {
@@ -48,7 +52,7 @@ func (r *RedisProgressStore) GetSlotStatus(ctx context.Context, slot uint64) (Sl
 
 // MarkSlotStatus 设置 slot 的处理状态
 func (r *RedisProgressStore) MarkSlotStatus(ctx context.Context, slot uint64, status SlotStatus) error {
-	return r.rdb.Set(ctx, r.getKey(slot), status, defaultTTL).Err()
+	return r.rdb.Set(ctx, r.getKey(slot), status, r.ttl).Err()
 }
 
 func (r *RedisProgressStore) MarkSlotProcessed(ctx context.Context, slot uint64) error {
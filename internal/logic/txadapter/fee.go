@@ -0,0 +1,93 @@
+package txadapter
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"encoding/binary"
+	"math"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+)
+
+// 来源：https://github.com/anza-xyz/agave/blob/master/sdk/compute-budget-interface/src/lib.rs
+const (
+	computeBudgetSetUnitLimit = 2 // SetComputeUnitLimit(u32)
+	computeBudgetSetUnitPrice = 3 // SetComputeUnitPrice(u64, micro-lamports)
+
+	defaultInstructionCULimit = 200_000   // 未设置 CU 上限时，每条非 ComputeBudget 指令的默认额度
+	maxComputeUnitLimit       = 1_400_000 // 单笔交易 CU 上限
+	microLamportsPerLamport   = 1_000_000
+	lamportsPerSignature      = 5000
+
+	systemTransfer = 2 // System Program Transfer 指令序号（u32）
+)
+
+// fillFeeInfo 从 Meta 与 ComputeBudget / System Transfer 指令中解析手续费、优先费、CU 与 Jito 小费信息。
+func fillFeeInfo(adapted *core.AdaptedTx, meta *pb.TransactionStatusMeta) {
+	adapted.Fee = meta.Fee
+	if meta.ComputeUnitsConsumed != nil {
+		adapted.ComputeUnitsConsumed = *meta.ComputeUnitsConsumed
+	}
+
+	var (
+		limitSet     bool
+		otherIxCount uint64
+	)
+	for _, ix := range adapted.Instructions {
+		// Jito 小费：主指令或 CPI 中的 System Transfer，转入账户为 tip 账户
+		if ix.ProgramID == consts.SystemProgram {
+			if len(ix.Data) >= 12 && len(ix.Accounts) >= 2 && binary.LittleEndian.Uint32(ix.Data[:4]) == systemTransfer {
+				if _, ok := consts.JitoTipAccounts[ix.Accounts[1]]; ok {
+					adapted.JitoTip += binary.LittleEndian.Uint64(ix.Data[4:12])
+				}
+			}
+		}
+
+		// ComputeBudget 只在主指令中生效
+		if ix.InnerIndex != 0 {
+			continue
+		}
+		if ix.ProgramID != consts.ComputeBudgetProgram {
+			otherIxCount++
+			continue
+		}
+		if len(ix.Data) == 0 {
+			continue
+		}
+		switch ix.Data[0] {
+		case computeBudgetSetUnitLimit:
+			if len(ix.Data) >= 5 {
+				adapted.ComputeUnitLimit = binary.LittleEndian.Uint32(ix.Data[1:5])
+				limitSet = true
+			}
+		case computeBudgetSetUnitPrice:
+			if len(ix.Data) >= 9 {
+				adapted.ComputeUnitPrice = binary.LittleEndian.Uint64(ix.Data[1:9])
+			}
+		}
+	}
+
+	if !limitSet {
+		adapted.ComputeUnitLimit = uint32(min(otherIxCount*defaultInstructionCULimit, maxComputeUnitLimit))
+	}
+	adapted.ComputeUnitLimit = min(adapted.ComputeUnitLimit, maxComputeUnitLimit)
+
+	// 优先费 = ceil(price × limit / 1e6)，按申请的 CU 上限收取而非实际消耗
+	priorityFee := uint64(math.MaxUint64)
+	if adapted.ComputeUnitPrice <= (math.MaxUint64-microLamportsPerLamport)/maxComputeUnitLimit { // 防止溢出
+		priorityFee = (adapted.ComputeUnitPrice*uint64(adapted.ComputeUnitLimit) + microLamportsPerLamport - 1) / microLamportsPerLamport
+	}
+	if priorityFee <= adapted.Fee {
+		adapted.PriorityFee = priorityFee
+		adapted.BaseFee = adapted.Fee - priorityFee
+	} else {
+		// 与 Meta.Fee 不一致（如运行时默认 CU 规则变化），按每个签名的基础费用拆分
+		adapted.BaseFee = min(uint64(len(adapted.Signers))*lamportsPerSignature, adapted.Fee)
+		adapted.PriorityFee = adapted.Fee - adapted.BaseFee
+	}
+
+	// 失败交易的转账已回滚
+	if adapted.Err != nil {
+		adapted.JitoTip = 0
+	}
+}
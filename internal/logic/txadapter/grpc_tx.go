@@ -57,15 +57,19 @@ func buildSolBalances(
 	postList := tx.Meta.PostBalances // 交易执行后余额列表
 
 	balanceMap := make(map[types.Pubkey]*core.SolBalance, len(preList)+len(postList))
+	accessOf := newAccountAccess(tx)
 
 	for i, preBalance := range preList {
 		account := accountKeys[i]
+		signer, writable := accessOf(i)
 		balanceMap[account] = &core.SolBalance{
 			Account:     account,
 			PreBalance:  preBalance,
 			PostBalance: 0,
 			TxIndex:     uint16(tx.Index),
 			InnerIndex:  uint16(i),
+			Signer:      signer,
+			Writable:    writable,
 		}
 	}
 
@@ -76,12 +80,15 @@ func buildSolBalances(
 		if tb, ok := balanceMap[account]; ok {
 			tb.PostBalance = postBalance
 		} else {
+			signer, writable := accessOf(i)
 			balanceMap[account] = &core.SolBalance{
 				Account:     account,
 				PreBalance:  0,
 				PostBalance: postBalance,
 				TxIndex:     uint16(tx.Index),
 				InnerIndex:  uint16(newIndex),
+				Signer:      signer,
+				Writable:    writable,
 			}
 			newIndex++
 		}
@@ -90,6 +97,31 @@ func buildSolBalances(
 	return balanceMap
 }
 
+// newAccountAccess 根据 message header 返回判断账户（完整账户列表下标）是否为 signer / 可写的函数。
+// 账户顺序：[可写 signer, 只读 signer, 可写非 signer, 只读非 signer] + ALT 可写 + ALT 只读。
+func newAccountAccess(tx *pb.SubscribeUpdateTransactionInfo) func(i int) (signer, writable bool) {
+	header := tx.Transaction.Message.Header
+	if header == nil {
+		return func(int) (bool, bool) { return false, false }
+	}
+	numSigners := int(header.NumRequiredSignatures)
+	numWritableSigners := numSigners - int(header.NumReadonlySignedAccounts)
+	numStatic := len(tx.Transaction.Message.AccountKeys)
+	numWritableStatic := numStatic - int(header.NumReadonlyUnsignedAccounts)
+	numWritable := numStatic + len(tx.Meta.LoadedWritableAddresses)
+
+	return func(i int) (bool, bool) {
+		switch {
+		case i < numSigners:
+			return true, i < numWritableSigners
+		case i < numStatic:
+			return false, i < numWritableStatic
+		default:
+			return false, i < numWritable
+		}
+	}
+}
+
 // buildBalances 构建交易中的 Token 余额变化及 mint → decimals 映射（含去重与所有权信息）。
 // 返回：
 //   - balanceMap：token account → TokenBalance（含 mint、owner、pre/post 余额等）
@@ -123,13 +155,14 @@ func buildBalances(
 
 		account := accountKeys[post.AccountIndex]
 		decimals := uint8(post.UiTokenAmount.Decimals)
+		tokenProgramID := tools.ToTokenPubkey(post.ProgramId)
 		balanceMap[account] = &core.TokenBalance{
 			TokenAccount:   account,
-			Token:          mintResolver.resolve(post.Mint, decimals),
+			Token:          mintResolver.resolve(post.Mint, decimals, tokenProgramID),
 			PostBalance:    utils.ParseUint64(post.UiTokenAmount.Amount),
 			PostOwner:      ownerResolver.resolve(post.Owner),
 			Decimals:       decimals,
-			TokenProgramID: tools.ToTokenPubkey(post.ProgramId),
+			TokenProgramID: tokenProgramID,
 			TxIndex:        uint16(tx.Index),
 			InnerIndex:     uint16(i),
 		}
@@ -153,15 +186,16 @@ func buildBalances(
 			// Pre-only（如销毁账户），构造最小结构
 			decimals := uint8(pre.UiTokenAmount.Decimals)
 			owner := ownerResolver.resolve(pre.Owner)
+			tokenProgramID := tools.ToTokenPubkey(pre.ProgramId)
 			balanceMap[account] = &core.TokenBalance{
 				TokenAccount:   account,
-				Token:          mintResolver.resolve(pre.Mint, decimals),
+				Token:          mintResolver.resolve(pre.Mint, decimals, tokenProgramID),
 				HasPreOwner:    true,
 				PreOwner:       owner,
 				PostOwner:      owner, // Pre-only 情况默认设置 PostOwner = PreOwner
 				PreBalance:     utils.ParseUint64(pre.UiTokenAmount.Amount),
 				Decimals:       decimals,
-				TokenProgramID: tools.ToTokenPubkey(pre.ProgramId),
+				TokenProgramID: tokenProgramID,
 				TxIndex:        uint16(tx.Index),
 				InnerIndex:     uint16(newIndex),
 			}
@@ -184,35 +218,53 @@ func buildInstructions(
 	instructions := make([]*core.AdaptedInstruction, 0, max(len(rawInstructions)*2, 32))
 	innerIndex := 0
 
+	// parents[h] 记录当前主指令下最近一条栈深度为 h 的指令下标，用于确定 inner 指令的父指令
+	var parents []int
+
 	for i, inst := range rawInstructions {
-		// 解析主指令，标记 InnerIndex = 0
+		// 解析主指令，标记 InnerIndex = 0，栈深度为 1
 		accounts := make([]types.Pubkey, 0, len(inst.Accounts))
 		for _, idx := range inst.Accounts {
 			accounts = append(accounts, accountKeys[idx])
 		}
+		mainIndex := len(instructions)
 		instructions = append(instructions, &core.AdaptedInstruction{
-			IxIndex:    uint16(i),
-			InnerIndex: 0,
-			ProgramID:  accountKeys[inst.ProgramIdIndex],
-			Accounts:   accounts,
-			Data:       inst.Data,
+			IxIndex:     uint16(i),
+			InnerIndex:  0,
+			StackHeight: 1,
+			ParentIndex: -1,
+			ProgramID:   accountKeys[inst.ProgramIdIndex],
+			Accounts:    accounts,
+			Data:        inst.Data,
 		})
 
 		// 解析 inner 指令（如存在），InnerIndex 从1开始递增
 		// 注意：Solana 标准中，每个主指令最多对应一个 inner 指令块，
 		// 且 inner 列表按主指令索引（Index）递增排列，因此此处采用顺序匹配，无需 map 或多次扫描。
 		if innerIndex < len(rawInners) && int(rawInners[innerIndex].Index) == i {
+			parents = append(parents[:0], -1, mainIndex) // 下标即栈深度：parents[1] 为主指令
 			for j, inner := range rawInners[innerIndex].Instructions {
 				innerAccounts := make([]types.Pubkey, 0, len(inner.Accounts))
 				for _, idx := range inner.Accounts {
 					innerAccounts = append(innerAccounts, accountKeys[idx])
 				}
+
+				// stack_height 为空（旧版节点）时无法还原调用树，父指令统一视为主指令
+				stackHeight, parentIndex := 0, mainIndex
+				if inner.StackHeight != nil && *inner.StackHeight >= 2 && int(*inner.StackHeight) <= len(parents) {
+					stackHeight = int(*inner.StackHeight)
+					parentIndex = parents[stackHeight-1]
+					parents = append(parents[:stackHeight], len(instructions))
+				}
+
 				instructions = append(instructions, &core.AdaptedInstruction{
-					IxIndex:    uint16(i),
-					InnerIndex: uint16(j + 1), // InnerIndex从1开始递增
-					ProgramID:  accountKeys[inner.ProgramIdIndex],
-					Accounts:   innerAccounts,
-					Data:       inner.Data,
+					IxIndex:     uint16(i),
+					InnerIndex:  uint16(j + 1), // InnerIndex从1开始递增
+					StackHeight: uint8(stackHeight),
+					ParentIndex: parentIndex,
+					ProgramID:   accountKeys[inner.ProgramIdIndex],
+					Accounts:    innerAccounts,
+					Data:        inner.Data,
 				})
 			}
 			innerIndex++
@@ -275,14 +327,23 @@ func AdaptGrpcTx(
 	}
 
 	// 组装最终结构体
-	return &core.AdaptedTx{
+	adapted := &core.AdaptedTx{
 		TxCtx:         txCtx,
 		TxIndex:       uint32(tx.Index),
 		Signature:     tx.Transaction.Signatures[0],
 		Signers:       signers,
 		Instructions:  instructions,
+		LogMessages:   tx.Meta.LogMessages,
 		SolBalances:   buildSolBalances(tx, accountKeys),
 		Balances:      balances,
 		TokenDecimals: tokenDecimals,
-	}, nil
+		Err:           decodeTxError(tx.Meta.Err),
+	}
+
+	// 解析手续费、优先费、CU 与 Jito 小费
+	fillFeeInfo(adapted, tx.Meta)
+
+	// 标记执行后属于 System Program 的账户，用于原生 SOL 余额推送
+	markSystemOwned(adapted)
+	return adapted, nil
 }
@@ -0,0 +1,62 @@
+package txadapter
+
+import (
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/internal/pkg/types"
+	"testing"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func innerIx(program uint32, stackHeight uint32) *pb.InnerInstruction {
+	ix := &pb.InnerInstruction{ProgramIdIndex: program}
+	if stackHeight > 0 {
+		ix.StackHeight = &stackHeight
+	}
+	return ix
+}
+
+func TestBuildInstructions_CallTree(t *testing.T) {
+	keys := []types.Pubkey{testfixture.Key("tree:router"), testfixture.Key("tree:amm"), testfixture.Key("tree:token")}
+	tx := &pb.SubscribeUpdateTransactionInfo{
+		Transaction: &pb.Transaction{Message: &pb.Message{Instructions: []*pb.CompiledInstruction{
+			{ProgramIdIndex: 0}, {ProgramIdIndex: 2}, {ProgramIdIndex: 0},
+		}}},
+		Meta: &pb.TransactionStatusMeta{InnerInstructions: []*pb.InnerInstructions{
+			// ix0: router → amm → (token, token → hook)，router → token
+			{Index: 0, Instructions: []*pb.InnerInstruction{
+				innerIx(1, 2), innerIx(2, 3), innerIx(2, 3), innerIx(0, 4), innerIx(2, 2),
+			}},
+			// ix1 没有 inner 指令；ix2 的 stack_height 缺失或越级时父指令视为主指令
+			{Index: 2, Instructions: []*pb.InnerInstruction{innerIx(1, 0), innerIx(2, 5)}},
+		}},
+	}
+
+	instrs := buildInstructions(tx, keys)
+	require.Len(t, instrs, 10)
+
+	type node struct {
+		ixIndex, innerIndex uint16
+		stackHeight         uint8
+		parent              int
+	}
+	want := []node{
+		{0, 0, 1, -1},
+		{0, 1, 2, 0},
+		{0, 2, 3, 1},
+		{0, 3, 3, 1},
+		{0, 4, 4, 3},
+		{0, 5, 2, 0},
+		{1, 0, 1, -1},
+		{2, 0, 1, -1},
+		{2, 1, 0, 7},
+		{2, 2, 0, 7},
+	}
+	for i, w := range want {
+		got := node{instrs[i].IxIndex, instrs[i].InnerIndex, instrs[i].StackHeight, instrs[i].ParentIndex}
+		assert.Equal(t, w, got, "instrs[%d]", i)
+	}
+	assert.Equal(t, keys[0], instrs[4].ProgramID)
+}
@@ -1,6 +1,7 @@
 package txadapter
 
 import (
+	"dex-indexer-sol/internal/cache"
 	"dex-indexer-sol/internal/consts"
 	"dex-indexer-sol/internal/logic/core"
 	"dex-indexer-sol/internal/pkg/types"
@@ -25,8 +26,8 @@ func newMintResolver(capacity int) *mintResolver {
 }
 
 // resolve 返回指定 mintStr 对应的 Pubkey。
-// 若缓存命中则直接返回，否则进行 base58 解码并缓存后返回。
-func (r *mintResolver) resolve(mintStr string, decimals uint8) types.Pubkey {
+// 若缓存命中则直接返回，否则进行 base58 解码并缓存后返回，同时将 decimals 与 token program 记录到全局 mint 注册表。
+func (r *mintResolver) resolve(mintStr string, decimals uint8, tokenProgram types.Pubkey) types.Pubkey {
 	switch mintStr {
 	case consts.WSOLMintStr:
 		return consts.WSOLMint
@@ -42,6 +43,7 @@ func (r *mintResolver) resolve(mintStr string, decimals uint8) types.Pubkey {
 	}
 	pk := types.PubkeyFromBase58(mintStr)
 	r.cache = append(r.cache, mintKV{base58: mintStr, pubkey: pk, decimals: decimals})
+	cache.Mints().ObserveDecimals(pk, decimals, tokenProgram)
 	return pk
 }
 
@@ -0,0 +1,427 @@
+package txadapter
+
+import (
+	"bytes"
+	"dex-indexer-sol/internal/consts"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/rpc"
+	sdktypes "github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+)
+
+// rpcJsonTransaction 对应 json 编码下 getBlock 返回的 transaction 结构
+type rpcJsonTransaction struct {
+	Signatures []string `json:"signatures"`
+	Message    struct {
+		AccountKeys []string `json:"accountKeys"`
+		Header      struct {
+			NumRequiredSignatures       uint32 `json:"numRequiredSignatures"`
+			NumReadonlySignedAccounts   uint32 `json:"numReadonlySignedAccounts"`
+			NumReadonlyUnsignedAccounts uint32 `json:"numReadonlyUnsignedAccounts"`
+		} `json:"header"`
+		RecentBlockhash string `json:"recentBlockhash"`
+		Instructions    []struct {
+			ProgramIDIndex uint32 `json:"programIdIndex"`
+			Accounts       []int  `json:"accounts"`
+			Data           string `json:"data"` // base58
+		} `json:"instructions"`
+		AddressTableLookups []struct {
+			AccountKey      string `json:"accountKey"`
+			WritableIndexes []int  `json:"writableIndexes"`
+			ReadonlyIndexes []int  `json:"readonlyIndexes"`
+		} `json:"addressTableLookups"`
+	} `json:"message"`
+}
+
+// rpcInnerInstruction 对应 getBlock 返回的 meta.innerInstructions[].instructions[] 结构。
+// 注意：即使交易以 base64 编码返回，inner 指令仍是 json 结构，data 为 base58 编码。
+type rpcInnerInstruction struct {
+	ProgramIDIndex uint32  `json:"programIdIndex"`
+	Accounts       []int   `json:"accounts"`
+	Data           string  `json:"data"`
+	StackHeight    *uint32 `json:"stackHeight"`
+}
+
+// ConvertRpcBlock 将 RPC getBlock（encoding=base64/base58/json, transactionDetails=full）的结果
+// 转换为 Yellowstone 的 SubscribeUpdateBlock 结构，使 RPC 补块可以复用 gRPC 的完整处理流程。
+// 任一交易无法解析时返回错误：缺少交易的区块一旦分发并写入进度，该交易的事件将永久丢失，应由调用方重试或记为失败。
+func ConvertRpcBlock(slot uint64, block *rpc.GetBlock) (*pb.SubscribeUpdateBlock, error) {
+	if block == nil {
+		return nil, fmt.Errorf("slot %d: empty block", slot)
+	}
+
+	result := &pb.SubscribeUpdateBlock{
+		Slot:            slot,
+		Blockhash:       block.Blockhash,
+		ParentSlot:      block.ParentSlot,
+		ParentBlockhash: block.PreviousBlockhash,
+		BlockTime:       &pb.UnixTimestamp{},
+		Transactions:    make([]*pb.SubscribeUpdateTransactionInfo, 0, len(block.Transactions)),
+	}
+	if block.BlockTime != nil {
+		result.BlockTime.Timestamp = *block.BlockTime
+	}
+	if block.BlockHeight != nil {
+		result.BlockHeight = &pb.BlockHeight{BlockHeight: uint64(*block.BlockHeight)}
+	}
+
+	for i := range block.Transactions {
+		tx, err := convertRpcTx(&block.Transactions[i], uint64(i))
+		if err != nil {
+			return nil, fmt.Errorf("slot %d: tx %d: %w", slot, i, err)
+		}
+		result.Transactions = append(result.Transactions, tx)
+	}
+	result.ExecutedTransactionCount = uint64(len(block.Transactions))
+	return result, nil
+}
+
+// convertRpcTx 转换单笔交易，index 为交易在区块中的位置（与 gRPC 的 Index 语义一致）。
+// 支持 base64 / base58 / json 三种 encoding（jsonParsed 不支持）。
+func convertRpcTx(rawTx *rpc.GetBlockTransaction, index uint64) (*pb.SubscribeUpdateTransactionInfo, error) {
+	if rawTx.Meta == nil {
+		return nil, fmt.Errorf("missing meta")
+	}
+
+	var (
+		signatures [][]byte
+		message    *pb.Message
+		err        error
+	)
+	switch raw := rawTx.Transaction.(type) {
+	case []any:
+		// base64 / base58 编码下，transaction 字段形如 ["<data>", "base64"]
+		signatures, message, err = decodeEncodedTransaction(raw)
+	case map[string]any:
+		// json 编码下，transaction 字段为 {signatures, message}
+		signatures, message, err = decodeJsonTransaction(raw)
+		if err == nil {
+			message.Versioned = isVersionedTx(rawTx.Version)
+		}
+	default:
+		err = fmt.Errorf("unexpected transaction encoding: %T", rawTx.Transaction)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("missing signature")
+	}
+
+	meta, err := convertRpcMeta(rawTx.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SubscribeUpdateTransactionInfo{
+		Signature: signatures[0],
+		IsVote:    isVoteMessage(message),
+		Transaction: &pb.Transaction{
+			Signatures: signatures,
+			Message:    message,
+		},
+		Meta:  meta,
+		Index: index,
+	}, nil
+}
+
+// decodeEncodedTransaction 解析 base64 / base58 编码的交易（wire format）
+func decodeEncodedTransaction(encoded []any) ([][]byte, *pb.Message, error) {
+	if len(encoded) == 0 {
+		return nil, nil, fmt.Errorf("unexpected transaction payload")
+	}
+	data, ok := encoded[0].(string)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected transaction payload")
+	}
+	encoding := "base64"
+	if len(encoded) > 1 {
+		if e, ok := encoded[1].(string); ok {
+			encoding = e
+		}
+	}
+
+	var (
+		raw []byte
+		err error
+	)
+	switch encoding {
+	case "base64":
+		raw, err = base64.StdEncoding.DecodeString(data)
+	case "base58":
+		raw, err = base58.Decode(data)
+	default:
+		return nil, nil, fmt.Errorf("unsupported transaction encoding: %s", encoding)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode %s error: %w", encoding, err)
+	}
+
+	decoded, err := sdktypes.TransactionDeserialize(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deserialize transaction error: %w", err)
+	}
+	message, err := convertRpcMessage(&decoded.Message)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signatures := make([][]byte, len(decoded.Signatures))
+	for i, sig := range decoded.Signatures {
+		signatures[i] = sig
+	}
+	return signatures, message, nil
+}
+
+// decodeJsonTransaction 解析 json 编码的交易（账户与签名为 base58，指令 data 为 base58）
+func decodeJsonTransaction(raw map[string]any) ([][]byte, *pb.Message, error) {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal transaction error: %w", err)
+	}
+	var tx rpcJsonTransaction
+	if err = json.Unmarshal(b, &tx); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal transaction error: %w", err)
+	}
+
+	signatures, err := decodeBase58List(tx.Signatures)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode signatures error: %w", err)
+	}
+	accountKeys, err := decodeBase58List(tx.Message.AccountKeys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode accountKeys error: %w", err)
+	}
+	recentBlockhash, err := base58.Decode(tx.Message.RecentBlockhash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode recent blockhash error: %w", err)
+	}
+
+	instructions := make([]*pb.CompiledInstruction, len(tx.Message.Instructions))
+	for i, inst := range tx.Message.Instructions {
+		data, err := base58.Decode(inst.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode instruction data error: %w", err)
+		}
+		instructions[i] = &pb.CompiledInstruction{
+			ProgramIdIndex: inst.ProgramIDIndex,
+			Accounts:       intsToBytes(inst.Accounts),
+			Data:           data,
+		}
+	}
+
+	lookups := make([]*pb.MessageAddressTableLookup, len(tx.Message.AddressTableLookups))
+	for i, table := range tx.Message.AddressTableLookups {
+		accountKey, err := base58.Decode(table.AccountKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode lookup table key error: %w", err)
+		}
+		lookups[i] = &pb.MessageAddressTableLookup{
+			AccountKey:      accountKey,
+			WritableIndexes: intsToBytes(table.WritableIndexes),
+			ReadonlyIndexes: intsToBytes(table.ReadonlyIndexes),
+		}
+	}
+
+	return signatures, &pb.Message{
+		Header: &pb.MessageHeader{
+			NumRequiredSignatures:       tx.Message.Header.NumRequiredSignatures,
+			NumReadonlySignedAccounts:   tx.Message.Header.NumReadonlySignedAccounts,
+			NumReadonlyUnsignedAccounts: tx.Message.Header.NumReadonlyUnsignedAccounts,
+		},
+		AccountKeys:         accountKeys,
+		RecentBlockhash:     recentBlockhash,
+		Instructions:        instructions,
+		Versioned:           false, // 由调用方根据 version 字段设置
+		AddressTableLookups: lookups,
+	}, nil
+}
+
+func convertRpcMessage(msg *sdktypes.Message) (*pb.Message, error) {
+	recentBlockhash, err := base58.Decode(msg.RecentBlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("decode recent blockhash error: %w", err)
+	}
+
+	accountKeys := make([][]byte, len(msg.Accounts))
+	for i := range msg.Accounts {
+		accountKeys[i] = msg.Accounts[i].Bytes()
+	}
+
+	instructions := make([]*pb.CompiledInstruction, len(msg.Instructions))
+	for i, inst := range msg.Instructions {
+		instructions[i] = &pb.CompiledInstruction{
+			ProgramIdIndex: uint32(inst.ProgramIDIndex),
+			Accounts:       intsToBytes(inst.Accounts),
+			Data:           inst.Data,
+		}
+	}
+
+	lookups := make([]*pb.MessageAddressTableLookup, len(msg.AddressLookupTables))
+	for i, table := range msg.AddressLookupTables {
+		lookups[i] = &pb.MessageAddressTableLookup{
+			AccountKey:      table.AccountKey.Bytes(),
+			WritableIndexes: table.WritableIndexes,
+			ReadonlyIndexes: table.ReadonlyIndexes,
+		}
+	}
+
+	return &pb.Message{
+		Header: &pb.MessageHeader{
+			NumRequiredSignatures:       uint32(msg.Header.NumRequireSignatures),
+			NumReadonlySignedAccounts:   uint32(msg.Header.NumReadonlySignedAccounts),
+			NumReadonlyUnsignedAccounts: uint32(msg.Header.NumReadonlyUnsignedAccounts),
+		},
+		AccountKeys:         accountKeys,
+		RecentBlockhash:     recentBlockhash,
+		Instructions:        instructions,
+		Versioned:           msg.Version != sdktypes.MessageVersionLegacy,
+		AddressTableLookups: lookups,
+	}, nil
+}
+
+func convertRpcMeta(meta *rpc.TransactionMeta) (*pb.TransactionStatusMeta, error) {
+	result := &pb.TransactionStatusMeta{
+		Fee:                  meta.Fee,
+		PreBalances:          int64sToUint64s(meta.PreBalances),
+		PostBalances:         int64sToUint64s(meta.PostBalances),
+		LogMessages:          meta.LogMessages,
+		PreTokenBalances:     convertRpcTokenBalances(meta.PreTokenBalances),
+		PostTokenBalances:    convertRpcTokenBalances(meta.PostTokenBalances),
+		ComputeUnitsConsumed: meta.ComputeUnitsConsumed,
+	}
+
+	// 失败交易：gRPC 中 Err 为 bincode 编码，这里转换为相同编码；无法识别时保留 json 原文，仅用于区分成功/失败
+	if meta.Err != nil {
+		errBytes, err := encodeRpcTxError(meta.Err)
+		if err != nil {
+			errBytes, _ = json.Marshal(meta.Err)
+		}
+		result.Err = &pb.TransactionError{Err: errBytes}
+	}
+
+	// Address Lookup Table 加载的账户
+	loadedWritable, err := decodeBase58List(meta.LoadedAddresses.Writable)
+	if err != nil {
+		return nil, fmt.Errorf("decode loadedAddresses.writable error: %w", err)
+	}
+	loadedReadonly, err := decodeBase58List(meta.LoadedAddresses.Readonly)
+	if err != nil {
+		return nil, fmt.Errorf("decode loadedAddresses.readonly error: %w", err)
+	}
+	result.LoadedWritableAddresses = loadedWritable
+	result.LoadedReadonlyAddresses = loadedReadonly
+
+	// inner 指令
+	result.InnerInstructions = make([]*pb.InnerInstructions, 0, len(meta.InnerInstructions))
+	for _, group := range meta.InnerInstructions {
+		inners := make([]*pb.InnerInstruction, 0, len(group.Instructions))
+		for _, rawInner := range group.Instructions {
+			inner, err := decodeRpcInnerInstruction(rawInner)
+			if err != nil {
+				return nil, err
+			}
+			inners = append(inners, inner)
+		}
+		result.InnerInstructions = append(result.InnerInstructions, &pb.InnerInstructions{
+			Index:        uint32(group.Index),
+			Instructions: inners,
+		})
+	}
+	return result, nil
+}
+
+// decodeRpcInnerInstruction 将 json 反序列化得到的 map 结构转为 InnerInstruction。
+func decodeRpcInnerInstruction(raw any) (*pb.InnerInstruction, error) {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("marshal inner instruction error: %w", err)
+	}
+	var inner rpcInnerInstruction
+	if err = json.Unmarshal(b, &inner); err != nil {
+		return nil, fmt.Errorf("unmarshal inner instruction error: %w", err)
+	}
+	data, err := base58.Decode(inner.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode inner instruction data error: %w", err)
+	}
+	return &pb.InnerInstruction{
+		ProgramIdIndex: inner.ProgramIDIndex,
+		Accounts:       intsToBytes(inner.Accounts),
+		Data:           data,
+		StackHeight:    inner.StackHeight,
+	}, nil
+}
+
+func convertRpcTokenBalances(list []rpc.TransactionMetaTokenBalance) []*pb.TokenBalance {
+	result := make([]*pb.TokenBalance, 0, len(list))
+	for _, b := range list {
+		result = append(result, &pb.TokenBalance{
+			AccountIndex: uint32(b.AccountIndex),
+			Mint:         b.Mint,
+			Owner:        b.Owner,
+			ProgramId:    b.ProgramId,
+			UiTokenAmount: &pb.UiTokenAmount{
+				Decimals:       uint32(b.UITokenAmount.Decimals),
+				Amount:         b.UITokenAmount.Amount,
+				UiAmountString: b.UITokenAmount.UIAmountString,
+			},
+		})
+	}
+	return result
+}
+
+// isVersionedTx 判断 getBlock 返回的 version 字段（"legacy" 或数字版本号）是否为 versioned 交易
+func isVersionedTx(version any) bool {
+	switch v := version.(type) {
+	case nil:
+		return false
+	case string:
+		return v != "legacy"
+	default:
+		return true
+	}
+}
+
+// isVoteMessage 判断交易是否为投票交易（gRPC 中由服务端给出 IsVote，RPC 需自行判断）
+func isVoteMessage(msg *pb.Message) bool {
+	for _, inst := range msg.Instructions {
+		idx := int(inst.ProgramIdIndex)
+		if idx < len(msg.AccountKeys) && bytes.Equal(msg.AccountKeys[idx], consts.VoteProgram[:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeBase58List(list []string) ([][]byte, error) {
+	result := make([][]byte, len(list))
+	for i, s := range list {
+		b, err := base58.Decode(s)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = b
+	}
+	return result, nil
+}
+
+func intsToBytes(list []int) []byte {
+	result := make([]byte, len(list))
+	for i, v := range list {
+		result[i] = byte(v)
+	}
+	return result
+}
+
+func int64sToUint64s(list []int64) []uint64 {
+	result := make([]uint64, len(list))
+	for i, v := range list {
+		result[i] = uint64(v)
+	}
+	return result
+}
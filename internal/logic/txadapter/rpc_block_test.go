@@ -0,0 +1,236 @@
+package txadapter
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/internal/pkg/types"
+	"path/filepath"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/getblock_*.json 为同一个区块分别以 json / base64 encoding 返回的 getBlock 结果：
+//
+//	tx0：v0 交易，账户含 Address Lookup Table 加载的地址；ComputeBudget + Jito tip + 多层 CPI 的路由指令
+//	tx1：legacy 交易，执行失败（InstructionError: [1, {"Custom": 6001}]）
+const fixtureSlot = 330_000_001
+
+func loadBlockFixture(t *testing.T, name string) *rpc.GetBlock {
+	t.Helper()
+	return testfixture.LoadBlock(t, filepath.Join("testdata", name))
+}
+
+func adaptBlockFixture(t *testing.T, name string) []*core.AdaptedTx {
+	t.Helper()
+	block := loadBlockFixture(t, name)
+	txCtx, err := NewRpcTxContext(fixtureSlot, block)
+	require.NoError(t, err)
+
+	txs := make([]*core.AdaptedTx, len(block.Transactions))
+	for i := range block.Transactions {
+		txs[i], err = AdaptRpcTx(txCtx, map[string]types.Pubkey{}, &block.Transactions[i], uint64(i))
+		require.NoError(t, err, "tx %d", i)
+	}
+	return txs
+}
+
+func TestAdaptRpcTx_EncodingsMatch(t *testing.T) {
+	jsonTxs := adaptBlockFixture(t, "getblock_json.json")
+	base64Txs := adaptBlockFixture(t, "getblock_base64.json")
+	require.Len(t, base64Txs, len(jsonTxs))
+	for i := range jsonTxs {
+		assert.Equal(t, jsonTxs[i], base64Txs[i], "tx %d", i)
+	}
+}
+
+func TestAdaptRpcTx_VersionedTx(t *testing.T) {
+	tx := adaptBlockFixture(t, "getblock_json.json")[0]
+	var (
+		wallet     = testfixture.Key("adapter:wallet")
+		srcAccount = testfixture.Key("adapter:src")
+		dstAccount = testfixture.Key("adapter:dst")
+		vault      = testfixture.Key("adapter:vault")
+		mint       = testfixture.Key("adapter:mint")
+		router     = testfixture.Key("adapter:router")
+		pool       = testfixture.Key("adapter:pool")
+	)
+
+	assert.Equal(t, uint64(fixtureSlot), tx.TxCtx.Slot)
+	assert.Equal(t, uint64(fixtureSlot-1), tx.TxCtx.ParentSlot)
+	assert.Equal(t, int64(1760000001), tx.TxCtx.BlockTime)
+	assert.Equal(t, core.EpochOfSlot(fixtureSlot), tx.TxCtx.Epoch)
+	assert.Equal(t, uint32(0), tx.TxIndex)
+	assert.Len(t, tx.Signature, 64)
+	assert.Equal(t, [][]byte{wallet[:]}, tx.Signers)
+	assert.Nil(t, tx.Err)
+
+	// 展平后的指令：3 条主指令 + 路由指令及其 4 条 inner 指令
+	require.Len(t, tx.Instructions, 8)
+	routerIx := tx.Instructions[3]
+	assert.Equal(t, router, routerIx.ProgramID)
+	assert.Equal(t, uint16(3), routerIx.IxIndex)
+	assert.Equal(t, -1, routerIx.ParentIndex)
+	// ALT 加载的账户（vault 可写、mint 只读）排在静态账户之后
+	assert.Equal(t, []types.Pubkey{wallet, srcAccount, dstAccount, vault, mint, consts.TokenProgram}, routerIx.Accounts)
+
+	expected := []struct {
+		program     types.Pubkey
+		innerIndex  uint16
+		stackHeight uint8
+		parent      int
+	}{
+		{consts.TokenProgram, 1, 2, 3},
+		{pool, 2, 2, 3},
+		{consts.TokenProgram, 3, 3, 5},
+		{router, 4, 2, 3},
+	}
+	for i, want := range expected {
+		ix := tx.Instructions[4+i]
+		assert.Equal(t, want.program, ix.ProgramID, "inner %d", i)
+		assert.Equal(t, uint16(3), ix.IxIndex, "inner %d", i)
+		assert.Equal(t, want.innerIndex, ix.InnerIndex, "inner %d", i)
+		assert.Equal(t, want.stackHeight, ix.StackHeight, "inner %d", i)
+		assert.Equal(t, want.parent, ix.ParentIndex, "inner %d", i)
+	}
+	assert.Equal(t, []types.Pubkey{vault, dstAccount, pool}, tx.Instructions[6].Accounts)
+
+	// token 余额：dst 只出现在 post 中（本交易内创建）
+	require.Len(t, tx.Balances, 2)
+	src := tx.Balances[srcAccount]
+	require.NotNil(t, src)
+	assert.Equal(t, consts.USDCMint, src.Token)
+	assert.Equal(t, wallet, src.PostOwner)
+	assert.Equal(t, uint64(1_500_000), src.PreBalance)
+	assert.Equal(t, uint64(1_000_000), src.PostBalance)
+	assert.Equal(t, uint8(6), src.Decimals)
+	dst := tx.Balances[dstAccount]
+	require.NotNil(t, dst)
+	assert.Equal(t, mint, dst.Token)
+	assert.False(t, dst.HasPreOwner)
+	assert.Equal(t, uint64(123), dst.PostBalance)
+
+	// SOL 余额与账户权限
+	walletSol := tx.SolBalances[wallet]
+	require.NotNil(t, walletSol)
+	assert.True(t, walletSol.Signer)
+	assert.True(t, walletSol.Writable)
+	assert.Equal(t, uint64(10_000_000-12_500-1_000_000), walletSol.PostBalance)
+	assert.True(t, tx.SolBalances[vault].Writable)
+	assert.False(t, tx.SolBalances[mint].Writable)
+
+	// 手续费：price 25000 × limit 300000 / 1e6 = 7500
+	assert.Equal(t, uint64(12_500), tx.Fee)
+	assert.Equal(t, uint64(7_500), tx.PriorityFee)
+	assert.Equal(t, uint64(5_000), tx.BaseFee)
+	assert.Equal(t, uint32(300_000), tx.ComputeUnitLimit)
+	assert.Equal(t, uint64(25_000), tx.ComputeUnitPrice)
+	assert.Equal(t, uint64(123_456), tx.ComputeUnitsConsumed)
+	assert.Equal(t, uint64(1_000_000), tx.JitoTip)
+
+	assert.Len(t, tx.LogMessages, 3)
+}
+
+func TestAdaptRpcTx_FailedLegacyTx(t *testing.T) {
+	tx := adaptBlockFixture(t, "getblock_json.json")[1]
+
+	assert.Equal(t, uint32(1), tx.TxIndex)
+	require.NotNil(t, tx.Err)
+	assert.Equal(t, &core.TxError{Code: "InstructionError", IxIndex: 1, IxCode: "Custom", CustomCode: 6001}, tx.Err)
+	// 失败交易的转账已回滚，不计 Jito 小费
+	assert.Equal(t, uint64(0), tx.JitoTip)
+	// 未设置 ComputeBudget：2 条指令 × 200000
+	assert.Equal(t, uint32(400_000), tx.ComputeUnitLimit)
+	assert.Equal(t, uint64(0), tx.PriorityFee)
+	assert.Equal(t, uint64(5_000), tx.BaseFee)
+}
+
+func TestAdaptRpcTx_Errors(t *testing.T) {
+	block := loadBlockFixture(t, "getblock_base64.json")
+	txCtx, err := NewRpcTxContext(fixtureSlot, block)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		mutate func(tx *rpc.GetBlockTransaction)
+		errMsg string
+	}{
+		{"missing meta", func(tx *rpc.GetBlockTransaction) { tx.Meta = nil }, "missing meta"},
+		{"unsupported encoding", func(tx *rpc.GetBlockTransaction) {
+			tx.Transaction = []any{"AQID", "base32"}
+		}, "unsupported transaction encoding"},
+		{"truncated wire format", func(tx *rpc.GetBlockTransaction) {
+			tx.Transaction = []any{"AQID", "base64"}
+		}, "deserialize transaction error"},
+		{"jsonParsed", func(tx *rpc.GetBlockTransaction) { tx.Transaction = "parsed" }, "unexpected transaction encoding"},
+		{"bad loaded address", func(tx *rpc.GetBlockTransaction) {
+			tx.Meta.LoadedAddresses.Writable = []string{"not-base58-0OIl"}
+		}, "loadedAddresses.writable"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			raw := block.Transactions[0]
+			meta := *raw.Meta
+			raw.Meta = &meta
+			c.mutate(&raw)
+			_, err := AdaptRpcTx(txCtx, map[string]types.Pubkey{}, &raw, 0)
+			assert.ErrorContains(t, err, c.errMsg)
+		})
+	}
+}
+
+func TestNewRpcTxContext_Errors(t *testing.T) {
+	_, err := NewRpcTxContext(fixtureSlot, nil)
+	assert.ErrorContains(t, err, "empty block")
+
+	block := loadBlockFixture(t, "getblock_json.json")
+	block.Blockhash = "invalid"
+	_, err = NewRpcTxContext(fixtureSlot, block)
+	assert.ErrorContains(t, err, "invalid blockhash")
+
+	// 缺少 previousBlockhash / blockTime 时为零值
+	block = loadBlockFixture(t, "getblock_json.json")
+	block.PreviousBlockhash = ""
+	block.BlockTime = nil
+	txCtx, err := NewRpcTxContext(fixtureSlot, block)
+	require.NoError(t, err)
+	assert.Zero(t, txCtx.ParentBlockHash)
+	assert.Zero(t, txCtx.BlockTime)
+}
+
+func TestConvertRpcBlock(t *testing.T) {
+	for _, name := range []string{"getblock_json.json", "getblock_base64.json"} {
+		t.Run(name, func(t *testing.T) {
+			block := loadBlockFixture(t, name)
+			converted, err := ConvertRpcBlock(fixtureSlot, block)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(fixtureSlot), converted.Slot)
+			assert.Equal(t, uint64(fixtureSlot-1), converted.ParentSlot)
+			assert.Equal(t, block.Blockhash, converted.Blockhash)
+			assert.Equal(t, block.PreviousBlockhash, converted.ParentBlockhash)
+			assert.Equal(t, uint64(310_000_001), converted.BlockHeight.BlockHeight)
+			assert.Equal(t, int64(1760000001), converted.BlockTime.Timestamp)
+			assert.Equal(t, uint64(2), converted.ExecutedTransactionCount)
+			require.Len(t, converted.Transactions, 2)
+			assert.Equal(t, uint64(1), converted.Transactions[1].Index)
+			assert.False(t, converted.Transactions[0].IsVote)
+			assert.True(t, converted.Transactions[0].Transaction.Message.Versioned)
+			assert.False(t, converted.Transactions[1].Transaction.Message.Versioned)
+		})
+	}
+}
+
+func TestConvertRpcBlock_RejectsBadTx(t *testing.T) {
+	_, err := ConvertRpcBlock(fixtureSlot, nil)
+	assert.ErrorContains(t, err, "empty block")
+
+	// 缺少任一交易的区块不能分发，否则该交易的事件永久丢失
+	block := loadBlockFixture(t, "getblock_json.json")
+	block.Transactions[1].Meta = nil
+	converted, err := ConvertRpcBlock(fixtureSlot, block)
+	assert.Nil(t, converted)
+	assert.ErrorContains(t, err, "tx 1: missing meta")
+}
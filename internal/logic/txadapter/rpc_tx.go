@@ -0,0 +1,53 @@
+package txadapter
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/types"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/rpc"
+)
+
+// NewRpcTxContext 根据 RPC getBlock 的结果构造区块上下文，供 AdaptRpcTx 使用。
+func NewRpcTxContext(slot uint64, block *rpc.GetBlock) (*core.TxContext, error) {
+	if block == nil {
+		return nil, fmt.Errorf("slot %d: empty block", slot)
+	}
+
+	blockHash, err := types.HashFromBase58(block.Blockhash)
+	if err != nil {
+		return nil, fmt.Errorf("slot %d: invalid blockhash %s: %w", slot, block.Blockhash, err)
+	}
+	var parentBlockHash types.Hash
+	if block.PreviousBlockhash != "" {
+		parentBlockHash, _ = types.HashFromBase58(block.PreviousBlockhash)
+	}
+
+	txCtx := &core.TxContext{
+		Slot:            slot,
+		Epoch:           core.EpochOfSlot(slot),
+		BlockHash:       blockHash,
+		ParentSlot:      block.ParentSlot,
+		ParentBlockHash: parentBlockHash,
+	}
+	if block.BlockTime != nil {
+		txCtx.BlockTime = *block.BlockTime
+	}
+	return txCtx, nil
+}
+
+// AdaptRpcTx 将 JSON-RPC getBlock 返回的单笔交易（encoding=base64/base58/json，含 loadedAddresses、
+// inner 指令与 token 余额）转换为 core.AdaptedTx，与 AdaptGrpcTx 的结果一致，使回填、补块与测试数据可以复用全部解析器。
+// index 为交易在区块中的位置。
+func AdaptRpcTx(
+	txCtx *core.TxContext,
+	ownerCache map[string]types.Pubkey,
+	rawTx *rpc.GetBlockTransaction,
+	index uint64,
+) (*core.AdaptedTx, error) {
+	tx, err := convertRpcTx(rawTx, index)
+	if err != nil {
+		return nil, fmt.Errorf("convert rpc tx error: %w", err)
+	}
+	return AdaptGrpcTx(txCtx, ownerCache, tx)
+}
@@ -0,0 +1,108 @@
+package txadapter
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+)
+
+// 来源：https://github.com/anza-xyz/solana-sdk/blob/master/system-interface/src/instruction.rs
+const (
+	systemCreateAccount         = 0
+	systemAssign                = 1
+	systemCreateAccountWithSeed = 3
+	systemWithdrawNonceAccount  = 5
+	systemAssignWithSeed        = 10
+	systemTransferWithSeed      = 11
+)
+
+// markSystemOwned 根据交易内容标记执行后由 System Program 所有的账户（SolBalance.SystemOwned）。
+// gRPC / RPC 交易数据不含账户 owner，只能从以下可确定的情形推断：
+//   - 交易前不存在（余额为 0）的账户默认属于 System Program；
+//   - System Transfer / TransferWithSeed / WithdrawNonceAccount 的来源账户必须属于 System Program；
+//   - CreateAccount / Assign 系列指令（含 CPI）显式设置 owner，按执行顺序覆盖以上结果。
+//
+// 失败交易的状态变更已全部回滚，只按交易前状态判断。
+func markSystemOwned(adapted *core.AdaptedTx) {
+	owners := make(map[types.Pubkey]types.Pubkey)
+	for account, balance := range adapted.SolBalances {
+		if balance.PreBalance == 0 {
+			owners[account] = consts.SystemProgram
+		}
+	}
+
+	if adapted.Err == nil {
+		for _, ix := range adapted.Instructions {
+			if ix.ProgramID != consts.SystemProgram || len(ix.Data) < 4 || len(ix.Accounts) == 0 {
+				continue
+			}
+			if account, owner, ok := parseSystemOwner(ix); ok {
+				owners[account] = owner
+			}
+		}
+	}
+
+	for account, owner := range owners {
+		if balance, ok := adapted.SolBalances[account]; ok && owner == consts.SystemProgram {
+			balance.SystemOwned = true
+		}
+	}
+}
+
+// parseSystemOwner 解析 System 指令执行后可确定 owner 的账户
+func parseSystemOwner(ix *core.AdaptedInstruction) (account, owner types.Pubkey, ok bool) {
+	data := ix.Data[4:]
+	switch binary.LittleEndian.Uint32(ix.Data[:4]) {
+	// Transfer / TransferWithSeed / WithdrawNonceAccount: accounts[0] 为来源账户
+	case systemTransfer, systemTransferWithSeed, systemWithdrawNonceAccount:
+		return ix.Accounts[0], consts.SystemProgram, true
+
+	// CreateAccount: lamports(8) + space(8) + owner(32)，accounts = [funder, new_account]
+	case systemCreateAccount:
+		if len(ix.Accounts) < 2 || len(data) < 48 {
+			return account, owner, false
+		}
+		copy(owner[:], data[16:48])
+		return ix.Accounts[1], owner, true
+
+	// CreateAccountWithSeed: base(32) + seed + lamports(8) + space(8) + owner(32)，accounts = [funder, new_account]
+	case systemCreateAccountWithSeed:
+		data, ok = skipSeed(data)
+		if !ok || len(ix.Accounts) < 2 || len(data) < 48 {
+			return account, owner, false
+		}
+		copy(owner[:], data[16:48])
+		return ix.Accounts[1], owner, true
+
+	// Assign: owner(32)，accounts = [account]
+	case systemAssign:
+		if len(data) < 32 {
+			return account, owner, false
+		}
+		copy(owner[:], data[:32])
+		return ix.Accounts[0], owner, true
+
+	// AssignWithSeed: base(32) + seed + owner(32)，accounts = [account, base]
+	case systemAssignWithSeed:
+		data, ok = skipSeed(data)
+		if !ok || len(data) < 32 {
+			return account, owner, false
+		}
+		copy(owner[:], data[:32])
+		return ix.Accounts[0], owner, true
+	}
+	return account, owner, false
+}
+
+// skipSeed 跳过 base(32) 与 bincode 编码的 seed 字符串（u64 长度 + 内容）
+func skipSeed(data []byte) ([]byte, bool) {
+	if len(data) < 40 {
+		return nil, false
+	}
+	seedLen := binary.LittleEndian.Uint64(data[32:40])
+	if seedLen > uint64(len(data)-40) {
+		return nil, false
+	}
+	return data[40+seedLen:], true
+}
@@ -0,0 +1,144 @@
+package txadapter
+
+import (
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/logic/core"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/binary"
+	"testing"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func systemIx(index uint32, args ...[]byte) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, index)
+	for _, arg := range args {
+		data = append(data, arg...)
+	}
+	return data
+}
+
+func u64Bytes(v uint64) []byte { return binary.LittleEndian.AppendUint64(nil, v) }
+
+func seedBytes(base types.Pubkey, seed string) []byte {
+	return append(append(base[:], u64Bytes(uint64(len(seed)))...), seed...)
+}
+
+// solBalanceTx 构造涉及多种账户的交易：
+//
+//	0 wallet（signer）   1 recipient（已存在，非 signer）   2 fresh（交易前不存在）   3 vault（池子 PDA）
+//	4 created（CreateAccount → Token Program）   5 nonce   6 assigned（Assign → 其它程序）   7 seeded（CreateAccountWithSeed → System）
+//	8 token_account（SPL Token 账户）   9 System Program
+func solBalanceTx(t *testing.T, failed bool) *core.AdaptedTx {
+	t.Helper()
+	names := []string{"sol:wallet", "sol:recipient", "sol:fresh", "sol:vault", "sol:created", "sol:nonce", "sol:assigned", "sol:seeded", "sol:token_account"}
+	keys := make([][]byte, 0, len(names)+1)
+	for _, name := range names {
+		keys = append(keys, testfixture.Bytes(name))
+	}
+	keys = append(keys, consts.SystemProgram[:])
+	program := testfixture.Key("sol:program")
+	wallet := testfixture.Key("sol:wallet")
+
+	ix := func(data []byte, accounts ...byte) *pb.CompiledInstruction {
+		return &pb.CompiledInstruction{ProgramIdIndex: 9, Accounts: accounts, Data: data}
+	}
+	tx := &pb.SubscribeUpdateTransactionInfo{
+		Signature: testfixture.Bytes("sol:sig"),
+		Transaction: &pb.Transaction{
+			Signatures: [][]byte{testfixture.Bytes("sol:sig")},
+			Message: &pb.Message{
+				Header:      &pb.MessageHeader{NumRequiredSignatures: 1, NumReadonlyUnsignedAccounts: 1},
+				AccountKeys: keys,
+				Instructions: []*pb.CompiledInstruction{
+					ix(systemIx(2, u64Bytes(1_000)), 0, 1),
+					ix(systemIx(2, u64Bytes(2_000)), 0, 2),
+					ix(systemIx(2, u64Bytes(3_000)), 0, 3),
+					ix(systemIx(0, u64Bytes(2_039_280), u64Bytes(165), consts.TokenProgram[:]), 0, 4),
+					ix(systemIx(5, u64Bytes(500)), 5, 0),
+					ix(systemIx(1, program[:]), 6),
+					ix(systemIx(3, seedBytes(wallet, "vote"), u64Bytes(4_000), u64Bytes(0), consts.SystemProgram[:]), 0, 7),
+				},
+			},
+		},
+		Meta: &pb.TransactionStatusMeta{
+			Fee:          5000,
+			PreBalances:  []uint64{10_000_000, 50_000, 0, 900_000, 0, 1_500, 7_000, 0, 2_039_280, 1},
+			PostBalances: []uint64{10_000_000 - 5000 - 1_000 - 2_000 - 3_000 - 2_039_280 + 500 - 4_000, 51_000, 2_000, 903_000, 2_039_280, 1_000, 7_000, 4_000, 2_039_281, 1},
+			PostTokenBalances: []*pb.TokenBalance{{
+				AccountIndex:  8,
+				Mint:          testfixture.Key("sol:mint").String(),
+				Owner:         wallet.String(),
+				ProgramId:     consts.TokenProgramStr,
+				UiTokenAmount: &pb.UiTokenAmount{Amount: "1", Decimals: 6},
+			}},
+		},
+	}
+	if failed {
+		tx.Meta.Err = &pb.TransactionError{Err: []byte{8, 0, 0, 0, 0, 25, 0, 0, 0, 1, 0, 0, 0}}
+		tx.Meta.PostBalances = append([]uint64{10_000_000 - 5000}, tx.Meta.PreBalances[1:]...)
+	}
+
+	adapted, err := AdaptGrpcTx(&core.TxContext{Slot: 100}, map[string]types.Pubkey{}, tx)
+	require.NoError(t, err)
+	return adapted
+}
+
+func TestMarkSystemOwned(t *testing.T) {
+	tx := solBalanceTx(t, false)
+	want := map[string]bool{
+		"sol:wallet":        true,  // Transfer 来源
+		"sol:recipient":     false, // 已存在的非 signer 账户，无法确定 owner
+		"sol:fresh":         true,  // 交易前不存在，由转账创建
+		"sol:vault":         false, // 池子 PDA
+		"sol:created":       false, // CreateAccount 分配给 Token Program
+		"sol:nonce":         true,  // WithdrawNonceAccount 来源
+		"sol:assigned":      false, // Assign 给其它程序
+		"sol:seeded":        true,  // CreateAccountWithSeed 分配给 System Program
+		"sol:token_account": false,
+	}
+	for name, owned := range want {
+		assert.Equal(t, owned, tx.SolBalances[testfixture.Key(name)].SystemOwned, name)
+	}
+}
+
+func TestAppendSolBalanceUpdates(t *testing.T) {
+	tx := solBalanceTx(t, false)
+	tx.AppendSolBalanceUpdates(0)
+
+	var solAccounts []types.Pubkey
+	for account, balance := range tx.Balances {
+		if balance.Token == consts.SOLMint {
+			solAccounts = append(solAccounts, account)
+			assert.Equal(t, account, balance.PostOwner, "SOL 余额的 owner 为账户本身")
+		}
+	}
+	assert.ElementsMatch(t, []types.Pubkey{
+		testfixture.Key("sol:wallet"), testfixture.Key("sol:fresh"), testfixture.Key("sol:nonce"), testfixture.Key("sol:seeded"),
+	}, solAccounts, "只推送 signer 与属于 System Program 的账户，SPL Token 账户与余额不变的账户跳过")
+
+	wallet := tx.Balances[testfixture.Key("sol:wallet")]
+	assert.Equal(t, uint64(10_000_000), wallet.PreBalance)
+	assert.Equal(t, uint64(7_946_220), wallet.PostBalance)
+
+	// 变化量低于阈值的账户跳过
+	tx = solBalanceTx(t, false)
+	tx.AppendSolBalanceUpdates(2_000)
+	assert.Contains(t, tx.Balances, testfixture.Key("sol:wallet"))
+	assert.Contains(t, tx.Balances, testfixture.Key("sol:fresh"))
+	assert.NotContains(t, tx.Balances, testfixture.Key("sol:nonce"))
+}
+
+func TestAppendSolBalanceUpdates_FailedTx(t *testing.T) {
+	tx := solBalanceTx(t, true)
+	require.NotNil(t, tx.Err)
+	assert.False(t, tx.SolBalances[testfixture.Key("sol:nonce")].SystemOwned, "失败交易的指令未生效")
+
+	tx.Balances = make(map[types.Pubkey]*core.TokenBalance)
+	tx.AppendSolBalanceUpdates(0)
+	require.Len(t, tx.Balances, 1, "只有手续费支付者的余额变化")
+	assert.Equal(t, uint64(10_000_000-5000), tx.Balances[testfixture.Key("sol:wallet")].PostBalance)
+}
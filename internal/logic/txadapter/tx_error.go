@@ -0,0 +1,251 @@
+package txadapter
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"encoding/binary"
+	"fmt"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+)
+
+// 来源：https://github.com/anza-xyz/agave/blob/master/sdk/transaction-error/src/lib.rs
+// 下标即 bincode 编码中的枚举序号
+var txErrorNames = []string{
+	"AccountInUse",
+	"AccountLoadedTwice",
+	"AccountNotFound",
+	"ProgramAccountNotFound",
+	"InsufficientFundsForFee",
+	"InvalidAccountForFee",
+	"AlreadyProcessed",
+	"BlockhashNotFound",
+	"InstructionError", // 8: (u8 指令索引, InstructionError)
+	"CallChainTooDeep",
+	"MissingSignatureForFee",
+	"InvalidAccountIndex",
+	"SignatureFailure",
+	"InvalidProgramForExecution",
+	"SanitizeFailure",
+	"ClusterMaintenance",
+	"AccountBorrowOutstanding",
+	"WouldExceedMaxBlockCostLimit",
+	"UnsupportedVersion",
+	"InvalidWritableAccount",
+	"WouldExceedMaxAccountCostLimit",
+	"WouldExceedAccountDataBlockLimit",
+	"TooManyAccountLocks",
+	"AddressLookupTableNotFound",
+	"InvalidAddressLookupTableOwner",
+	"InvalidAddressLookupTableData",
+	"InvalidAddressLookupTableIndex",
+	"InvalidRentPayingAccount",
+	"WouldExceedMaxVoteCostLimit",
+	"WouldExceedAccountDataTotalLimit",
+	"DuplicateInstruction",     // 30: u8
+	"InsufficientFundsForRent", // 31: { account_index: u8 }
+	"MaxLoadedAccountsDataSizeExceeded",
+	"InvalidLoadedAccountsDataSizeLimit",
+	"ResanitizationNeeded",
+	"ProgramExecutionTemporarilyRestricted", // 35: { account_index: u8 }
+	"UnbalancedTransaction",
+	"ProgramCacheHitMaxLimit",
+	"CommitCancelled",
+}
+
+// 来源：https://github.com/anza-xyz/agave/blob/master/sdk/instruction/src/error.rs
+var ixErrorNames = []string{
+	"GenericError",
+	"InvalidArgument",
+	"InvalidInstructionData",
+	"InvalidAccountData",
+	"AccountDataTooSmall",
+	"InsufficientFunds",
+	"IncorrectProgramId",
+	"MissingRequiredSignature",
+	"AccountAlreadyInitialized",
+	"UninitializedAccount",
+	"UnbalancedInstruction",
+	"ModifiedProgramId",
+	"ExternalAccountLamportSpend",
+	"ExternalAccountDataModified",
+	"ReadonlyLamportChange",
+	"ReadonlyDataModified",
+	"DuplicateAccountIndex",
+	"ExecutableModified",
+	"RentEpochModified",
+	"NotEnoughAccountKeys",
+	"AccountDataSizeChanged",
+	"AccountNotExecutable",
+	"AccountBorrowFailed",
+	"AccountBorrowOutstanding",
+	"DuplicateAccountOutOfSync",
+	"Custom", // 25: u32
+	"InvalidError",
+	"ExecutableDataModified",
+	"ExecutableLamportChange",
+	"ExecutableAccountNotRentExempt",
+	"UnsupportedProgramId",
+	"CallDepth",
+	"MissingAccount",
+	"ReentrancyNotAllowed",
+	"MaxSeedLengthExceeded",
+	"InvalidSeeds",
+	"InvalidRealloc",
+	"ComputationalBudgetExceeded",
+	"PrivilegeEscalation",
+	"ProgramEnvironmentSetupFailure",
+	"ProgramFailedToComplete",
+	"ProgramFailedToCompile",
+	"Immutable",
+	"IncorrectAuthority",
+	"BorshIoError", // 44: String
+	"AccountNotRentExempt",
+	"InvalidAccountOwner",
+	"ArithmeticOverflow",
+	"UnsupportedSysvar",
+	"IllegalOwner",
+	"MaxAccountsDataAllocationsExceeded",
+	"MaxAccountsExceeded",
+	"MaxInstructionTraceLengthExceeded",
+	"BuiltinProgramsMustConsumeComputeUnits",
+}
+
+const (
+	txErrInstructionError = 8
+	ixErrCustom           = 25
+	ixErrBorshIoError     = 44
+)
+
+// decodeTxError 解码 gRPC Meta.Err 中 bincode 编码的 TransactionError，成功交易返回 nil。
+// 无法识别的编码返回 Code = "Unknown"，保证失败交易仍能被识别。
+func decodeTxError(txErr *pb.TransactionError) *core.TxError {
+	if txErr == nil {
+		return nil
+	}
+	err := txErr.Err
+	result := &core.TxError{Code: "Unknown", IxIndex: -1}
+	if len(err) < 4 {
+		return result
+	}
+
+	code := binary.LittleEndian.Uint32(err)
+	if int(code) < len(txErrorNames) {
+		result.Code = txErrorNames[code]
+	}
+	if code != txErrInstructionError || len(err) < 9 {
+		return result
+	}
+
+	// InstructionError(u8, InstructionError)
+	result.IxIndex = int32(err[4])
+	ixCode := binary.LittleEndian.Uint32(err[5:9])
+	if int(ixCode) < len(ixErrorNames) {
+		result.IxCode = ixErrorNames[ixCode]
+	} else {
+		result.IxCode = "Unknown"
+	}
+	if ixCode == ixErrCustom && len(err) >= 13 {
+		result.CustomCode = binary.LittleEndian.Uint32(err[9:13])
+	}
+	return result
+}
+
+// encodeRpcTxError 将 JSON-RPC 返回的 err（json 解码后的值）转换为与 gRPC 一致的 bincode 编码，
+// 使 RPC 来源的失败交易与 gRPC 走同一套错误解码。常见形式：
+//
+//	"AccountInUse"
+//	{"InstructionError": [0, {"Custom": 6001}]}
+//	{"InstructionError": [2, "InvalidAccountData"]}
+//	{"InsufficientFundsForRent": {"account_index": 2}}
+func encodeRpcTxError(v any) ([]byte, error) {
+	switch e := v.(type) {
+	case string:
+		code, ok := indexOf(txErrorNames, e)
+		if !ok {
+			return nil, fmt.Errorf("unknown transaction error: %s", e)
+		}
+		return binary.LittleEndian.AppendUint32(nil, code), nil
+
+	case map[string]any:
+		for name, payload := range e {
+			code, ok := indexOf(txErrorNames, name)
+			if !ok {
+				return nil, fmt.Errorf("unknown transaction error: %s", name)
+			}
+			buf := binary.LittleEndian.AppendUint32(nil, code)
+			if code == txErrInstructionError {
+				return appendRpcInstructionError(buf, payload)
+			}
+			// DuplicateInstruction(u8) / InsufficientFundsForRent{account_index: u8} 等带 u8 参数的错误
+			if n, ok := jsonUint(payload); ok {
+				return append(buf, byte(n)), nil
+			}
+			if m, ok := payload.(map[string]any); ok {
+				if n, ok := jsonUint(m["account_index"]); ok {
+					return append(buf, byte(n)), nil
+				}
+			}
+			return buf, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected transaction error: %v", v)
+}
+
+// appendRpcInstructionError 编码 InstructionError 的 [指令索引, 错误] 部分
+func appendRpcInstructionError(buf []byte, payload any) ([]byte, error) {
+	arr, ok := payload.([]any)
+	if !ok || len(arr) != 2 {
+		return nil, fmt.Errorf("unexpected InstructionError: %v", payload)
+	}
+	ixIndex, ok := jsonUint(arr[0])
+	if !ok {
+		return nil, fmt.Errorf("unexpected InstructionError index: %v", arr[0])
+	}
+	buf = append(buf, byte(ixIndex))
+
+	switch e := arr[1].(type) {
+	case string:
+		code, ok := indexOf(ixErrorNames, e)
+		if !ok {
+			return nil, fmt.Errorf("unknown instruction error: %s", e)
+		}
+		return binary.LittleEndian.AppendUint32(buf, code), nil
+
+	case map[string]any:
+		if custom, ok := jsonUint(e["Custom"]); ok {
+			buf = binary.LittleEndian.AppendUint32(buf, ixErrCustom)
+			return binary.LittleEndian.AppendUint32(buf, uint32(custom)), nil
+		}
+		if msg, ok := e["BorshIoError"].(string); ok {
+			buf = binary.LittleEndian.AppendUint32(buf, ixErrBorshIoError)
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(len(msg)))
+			return append(buf, msg...), nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected instruction error: %v", arr[1])
+}
+
+func indexOf(names []string, name string) (uint32, bool) {
+	for i, n := range names {
+		if n == name {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
+
+// jsonUint 读取 json 解码后的非负整数（encoding/json 解码到 any 时数字为 float64）
+func jsonUint(v any) (uint64, bool) {
+	switch n := v.(type) {
+	case float64:
+		if n < 0 {
+			return 0, false
+		}
+		return uint64(n), true
+	case int:
+		return uint64(n), true
+	case uint64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,96 @@
+package txadapter
+
+import (
+	"dex-indexer-sol/internal/logic/core"
+	"encoding/json"
+	"testing"
+
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTxError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  []byte
+		want *core.TxError
+	}{
+		{
+			name: "InsufficientFundsForFee",
+			err:  []byte{4, 0, 0, 0},
+			want: &core.TxError{Code: "InsufficientFundsForFee", IxIndex: -1},
+		},
+		{
+			name: "InstructionError Custom",
+			err:  []byte{8, 0, 0, 0, 3, 25, 0, 0, 0, 0x71, 0x17, 0, 0},
+			want: &core.TxError{Code: "InstructionError", IxIndex: 3, IxCode: "Custom", CustomCode: 6001},
+		},
+		{
+			name: "InstructionError builtin",
+			err:  []byte{8, 0, 0, 0, 0, 3, 0, 0, 0},
+			want: &core.TxError{Code: "InstructionError", IxIndex: 0, IxCode: "InvalidAccountData"},
+		},
+		{
+			name: "InstructionError unknown code",
+			err:  []byte{8, 0, 0, 0, 1, 0xff, 0, 0, 0},
+			want: &core.TxError{Code: "InstructionError", IxIndex: 1, IxCode: "Unknown"},
+		},
+		{
+			name: "InsufficientFundsForRent",
+			err:  []byte{31, 0, 0, 0, 2},
+			want: &core.TxError{Code: "InsufficientFundsForRent", IxIndex: -1},
+		},
+		{
+			name: "unknown tx error",
+			err:  []byte{0xff, 0, 0, 0},
+			want: &core.TxError{Code: "Unknown", IxIndex: -1},
+		},
+		{
+			name: "truncated",
+			err:  []byte{8, 0},
+			want: &core.TxError{Code: "Unknown", IxIndex: -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decodeTxError(&pb.TransactionError{Err: tt.err}))
+		})
+	}
+
+	assert.Nil(t, decodeTxError(nil))
+}
+
+// RPC 返回的 json 错误经 encodeRpcTxError 转为 bincode 后，应与 gRPC 的编码一致
+func TestEncodeRpcTxError(t *testing.T) {
+	tests := []struct {
+		json string
+		want []byte
+	}{
+		{`"AccountInUse"`, []byte{0, 0, 0, 0}},
+		{`"BlockhashNotFound"`, []byte{7, 0, 0, 0}},
+		{`{"InstructionError": [3, {"Custom": 6001}]}`, []byte{8, 0, 0, 0, 3, 25, 0, 0, 0, 0x71, 0x17, 0, 0}},
+		{`{"InstructionError": [0, "InvalidAccountData"]}`, []byte{8, 0, 0, 0, 0, 3, 0, 0, 0}},
+		{`{"InstructionError": [1, {"BorshIoError": "ab"}]}`, []byte{8, 0, 0, 0, 1, 44, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 'a', 'b'}},
+		{`{"DuplicateInstruction": 5}`, []byte{30, 0, 0, 0, 5}},
+		{`{"InsufficientFundsForRent": {"account_index": 2}}`, []byte{31, 0, 0, 0, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var v any
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &v))
+			got, err := encodeRpcTxError(v)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEncodeRpcTxError_Unknown(t *testing.T) {
+	for _, s := range []string{`"NoSuchError"`, `{"InstructionError": [0, "NoSuchError"]}`, `{"InstructionError": [0]}`, `42`} {
+		var v any
+		require.NoError(t, json.Unmarshal([]byte(s), &v))
+		_, err := encodeRpcTxError(v)
+		assert.Error(t, err, s)
+	}
+}
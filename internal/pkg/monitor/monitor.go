@@ -5,11 +5,15 @@ import (
 	"dex-indexer-sol/internal/pkg/logger"
 	"encoding/json"
 	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"net/http"
 	"time"
 )
 
+// Registry 索引器自身的监控指标注册表，与默认注册表（Go 运行时、进程、第三方库指标）一起通过 /metrics 暴露
+var Registry = prometheus.NewRegistry()
+
 type MonitorServer struct {
 	port   int
 	server *http.Server
@@ -17,7 +21,8 @@ type MonitorServer struct {
 
 func NewMonitorServer(port int) *MonitorServer {
 	mux := http.NewServeMux()
-	mux.Handle("/metrics", promhttp.Handler())
+	gatherers := prometheus.Gatherers{prometheus.DefaultGatherer, Registry}
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{}))
 	mux.Handle("/", &ProfileHandler{})
 
 	return &MonitorServer{
@@ -0,0 +1,37 @@
+// Package testfixture 提供单元测试共用的 getBlock 区块夹具读取与构造账户地址。
+//
+// testdata 下的区块为 getBlock（encoding=json/base64）格式，程序地址、指令 / 事件 / 日志布局与主网一致；
+// 用户、池子、mint 等账户为构造的地址，统一由名称派生（sha256(name)），测试中通过 Key 按名称引用，无需硬编码地址。
+package testfixture
+
+import (
+	"crypto/sha256"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// Key 返回夹具中名称为 name 的构造账户地址
+func Key(name string) types.Pubkey {
+	return sha256.Sum256([]byte(name))
+}
+
+// Bytes 同 Key，返回 []byte，便于与 pb 事件字段比较
+func Bytes(name string) []byte {
+	key := Key(name)
+	return key[:]
+}
+
+// LoadBlock 读取 getBlock 格式的区块夹具
+func LoadBlock(t testing.TB, path string) *rpc.GetBlock {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var block rpc.GetBlock
+	require.NoError(t, json.Unmarshal(data, &block))
+	return &block
+}
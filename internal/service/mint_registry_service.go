@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"dex-indexer-sol/internal/cache"
+	"dex-indexer-sol/internal/config"
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/logger"
+	"dex-indexer-sol/internal/pkg/types"
+	"dex-indexer-sol/internal/tools"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/redis/go-redis/v9"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMintRedisKey      = "mint:registry"
+	maxMultipleAccounts      = 100 // getMultipleAccounts 单次最多查询的账户数
+	mintAccountSize          = 82  // SPL Token Mint 账户长度（Token-2022 带扩展时更长）
+	mintRpcTimeout           = 10 * time.Second
+	mintStoreTimeout         = 10 * time.Second
+	defaultMintFlushInterval = 5 * time.Second
+	defaultMintWarmInterval  = time.Second
+)
+
+// MintRegistryService 负责全局 mint 注册表的加载、定期增量持久化，以及通过 RPC 补全未命中的 mint。
+type MintRegistryService struct {
+	registry      *cache.MintRegistry
+	store         cache.MintStore // 为 nil 表示不持久化
+	client        *client.Client  // 为 nil 表示不通过 RPC 补全
+	flushInterval time.Duration
+	warmInterval  time.Duration
+	ctx           context.Context
+	cancel        context.CancelFunc
+	done          chan struct{}
+	stopOnce      sync.Once
+}
+
+func NewMintRegistryService(cfg *config.MintRegistryConfig, redisAddr, rpcEndpoint string) (*MintRegistryService, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &MintRegistryService{
+		registry:      cache.Mints(),
+		flushInterval: time.Duration(cfg.FlushIntervalSec) * time.Second,
+		warmInterval:  time.Duration(cfg.WarmupIntervalMs) * time.Millisecond,
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+	if s.flushInterval <= 0 {
+		s.flushInterval = defaultMintFlushInterval
+	}
+	if s.warmInterval <= 0 {
+		s.warmInterval = defaultMintWarmInterval
+	}
+
+	rpcWarmup := cfg.RpcWarmup && rpcEndpoint != ""
+	if cfg.RpcWarmup && !rpcWarmup {
+		logger.Warnf("[MintRegistry] 未配置 rpc_endpoint，忽略 rpc_warmup")
+	}
+	s.registry.Configure(cfg.MaxEntries, rpcWarmup, time.Duration(cfg.NegativeTtlSec)*time.Second)
+
+	// 1. 加载持久化数据
+	switch cfg.Store {
+	case "", "none":
+	case "disk":
+		if cfg.Path == "" {
+			cancel()
+			return nil, errors.New("[MintRegistry] disk 模式需要配置 path")
+		}
+		store, err := cache.NewDiskMintStore(cfg.Path)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		s.store = store
+	case "redis":
+		key := cfg.RedisKey
+		if key == "" {
+			key = defaultMintRedisKey
+		}
+		s.store = cache.NewRedisMintStore(redis.NewClient(&redis.Options{Addr: redisAddr}), key)
+	default:
+		cancel()
+		return nil, fmt.Errorf("[MintRegistry] 不支持的 store: %s", cfg.Store)
+	}
+
+	if s.store != nil {
+		loadCtx, loadCancel := context.WithTimeout(ctx, time.Minute)
+		start := time.Now()
+		mints, err := s.store.Load(loadCtx)
+		loadCancel()
+		if err != nil {
+			_ = s.store.Close()
+			cancel()
+			return nil, fmt.Errorf("[MintRegistry] 加载持久化数据失败: %w", err)
+		}
+		s.registry.Load(mints)
+		logger.Infof("[MintRegistry] 加载 %d 个 mint，耗时 %v", len(mints), time.Since(start))
+	}
+
+	// 2. RPC 预热配置中的 mint
+	if rpcWarmup {
+		s.client = client.NewClient(rpcEndpoint)
+		mints := make([]types.Pubkey, 0, len(cfg.WarmupMints))
+		for _, str := range cfg.WarmupMints {
+			mint, err := types.TryPubkeyFromBase58(str)
+			if err != nil {
+				logger.Warnf("[MintRegistry] 忽略无效的 warmup mint: %v", err)
+				continue
+			}
+			mints = append(mints, mint)
+		}
+		for i := 0; i < len(mints); i += maxMultipleAccounts {
+			if err := s.fetchMints(mints[i:min(i+maxMultipleAccounts, len(mints))]); err != nil {
+				logger.Warnf("[MintRegistry] 启动预热失败: %v", err)
+				break
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *MintRegistryService) Start() {
+	defer close(s.done)
+
+	flushTicker := time.NewTicker(s.flushInterval)
+	defer flushTicker.Stop()
+
+	var warmC <-chan time.Time
+	if s.client != nil {
+		warmTicker := time.NewTicker(s.warmInterval)
+		defer warmTicker.Stop()
+		warmC = warmTicker.C
+	}
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-flushTicker.C:
+			s.flush()
+		case <-warmC:
+			s.warmPending()
+		}
+	}
+}
+
+func (s *MintRegistryService) Stop() {
+	s.stopOnce.Do(func() {
+		s.cancel()
+		<-s.done
+		s.flush()
+		if s.store != nil {
+			if err := s.store.Close(); err != nil {
+				logger.Warnf("[MintRegistry] 关闭存储失败: %v", err)
+			}
+		}
+		logger.Infof("[MintRegistry] 已停止，共缓存 %d 个 mint", s.registry.Len())
+	})
+}
+
+// flush 将变更的 mint 写入持久化存储，失败时重新标记，等待下次写入
+func (s *MintRegistryService) flush() {
+	if s.store == nil {
+		return
+	}
+	dirty := s.registry.TakeDirty()
+	if len(dirty) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mintStoreTimeout)
+	defer cancel()
+	if err := s.store.Save(ctx, dirty); err != nil {
+		logger.Warnf("[MintRegistry] 持久化 %d 个 mint 失败: %v", len(dirty), err)
+		s.registry.MarkDirty(dirty)
+	}
+}
+
+// warmPending 通过 RPC 批量查询解析过程中未命中的 mint
+func (s *MintRegistryService) warmPending() {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[MintRegistry] warmPending panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	mints := s.registry.TakePending(maxMultipleAccounts)
+	if len(mints) == 0 {
+		return
+	}
+	if err := s.fetchMints(mints); err != nil {
+		logger.Warnf("[MintRegistry] RPC 补全 %d 个 mint 失败: %v", len(mints), err)
+	}
+}
+
+// fetchMints 通过 getMultipleAccounts 读取 mint 账户并写入注册表
+func (s *MintRegistryService) fetchMints(mints []types.Pubkey) error {
+	if len(mints) == 0 {
+		return nil
+	}
+	addrs := make([]string, len(mints))
+	for i, mint := range mints {
+		addrs[i] = mint.String()
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, mintRpcTimeout)
+	defer cancel()
+	infos, err := s.client.GetMultipleAccounts(ctx, addrs)
+	if err != nil {
+		return fmt.Errorf("GetMultipleAccounts failed: %w", err)
+	}
+	if len(infos) != len(mints) {
+		return fmt.Errorf("返回账户数与请求不一致: got=%d want=%d", len(infos), len(mints))
+	}
+
+	var notMints []types.Pubkey
+	for i, info := range infos {
+		mintInfo, ok := parseMintAccount(types.Pubkey(info.Owner), info.Data)
+		if !ok {
+			notMints = append(notMints, mints[i]) // 账户不存在或不是 mint 账户，缓存期内不再查询
+			continue
+		}
+		s.registry.Put(mints[i], mintInfo)
+	}
+	s.registry.MarkNotMint(notMints)
+	return nil
+}
+
+// parseMintAccount 解析 SPL Token / Token-2022 Mint 账户数据：
+//
+//	[0:4]   mint_authority COption 标记   [4:36]  mint_authority
+//	[36:44] supply                        [44]    decimals
+//	[45]    is_initialized                [46:50] freeze_authority COption 标记   [50:82] freeze_authority
+//
+// Token-2022 带扩展的 mint 在 [165] 记录 AccountType，据此与同属 Token Program 的 token 账户（165 字节）区分。
+func parseMintAccount(owner types.Pubkey, data []byte) (cache.MintInfo, bool) {
+	var info cache.MintInfo
+	if !tools.IsSPLTokenPubkey(owner) || !isMintAccountData(data) || data[45] == 0 {
+		return info, false
+	}
+	info.TokenProgram = owner
+	info.HasAccount = true
+	if binary.LittleEndian.Uint32(data[0:4]) == 1 {
+		copy(info.MintAuthority[:], data[4:36])
+	}
+	info.Supply = binary.LittleEndian.Uint64(data[36:44])
+	info.Decimals = data[44]
+	if binary.LittleEndian.Uint32(data[46:50]) == 1 {
+		copy(info.FreezeAuthority[:], data[50:82])
+	}
+	if owner == consts.TokenProgram2022 {
+		parseMintExtensions(&info, data)
+	}
+	return info, true
+}
+
+// isMintAccountData 判断账户数据是否为 mint：82 字节，或 Token-2022 扩展布局且 AccountType 为 Mint
+func isMintAccountData(data []byte) bool {
+	if len(data) == mintAccountSize {
+		return true
+	}
+	return len(data) > token2022AccountTypeOffset && data[token2022AccountTypeOffset] == token2022AccountTypeMint
+}
+
+// 来源：https://github.com/solana-program/token-2022/blob/main/program/src/extension/mod.rs
+const (
+	token2022AccountTypeOffset = 165 // 扩展数据从 Account::LEN 之后开始：[165] AccountType，[166:] TLV
+	token2022AccountTypeMint   = 1
+	extensionTransferFeeConfig = 1
+	transferFeeConfigSize      = 108 // authority(32) + withdraw authority(32) + withheld(8) + older(18) + newer(18)
+)
+
+// parseMintExtensions 遍历 Token-2022 mint 的 TLV 扩展（type u16 + length u16 + value），目前只解析 TransferFeeConfig
+func parseMintExtensions(info *cache.MintInfo, data []byte) {
+	if len(data) <= token2022AccountTypeOffset || data[token2022AccountTypeOffset] != token2022AccountTypeMint {
+		return
+	}
+	for tlv := data[token2022AccountTypeOffset+1:]; len(tlv) >= 4; {
+		extType := binary.LittleEndian.Uint16(tlv[0:2])
+		length := int(binary.LittleEndian.Uint16(tlv[2:4]))
+		if len(tlv) < 4+length {
+			return
+		}
+		value := tlv[4 : 4+length]
+		if extType == extensionTransferFeeConfig && length >= transferFeeConfigSize {
+			info.HasTransferFee = true
+			info.OlderTransferFee = parseTransferFee(value[72:90])
+			info.NewerTransferFee = parseTransferFee(value[90:108])
+		}
+		tlv = tlv[4+length:]
+	}
+}
+
+// parseTransferFee 解析 TransferFee：epoch(u64) + maximum_fee(u64) + transfer_fee_basis_points(u16)
+func parseTransferFee(data []byte) cache.TransferFee {
+	return cache.TransferFee{
+		Epoch:       binary.LittleEndian.Uint64(data[0:8]),
+		MaximumFee:  binary.LittleEndian.Uint64(data[8:16]),
+		BasisPoints: binary.LittleEndian.Uint16(data[16:18]),
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"dex-indexer-sol/internal/cache"
+	"dex-indexer-sol/internal/config"
+	"dex-indexer-sol/internal/consts"
+	"dex-indexer-sol/internal/pkg/testfixture"
+	"dex-indexer-sol/internal/pkg/types"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 主网 USDC mint 的 authority
+var (
+	usdcMintAuthority   = types.PubkeyFromBase58("BJE5MMbqXjVwjAF7oxwPYXnTXDyspzZyt4vwenNw5ruG")
+	usdcFreezeAuthority = types.PubkeyFromBase58("7dGbd2QZcCKcTndnHcTL8q7SMVXAkp688NTQYwB7Ff4")
+)
+
+// mintAccountData 按 SPL Token Mint 布局构造 82 字节账户数据，authority 为零值时 COption 为 None
+func mintAccountData(mintAuthority types.Pubkey, supply uint64, decimals uint8, freezeAuthority types.Pubkey) []byte {
+	data := make([]byte, mintAccountSize)
+	if mintAuthority != (types.Pubkey{}) {
+		binary.LittleEndian.PutUint32(data[0:4], 1)
+		copy(data[4:36], mintAuthority[:])
+	}
+	binary.LittleEndian.PutUint64(data[36:44], supply)
+	data[44] = decimals
+	data[45] = 1
+	if freezeAuthority != (types.Pubkey{}) {
+		binary.LittleEndian.PutUint32(data[46:50], 1)
+		copy(data[50:82], freezeAuthority[:])
+	}
+	return data
+}
+
+type testAccount struct {
+	owner types.Pubkey
+	data  []byte
+}
+
+// newTestAccountRpc 模拟 getMultipleAccounts，未登记的账户返回 null
+func newTestAccountRpc(t *testing.T, accounts map[string]testAccount) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+			Params []json.RawMessage
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "getMultipleAccounts", req.Method)
+		var addrs []string
+		require.NoError(t, json.Unmarshal(req.Params[0], &addrs))
+
+		values := make([]any, len(addrs))
+		for i, addr := range addrs {
+			if account, ok := accounts[addr]; ok {
+				values[i] = map[string]any{
+					"data":       []string{base64.StdEncoding.EncodeToString(account.data), "base64"},
+					"owner":      account.owner.String(),
+					"lamports":   1461600,
+					"executable": false,
+					"rentEpoch":  0,
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0", "id": req.ID,
+			"result": map[string]any{"context": map[string]any{"slot": 1}, "value": values},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestParseMintAccount(t *testing.T) {
+	info, ok := parseMintAccount(consts.TokenProgram, mintAccountData(usdcMintAuthority, 9_000_000_000_000_000, 6, usdcFreezeAuthority))
+	require.True(t, ok)
+	assert.Equal(t, cache.MintInfo{
+		Decimals:        6,
+		TokenProgram:    consts.TokenProgram,
+		Supply:          9_000_000_000_000_000,
+		MintAuthority:   usdcMintAuthority,
+		FreezeAuthority: usdcFreezeAuthority,
+		HasAccount:      true,
+	}, info)
+
+	// 放弃 authority 的 mint
+	info, ok = parseMintAccount(consts.TokenProgram, mintAccountData(types.Pubkey{}, 1, 9, types.Pubkey{}))
+	require.True(t, ok)
+	assert.Zero(t, info.MintAuthority)
+	assert.Zero(t, info.FreezeAuthority)
+}
+
+func TestParseMintAccount_NotMint(t *testing.T) {
+	data := mintAccountData(usdcMintAuthority, 1, 6, types.Pubkey{})
+	_, ok := parseMintAccount(consts.SystemProgram, data)
+	assert.False(t, ok, "owner 不是 Token Program")
+
+	uninitialized := append([]byte{}, data...)
+	uninitialized[45] = 0
+	_, ok = parseMintAccount(consts.TokenProgram, uninitialized)
+	assert.False(t, ok)
+
+	_, ok = parseMintAccount(consts.TokenProgram, data[:81])
+	assert.False(t, ok)
+
+	// token 账户（165 字节）同属 Token Program，[45] 位于 owner 中通常非 0
+	tokenAccount := make([]byte, 165)
+	copy(tokenAccount, testfixture.Bytes("mint:a"))
+	copy(tokenAccount[32:], testfixture.Bytes("mint:owner"))
+	tokenAccount[45] = 1
+	_, ok = parseMintAccount(consts.TokenProgram, tokenAccount)
+	assert.False(t, ok)
+
+	// Token-2022 带扩展的 token 账户：AccountType = Account
+	tokenAccount2022 := append(tokenAccount, 2, 0, 0, 0, 0)
+	_, ok = parseMintAccount(consts.TokenProgram2022, tokenAccount2022)
+	assert.False(t, ok)
+}
+
+func TestMintRegistryService_FetchMints(t *testing.T) {
+	usdc, burnt, wallet, missing := consts.USDCMint, testfixture.Key("mint:burnt"), testfixture.Key("mint:wallet"), testfixture.Key("mint:missing")
+	endpoint := newTestAccountRpc(t, map[string]testAccount{
+		usdc.String():   {consts.TokenProgram, mintAccountData(usdcMintAuthority, 100, 6, usdcFreezeAuthority)},
+		burnt.String():  {consts.TokenProgram, mintAccountData(types.Pubkey{}, 5, 9, types.Pubkey{})},
+		wallet.String(): {consts.SystemProgram, nil},
+	})
+
+	registry := cache.NewMintRegistry()
+	registry.Configure(0, true, 0)
+	s := &MintRegistryService{registry: registry, client: client.NewClient(endpoint), ctx: context.Background()}
+
+	for _, mint := range []types.Pubkey{usdc, burnt, wallet, missing} {
+		_, ok := registry.Decimals(mint)
+		require.False(t, ok)
+	}
+	s.warmPending()
+
+	info, ok := registry.Get(usdc)
+	require.True(t, ok)
+	assert.Equal(t, usdcMintAuthority, info.MintAuthority)
+	assert.Equal(t, uint64(100), info.Supply)
+	decimals, ok := registry.Decimals(burnt)
+	require.True(t, ok)
+	assert.Equal(t, uint8(9), decimals)
+
+	// 不是 mint 的账户缓存期内不再查询
+	registry.Decimals(wallet)
+	registry.Decimals(missing)
+	assert.Empty(t, registry.TakePending(10))
+}
+
+func TestMintRegistryService_DiskStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mints.bin")
+	mint := testfixture.Key("mint:service_disk")
+	cfg := &config.MintRegistryConfig{Store: "disk", Path: path}
+
+	s, err := NewMintRegistryService(cfg, "", "")
+	require.NoError(t, err)
+	go s.Start()
+	cache.Mints().ObserveDecimals(mint, 7, consts.TokenProgram)
+	s.Stop() // 停止时写入剩余的变更
+
+	store, err := cache.NewDiskMintStore(path)
+	require.NoError(t, err)
+	mints, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+	require.Contains(t, mints, mint)
+	assert.Equal(t, uint8(7), mints[mint].Decimals)
+}
+
+func TestNewMintRegistryService_InvalidConfig(t *testing.T) {
+	_, err := NewMintRegistryService(&config.MintRegistryConfig{Store: "disk"}, "", "")
+	assert.ErrorContains(t, err, "path")
+	_, err = NewMintRegistryService(&config.MintRegistryConfig{Store: "s3"}, "", "")
+	assert.ErrorContains(t, err, "不支持的 store")
+}
+
+// token2022MintData 按 Token-2022 扩展布局构造 mint 账户数据：Mint(82) + 填充至 165 + AccountType + TLV 扩展
+func token2022MintData(base []byte, extensions ...[]byte) []byte {
+	data := make([]byte, token2022AccountTypeOffset, 256)
+	copy(data, base)
+	data = append(data, token2022AccountTypeMint)
+	for _, ext := range extensions {
+		data = append(data, ext...)
+	}
+	return data
+}
+
+func tlvExtension(extType uint16, value []byte) []byte {
+	ext := binary.LittleEndian.AppendUint16(nil, extType)
+	ext = binary.LittleEndian.AppendUint16(ext, uint16(len(value)))
+	return append(ext, value...)
+}
+
+func transferFeeConfigValue(older, newer cache.TransferFee) []byte {
+	value := make([]byte, 72, transferFeeConfigSize)
+	copy(value[0:32], testfixture.Bytes("mint:fee_authority"))
+	copy(value[32:64], testfixture.Bytes("mint:withdraw_authority"))
+	binary.LittleEndian.PutUint64(value[64:72], 123) // withheld_amount
+	for _, fee := range []cache.TransferFee{older, newer} {
+		value = binary.LittleEndian.AppendUint64(value, fee.Epoch)
+		value = binary.LittleEndian.AppendUint64(value, fee.MaximumFee)
+		value = binary.LittleEndian.AppendUint16(value, fee.BasisPoints)
+	}
+	return value
+}
+
+func TestParseMintAccount_TransferFeeConfig(t *testing.T) {
+	older := cache.TransferFee{Epoch: 600, MaximumFee: 5_000_000, BasisPoints: 100}
+	newer := cache.TransferFee{Epoch: 702, MaximumFee: 9_000_000, BasisPoints: 250}
+	base := mintAccountData(usdcMintAuthority, 1_000, 6, types.Pubkey{})
+
+	// TransferFeeConfig 前有其它扩展（MetadataPointer = 18）
+	data := token2022MintData(base,
+		tlvExtension(18, make([]byte, 64)),
+		tlvExtension(extensionTransferFeeConfig, transferFeeConfigValue(older, newer)))
+	info, ok := parseMintAccount(consts.TokenProgram2022, data)
+	require.True(t, ok)
+	assert.Equal(t, uint8(6), info.Decimals)
+	assert.Equal(t, consts.TokenProgram2022, info.TokenProgram)
+	assert.True(t, info.HasTransferFee)
+	assert.Equal(t, older, info.OlderTransferFee)
+	assert.Equal(t, newer, info.NewerTransferFee)
+	assert.Equal(t, uint64(10), info.TransferFeeAt(701, 1_000))
+	assert.Equal(t, uint64(25), info.TransferFeeAt(702, 1_000))
+
+	// 没有 TransferFeeConfig 扩展
+	info, ok = parseMintAccount(consts.TokenProgram2022, token2022MintData(base, tlvExtension(18, make([]byte, 64))))
+	require.True(t, ok)
+	assert.False(t, info.HasTransferFee)
+
+	// 扩展长度不足或 TLV 被截断时不解析费率
+	short := transferFeeConfigValue(older, newer)[:transferFeeConfigSize-1]
+	info, ok = parseMintAccount(consts.TokenProgram2022, token2022MintData(base, tlvExtension(extensionTransferFeeConfig, short)))
+	require.True(t, ok)
+	assert.False(t, info.HasTransferFee)
+	truncated := token2022MintData(base, tlvExtension(extensionTransferFeeConfig, transferFeeConfigValue(older, newer)))
+	info, ok = parseMintAccount(consts.TokenProgram2022, truncated[:len(truncated)-1])
+	require.True(t, ok)
+	assert.False(t, info.HasTransferFee)
+}
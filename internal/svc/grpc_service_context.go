@@ -1,12 +1,17 @@
 package svc
 
 import (
+	"context"
+	"database/sql"
 	"dex-indexer-sol/internal/cache"
 	"dex-indexer-sol/internal/config"
+	"dex-indexer-sol/internal/logic/core"
 	"dex-indexer-sol/internal/logic/progress"
 	"dex-indexer-sol/internal/pkg/logger"
 	"dex-indexer-sol/internal/pkg/mq"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/redis/go-redis/v9"
+	"time"
 )
 
 // GrpcServiceContext 包含GRPC服务资源
@@ -26,42 +31,77 @@ func NewGrpcServiceContext(c config.GrpcConfig) (*GrpcServiceContext, error) {
 		return nil, err
 	}
 
-	//// 2. 初始化 Redis 客户端（用于 slot 状态缓存）
-	//rdb := redis.NewClient(&redis.Options{
-	//	Addr: c.RedisAddr, // eg: "127.0.0.1:6379"
-	//	// 可按需添加密码/数据库等参数
-	//})
-	//
-	//// 3. 初始化 PostgreSQL 数据库连接（用于 slot 落库）
-	//db, err := sql.Open("postgres", c.PostgresDSN)
-	//if err != nil {
-	//	logger.Errorf("PostgreSQL 连接失败: %v", err)
-	//	return nil, err
-	//}
-
-	// 4. 判定"近期 block"的时间阈值（默认 60 秒）
-	threshold := c.ProgressConf.RecentThresholdSec
-	if threshold <= 0 {
-		threshold = 60
+	// 2. 初始化进度管理器（Redis 判重 + DB 持久化）
+	pm, err := newProgressManager(c)
+	if err != nil {
+		producer.Close()
+		return nil, err
 	}
 
-	// 5. 初始化进度管理器（Redis + DB + 缓冲）
-	//redisStore := progress.NewRedisProgressStore(rdb)
-	//dbStore := progress.NewDBProgressStore(db)
-	//pm := progress.NewProgressManager(nil, nil, threshold)
+	// 设置 epoch 长度，用于计算 Token-2022 转账费率的生效 epoch
+	core.SetSlotsPerEpoch(c.SlotsPerEpoch)
 
-	// 6. 构造上下文
+	// 3. 构造上下文
 	ctx := &GrpcServiceContext{
 		Config:          c,
 		PriceCache:      cache.NewPriceCache(),
 		Producer:        producer,
-		ProgressManager: nil,
+		ProgressManager: pm,
 	}
 
 	logger.Infof("GRPC 服务上下文初始化完成")
 	return ctx, nil
 }
 
+// newProgressManager 按配置创建进度管理器：未配置 redis_addr 时返回 nil（不判重也不写进度），
+// 未配置 postgres_dsn 时只使用 Redis
+func newProgressManager(c config.GrpcConfig) (*progress.ProgressManager, error) {
+	if c.RedisAddr == "" {
+		logger.Warnf("未配置 redis_addr，进度管理已禁用：不跳过已处理的 slot，也不记录处理进度")
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 1. Redis（slot 状态判重）
+	rdb := redis.NewClient(&redis.Options{Addr: c.RedisAddr})
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		_ = rdb.Close()
+		logger.Errorf("Redis 连接失败: %v", err)
+		return nil, err
+	}
+	ttlHours := c.ProgressConf.RedisTTLHours
+	if ttlHours <= 0 {
+		ttlHours = 24
+	}
+	redisStore := progress.NewRedisProgressStore(rdb, time.Duration(ttlHours)*time.Hour)
+
+	// 2. PostgreSQL（slot 落库，可选）
+	var dbStore *progress.DBProgressStore
+	if c.PostgresDSN != "" {
+		db, err := sql.Open("postgres", c.PostgresDSN)
+		if err == nil {
+			err = db.PingContext(ctx)
+		}
+		if err != nil {
+			_ = rdb.Close()
+			logger.Errorf("PostgreSQL 连接失败: %v", err)
+			return nil, err
+		}
+		dbStore = progress.NewDBProgressStore(db)
+	} else {
+		logger.Warnf("未配置 postgres_dsn，进度只写 Redis（保留 %d 小时）", ttlHours)
+	}
+
+	// 3. 判定"近期 block"的时间阈值（默认 60 秒）
+	threshold := c.ProgressConf.RecentThresholdSec
+	if threshold <= 0 {
+		threshold = 60
+	}
+	return progress.NewProgressManager(redisStore, dbStore, threshold), nil
+}
+
 // Close 关闭服务上下文中的资源
 func (ctx *GrpcServiceContext) Close() {
 	if ctx.Producer != nil {
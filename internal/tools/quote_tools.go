@@ -6,6 +6,7 @@ import (
 )
 
 const (
+	SOLDecimals  = 9 // 原生 SOL（lamports）精度
 	WSOLDecimals = 9
 	USDCDecimals = 6
 	USDTDecimals = 6
@@ -24,37 +24,52 @@ const (
 type DexType int32
 
 const (
-	DexType_DEX_UNKNOWN        DexType = 0 // 未知 DEX 类型
-	DexType_DEX_RAYDIUM_V4     DexType = 1 // Raydium V4（限价订单簿 + AMM）
-	DexType_DEX_RAYDIUM_CLMM   DexType = 2 // Raydium CLMM（集中流动性）
-	DexType_DEX_PUMPFUN_AMM    DexType = 3 // Pump.fun AMM 外盘
-	DexType_DEX_PUMPFUN        DexType = 4 // Pump.fun 内盘（伪撮合）
-	DexType_DEX_RAYDIUM_CPMM   DexType = 5 // Raydium CPMM
-	DexType_DEX_METEORA_DLMM   DexType = 6 // Meteora DLMM
-	DexType_DEX_ORCA_WHIRLPOOL DexType = 7 // Orca Whirlpool（集中流动性）
+	DexType_DEX_UNKNOWN           DexType = 0  // 未知 DEX 类型
+	DexType_DEX_RAYDIUM_V4        DexType = 1  // Raydium V4（限价订单簿 + AMM）
+	DexType_DEX_RAYDIUM_CLMM      DexType = 2  // Raydium CLMM（集中流动性）
+	DexType_DEX_PUMPFUN_AMM       DexType = 3  // Pump.fun AMM 外盘
+	DexType_DEX_PUMPFUN           DexType = 4  // Pump.fun 内盘（伪撮合）
+	DexType_DEX_RAYDIUM_CPMM      DexType = 5  // Raydium CPMM
+	DexType_DEX_METEORA_DLMM      DexType = 6  // Meteora DLMM
+	DexType_DEX_ORCA_WHIRLPOOL    DexType = 7  // Orca Whirlpool（集中流动性）
+	DexType_DEX_METEORA_DAMM      DexType = 8  // Meteora DAMM（Dynamic AMM v1 与 DAMM v2）
+	DexType_DEX_RAYDIUM_LAUNCHLAB DexType = 9  // Raydium LaunchLab 内盘（bonding curve）
+	DexType_DEX_METEORA_DBC       DexType = 10 // Meteora Dynamic Bonding Curve 内盘，毕业后迁移至 DAMM
+	DexType_DEX_OPENBOOK_V2       DexType = 11 // OpenBook v2（订单簿）
+	DexType_DEX_PHOENIX           DexType = 12 // Phoenix（订单簿）
 )
 
 // Enum value maps for DexType.
 var (
 	DexType_name = map[int32]string{
-		0: "DEX_UNKNOWN",
-		1: "DEX_RAYDIUM_V4",
-		2: "DEX_RAYDIUM_CLMM",
-		3: "DEX_PUMPFUN_AMM",
-		4: "DEX_PUMPFUN",
-		5: "DEX_RAYDIUM_CPMM",
-		6: "DEX_METEORA_DLMM",
-		7: "DEX_ORCA_WHIRLPOOL",
+		0:  "DEX_UNKNOWN",
+		1:  "DEX_RAYDIUM_V4",
+		2:  "DEX_RAYDIUM_CLMM",
+		3:  "DEX_PUMPFUN_AMM",
+		4:  "DEX_PUMPFUN",
+		5:  "DEX_RAYDIUM_CPMM",
+		6:  "DEX_METEORA_DLMM",
+		7:  "DEX_ORCA_WHIRLPOOL",
+		8:  "DEX_METEORA_DAMM",
+		9:  "DEX_RAYDIUM_LAUNCHLAB",
+		10: "DEX_METEORA_DBC",
+		11: "DEX_OPENBOOK_V2",
+		12: "DEX_PHOENIX",
 	}
 	DexType_value = map[string]int32{
-		"DEX_UNKNOWN":        0,
-		"DEX_RAYDIUM_V4":     1,
-		"DEX_RAYDIUM_CLMM":   2,
-		"DEX_PUMPFUN_AMM":    3,
-		"DEX_PUMPFUN":        4,
-		"DEX_RAYDIUM_CPMM":   5,
-		"DEX_METEORA_DLMM":   6,
-		"DEX_ORCA_WHIRLPOOL": 7,
+		"DEX_UNKNOWN":           0,
+		"DEX_RAYDIUM_V4":        1,
+		"DEX_RAYDIUM_CLMM":      2,
+		"DEX_PUMPFUN_AMM":       3,
+		"DEX_PUMPFUN":           4,
+		"DEX_RAYDIUM_CPMM":      5,
+		"DEX_METEORA_DLMM":      6,
+		"DEX_ORCA_WHIRLPOOL":    7,
+		"DEX_METEORA_DAMM":      8,
+		"DEX_RAYDIUM_LAUNCHLAB": 9,
+		"DEX_METEORA_DBC":       10,
+		"DEX_OPENBOOK_V2":       11,
+		"DEX_PHOENIX":           12,
 	}
 )
 
@@ -151,8 +166,15 @@ const (
 	EventType_CREATE_POOL      EventType = 9
 	EventType_MIGRATE          EventType = 10
 	EventType_LAUNCHPAD_TOKEN  EventType = 11
+	EventType_FAILED_TRADE     EventType = 12 // 执行失败的交易（需开启 failed_tx.enable）
+	EventType_ACCOUNT_CREATE   EventType = 13 // System Program 创建账户（CreateAccount / CreateAccountWithSeed）
+	EventType_ACCOUNT_CLOSE    EventType = 14 // nonce 账户的 SOL 被全部提取（WithdrawNonceAccount 后账户被回收）
+	EventType_ROUTE_SWAP       EventType = 15 // 聚合器（Jupiter）路由兑换，各 hop 的 TradeEvent 通过 parent_event_id 关联
+	EventType_ORDERBOOK_FILL   EventType = 16 // 订单簿 DEX 限价单穿价成交（无转账可还原成交总额，仅含逐笔成交 lots）
 	// --- 系统/同步类事件（编号从 60 开始） ---
 	EventType_BALANCE_UPDATE EventType = 60
+	EventType_SLOT_ROLLBACK  EventType = 61 // slot 回滚（分叉导致已下发的 slot 被孤立）
+	EventType_SLOT_FINALIZED EventType = 62 // slot 已 finalized（已下发的事件不会再回滚）
 )
 
 // Enum value maps for EventType.
@@ -170,7 +192,14 @@ var (
 		9:  "CREATE_POOL",
 		10: "MIGRATE",
 		11: "LAUNCHPAD_TOKEN",
+		12: "FAILED_TRADE",
+		13: "ACCOUNT_CREATE",
+		14: "ACCOUNT_CLOSE",
+		15: "ROUTE_SWAP",
+		16: "ORDERBOOK_FILL",
 		60: "BALANCE_UPDATE",
+		61: "SLOT_ROLLBACK",
+		62: "SLOT_FINALIZED",
 	}
 	EventType_value = map[string]int32{
 		"UNKNOWN":          0,
@@ -185,7 +214,14 @@ var (
 		"CREATE_POOL":      9,
 		"MIGRATE":          10,
 		"LAUNCHPAD_TOKEN":  11,
+		"FAILED_TRADE":     12,
+		"ACCOUNT_CREATE":   13,
+		"ACCOUNT_CLOSE":    14,
+		"ROUTE_SWAP":       15,
+		"ORDERBOOK_FILL":   16,
 		"BALANCE_UPDATE":   60,
+		"SLOT_ROLLBACK":    61,
+		"SLOT_FINALIZED":   62,
 	}
 )
 
@@ -216,6 +252,59 @@ func (EventType) EnumDescriptor() ([]byte, []int) {
 	return file_event_proto_rawDescGZIP(), []int{2}
 }
 
+// slot 回滚原因
+type RollbackReason int32
+
+const (
+	RollbackReason_ROLLBACK_UNKNOWN         RollbackReason = 0
+	RollbackReason_ROLLBACK_PARENT_MISMATCH RollbackReason = 1 // 后续区块的父链跳过了该 slot（或父哈希不一致）
+	RollbackReason_ROLLBACK_NOT_FINALIZED   RollbackReason = 2 // finalized 链上不包含该 slot
+	RollbackReason_ROLLBACK_DEAD_SLOT       RollbackReason = 3 // 服务端通知该 slot 为 dead
+)
+
+// Enum value maps for RollbackReason.
+var (
+	RollbackReason_name = map[int32]string{
+		0: "ROLLBACK_UNKNOWN",
+		1: "ROLLBACK_PARENT_MISMATCH",
+		2: "ROLLBACK_NOT_FINALIZED",
+		3: "ROLLBACK_DEAD_SLOT",
+	}
+	RollbackReason_value = map[string]int32{
+		"ROLLBACK_UNKNOWN":         0,
+		"ROLLBACK_PARENT_MISMATCH": 1,
+		"ROLLBACK_NOT_FINALIZED":   2,
+		"ROLLBACK_DEAD_SLOT":       3,
+	}
+)
+
+func (x RollbackReason) Enum() *RollbackReason {
+	p := new(RollbackReason)
+	*p = x
+	return p
+}
+
+func (x RollbackReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RollbackReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_event_proto_enumTypes[3].Descriptor()
+}
+
+func (RollbackReason) Type() protoreflect.EnumType {
+	return &file_event_proto_enumTypes[3]
+}
+
+func (x RollbackReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RollbackReason.Descriptor instead.
+func (RollbackReason) EnumDescriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{3}
+}
+
 // slot级别的事件数组（封装一个 slot 的全部事件）
 type Events struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -383,6 +472,12 @@ type Event struct {
 	//	*Event_Balance
 	//	*Event_Migrate
 	//	*Event_Token
+	//	*Event_Rollback
+	//	*Event_Finalized
+	//	*Event_FailedTrade
+	//	*Event_Account
+	//	*Event_RouteSwap
+	//	*Event_OrderbookFill
 	Event         isEvent_Event `protobuf_oneof:"event"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -497,6 +592,60 @@ func (x *Event) GetToken() *LaunchpadTokenEvent {
 	return nil
 }
 
+func (x *Event) GetRollback() *SlotRollbackEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Rollback); ok {
+			return x.Rollback
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetFinalized() *SlotFinalizedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Finalized); ok {
+			return x.Finalized
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetFailedTrade() *FailedTradeEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_FailedTrade); ok {
+			return x.FailedTrade
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetAccount() *AccountEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Account); ok {
+			return x.Account
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetRouteSwap() *RouteSwapEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_RouteSwap); ok {
+			return x.RouteSwap
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetOrderbookFill() *OrderbookFillEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_OrderbookFill); ok {
+			return x.OrderbookFill
+		}
+	}
+	return nil
+}
+
 type isEvent_Event interface {
 	isEvent_Event()
 }
@@ -533,6 +682,30 @@ type Event_Token struct {
 	Token *LaunchpadTokenEvent `protobuf:"bytes,8,opt,name=token,proto3,oneof"`
 }
 
+type Event_Rollback struct {
+	Rollback *SlotRollbackEvent `protobuf:"bytes,9,opt,name=rollback,proto3,oneof"`
+}
+
+type Event_Finalized struct {
+	Finalized *SlotFinalizedEvent `protobuf:"bytes,10,opt,name=finalized,proto3,oneof"`
+}
+
+type Event_FailedTrade struct {
+	FailedTrade *FailedTradeEvent `protobuf:"bytes,11,opt,name=failed_trade,json=failedTrade,proto3,oneof"`
+}
+
+type Event_Account struct {
+	Account *AccountEvent `protobuf:"bytes,12,opt,name=account,proto3,oneof"`
+}
+
+type Event_RouteSwap struct {
+	RouteSwap *RouteSwapEvent `protobuf:"bytes,13,opt,name=route_swap,json=routeSwap,proto3,oneof"`
+}
+
+type Event_OrderbookFill struct {
+	OrderbookFill *OrderbookFillEvent `protobuf:"bytes,14,opt,name=orderbook_fill,json=orderbookFill,proto3,oneof"`
+}
+
 func (*Event_Trade) isEvent_Event() {}
 
 func (*Event_Transfer) isEvent_Event() {}
@@ -549,6 +722,111 @@ func (*Event_Migrate) isEvent_Event() {}
 
 func (*Event_Token) isEvent_Event() {}
 
+func (*Event_Rollback) isEvent_Event() {}
+
+func (*Event_Finalized) isEvent_Event() {}
+
+func (*Event_FailedTrade) isEvent_Event() {}
+
+func (*Event_Account) isEvent_Event() {}
+
+func (*Event_RouteSwap) isEvent_Event() {}
+
+func (*Event_OrderbookFill) isEvent_Event() {}
+
+// 交易手续费与计算单元信息（同一交易内的所有事件相同）
+type TxFee struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Fee                  uint64                 `protobuf:"varint,1,opt,name=fee,proto3" json:"fee,omitempty"`                                                                 // 交易总手续费（lamports）= base_fee + priority_fee
+	BaseFee              uint64                 `protobuf:"varint,2,opt,name=base_fee,json=baseFee,proto3" json:"base_fee,omitempty"`                                          // 基础手续费（lamports，按签名数收取）
+	PriorityFee          uint64                 `protobuf:"varint,3,opt,name=priority_fee,json=priorityFee,proto3" json:"priority_fee,omitempty"`                              // 优先费（lamports）= compute_unit_price × compute_unit_limit / 1e6
+	ComputeUnitPrice     uint64                 `protobuf:"varint,4,opt,name=compute_unit_price,json=computeUnitPrice,proto3" json:"compute_unit_price,omitempty"`             // CU 单价（micro-lamports），未设置为 0
+	ComputeUnitLimit     uint32                 `protobuf:"varint,5,opt,name=compute_unit_limit,json=computeUnitLimit,proto3" json:"compute_unit_limit,omitempty"`             // CU 上限（未设置时为运行时默认值）
+	ComputeUnitsConsumed uint64                 `protobuf:"varint,6,opt,name=compute_units_consumed,json=computeUnitsConsumed,proto3" json:"compute_units_consumed,omitempty"` // 实际消耗的 CU
+	JitoTip              uint64                 `protobuf:"varint,7,opt,name=jito_tip,json=jitoTip,proto3" json:"jito_tip,omitempty"`                                          // 转入 Jito tip 账户的 SOL（lamports）
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *TxFee) Reset() {
+	*x = TxFee{}
+	mi := &file_event_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxFee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxFee) ProtoMessage() {}
+
+func (x *TxFee) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxFee.ProtoReflect.Descriptor instead.
+func (*TxFee) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TxFee) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *TxFee) GetBaseFee() uint64 {
+	if x != nil {
+		return x.BaseFee
+	}
+	return 0
+}
+
+func (x *TxFee) GetPriorityFee() uint64 {
+	if x != nil {
+		return x.PriorityFee
+	}
+	return 0
+}
+
+func (x *TxFee) GetComputeUnitPrice() uint64 {
+	if x != nil {
+		return x.ComputeUnitPrice
+	}
+	return 0
+}
+
+func (x *TxFee) GetComputeUnitLimit() uint32 {
+	if x != nil {
+		return x.ComputeUnitLimit
+	}
+	return 0
+}
+
+func (x *TxFee) GetComputeUnitsConsumed() uint64 {
+	if x != nil {
+		return x.ComputeUnitsConsumed
+	}
+	return 0
+}
+
+func (x *TxFee) GetJitoTip() uint64 {
+	if x != nil {
+		return x.JitoTip
+	}
+	return 0
+}
+
 // 交易事件（token统一表示base token）
 type TradeEvent struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
@@ -575,13 +853,22 @@ type TradeEvent struct {
 	PairQuoteBalance  uint64                 `protobuf:"varint,21,opt,name=pair_quote_balance,json=pairQuoteBalance,proto3" json:"pair_quote_balance,omitempty"`   // 交易后池子quote token余额
 	UserTokenBalance  uint64                 `protobuf:"varint,22,opt,name=user_token_balance,json=userTokenBalance,proto3" json:"user_token_balance,omitempty"`   // 交易后用户base token余额
 	UserQuoteBalance  uint64                 `protobuf:"varint,23,opt,name=user_quote_balance,json=userQuoteBalance,proto3" json:"user_quote_balance,omitempty"`   // 交易后用户quote token余额
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	TxFee             *TxFee                 `protobuf:"bytes,24,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`                                       // 所属交易的手续费信息
+	// Token-2022 转账手续费：token_amount / quote_token_amount 为转出方转出的总额（gross），
+	// 以下为扣除转账手续费后接收方实际到账的数量（net），无手续费时与 gross 相同
+	TokenAmountNet      uint64 `protobuf:"varint,25,opt,name=token_amount_net,json=tokenAmountNet,proto3" json:"token_amount_net,omitempty"`
+	QuoteTokenAmountNet uint64 `protobuf:"varint,26,opt,name=quote_token_amount_net,json=quoteTokenAmountNet,proto3" json:"quote_token_amount_net,omitempty"`
+	ParentEventId       uint64 `protobuf:"varint,27,opt,name=parent_event_id,json=parentEventId,proto3" json:"parent_event_id,omitempty"` // 所属聚合器路由的 RouteSwapEvent.event_id，非路由内的交易为 0
+	// 订单簿 DEX（OpenBook v2 / Phoenix）：user_wallet 为 taker，type 为 taker 方向，
+	// 以下为 taker 订单与各 maker 订单的逐笔成交明细，AMM 交易为空
+	Fills         []*OrderbookFill `protobuf:"bytes,28,rep,name=fills,proto3" json:"fills,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TradeEvent) Reset() {
 	*x = TradeEvent{}
-	mi := &file_event_proto_msgTypes[3]
+	mi := &file_event_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -593,7 +880,7 @@ func (x *TradeEvent) String() string {
 func (*TradeEvent) ProtoMessage() {}
 
 func (x *TradeEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[3]
+	mi := &file_event_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -606,7 +893,7 @@ func (x *TradeEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TradeEvent.ProtoReflect.Descriptor instead.
 func (*TradeEvent) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{3}
+	return file_event_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *TradeEvent) GetType() EventType {
@@ -770,43 +1057,70 @@ func (x *TradeEvent) GetUserQuoteBalance() uint64 {
 	return 0
 }
 
-// 转账事件
-type TransferEvent struct {
+func (x *TradeEvent) GetTxFee() *TxFee {
+	if x != nil {
+		return x.TxFee
+	}
+	return nil
+}
+
+func (x *TradeEvent) GetTokenAmountNet() uint64 {
+	if x != nil {
+		return x.TokenAmountNet
+	}
+	return 0
+}
+
+func (x *TradeEvent) GetQuoteTokenAmountNet() uint64 {
+	if x != nil {
+		return x.QuoteTokenAmountNet
+	}
+	return 0
+}
+
+func (x *TradeEvent) GetParentEventId() uint64 {
+	if x != nil {
+		return x.ParentEventId
+	}
+	return 0
+}
+
+func (x *TradeEvent) GetFills() []*OrderbookFill {
+	if x != nil {
+		return x.Fills
+	}
+	return nil
+}
+
+// 订单簿 DEX 中 taker 订单与单个 maker 订单的一次成交
+type OrderbookFill struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
-	Type             EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`
-	EventId          uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"` // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
-	Slot             uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`
-	BlockTime        int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`
-	TxHash           []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
-	Signers          [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                               // 签名者地址列表（通常为交易的发起者们）
-	Token            []byte                 `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`                                                   // 被转移token的mint地址
-	SrcAccount       []byte                 `protobuf:"bytes,8,opt,name=src_account,json=srcAccount,proto3" json:"src_account,omitempty"`                       // 来源 token 账户地址
-	DestAccount      []byte                 `protobuf:"bytes,9,opt,name=dest_account,json=destAccount,proto3" json:"dest_account,omitempty"`                    // 目标 token 账户地址
-	SrcWallet        []byte                 `protobuf:"bytes,10,opt,name=src_wallet,json=srcWallet,proto3" json:"src_wallet,omitempty"`                         // 来源钱包地址（为src_account的owner）
-	DestWallet       []byte                 `protobuf:"bytes,11,opt,name=dest_wallet,json=destWallet,proto3" json:"dest_wallet,omitempty"`                      // 目标钱包地址（为dest_account的owner）
-	Amount           uint64                 `protobuf:"varint,12,opt,name=amount,proto3" json:"amount,omitempty"`                                               // 转账数量（原生单位）
-	Decimals         uint32                 `protobuf:"varint,13,opt,name=decimals,proto3" json:"decimals,omitempty"`                                           // token 精度
-	SrcTokenBalance  uint64                 `protobuf:"varint,14,opt,name=src_token_balance,json=srcTokenBalance,proto3" json:"src_token_balance,omitempty"`    // 转账后，来源账户余额
-	DestTokenBalance uint64                 `protobuf:"varint,15,opt,name=dest_token_balance,json=destTokenBalance,proto3" json:"dest_token_balance,omitempty"` // 转账后，目标账户余额
+	Maker            []byte                 `protobuf:"bytes,1,opt,name=maker,proto3" json:"maker,omitempty"`                                                  // maker 钱包（OpenBook v2 的成交日志只有 OpenOrders 账户，无法确定钱包时为空）
+	PriceInTicks     uint64                 `protobuf:"varint,2,opt,name=price_in_ticks,json=priceInTicks,proto3" json:"price_in_ticks,omitempty"`             // 成交价（Phoenix 为 ticks；OpenBook v2 为每 base lot 的 quote lots）
+	BaseLots         uint64                 `protobuf:"varint,3,opt,name=base_lots,json=baseLots,proto3" json:"base_lots,omitempty"`                           // 成交 base lots
+	QuoteLots        uint64                 `protobuf:"varint,4,opt,name=quote_lots,json=quoteLots,proto3" json:"quote_lots,omitempty"`                        // 成交 quote lots（不含 taker 手续费）
+	TokenAmount      uint64                 `protobuf:"varint,5,opt,name=token_amount,json=tokenAmount,proto3" json:"token_amount,omitempty"`                  // 按 lots 比例分摊的 base token 数量（原生最小单位），各笔之和等于 token_amount
+	QuoteTokenAmount uint64                 `protobuf:"varint,6,opt,name=quote_token_amount,json=quoteTokenAmount,proto3" json:"quote_token_amount,omitempty"` // 按 lots 比例分摊的 quote token 数量（含 taker 手续费），各笔之和等于 quote_token_amount
+	MakerOpenOrders  []byte                 `protobuf:"bytes,7,opt,name=maker_open_orders,json=makerOpenOrders,proto3" json:"maker_open_orders,omitempty"`     // maker 的 OpenOrders 账户（仅 OpenBook v2）
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
 
-func (x *TransferEvent) Reset() {
-	*x = TransferEvent{}
-	mi := &file_event_proto_msgTypes[4]
+func (x *OrderbookFill) Reset() {
+	*x = OrderbookFill{}
+	mi := &file_event_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TransferEvent) String() string {
+func (x *OrderbookFill) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransferEvent) ProtoMessage() {}
+func (*OrderbookFill) ProtoMessage() {}
 
-func (x *TransferEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[4]
+func (x *OrderbookFill) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -817,163 +1131,1047 @@ func (x *TransferEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TransferEvent.ProtoReflect.Descriptor instead.
-func (*TransferEvent) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use OrderbookFill.ProtoReflect.Descriptor instead.
+func (*OrderbookFill) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *TransferEvent) GetType() EventType {
+func (x *OrderbookFill) GetMaker() []byte {
 	if x != nil {
-		return x.Type
+		return x.Maker
 	}
-	return EventType_UNKNOWN
+	return nil
 }
 
-func (x *TransferEvent) GetEventId() uint64 {
+func (x *OrderbookFill) GetPriceInTicks() uint64 {
 	if x != nil {
-		return x.EventId
+		return x.PriceInTicks
 	}
 	return 0
 }
 
-func (x *TransferEvent) GetSlot() uint64 {
+func (x *OrderbookFill) GetBaseLots() uint64 {
 	if x != nil {
-		return x.Slot
+		return x.BaseLots
 	}
 	return 0
 }
 
-func (x *TransferEvent) GetBlockTime() int64 {
+func (x *OrderbookFill) GetQuoteLots() uint64 {
 	if x != nil {
-		return x.BlockTime
+		return x.QuoteLots
 	}
 	return 0
 }
 
-func (x *TransferEvent) GetTxHash() []byte {
+func (x *OrderbookFill) GetTokenAmount() uint64 {
 	if x != nil {
-		return x.TxHash
+		return x.TokenAmount
 	}
-	return nil
+	return 0
 }
 
-func (x *TransferEvent) GetSigners() [][]byte {
+func (x *OrderbookFill) GetQuoteTokenAmount() uint64 {
 	if x != nil {
-		return x.Signers
+		return x.QuoteTokenAmount
 	}
-	return nil
+	return 0
 }
 
-func (x *TransferEvent) GetToken() []byte {
+func (x *OrderbookFill) GetMakerOpenOrders() []byte {
 	if x != nil {
-		return x.Token
+		return x.MakerOpenOrders
 	}
 	return nil
 }
 
-func (x *TransferEvent) GetSrcAccount() []byte {
-	if x != nil {
-		return x.SrcAccount
-	}
-	return nil
+// 订单簿限价单成交事件：PlaceOrder（OpenBook v2）/ PlaceLimitOrder（Phoenix）穿价时与 maker 订单的成交。
+// 限价单的资金包含挂单部分或来自 OpenOrders / seat 中的存量资金，无法按转账还原成交总额，
+// 因此只输出日志中的逐笔成交（fills 的 token_amount / quote_token_amount 为 0）
+type OrderbookFillEvent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Type            EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                              // 事件类型（ORDERBOOK_FILL）
+	EventId         uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                           // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
+	Slot            uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`                                                // 区块 slot
+	BlockTime       int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`                     // 区块时间（Unix 秒）
+	TxHash          []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`                               // 交易哈希（64 字节）
+	Signers         [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                           // 签名者地址列表
+	Dex             uint32                 `protobuf:"varint,7,opt,name=dex,proto3" json:"dex,omitempty"`                                                  // 所属 DEX 平台编号
+	PairAddress     []byte                 `protobuf:"bytes,8,opt,name=pair_address,json=pairAddress,proto3" json:"pair_address,omitempty"`                // 市场地址
+	UserWallet      []byte                 `protobuf:"bytes,9,opt,name=user_wallet,json=userWallet,proto3" json:"user_wallet,omitempty"`                   // taker 钱包
+	TakerOpenOrders []byte                 `protobuf:"bytes,10,opt,name=taker_open_orders,json=takerOpenOrders,proto3" json:"taker_open_orders,omitempty"` // taker 的 OpenOrders 账户（仅 OpenBook v2）
+	Side            EventType              `protobuf:"varint,11,opt,name=side,proto3,enum=pb.EventType" json:"side,omitempty"`                             // taker 方向（TRADE_BUY 买入 base / TRADE_SELL 卖出 base）
+	Fills           []*OrderbookFill       `protobuf:"bytes,12,rep,name=fills,proto3" json:"fills,omitempty"`                                              // 按撮合顺序排列的逐笔成交
+	TxFee           *TxFee                 `protobuf:"bytes,13,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`                                 // 所属交易的手续费信息
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *TransferEvent) GetDestAccount() []byte {
-	if x != nil {
-		return x.DestAccount
-	}
-	return nil
+func (x *OrderbookFillEvent) Reset() {
+	*x = OrderbookFillEvent{}
+	mi := &file_event_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *TransferEvent) GetSrcWallet() []byte {
-	if x != nil {
-		return x.SrcWallet
-	}
-	return nil
+func (x *OrderbookFillEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *TransferEvent) GetDestWallet() []byte {
+func (*OrderbookFillEvent) ProtoMessage() {}
+
+func (x *OrderbookFillEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[6]
 	if x != nil {
-		return x.DestWallet
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderbookFillEvent.ProtoReflect.Descriptor instead.
+func (*OrderbookFillEvent) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *OrderbookFillEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_UNKNOWN
+}
+
+func (x *OrderbookFillEvent) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *OrderbookFillEvent) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *OrderbookFillEvent) GetBlockTime() int64 {
+	if x != nil {
+		return x.BlockTime
+	}
+	return 0
+}
+
+func (x *OrderbookFillEvent) GetTxHash() []byte {
+	if x != nil {
+		return x.TxHash
+	}
+	return nil
+}
+
+func (x *OrderbookFillEvent) GetSigners() [][]byte {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+func (x *OrderbookFillEvent) GetDex() uint32 {
+	if x != nil {
+		return x.Dex
+	}
+	return 0
+}
+
+func (x *OrderbookFillEvent) GetPairAddress() []byte {
+	if x != nil {
+		return x.PairAddress
+	}
+	return nil
+}
+
+func (x *OrderbookFillEvent) GetUserWallet() []byte {
+	if x != nil {
+		return x.UserWallet
+	}
+	return nil
+}
+
+func (x *OrderbookFillEvent) GetTakerOpenOrders() []byte {
+	if x != nil {
+		return x.TakerOpenOrders
+	}
+	return nil
+}
+
+func (x *OrderbookFillEvent) GetSide() EventType {
+	if x != nil {
+		return x.Side
+	}
+	return EventType_UNKNOWN
+}
+
+func (x *OrderbookFillEvent) GetFills() []*OrderbookFill {
+	if x != nil {
+		return x.Fills
+	}
+	return nil
+}
+
+func (x *OrderbookFillEvent) GetTxFee() *TxFee {
+	if x != nil {
+		return x.TxFee
+	}
+	return nil
+}
+
+// 失败交易事件：交易执行失败（如滑点超限），链上无实际成交，金额取自指令参数
+type FailedTradeEvent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Type            EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                               // 事件类型（FAILED_TRADE）
+	EventId         uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                            // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
+	Slot            uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`                                                 // 区块 slot
+	BlockTime       int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`                      // 区块时间（Unix 秒）
+	TxHash          []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`                                // 交易哈希（64 字节）
+	Signers         [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                            // 签名者地址列表
+	Dex             uint32                 `protobuf:"varint,7,opt,name=dex,proto3" json:"dex,omitempty"`                                                   // 所属 DEX 平台编号
+	PairAddress     []byte                 `protobuf:"bytes,8,opt,name=pair_address,json=pairAddress,proto3" json:"pair_address,omitempty"`                 // 意图交易的池子地址
+	UserWallet      []byte                 `protobuf:"bytes,9,opt,name=user_wallet,json=userWallet,proto3" json:"user_wallet,omitempty"`                    // 用户钱包地址
+	Side            EventType              `protobuf:"varint,10,opt,name=side,proto3,enum=pb.EventType" json:"side,omitempty"`                              // 交易方向（TRADE_BUY / TRADE_SELL，无法确定 quote 时为 TRADE_UNKNOWN）
+	InputToken      []byte                 `protobuf:"bytes,11,opt,name=input_token,json=inputToken,proto3" json:"input_token,omitempty"`                   // 支付的 token mint（无法确定时为空）
+	OutputToken     []byte                 `protobuf:"bytes,12,opt,name=output_token,json=outputToken,proto3" json:"output_token,omitempty"`                // 获得的 token mint（无法确定时为空）
+	AmountIn        uint64                 `protobuf:"varint,13,opt,name=amount_in,json=amountIn,proto3" json:"amount_in,omitempty"`                        // 指令参数中的输入数量（exact_out 时为最大输入数量）
+	AmountOut       uint64                 `protobuf:"varint,14,opt,name=amount_out,json=amountOut,proto3" json:"amount_out,omitempty"`                     // 指令参数中的输出数量（exact_in 时为最小输出数量）
+	ExactOut        bool                   `protobuf:"varint,15,opt,name=exact_out,json=exactOut,proto3" json:"exact_out,omitempty"`                        // true：指定输出数量；false：指定输入数量
+	Fee             uint64                 `protobuf:"varint,16,opt,name=fee,proto3" json:"fee,omitempty"`                                                  // 交易实际支付的手续费（lamports，失败交易同样扣除）
+	ErrorCode       string                 `protobuf:"bytes,17,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`                      // TransactionError 名称（如 InstructionError）
+	ErrorIxIndex    int32                  `protobuf:"varint,18,opt,name=error_ix_index,json=errorIxIndex,proto3" json:"error_ix_index,omitempty"`          // 出错的主指令索引，非指令错误时为 -1
+	ErrorIxCode     string                 `protobuf:"bytes,19,opt,name=error_ix_code,json=errorIxCode,proto3" json:"error_ix_code,omitempty"`              // InstructionError 名称（如 Custom），非指令错误时为空
+	CustomErrorCode uint32                 `protobuf:"varint,20,opt,name=custom_error_code,json=customErrorCode,proto3" json:"custom_error_code,omitempty"` // 程序自定义错误码（InstructionError::Custom）
+	TxFee           *TxFee                 `protobuf:"bytes,21,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`                                  // 所属交易的手续费信息
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FailedTradeEvent) Reset() {
+	*x = FailedTradeEvent{}
+	mi := &file_event_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailedTradeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailedTradeEvent) ProtoMessage() {}
+
+func (x *FailedTradeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailedTradeEvent.ProtoReflect.Descriptor instead.
+func (*FailedTradeEvent) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FailedTradeEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_UNKNOWN
+}
+
+func (x *FailedTradeEvent) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *FailedTradeEvent) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *FailedTradeEvent) GetBlockTime() int64 {
+	if x != nil {
+		return x.BlockTime
+	}
+	return 0
+}
+
+func (x *FailedTradeEvent) GetTxHash() []byte {
+	if x != nil {
+		return x.TxHash
+	}
+	return nil
+}
+
+func (x *FailedTradeEvent) GetSigners() [][]byte {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+func (x *FailedTradeEvent) GetDex() uint32 {
+	if x != nil {
+		return x.Dex
+	}
+	return 0
+}
+
+func (x *FailedTradeEvent) GetPairAddress() []byte {
+	if x != nil {
+		return x.PairAddress
+	}
+	return nil
+}
+
+func (x *FailedTradeEvent) GetUserWallet() []byte {
+	if x != nil {
+		return x.UserWallet
+	}
+	return nil
+}
+
+func (x *FailedTradeEvent) GetSide() EventType {
+	if x != nil {
+		return x.Side
+	}
+	return EventType_UNKNOWN
+}
+
+func (x *FailedTradeEvent) GetInputToken() []byte {
+	if x != nil {
+		return x.InputToken
+	}
+	return nil
+}
+
+func (x *FailedTradeEvent) GetOutputToken() []byte {
+	if x != nil {
+		return x.OutputToken
+	}
+	return nil
+}
+
+func (x *FailedTradeEvent) GetAmountIn() uint64 {
+	if x != nil {
+		return x.AmountIn
+	}
+	return 0
+}
+
+func (x *FailedTradeEvent) GetAmountOut() uint64 {
+	if x != nil {
+		return x.AmountOut
+	}
+	return 0
+}
+
+func (x *FailedTradeEvent) GetExactOut() bool {
+	if x != nil {
+		return x.ExactOut
+	}
+	return false
+}
+
+func (x *FailedTradeEvent) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *FailedTradeEvent) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *FailedTradeEvent) GetErrorIxIndex() int32 {
+	if x != nil {
+		return x.ErrorIxIndex
+	}
+	return 0
+}
+
+func (x *FailedTradeEvent) GetErrorIxCode() string {
+	if x != nil {
+		return x.ErrorIxCode
+	}
+	return ""
+}
+
+func (x *FailedTradeEvent) GetCustomErrorCode() uint32 {
+	if x != nil {
+		return x.CustomErrorCode
+	}
+	return 0
+}
+
+func (x *FailedTradeEvent) GetTxFee() *TxFee {
+	if x != nil {
+		return x.TxFee
+	}
+	return nil
+}
+
+// 转账事件
+type TransferEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`
+	EventId          uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"` // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
+	Slot             uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`
+	BlockTime        int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`
+	TxHash           []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Signers          [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                               // 签名者地址列表（通常为交易的发起者们）
+	Token            []byte                 `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`                                                   // 被转移token的mint地址
+	SrcAccount       []byte                 `protobuf:"bytes,8,opt,name=src_account,json=srcAccount,proto3" json:"src_account,omitempty"`                       // 来源 token 账户地址
+	DestAccount      []byte                 `protobuf:"bytes,9,opt,name=dest_account,json=destAccount,proto3" json:"dest_account,omitempty"`                    // 目标 token 账户地址
+	SrcWallet        []byte                 `protobuf:"bytes,10,opt,name=src_wallet,json=srcWallet,proto3" json:"src_wallet,omitempty"`                         // 来源钱包地址（为src_account的owner）
+	DestWallet       []byte                 `protobuf:"bytes,11,opt,name=dest_wallet,json=destWallet,proto3" json:"dest_wallet,omitempty"`                      // 目标钱包地址（为dest_account的owner）
+	Amount           uint64                 `protobuf:"varint,12,opt,name=amount,proto3" json:"amount,omitempty"`                                               // 转账数量（原生单位）
+	Decimals         uint32                 `protobuf:"varint,13,opt,name=decimals,proto3" json:"decimals,omitempty"`                                           // token 精度
+	SrcTokenBalance  uint64                 `protobuf:"varint,14,opt,name=src_token_balance,json=srcTokenBalance,proto3" json:"src_token_balance,omitempty"`    // 转账后，来源账户余额
+	DestTokenBalance uint64                 `protobuf:"varint,15,opt,name=dest_token_balance,json=destTokenBalance,proto3" json:"dest_token_balance,omitempty"` // 转账后，目标账户余额
+	TxFee            *TxFee                 `protobuf:"bytes,16,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`                                     // 所属交易的手续费信息
+	Fee              uint64                 `protobuf:"varint,17,opt,name=fee,proto3" json:"fee,omitempty"`                                                     // Token-2022 转账手续费（由目标账户预扣），目标账户实际到账 amount - fee
+	WithheldWithdraw bool                   `protobuf:"varint,18,opt,name=withheld_withdraw,json=withheldWithdraw,proto3" json:"withheld_withdraw,omitempty"`   // 是否为提取预扣手续费（WithdrawWithheldTokens），此时 src_account 为 mint
+	ParentEventId    uint64                 `protobuf:"varint,19,opt,name=parent_event_id,json=parentEventId,proto3" json:"parent_event_id,omitempty"`          // 所属聚合器路由的 RouteSwapEvent.event_id，非路由内的转账为 0
+	// 是否为原生 SOL 转账（System Program Transfer / TransferWithSeed / WithdrawNonceAccount）。
+	// 此时 token 为 SOLMint（So11111111111111111111111111111111111111111，区别于 WSOL mint），
+	// 没有 token 账户：src/dest_account 与 src/dest_wallet 相同，amount 与余额单位为 lamports（decimals = 9）
+	Native        bool `protobuf:"varint,20,opt,name=native,proto3" json:"native,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferEvent) Reset() {
+	*x = TransferEvent{}
+	mi := &file_event_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferEvent) ProtoMessage() {}
+
+func (x *TransferEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferEvent.ProtoReflect.Descriptor instead.
+func (*TransferEvent) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TransferEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_UNKNOWN
+}
+
+func (x *TransferEvent) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *TransferEvent) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *TransferEvent) GetBlockTime() int64 {
+	if x != nil {
+		return x.BlockTime
+	}
+	return 0
+}
+
+func (x *TransferEvent) GetTxHash() []byte {
+	if x != nil {
+		return x.TxHash
+	}
+	return nil
+}
+
+func (x *TransferEvent) GetSigners() [][]byte {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+func (x *TransferEvent) GetToken() []byte {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *TransferEvent) GetSrcAccount() []byte {
+	if x != nil {
+		return x.SrcAccount
+	}
+	return nil
+}
+
+func (x *TransferEvent) GetDestAccount() []byte {
+	if x != nil {
+		return x.DestAccount
+	}
+	return nil
+}
+
+func (x *TransferEvent) GetSrcWallet() []byte {
+	if x != nil {
+		return x.SrcWallet
+	}
+	return nil
+}
+
+func (x *TransferEvent) GetDestWallet() []byte {
+	if x != nil {
+		return x.DestWallet
+	}
+	return nil
 }
 
 func (x *TransferEvent) GetAmount() uint64 {
 	if x != nil {
-		return x.Amount
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TransferEvent) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *TransferEvent) GetSrcTokenBalance() uint64 {
+	if x != nil {
+		return x.SrcTokenBalance
+	}
+	return 0
+}
+
+func (x *TransferEvent) GetDestTokenBalance() uint64 {
+	if x != nil {
+		return x.DestTokenBalance
+	}
+	return 0
+}
+
+func (x *TransferEvent) GetTxFee() *TxFee {
+	if x != nil {
+		return x.TxFee
+	}
+	return nil
+}
+
+func (x *TransferEvent) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *TransferEvent) GetWithheldWithdraw() bool {
+	if x != nil {
+		return x.WithheldWithdraw
+	}
+	return false
+}
+
+func (x *TransferEvent) GetParentEventId() uint64 {
+	if x != nil {
+		return x.ParentEventId
+	}
+	return 0
+}
+
+func (x *TransferEvent) GetNative() bool {
+	if x != nil {
+		return x.Native
+	}
+	return false
+}
+
+// 添加/移除流动性事件（token统一表示base token）
+type LiquidityEvent struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Type                   EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                                                              // 事件类型，如 AddLiquidity、RemoveLiquidity 等
+	EventId                uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                                                           // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
+	Slot                   uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`                                                                                // 区块 Slot 高度
+	BlockTime              int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`                                                     // 区块时间戳（秒级）
+	TxHash                 []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`                                                               // 交易哈希
+	Signers                [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                                                           // 签名者地址列表（通常为交易的发起者们）
+	UserWallet             []byte                 `protobuf:"bytes,7,opt,name=user_wallet,json=userWallet,proto3" json:"user_wallet,omitempty"`                                                   // 用户钱包地址（通常与 signers 一致）
+	TokenDecimals          uint32                 `protobuf:"varint,8,opt,name=token_decimals,json=tokenDecimals,proto3" json:"token_decimals,omitempty"`                                         // token（即 base token）的精度
+	QuoteDecimals          uint32                 `protobuf:"varint,9,opt,name=quote_decimals,json=quoteDecimals,proto3" json:"quote_decimals,omitempty"`                                         // quote token 的精度
+	Dex                    uint32                 `protobuf:"varint,10,opt,name=dex,proto3" json:"dex,omitempty"`                                                                                 // 所属 DEX 平台编号（例如 RaydiumV4 = 1）
+	TokenAmount            uint64                 `protobuf:"varint,11,opt,name=token_amount,json=tokenAmount,proto3" json:"token_amount,omitempty"`                                              // token 添加/移除的数量（base token）
+	QuoteTokenAmount       uint64                 `protobuf:"varint,12,opt,name=quote_token_amount,json=quoteTokenAmount,proto3" json:"quote_token_amount,omitempty"`                             // quote token 添加/移除的数量
+	Token                  []byte                 `protobuf:"bytes,13,opt,name=token,proto3" json:"token,omitempty"`                                                                              // token 的 mint 地址（base token）
+	QuoteToken             []byte                 `protobuf:"bytes,14,opt,name=quote_token,json=quoteToken,proto3" json:"quote_token,omitempty"`                                                  // quote token 的 mint 地址
+	PairAddress            []byte                 `protobuf:"bytes,15,opt,name=pair_address,json=pairAddress,proto3" json:"pair_address,omitempty"`                                               // 池子地址（如 AMM 池或交易对地址）
+	TokenAccount           []byte                 `protobuf:"bytes,16,opt,name=token_account,json=tokenAccount,proto3" json:"token_account,omitempty"`                                            // 池子中 token 的账户地址
+	QuoteTokenAccount      []byte                 `protobuf:"bytes,17,opt,name=quote_token_account,json=quoteTokenAccount,proto3" json:"quote_token_account,omitempty"`                           // 池子中 quote token 的账户地址
+	TokenAccountOwner      []byte                 `protobuf:"bytes,18,opt,name=token_account_owner,json=tokenAccountOwner,proto3" json:"token_account_owner,omitempty"`                           // 池子 token 账户的所有者
+	QuoteTokenAccountOwner []byte                 `protobuf:"bytes,19,opt,name=quote_token_account_owner,json=quoteTokenAccountOwner,proto3" json:"quote_token_account_owner,omitempty"`          // 池子 quote token 账户的所有者
+	PairTokenBalance       uint64                 `protobuf:"varint,20,opt,name=pair_token_balance,json=pairTokenBalance,proto3" json:"pair_token_balance,omitempty"`                             // 当前池子中 token 的余额（base token）
+	PairQuoteBalance       uint64                 `protobuf:"varint,21,opt,name=pair_quote_balance,json=pairQuoteBalance,proto3" json:"pair_quote_balance,omitempty"`                             // 当前池子中 quote token 的余额
+	UserTokenBalance       uint64                 `protobuf:"varint,22,opt,name=user_token_balance,json=userTokenBalance,proto3" json:"user_token_balance,omitempty"`                             // 用户 token 的余额（base token）
+	UserQuoteBalance       uint64                 `protobuf:"varint,23,opt,name=user_quote_balance,json=userQuoteBalance,proto3" json:"user_quote_balance,omitempty"`                             // 用户 quote token 的余额
+	TokenProgram           TokenProgramType       `protobuf:"varint,24,opt,name=token_program,json=tokenProgram,proto3,enum=pb.TokenProgramType" json:"token_program,omitempty"`                  // base token 的程序类型（SPL 或 Token-2022）
+	QuoteTokenProgram      TokenProgramType       `protobuf:"varint,25,opt,name=quote_token_program,json=quoteTokenProgram,proto3,enum=pb.TokenProgramType" json:"quote_token_program,omitempty"` // quote token 的程序类型（SPL 或 Token-2022）
+	TxFee                  *TxFee                 `protobuf:"bytes,26,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`                                                                 // 所属交易的手续费信息
+	// Token-2022 转账手续费：token_amount / quote_token_amount 为转出方转出的总额（gross），
+	// 以下为扣除转账手续费后接收方实际到账的数量（net），无手续费时与 gross 相同
+	TokenAmountNet      uint64 `protobuf:"varint,27,opt,name=token_amount_net,json=tokenAmountNet,proto3" json:"token_amount_net,omitempty"`
+	QuoteTokenAmountNet uint64 `protobuf:"varint,28,opt,name=quote_token_amount_net,json=quoteTokenAmountNet,proto3" json:"quote_token_amount_net,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *LiquidityEvent) Reset() {
+	*x = LiquidityEvent{}
+	mi := &file_event_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LiquidityEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LiquidityEvent) ProtoMessage() {}
+
+func (x *LiquidityEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LiquidityEvent.ProtoReflect.Descriptor instead.
+func (*LiquidityEvent) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LiquidityEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_UNKNOWN
+}
+
+func (x *LiquidityEvent) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *LiquidityEvent) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *LiquidityEvent) GetBlockTime() int64 {
+	if x != nil {
+		return x.BlockTime
+	}
+	return 0
+}
+
+func (x *LiquidityEvent) GetTxHash() []byte {
+	if x != nil {
+		return x.TxHash
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetSigners() [][]byte {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetUserWallet() []byte {
+	if x != nil {
+		return x.UserWallet
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetTokenDecimals() uint32 {
+	if x != nil {
+		return x.TokenDecimals
+	}
+	return 0
+}
+
+func (x *LiquidityEvent) GetQuoteDecimals() uint32 {
+	if x != nil {
+		return x.QuoteDecimals
+	}
+	return 0
+}
+
+func (x *LiquidityEvent) GetDex() uint32 {
+	if x != nil {
+		return x.Dex
+	}
+	return 0
+}
+
+func (x *LiquidityEvent) GetTokenAmount() uint64 {
+	if x != nil {
+		return x.TokenAmount
 	}
 	return 0
 }
 
-func (x *TransferEvent) GetDecimals() uint32 {
+func (x *LiquidityEvent) GetQuoteTokenAmount() uint64 {
 	if x != nil {
-		return x.Decimals
+		return x.QuoteTokenAmount
 	}
 	return 0
 }
 
-func (x *TransferEvent) GetSrcTokenBalance() uint64 {
+func (x *LiquidityEvent) GetToken() []byte {
 	if x != nil {
-		return x.SrcTokenBalance
+		return x.Token
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetQuoteToken() []byte {
+	if x != nil {
+		return x.QuoteToken
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetPairAddress() []byte {
+	if x != nil {
+		return x.PairAddress
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetTokenAccount() []byte {
+	if x != nil {
+		return x.TokenAccount
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetQuoteTokenAccount() []byte {
+	if x != nil {
+		return x.QuoteTokenAccount
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetTokenAccountOwner() []byte {
+	if x != nil {
+		return x.TokenAccountOwner
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetQuoteTokenAccountOwner() []byte {
+	if x != nil {
+		return x.QuoteTokenAccountOwner
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetPairTokenBalance() uint64 {
+	if x != nil {
+		return x.PairTokenBalance
 	}
 	return 0
 }
 
-func (x *TransferEvent) GetDestTokenBalance() uint64 {
+func (x *LiquidityEvent) GetPairQuoteBalance() uint64 {
 	if x != nil {
-		return x.DestTokenBalance
+		return x.PairQuoteBalance
 	}
 	return 0
 }
 
-// 添加/移除流动性事件（token统一表示base token）
-type LiquidityEvent struct {
-	state                  protoimpl.MessageState `protogen:"open.v1"`
-	Type                   EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                                                              // 事件类型，如 AddLiquidity、RemoveLiquidity 等
-	EventId                uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                                                           // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
-	Slot                   uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`                                                                                // 区块 Slot 高度
-	BlockTime              int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`                                                     // 区块时间戳（秒级）
-	TxHash                 []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`                                                               // 交易哈希
-	Signers                [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                                                           // 签名者地址列表（通常为交易的发起者们）
-	UserWallet             []byte                 `protobuf:"bytes,7,opt,name=user_wallet,json=userWallet,proto3" json:"user_wallet,omitempty"`                                                   // 用户钱包地址（通常与 signers 一致）
-	TokenDecimals          uint32                 `protobuf:"varint,8,opt,name=token_decimals,json=tokenDecimals,proto3" json:"token_decimals,omitempty"`                                         // token（即 base token）的精度
-	QuoteDecimals          uint32                 `protobuf:"varint,9,opt,name=quote_decimals,json=quoteDecimals,proto3" json:"quote_decimals,omitempty"`                                         // quote token 的精度
-	Dex                    uint32                 `protobuf:"varint,10,opt,name=dex,proto3" json:"dex,omitempty"`                                                                                 // 所属 DEX 平台编号（例如 RaydiumV4 = 1）
-	TokenAmount            uint64                 `protobuf:"varint,11,opt,name=token_amount,json=tokenAmount,proto3" json:"token_amount,omitempty"`                                              // token 添加/移除的数量（base token）
-	QuoteTokenAmount       uint64                 `protobuf:"varint,12,opt,name=quote_token_amount,json=quoteTokenAmount,proto3" json:"quote_token_amount,omitempty"`                             // quote token 添加/移除的数量
-	Token                  []byte                 `protobuf:"bytes,13,opt,name=token,proto3" json:"token,omitempty"`                                                                              // token 的 mint 地址（base token）
-	QuoteToken             []byte                 `protobuf:"bytes,14,opt,name=quote_token,json=quoteToken,proto3" json:"quote_token,omitempty"`                                                  // quote token 的 mint 地址
-	PairAddress            []byte                 `protobuf:"bytes,15,opt,name=pair_address,json=pairAddress,proto3" json:"pair_address,omitempty"`                                               // 池子地址（如 AMM 池或交易对地址）
-	TokenAccount           []byte                 `protobuf:"bytes,16,opt,name=token_account,json=tokenAccount,proto3" json:"token_account,omitempty"`                                            // 池子中 token 的账户地址
-	QuoteTokenAccount      []byte                 `protobuf:"bytes,17,opt,name=quote_token_account,json=quoteTokenAccount,proto3" json:"quote_token_account,omitempty"`                           // 池子中 quote token 的账户地址
-	TokenAccountOwner      []byte                 `protobuf:"bytes,18,opt,name=token_account_owner,json=tokenAccountOwner,proto3" json:"token_account_owner,omitempty"`                           // 池子 token 账户的所有者
-	QuoteTokenAccountOwner []byte                 `protobuf:"bytes,19,opt,name=quote_token_account_owner,json=quoteTokenAccountOwner,proto3" json:"quote_token_account_owner,omitempty"`          // 池子 quote token 账户的所有者
-	PairTokenBalance       uint64                 `protobuf:"varint,20,opt,name=pair_token_balance,json=pairTokenBalance,proto3" json:"pair_token_balance,omitempty"`                             // 当前池子中 token 的余额（base token）
-	PairQuoteBalance       uint64                 `protobuf:"varint,21,opt,name=pair_quote_balance,json=pairQuoteBalance,proto3" json:"pair_quote_balance,omitempty"`                             // 当前池子中 quote token 的余额
-	UserTokenBalance       uint64                 `protobuf:"varint,22,opt,name=user_token_balance,json=userTokenBalance,proto3" json:"user_token_balance,omitempty"`                             // 用户 token 的余额（base token）
-	UserQuoteBalance       uint64                 `protobuf:"varint,23,opt,name=user_quote_balance,json=userQuoteBalance,proto3" json:"user_quote_balance,omitempty"`                             // 用户 quote token 的余额
-	TokenProgram           TokenProgramType       `protobuf:"varint,24,opt,name=token_program,json=tokenProgram,proto3,enum=pb.TokenProgramType" json:"token_program,omitempty"`                  // base token 的程序类型（SPL 或 Token-2022）
-	QuoteTokenProgram      TokenProgramType       `protobuf:"varint,25,opt,name=quote_token_program,json=quoteTokenProgram,proto3,enum=pb.TokenProgramType" json:"quote_token_program,omitempty"` // quote token 的程序类型（SPL 或 Token-2022）
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+func (x *LiquidityEvent) GetUserTokenBalance() uint64 {
+	if x != nil {
+		return x.UserTokenBalance
+	}
+	return 0
+}
+
+func (x *LiquidityEvent) GetUserQuoteBalance() uint64 {
+	if x != nil {
+		return x.UserQuoteBalance
+	}
+	return 0
+}
+
+func (x *LiquidityEvent) GetTokenProgram() TokenProgramType {
+	if x != nil {
+		return x.TokenProgram
+	}
+	return TokenProgramType_TOKEN_OTHER
+}
+
+func (x *LiquidityEvent) GetQuoteTokenProgram() TokenProgramType {
+	if x != nil {
+		return x.QuoteTokenProgram
+	}
+	return TokenProgramType_TOKEN_OTHER
+}
+
+func (x *LiquidityEvent) GetTxFee() *TxFee {
+	if x != nil {
+		return x.TxFee
+	}
+	return nil
+}
+
+func (x *LiquidityEvent) GetTokenAmountNet() uint64 {
+	if x != nil {
+		return x.TokenAmountNet
+	}
+	return 0
+}
+
+func (x *LiquidityEvent) GetQuoteTokenAmountNet() uint64 {
+	if x != nil {
+		return x.QuoteTokenAmountNet
+	}
+	return 0
+}
+
+// 铸币事件
+type MintToEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Type           EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                            // 事件类型（MINT_TO）
+	EventId        uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                         // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
+	Slot           uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`                                              // 区块 slot
+	BlockTime      int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`                   // 区块时间（Unix 秒）
+	TxHash         []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`                             // 交易哈希
+	Signers        [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                         // 签名者地址列表（通常为交易的发起者们）
+	Token          []byte                 `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`                                             // 被铸造 token 的 mint 地址
+	ToAddress      []byte                 `protobuf:"bytes,8,opt,name=to_address,json=toAddress,proto3" json:"to_address,omitempty"`                    // 接收者钱包地址
+	ToTokenAccount []byte                 `protobuf:"bytes,9,opt,name=to_token_account,json=toTokenAccount,proto3" json:"to_token_account,omitempty"`   // 接收者 token account 地址
+	Amount         uint64                 `protobuf:"varint,10,opt,name=amount,proto3" json:"amount,omitempty"`                                         // 铸造的数量（原生单位）
+	Decimals       uint32                 `protobuf:"varint,11,opt,name=decimals,proto3" json:"decimals,omitempty"`                                     // token 精度
+	ToTokenBalance uint64                 `protobuf:"varint,12,opt,name=to_token_balance,json=toTokenBalance,proto3" json:"to_token_balance,omitempty"` // 铸造后的 token account 余额
+	TxFee          *TxFee                 `protobuf:"bytes,13,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`                               // 所属交易的手续费信息
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MintToEvent) Reset() {
+	*x = MintToEvent{}
+	mi := &file_event_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MintToEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MintToEvent) ProtoMessage() {}
+
+func (x *MintToEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MintToEvent.ProtoReflect.Descriptor instead.
+func (*MintToEvent) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MintToEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_UNKNOWN
+}
+
+func (x *MintToEvent) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *MintToEvent) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *MintToEvent) GetBlockTime() int64 {
+	if x != nil {
+		return x.BlockTime
+	}
+	return 0
+}
+
+func (x *MintToEvent) GetTxHash() []byte {
+	if x != nil {
+		return x.TxHash
+	}
+	return nil
+}
+
+func (x *MintToEvent) GetSigners() [][]byte {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+func (x *MintToEvent) GetToken() []byte {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *MintToEvent) GetToAddress() []byte {
+	if x != nil {
+		return x.ToAddress
+	}
+	return nil
+}
+
+func (x *MintToEvent) GetToTokenAccount() []byte {
+	if x != nil {
+		return x.ToTokenAccount
+	}
+	return nil
+}
+
+func (x *MintToEvent) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *MintToEvent) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
 }
 
-func (x *LiquidityEvent) Reset() {
-	*x = LiquidityEvent{}
-	mi := &file_event_proto_msgTypes[5]
+func (x *MintToEvent) GetToTokenBalance() uint64 {
+	if x != nil {
+		return x.ToTokenBalance
+	}
+	return 0
+}
+
+func (x *MintToEvent) GetTxFee() *TxFee {
+	if x != nil {
+		return x.TxFee
+	}
+	return nil
+}
+
+// 销毁事件
+type BurnEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                                  // 事件类型（BURN）
+	EventId          uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                               // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
+	Slot             uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`                                                    // 区块 slot
+	BlockTime        int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`                         // 区块时间（Unix 秒）
+	TxHash           []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`                                   // 交易哈希
+	Signers          [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                               // 签名者地址列表（通常为交易的发起者们）
+	Token            []byte                 `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`                                                   // 被销毁 token 的 mint 地址
+	FromAddress      []byte                 `protobuf:"bytes,8,opt,name=from_address,json=fromAddress,proto3" json:"from_address,omitempty"`                    // 发起者钱包地址
+	FromTokenAccount []byte                 `protobuf:"bytes,9,opt,name=from_token_account,json=fromTokenAccount,proto3" json:"from_token_account,omitempty"`   // 销毁操作的 token account 地址
+	Amount           uint64                 `protobuf:"varint,10,opt,name=amount,proto3" json:"amount,omitempty"`                                               // 被销毁的数量（原生单位）
+	Decimals         uint32                 `protobuf:"varint,11,opt,name=decimals,proto3" json:"decimals,omitempty"`                                           // token 精度
+	FromTokenBalance uint64                 `protobuf:"varint,12,opt,name=from_token_balance,json=fromTokenBalance,proto3" json:"from_token_balance,omitempty"` // 销毁后的 token account 余额
+	TxFee            *TxFee                 `protobuf:"bytes,13,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`                                     // 所属交易的手续费信息
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BurnEvent) Reset() {
+	*x = BurnEvent{}
+	mi := &file_event_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LiquidityEvent) String() string {
+func (x *BurnEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LiquidityEvent) ProtoMessage() {}
+func (*BurnEvent) ProtoMessage() {}
 
-func (x *LiquidityEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[5]
+func (x *BurnEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -984,220 +2182,276 @@ func (x *LiquidityEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LiquidityEvent.ProtoReflect.Descriptor instead.
-func (*LiquidityEvent) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use BurnEvent.ProtoReflect.Descriptor instead.
+func (*BurnEvent) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *LiquidityEvent) GetType() EventType {
+func (x *BurnEvent) GetType() EventType {
 	if x != nil {
 		return x.Type
 	}
 	return EventType_UNKNOWN
 }
 
-func (x *LiquidityEvent) GetEventId() uint64 {
+func (x *BurnEvent) GetEventId() uint64 {
 	if x != nil {
 		return x.EventId
 	}
 	return 0
 }
 
-func (x *LiquidityEvent) GetSlot() uint64 {
+func (x *BurnEvent) GetSlot() uint64 {
 	if x != nil {
 		return x.Slot
 	}
 	return 0
 }
 
-func (x *LiquidityEvent) GetBlockTime() int64 {
+func (x *BurnEvent) GetBlockTime() int64 {
 	if x != nil {
 		return x.BlockTime
 	}
 	return 0
 }
 
-func (x *LiquidityEvent) GetTxHash() []byte {
+func (x *BurnEvent) GetTxHash() []byte {
 	if x != nil {
 		return x.TxHash
 	}
 	return nil
 }
 
-func (x *LiquidityEvent) GetSigners() [][]byte {
+func (x *BurnEvent) GetSigners() [][]byte {
 	if x != nil {
 		return x.Signers
 	}
 	return nil
 }
 
-func (x *LiquidityEvent) GetUserWallet() []byte {
+func (x *BurnEvent) GetToken() []byte {
 	if x != nil {
-		return x.UserWallet
+		return x.Token
 	}
 	return nil
 }
 
-func (x *LiquidityEvent) GetTokenDecimals() uint32 {
+func (x *BurnEvent) GetFromAddress() []byte {
 	if x != nil {
-		return x.TokenDecimals
+		return x.FromAddress
 	}
-	return 0
+	return nil
 }
 
-func (x *LiquidityEvent) GetQuoteDecimals() uint32 {
+func (x *BurnEvent) GetFromTokenAccount() []byte {
 	if x != nil {
-		return x.QuoteDecimals
+		return x.FromTokenAccount
 	}
-	return 0
+	return nil
 }
 
-func (x *LiquidityEvent) GetDex() uint32 {
+func (x *BurnEvent) GetAmount() uint64 {
 	if x != nil {
-		return x.Dex
+		return x.Amount
 	}
 	return 0
 }
 
-func (x *LiquidityEvent) GetTokenAmount() uint64 {
+func (x *BurnEvent) GetDecimals() uint32 {
 	if x != nil {
-		return x.TokenAmount
+		return x.Decimals
 	}
 	return 0
 }
 
-func (x *LiquidityEvent) GetQuoteTokenAmount() uint64 {
+func (x *BurnEvent) GetFromTokenBalance() uint64 {
 	if x != nil {
-		return x.QuoteTokenAmount
+		return x.FromTokenBalance
 	}
 	return 0
 }
 
-func (x *LiquidityEvent) GetToken() []byte {
+func (x *BurnEvent) GetTxFee() *TxFee {
 	if x != nil {
-		return x.Token
+		return x.TxFee
 	}
 	return nil
 }
 
-func (x *LiquidityEvent) GetQuoteToken() []byte {
+// 账户创建 / 回收事件（System Program；普通钱包转空余额不产生回收事件）
+type AccountEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`          // 事件类型（ACCOUNT_CREATE / ACCOUNT_CLOSE）
+	EventId       uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`       // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index），回收事件为触发它的 SOL 转账事件 + 1
+	Slot          uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`                            // 区块 slot
+	BlockTime     int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"` // 区块时间（Unix 秒）
+	TxHash        []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`           // 交易哈希
+	Signers       [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                       // 签名者地址列表（通常为交易的发起者们）
+	Account       []byte                 `protobuf:"bytes,7,opt,name=account,proto3" json:"account,omitempty"`                       // 被创建 / 回收的账户地址
+	Counterparty  []byte                 `protobuf:"bytes,8,opt,name=counterparty,proto3" json:"counterparty,omitempty"`             // 创建时为出资账户（funder），回收时为接收剩余 SOL 的账户
+	Owner         []byte                 `protobuf:"bytes,9,opt,name=owner,proto3" json:"owner,omitempty"`                           // 创建时分配的所属程序，回收时为空
+	Lamports      uint64                 `protobuf:"varint,10,opt,name=lamports,proto3" json:"lamports,omitempty"`                   // 创建时存入的 lamports，回收时转出的 lamports
+	Space         uint64                 `protobuf:"varint,11,opt,name=space,proto3" json:"space,omitempty"`                         // 创建时分配的数据空间（字节），回收时为 0
+	TxFee         *TxFee                 `protobuf:"bytes,12,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`             // 所属交易的手续费信息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountEvent) Reset() {
+	*x = AccountEvent{}
+	mi := &file_event_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountEvent) ProtoMessage() {}
+
+func (x *AccountEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[12]
 	if x != nil {
-		return x.QuoteToken
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *LiquidityEvent) GetPairAddress() []byte {
+// Deprecated: Use AccountEvent.ProtoReflect.Descriptor instead.
+func (*AccountEvent) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AccountEvent) GetType() EventType {
 	if x != nil {
-		return x.PairAddress
+		return x.Type
 	}
-	return nil
+	return EventType_UNKNOWN
 }
 
-func (x *LiquidityEvent) GetTokenAccount() []byte {
+func (x *AccountEvent) GetEventId() uint64 {
 	if x != nil {
-		return x.TokenAccount
+		return x.EventId
 	}
-	return nil
+	return 0
 }
 
-func (x *LiquidityEvent) GetQuoteTokenAccount() []byte {
+func (x *AccountEvent) GetSlot() uint64 {
 	if x != nil {
-		return x.QuoteTokenAccount
+		return x.Slot
 	}
-	return nil
+	return 0
 }
 
-func (x *LiquidityEvent) GetTokenAccountOwner() []byte {
+func (x *AccountEvent) GetBlockTime() int64 {
 	if x != nil {
-		return x.TokenAccountOwner
+		return x.BlockTime
+	}
+	return 0
+}
+
+func (x *AccountEvent) GetTxHash() []byte {
+	if x != nil {
+		return x.TxHash
 	}
 	return nil
 }
 
-func (x *LiquidityEvent) GetQuoteTokenAccountOwner() []byte {
+func (x *AccountEvent) GetSigners() [][]byte {
 	if x != nil {
-		return x.QuoteTokenAccountOwner
+		return x.Signers
 	}
 	return nil
 }
 
-func (x *LiquidityEvent) GetPairTokenBalance() uint64 {
+func (x *AccountEvent) GetAccount() []byte {
 	if x != nil {
-		return x.PairTokenBalance
+		return x.Account
 	}
-	return 0
+	return nil
 }
 
-func (x *LiquidityEvent) GetPairQuoteBalance() uint64 {
+func (x *AccountEvent) GetCounterparty() []byte {
 	if x != nil {
-		return x.PairQuoteBalance
+		return x.Counterparty
 	}
-	return 0
+	return nil
 }
 
-func (x *LiquidityEvent) GetUserTokenBalance() uint64 {
+func (x *AccountEvent) GetOwner() []byte {
 	if x != nil {
-		return x.UserTokenBalance
+		return x.Owner
 	}
-	return 0
+	return nil
 }
 
-func (x *LiquidityEvent) GetUserQuoteBalance() uint64 {
+func (x *AccountEvent) GetLamports() uint64 {
 	if x != nil {
-		return x.UserQuoteBalance
+		return x.Lamports
 	}
 	return 0
 }
 
-func (x *LiquidityEvent) GetTokenProgram() TokenProgramType {
+func (x *AccountEvent) GetSpace() uint64 {
 	if x != nil {
-		return x.TokenProgram
+		return x.Space
 	}
-	return TokenProgramType_TOKEN_OTHER
+	return 0
 }
 
-func (x *LiquidityEvent) GetQuoteTokenProgram() TokenProgramType {
+func (x *AccountEvent) GetTxFee() *TxFee {
 	if x != nil {
-		return x.QuoteTokenProgram
+		return x.TxFee
 	}
-	return TokenProgramType_TOKEN_OTHER
+	return nil
 }
 
-// 铸币事件
-type MintToEvent struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Type           EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                            // 事件类型（MINT_TO）
-	EventId        uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                         // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
-	Slot           uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`                                              // 区块 slot
-	BlockTime      int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`                   // 区块时间（Unix 秒）
-	TxHash         []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`                             // 交易哈希
-	Signers        [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                         // 签名者地址列表（通常为交易的发起者们）
-	Token          []byte                 `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`                                             // 被铸造 token 的 mint 地址
-	ToAddress      []byte                 `protobuf:"bytes,8,opt,name=to_address,json=toAddress,proto3" json:"to_address,omitempty"`                    // 接收者钱包地址
-	ToTokenAccount []byte                 `protobuf:"bytes,9,opt,name=to_token_account,json=toTokenAccount,proto3" json:"to_token_account,omitempty"`   // 接收者 token account 地址
-	Amount         uint64                 `protobuf:"varint,10,opt,name=amount,proto3" json:"amount,omitempty"`                                         // 铸造的数量（原生单位）
-	Decimals       uint32                 `protobuf:"varint,11,opt,name=decimals,proto3" json:"decimals,omitempty"`                                     // token 精度
-	ToTokenBalance uint64                 `protobuf:"varint,12,opt,name=to_token_balance,json=toTokenBalance,proto3" json:"to_token_balance,omitempty"` // 铸造后的 token account 余额
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// 聚合器路由兑换事件（Jupiter v6 route / shared_accounts_route 等）
+// 金额取自聚合器逐 hop 发出的 SwapEvent，未解析的 DEX 也会出现在 hops 中
+type RouteSwapEvent struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Type              EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                                   // 事件类型（ROUTE_SWAP）
+	EventId           uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                                // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
+	Slot              uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`                                                     // 区块 slot
+	BlockTime         int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`                          // 区块时间（Unix 秒）
+	TxHash            []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`                                    // 交易哈希
+	Signers           [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                                // 签名者地址列表（通常为交易的发起者们）
+	Program           []byte                 `protobuf:"bytes,7,opt,name=program,proto3" json:"program,omitempty"`                                                // 聚合器程序地址
+	UserWallet        []byte                 `protobuf:"bytes,8,opt,name=user_wallet,json=userWallet,proto3" json:"user_wallet,omitempty"`                        // 用户钱包地址（user_transfer_authority）
+	UserSourceAccount []byte                 `protobuf:"bytes,9,opt,name=user_source_account,json=userSourceAccount,proto3" json:"user_source_account,omitempty"` // 用户支付的 token 账户
+	UserDestAccount   []byte                 `protobuf:"bytes,10,opt,name=user_dest_account,json=userDestAccount,proto3" json:"user_dest_account,omitempty"`      // 用户接收的 token 账户
+	InputMint         []byte                 `protobuf:"bytes,11,opt,name=input_mint,json=inputMint,proto3" json:"input_mint,omitempty"`                          // 用户支付的 token mint
+	OutputMint        []byte                 `protobuf:"bytes,12,opt,name=output_mint,json=outputMint,proto3" json:"output_mint,omitempty"`                       // 用户获得的 token mint
+	InputAmount       uint64                 `protobuf:"varint,13,opt,name=input_amount,json=inputAmount,proto3" json:"input_amount,omitempty"`                   // 支付数量（原生最小单位，为首批 hop 输入之和）
+	OutputAmount      uint64                 `protobuf:"varint,14,opt,name=output_amount,json=outputAmount,proto3" json:"output_amount,omitempty"`                // 获得数量（原生最小单位，为末批 hop 输出之和）
+	InputDecimals     uint32                 `protobuf:"varint,15,opt,name=input_decimals,json=inputDecimals,proto3" json:"input_decimals,omitempty"`
+	OutputDecimals    uint32                 `protobuf:"varint,16,opt,name=output_decimals,json=outputDecimals,proto3" json:"output_decimals,omitempty"`
+	Hops              []*RouteHop            `protobuf:"bytes,17,rep,name=hops,proto3" json:"hops,omitempty"`                // 按执行顺序排列的路由 hop
+	TxFee             *TxFee                 `protobuf:"bytes,18,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"` // 所属交易的手续费信息
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
-func (x *MintToEvent) Reset() {
-	*x = MintToEvent{}
-	mi := &file_event_proto_msgTypes[6]
+func (x *RouteSwapEvent) Reset() {
+	*x = RouteSwapEvent{}
+	mi := &file_event_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MintToEvent) String() string {
+func (x *RouteSwapEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MintToEvent) ProtoMessage() {}
+func (*RouteSwapEvent) ProtoMessage() {}
 
-func (x *MintToEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[6]
+func (x *RouteSwapEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1208,224 +2462,218 @@ func (x *MintToEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MintToEvent.ProtoReflect.Descriptor instead.
-func (*MintToEvent) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use RouteSwapEvent.ProtoReflect.Descriptor instead.
+func (*RouteSwapEvent) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *MintToEvent) GetType() EventType {
+func (x *RouteSwapEvent) GetType() EventType {
 	if x != nil {
 		return x.Type
 	}
 	return EventType_UNKNOWN
 }
 
-func (x *MintToEvent) GetEventId() uint64 {
+func (x *RouteSwapEvent) GetEventId() uint64 {
 	if x != nil {
 		return x.EventId
 	}
 	return 0
 }
 
-func (x *MintToEvent) GetSlot() uint64 {
+func (x *RouteSwapEvent) GetSlot() uint64 {
 	if x != nil {
 		return x.Slot
 	}
 	return 0
 }
 
-func (x *MintToEvent) GetBlockTime() int64 {
+func (x *RouteSwapEvent) GetBlockTime() int64 {
 	if x != nil {
 		return x.BlockTime
 	}
 	return 0
 }
 
-func (x *MintToEvent) GetTxHash() []byte {
+func (x *RouteSwapEvent) GetTxHash() []byte {
 	if x != nil {
 		return x.TxHash
 	}
 	return nil
 }
 
-func (x *MintToEvent) GetSigners() [][]byte {
+func (x *RouteSwapEvent) GetSigners() [][]byte {
 	if x != nil {
 		return x.Signers
 	}
 	return nil
 }
 
-func (x *MintToEvent) GetToken() []byte {
-	if x != nil {
-		return x.Token
-	}
-	return nil
-}
-
-func (x *MintToEvent) GetToAddress() []byte {
+func (x *RouteSwapEvent) GetProgram() []byte {
 	if x != nil {
-		return x.ToAddress
+		return x.Program
 	}
 	return nil
 }
 
-func (x *MintToEvent) GetToTokenAccount() []byte {
+func (x *RouteSwapEvent) GetUserWallet() []byte {
 	if x != nil {
-		return x.ToTokenAccount
+		return x.UserWallet
 	}
 	return nil
 }
 
-func (x *MintToEvent) GetAmount() uint64 {
-	if x != nil {
-		return x.Amount
-	}
-	return 0
-}
-
-func (x *MintToEvent) GetDecimals() uint32 {
-	if x != nil {
-		return x.Decimals
-	}
-	return 0
-}
-
-func (x *MintToEvent) GetToTokenBalance() uint64 {
-	if x != nil {
-		return x.ToTokenBalance
-	}
-	return 0
-}
-
-// 销毁事件
-type BurnEvent struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Type             EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                                  // 事件类型（BURN）
-	EventId          uint64                 `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                               // 事件唯一ID（slot << 32 | tx_index << 16 | ix_index << 8 | inner_index）
-	Slot             uint64                 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`                                                    // 区块 slot
-	BlockTime        int64                  `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`                         // 区块时间（Unix 秒）
-	TxHash           []byte                 `protobuf:"bytes,5,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`                                   // 交易哈希
-	Signers          [][]byte               `protobuf:"bytes,6,rep,name=signers,proto3" json:"signers,omitempty"`                                               // 签名者地址列表（通常为交易的发起者们）
-	Token            []byte                 `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`                                                   // 被销毁 token 的 mint 地址
-	FromAddress      []byte                 `protobuf:"bytes,8,opt,name=from_address,json=fromAddress,proto3" json:"from_address,omitempty"`                    // 发起者钱包地址
-	FromTokenAccount []byte                 `protobuf:"bytes,9,opt,name=from_token_account,json=fromTokenAccount,proto3" json:"from_token_account,omitempty"`   // 销毁操作的 token account 地址
-	Amount           uint64                 `protobuf:"varint,10,opt,name=amount,proto3" json:"amount,omitempty"`                                               // 被销毁的数量（原生单位）
-	Decimals         uint32                 `protobuf:"varint,11,opt,name=decimals,proto3" json:"decimals,omitempty"`                                           // token 精度
-	FromTokenBalance uint64                 `protobuf:"varint,12,opt,name=from_token_balance,json=fromTokenBalance,proto3" json:"from_token_balance,omitempty"` // 销毁后的 token account 余额
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
-}
-
-func (x *BurnEvent) Reset() {
-	*x = BurnEvent{}
-	mi := &file_event_proto_msgTypes[7]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *RouteSwapEvent) GetUserSourceAccount() []byte {
+	if x != nil {
+		return x.UserSourceAccount
+	}
+	return nil
 }
 
-func (x *BurnEvent) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *RouteSwapEvent) GetUserDestAccount() []byte {
+	if x != nil {
+		return x.UserDestAccount
+	}
+	return nil
 }
 
-func (*BurnEvent) ProtoMessage() {}
-
-func (x *BurnEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[7]
+func (x *RouteSwapEvent) GetInputMint() []byte {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.InputMint
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use BurnEvent.ProtoReflect.Descriptor instead.
-func (*BurnEvent) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{7}
+func (x *RouteSwapEvent) GetOutputMint() []byte {
+	if x != nil {
+		return x.OutputMint
+	}
+	return nil
 }
 
-func (x *BurnEvent) GetType() EventType {
+func (x *RouteSwapEvent) GetInputAmount() uint64 {
 	if x != nil {
-		return x.Type
+		return x.InputAmount
 	}
-	return EventType_UNKNOWN
+	return 0
 }
 
-func (x *BurnEvent) GetEventId() uint64 {
+func (x *RouteSwapEvent) GetOutputAmount() uint64 {
 	if x != nil {
-		return x.EventId
+		return x.OutputAmount
 	}
 	return 0
 }
 
-func (x *BurnEvent) GetSlot() uint64 {
+func (x *RouteSwapEvent) GetInputDecimals() uint32 {
 	if x != nil {
-		return x.Slot
+		return x.InputDecimals
 	}
 	return 0
 }
 
-func (x *BurnEvent) GetBlockTime() int64 {
+func (x *RouteSwapEvent) GetOutputDecimals() uint32 {
 	if x != nil {
-		return x.BlockTime
+		return x.OutputDecimals
 	}
 	return 0
 }
 
-func (x *BurnEvent) GetTxHash() []byte {
+func (x *RouteSwapEvent) GetHops() []*RouteHop {
 	if x != nil {
-		return x.TxHash
+		return x.Hops
 	}
 	return nil
 }
 
-func (x *BurnEvent) GetSigners() [][]byte {
+func (x *RouteSwapEvent) GetTxFee() *TxFee {
 	if x != nil {
-		return x.Signers
+		return x.TxFee
 	}
 	return nil
 }
 
-func (x *BurnEvent) GetToken() []byte {
+// 路由中的单次兑换
+type RouteHop struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amm           []byte                 `protobuf:"bytes,1,opt,name=amm,proto3" json:"amm,omitempty"` // 执行兑换的 DEX 程序地址
+	InputMint     []byte                 `protobuf:"bytes,2,opt,name=input_mint,json=inputMint,proto3" json:"input_mint,omitempty"`
+	InputAmount   uint64                 `protobuf:"varint,3,opt,name=input_amount,json=inputAmount,proto3" json:"input_amount,omitempty"`
+	OutputMint    []byte                 `protobuf:"bytes,4,opt,name=output_mint,json=outputMint,proto3" json:"output_mint,omitempty"`
+	OutputAmount  uint64                 `protobuf:"varint,5,opt,name=output_amount,json=outputAmount,proto3" json:"output_amount,omitempty"`
+	TradeEventId  uint64                 `protobuf:"varint,6,opt,name=trade_event_id,json=tradeEventId,proto3" json:"trade_event_id,omitempty"` // 对应 TradeEvent 的 event_id，DEX 未解析时为 0
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteHop) Reset() {
+	*x = RouteHop{}
+	mi := &file_event_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteHop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteHop) ProtoMessage() {}
+
+func (x *RouteHop) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[14]
 	if x != nil {
-		return x.Token
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *BurnEvent) GetFromAddress() []byte {
+// Deprecated: Use RouteHop.ProtoReflect.Descriptor instead.
+func (*RouteHop) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RouteHop) GetAmm() []byte {
 	if x != nil {
-		return x.FromAddress
+		return x.Amm
 	}
 	return nil
 }
 
-func (x *BurnEvent) GetFromTokenAccount() []byte {
+func (x *RouteHop) GetInputMint() []byte {
 	if x != nil {
-		return x.FromTokenAccount
+		return x.InputMint
 	}
 	return nil
 }
 
-func (x *BurnEvent) GetAmount() uint64 {
+func (x *RouteHop) GetInputAmount() uint64 {
 	if x != nil {
-		return x.Amount
+		return x.InputAmount
 	}
 	return 0
 }
 
-func (x *BurnEvent) GetDecimals() uint32 {
+func (x *RouteHop) GetOutputMint() []byte {
 	if x != nil {
-		return x.Decimals
+		return x.OutputMint
+	}
+	return nil
+}
+
+func (x *RouteHop) GetOutputAmount() uint64 {
+	if x != nil {
+		return x.OutputAmount
 	}
 	return 0
 }
 
-func (x *BurnEvent) GetFromTokenBalance() uint64 {
+func (x *RouteHop) GetTradeEventId() uint64 {
 	if x != nil {
-		return x.FromTokenBalance
+		return x.TradeEventId
 	}
 	return 0
 }
@@ -1449,7 +2697,7 @@ type BalanceUpdateEvent struct {
 
 func (x *BalanceUpdateEvent) Reset() {
 	*x = BalanceUpdateEvent{}
-	mi := &file_event_proto_msgTypes[8]
+	mi := &file_event_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1461,7 +2709,7 @@ func (x *BalanceUpdateEvent) String() string {
 func (*BalanceUpdateEvent) ProtoMessage() {}
 
 func (x *BalanceUpdateEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[8]
+	mi := &file_event_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1474,7 +2722,7 @@ func (x *BalanceUpdateEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BalanceUpdateEvent.ProtoReflect.Descriptor instead.
 func (*BalanceUpdateEvent) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{8}
+	return file_event_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *BalanceUpdateEvent) GetType() EventType {
@@ -1581,13 +2829,14 @@ type MigrateEvent struct {
 	SrcPairQuoteBalance        uint64                 `protobuf:"varint,30,opt,name=src_pair_quote_balance,json=srcPairQuoteBalance,proto3" json:"src_pair_quote_balance,omitempty"`                       // 来源池 quote token 余额
 	DestPairTokenBalance       uint64                 `protobuf:"varint,31,opt,name=dest_pair_token_balance,json=destPairTokenBalance,proto3" json:"dest_pair_token_balance,omitempty"`                    // 目标池 base token 余额
 	DestPairQuoteBalance       uint64                 `protobuf:"varint,32,opt,name=dest_pair_quote_balance,json=destPairQuoteBalance,proto3" json:"dest_pair_quote_balance,omitempty"`                    // 目标池 quote token 余额
+	TxFee                      *TxFee                 `protobuf:"bytes,33,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`                                                                      // 所属交易的手续费信息
 	unknownFields              protoimpl.UnknownFields
 	sizeCache                  protoimpl.SizeCache
 }
 
 func (x *MigrateEvent) Reset() {
 	*x = MigrateEvent{}
-	mi := &file_event_proto_msgTypes[9]
+	mi := &file_event_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1599,7 +2848,7 @@ func (x *MigrateEvent) String() string {
 func (*MigrateEvent) ProtoMessage() {}
 
 func (x *MigrateEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[9]
+	mi := &file_event_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1612,7 +2861,7 @@ func (x *MigrateEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MigrateEvent.ProtoReflect.Descriptor instead.
 func (*MigrateEvent) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{9}
+	return file_event_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *MigrateEvent) GetType() EventType {
@@ -1839,6 +3088,13 @@ func (x *MigrateEvent) GetDestPairQuoteBalance() uint64 {
 	return 0
 }
 
+func (x *MigrateEvent) GetTxFee() *TxFee {
+	if x != nil {
+		return x.TxFee
+	}
+	return nil
+}
+
 type LaunchpadTokenEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Type          EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                                             // 事件类型（LAUNCH_TOKEN）
@@ -1858,13 +3114,14 @@ type LaunchpadTokenEvent struct {
 	Name          string                 `protobuf:"bytes,15,opt,name=name,proto3" json:"name,omitempty"`                                                               // 名称
 	Uri           string                 `protobuf:"bytes,16,opt,name=uri,proto3" json:"uri,omitempty"`                                                                 // 元数据 URI
 	TokenProgram  TokenProgramType       `protobuf:"varint,17,opt,name=token_program,json=tokenProgram,proto3,enum=pb.TokenProgramType" json:"token_program,omitempty"` // token 的程序类型（SPL 或 Token-2022）
+	TxFee         *TxFee                 `protobuf:"bytes,18,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`                                                // 所属交易的手续费信息
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LaunchpadTokenEvent) Reset() {
 	*x = LaunchpadTokenEvent{}
-	mi := &file_event_proto_msgTypes[10]
+	mi := &file_event_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1876,7 +3133,7 @@ func (x *LaunchpadTokenEvent) String() string {
 func (*LaunchpadTokenEvent) ProtoMessage() {}
 
 func (x *LaunchpadTokenEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[10]
+	mi := &file_event_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1889,7 +3146,7 @@ func (x *LaunchpadTokenEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LaunchpadTokenEvent.ProtoReflect.Descriptor instead.
 func (*LaunchpadTokenEvent) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{10}
+	return file_event_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *LaunchpadTokenEvent) GetType() EventType {
@@ -2011,6 +3268,185 @@ func (x *LaunchpadTokenEvent) GetTokenProgram() TokenProgramType {
 	return TokenProgramType_TOKEN_OTHER
 }
 
+func (x *LaunchpadTokenEvent) GetTxFee() *TxFee {
+	if x != nil {
+		return x.TxFee
+	}
+	return nil
+}
+
+// slot 回滚事件：消费方需撤销 (slot, block_hash) 对应的全部事件
+// 会广播到 event/balance topic 的所有分区
+type SlotRollbackEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`                      // 事件类型（SLOT_ROLLBACK）
+	Slot          uint64                 `protobuf:"varint,2,opt,name=slot,proto3" json:"slot,omitempty"`                                        // 被回滚的 slot
+	BlockHash     []byte                 `protobuf:"bytes,3,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`              // 被回滚区块的哈希
+	ParentSlot    uint64                 `protobuf:"varint,4,opt,name=parent_slot,json=parentSlot,proto3" json:"parent_slot,omitempty"`          // 被回滚区块的父 slot
+	BlockTime     int64                  `protobuf:"varint,5,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`             // 被回滚区块的时间（Unix 秒）
+	CanonicalSlot uint64                 `protobuf:"varint,6,opt,name=canonical_slot,json=canonicalSlot,proto3" json:"canonical_slot,omitempty"` // 判定依据的规范链 slot（finalized slot 或冲突区块的 slot）
+	Reason        RollbackReason         `protobuf:"varint,7,opt,name=reason,proto3,enum=pb.RollbackReason" json:"reason,omitempty"`             // 回滚原因
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlotRollbackEvent) Reset() {
+	*x = SlotRollbackEvent{}
+	mi := &file_event_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlotRollbackEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlotRollbackEvent) ProtoMessage() {}
+
+func (x *SlotRollbackEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlotRollbackEvent.ProtoReflect.Descriptor instead.
+func (*SlotRollbackEvent) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SlotRollbackEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_UNKNOWN
+}
+
+func (x *SlotRollbackEvent) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *SlotRollbackEvent) GetBlockHash() []byte {
+	if x != nil {
+		return x.BlockHash
+	}
+	return nil
+}
+
+func (x *SlotRollbackEvent) GetParentSlot() uint64 {
+	if x != nil {
+		return x.ParentSlot
+	}
+	return 0
+}
+
+func (x *SlotRollbackEvent) GetBlockTime() int64 {
+	if x != nil {
+		return x.BlockTime
+	}
+	return 0
+}
+
+func (x *SlotRollbackEvent) GetCanonicalSlot() uint64 {
+	if x != nil {
+		return x.CanonicalSlot
+	}
+	return 0
+}
+
+func (x *SlotRollbackEvent) GetReason() RollbackReason {
+	if x != nil {
+		return x.Reason
+	}
+	return RollbackReason_ROLLBACK_UNKNOWN
+}
+
+// slot finalized 标记：该 (slot, block_hash) 已 finalized，之前下发的事件不会再被回滚
+// 会广播到 event topic 的所有分区，需要结算级确定性的消费方（如记账、PnL）可等待该标记
+type SlotFinalizedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`             // 事件类型（SLOT_FINALIZED）
+	Slot          uint64                 `protobuf:"varint,2,opt,name=slot,proto3" json:"slot,omitempty"`                               // finalized 的 slot
+	BlockHash     []byte                 `protobuf:"bytes,3,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`     // 区块哈希
+	ParentSlot    uint64                 `protobuf:"varint,4,opt,name=parent_slot,json=parentSlot,proto3" json:"parent_slot,omitempty"` // 父 slot
+	BlockTime     int64                  `protobuf:"varint,5,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`    // 区块时间（Unix 秒）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlotFinalizedEvent) Reset() {
+	*x = SlotFinalizedEvent{}
+	mi := &file_event_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlotFinalizedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlotFinalizedEvent) ProtoMessage() {}
+
+func (x *SlotFinalizedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlotFinalizedEvent.ProtoReflect.Descriptor instead.
+func (*SlotFinalizedEvent) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SlotFinalizedEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_UNKNOWN
+}
+
+func (x *SlotFinalizedEvent) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *SlotFinalizedEvent) GetBlockHash() []byte {
+	if x != nil {
+		return x.BlockHash
+	}
+	return nil
+}
+
+func (x *SlotFinalizedEvent) GetParentSlot() uint64 {
+	if x != nil {
+		return x.ParentSlot
+	}
+	return 0
+}
+
+func (x *SlotFinalizedEvent) GetBlockTime() int64 {
+	if x != nil {
+		return x.BlockTime
+	}
+	return 0
+}
+
 var File_event_proto protoreflect.FileDescriptor
 
 const file_event_proto_rawDesc = "" +
@@ -2029,7 +3465,7 @@ const file_event_proto_rawDesc = "" +
 	"TokenPrice\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\fR\x05token\x12\x14\n" +
 	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x1a\n" +
-	"\bdecimals\x18\x03 \x01(\rR\bdecimals\"\xfc\x02\n" +
+	"\bdecimals\x18\x03 \x01(\rR\bdecimals\"\xc8\x05\n" +
 	"\x05Event\x12&\n" +
 	"\x05trade\x18\x01 \x01(\v2\x0e.pb.TradeEventH\x00R\x05trade\x12/\n" +
 	"\btransfer\x18\x02 \x01(\v2\x11.pb.TransferEventH\x00R\btransfer\x122\n" +
@@ -2038,8 +3474,24 @@ const file_event_proto_rawDesc = "" +
 	"\x04burn\x18\x05 \x01(\v2\r.pb.BurnEventH\x00R\x04burn\x122\n" +
 	"\abalance\x18\x06 \x01(\v2\x16.pb.BalanceUpdateEventH\x00R\abalance\x12,\n" +
 	"\amigrate\x18\a \x01(\v2\x10.pb.MigrateEventH\x00R\amigrate\x12/\n" +
-	"\x05token\x18\b \x01(\v2\x17.pb.LaunchpadTokenEventH\x00R\x05tokenB\a\n" +
-	"\x05event\"\xa5\x06\n" +
+	"\x05token\x18\b \x01(\v2\x17.pb.LaunchpadTokenEventH\x00R\x05token\x123\n" +
+	"\brollback\x18\t \x01(\v2\x15.pb.SlotRollbackEventH\x00R\brollback\x126\n" +
+	"\tfinalized\x18\n" +
+	" \x01(\v2\x16.pb.SlotFinalizedEventH\x00R\tfinalized\x129\n" +
+	"\ffailed_trade\x18\v \x01(\v2\x14.pb.FailedTradeEventH\x00R\vfailedTrade\x12,\n" +
+	"\aaccount\x18\f \x01(\v2\x10.pb.AccountEventH\x00R\aaccount\x123\n" +
+	"\n" +
+	"route_swap\x18\r \x01(\v2\x12.pb.RouteSwapEventH\x00R\trouteSwap\x12?\n" +
+	"\x0eorderbook_fill\x18\x0e \x01(\v2\x16.pb.OrderbookFillEventH\x00R\rorderbookFillB\a\n" +
+	"\x05event\"\x84\x02\n" +
+	"\x05TxFee\x12\x10\n" +
+	"\x03fee\x18\x01 \x01(\x04R\x03fee\x12\x19\n" +
+	"\bbase_fee\x18\x02 \x01(\x04R\abaseFee\x12!\n" +
+	"\fpriority_fee\x18\x03 \x01(\x04R\vpriorityFee\x12,\n" +
+	"\x12compute_unit_price\x18\x04 \x01(\x04R\x10computeUnitPrice\x12,\n" +
+	"\x12compute_unit_limit\x18\x05 \x01(\rR\x10computeUnitLimit\x124\n" +
+	"\x16compute_units_consumed\x18\x06 \x01(\x04R\x14computeUnitsConsumed\x12\x19\n" +
+	"\bjito_tip\x18\a \x01(\x04R\ajitoTip\"\xf7\a\n" +
 	"\n" +
 	"TradeEvent\x12!\n" +
 	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
@@ -2069,7 +3521,66 @@ const file_event_proto_rawDesc = "" +
 	"\x12pair_token_balance\x18\x14 \x01(\x04R\x10pairTokenBalance\x12,\n" +
 	"\x12pair_quote_balance\x18\x15 \x01(\x04R\x10pairQuoteBalance\x12,\n" +
 	"\x12user_token_balance\x18\x16 \x01(\x04R\x10userTokenBalance\x12,\n" +
-	"\x12user_quote_balance\x18\x17 \x01(\x04R\x10userQuoteBalance\"\xdb\x03\n" +
+	"\x12user_quote_balance\x18\x17 \x01(\x04R\x10userQuoteBalance\x12 \n" +
+	"\x06tx_fee\x18\x18 \x01(\v2\t.pb.TxFeeR\x05txFee\x12(\n" +
+	"\x10token_amount_net\x18\x19 \x01(\x04R\x0etokenAmountNet\x123\n" +
+	"\x16quote_token_amount_net\x18\x1a \x01(\x04R\x13quoteTokenAmountNet\x12&\n" +
+	"\x0fparent_event_id\x18\x1b \x01(\x04R\rparentEventId\x12'\n" +
+	"\x05fills\x18\x1c \x03(\v2\x11.pb.OrderbookFillR\x05fills\"\x84\x02\n" +
+	"\rOrderbookFill\x12\x14\n" +
+	"\x05maker\x18\x01 \x01(\fR\x05maker\x12$\n" +
+	"\x0eprice_in_ticks\x18\x02 \x01(\x04R\fpriceInTicks\x12\x1b\n" +
+	"\tbase_lots\x18\x03 \x01(\x04R\bbaseLots\x12\x1d\n" +
+	"\n" +
+	"quote_lots\x18\x04 \x01(\x04R\tquoteLots\x12!\n" +
+	"\ftoken_amount\x18\x05 \x01(\x04R\vtokenAmount\x12,\n" +
+	"\x12quote_token_amount\x18\x06 \x01(\x04R\x10quoteTokenAmount\x12*\n" +
+	"\x11maker_open_orders\x18\a \x01(\fR\x0fmakerOpenOrders\"\xa8\x03\n" +
+	"\x12OrderbookFillEvent\x12!\n" +
+	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\x04R\aeventId\x12\x12\n" +
+	"\x04slot\x18\x03 \x01(\x04R\x04slot\x12\x1d\n" +
+	"\n" +
+	"block_time\x18\x04 \x01(\x03R\tblockTime\x12\x17\n" +
+	"\atx_hash\x18\x05 \x01(\fR\x06txHash\x12\x18\n" +
+	"\asigners\x18\x06 \x03(\fR\asigners\x12\x10\n" +
+	"\x03dex\x18\a \x01(\rR\x03dex\x12!\n" +
+	"\fpair_address\x18\b \x01(\fR\vpairAddress\x12\x1f\n" +
+	"\vuser_wallet\x18\t \x01(\fR\n" +
+	"userWallet\x12*\n" +
+	"\x11taker_open_orders\x18\n" +
+	" \x01(\fR\x0ftakerOpenOrders\x12!\n" +
+	"\x04side\x18\v \x01(\x0e2\r.pb.EventTypeR\x04side\x12'\n" +
+	"\x05fills\x18\f \x03(\v2\x11.pb.OrderbookFillR\x05fills\x12 \n" +
+	"\x06tx_fee\x18\r \x01(\v2\t.pb.TxFeeR\x05txFee\"\x95\x05\n" +
+	"\x10FailedTradeEvent\x12!\n" +
+	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\x04R\aeventId\x12\x12\n" +
+	"\x04slot\x18\x03 \x01(\x04R\x04slot\x12\x1d\n" +
+	"\n" +
+	"block_time\x18\x04 \x01(\x03R\tblockTime\x12\x17\n" +
+	"\atx_hash\x18\x05 \x01(\fR\x06txHash\x12\x18\n" +
+	"\asigners\x18\x06 \x03(\fR\asigners\x12\x10\n" +
+	"\x03dex\x18\a \x01(\rR\x03dex\x12!\n" +
+	"\fpair_address\x18\b \x01(\fR\vpairAddress\x12\x1f\n" +
+	"\vuser_wallet\x18\t \x01(\fR\n" +
+	"userWallet\x12!\n" +
+	"\x04side\x18\n" +
+	" \x01(\x0e2\r.pb.EventTypeR\x04side\x12\x1f\n" +
+	"\vinput_token\x18\v \x01(\fR\n" +
+	"inputToken\x12!\n" +
+	"\foutput_token\x18\f \x01(\fR\voutputToken\x12\x1b\n" +
+	"\tamount_in\x18\r \x01(\x04R\bamountIn\x12\x1d\n" +
+	"\n" +
+	"amount_out\x18\x0e \x01(\x04R\tamountOut\x12\x1b\n" +
+	"\texact_out\x18\x0f \x01(\bR\bexactOut\x12\x10\n" +
+	"\x03fee\x18\x10 \x01(\x04R\x03fee\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x11 \x01(\tR\terrorCode\x12$\n" +
+	"\x0eerror_ix_index\x18\x12 \x01(\x05R\ferrorIxIndex\x12\"\n" +
+	"\rerror_ix_code\x18\x13 \x01(\tR\verrorIxCode\x12*\n" +
+	"\x11custom_error_code\x18\x14 \x01(\rR\x0fcustomErrorCode\x12 \n" +
+	"\x06tx_fee\x18\x15 \x01(\v2\t.pb.TxFeeR\x05txFee\"\xfc\x04\n" +
 	"\rTransferEvent\x12!\n" +
 	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
 	"\bevent_id\x18\x02 \x01(\x04R\aeventId\x12\x12\n" +
@@ -2090,7 +3601,12 @@ const file_event_proto_rawDesc = "" +
 	"\x06amount\x18\f \x01(\x04R\x06amount\x12\x1a\n" +
 	"\bdecimals\x18\r \x01(\rR\bdecimals\x12*\n" +
 	"\x11src_token_balance\x18\x0e \x01(\x04R\x0fsrcTokenBalance\x12,\n" +
-	"\x12dest_token_balance\x18\x0f \x01(\x04R\x10destTokenBalance\"\xd9\a\n" +
+	"\x12dest_token_balance\x18\x0f \x01(\x04R\x10destTokenBalance\x12 \n" +
+	"\x06tx_fee\x18\x10 \x01(\v2\t.pb.TxFeeR\x05txFee\x12\x10\n" +
+	"\x03fee\x18\x11 \x01(\x04R\x03fee\x12+\n" +
+	"\x11withheld_withdraw\x18\x12 \x01(\bR\x10withheldWithdraw\x12&\n" +
+	"\x0fparent_event_id\x18\x13 \x01(\x04R\rparentEventId\x12\x16\n" +
+	"\x06native\x18\x14 \x01(\bR\x06native\"\xda\b\n" +
 	"\x0eLiquidityEvent\x12!\n" +
 	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
 	"\bevent_id\x18\x02 \x01(\x04R\aeventId\x12\x12\n" +
@@ -2120,7 +3636,10 @@ const file_event_proto_rawDesc = "" +
 	"\x12user_token_balance\x18\x16 \x01(\x04R\x10userTokenBalance\x12,\n" +
 	"\x12user_quote_balance\x18\x17 \x01(\x04R\x10userQuoteBalance\x129\n" +
 	"\rtoken_program\x18\x18 \x01(\x0e2\x14.pb.TokenProgramTypeR\ftokenProgram\x12D\n" +
-	"\x13quote_token_program\x18\x19 \x01(\x0e2\x14.pb.TokenProgramTypeR\x11quoteTokenProgram\"\xee\x02\n" +
+	"\x13quote_token_program\x18\x19 \x01(\x0e2\x14.pb.TokenProgramTypeR\x11quoteTokenProgram\x12 \n" +
+	"\x06tx_fee\x18\x1a \x01(\v2\t.pb.TxFeeR\x05txFee\x12(\n" +
+	"\x10token_amount_net\x18\x1b \x01(\x04R\x0etokenAmountNet\x123\n" +
+	"\x16quote_token_amount_net\x18\x1c \x01(\x04R\x13quoteTokenAmountNet\"\x90\x03\n" +
 	"\vMintToEvent\x12!\n" +
 	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
 	"\bevent_id\x18\x02 \x01(\x04R\aeventId\x12\x12\n" +
@@ -2136,7 +3655,8 @@ const file_event_proto_rawDesc = "" +
 	"\x06amount\x18\n" +
 	" \x01(\x04R\x06amount\x12\x1a\n" +
 	"\bdecimals\x18\v \x01(\rR\bdecimals\x12(\n" +
-	"\x10to_token_balance\x18\f \x01(\x04R\x0etoTokenBalance\"\xf8\x02\n" +
+	"\x10to_token_balance\x18\f \x01(\x04R\x0etoTokenBalance\x12 \n" +
+	"\x06tx_fee\x18\r \x01(\v2\t.pb.TxFeeR\x05txFee\"\x9a\x03\n" +
 	"\tBurnEvent\x12!\n" +
 	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
 	"\bevent_id\x18\x02 \x01(\x04R\aeventId\x12\x12\n" +
@@ -2151,7 +3671,56 @@ const file_event_proto_rawDesc = "" +
 	"\x06amount\x18\n" +
 	" \x01(\x04R\x06amount\x12\x1a\n" +
 	"\bdecimals\x18\v \x01(\rR\bdecimals\x12,\n" +
-	"\x12from_token_balance\x18\f \x01(\x04R\x10fromTokenBalance\"\xab\x02\n" +
+	"\x12from_token_balance\x18\f \x01(\x04R\x10fromTokenBalance\x12 \n" +
+	"\x06tx_fee\x18\r \x01(\v2\t.pb.TxFeeR\x05txFee\"\xda\x02\n" +
+	"\fAccountEvent\x12!\n" +
+	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\x04R\aeventId\x12\x12\n" +
+	"\x04slot\x18\x03 \x01(\x04R\x04slot\x12\x1d\n" +
+	"\n" +
+	"block_time\x18\x04 \x01(\x03R\tblockTime\x12\x17\n" +
+	"\atx_hash\x18\x05 \x01(\fR\x06txHash\x12\x18\n" +
+	"\asigners\x18\x06 \x03(\fR\asigners\x12\x18\n" +
+	"\aaccount\x18\a \x01(\fR\aaccount\x12\"\n" +
+	"\fcounterparty\x18\b \x01(\fR\fcounterparty\x12\x14\n" +
+	"\x05owner\x18\t \x01(\fR\x05owner\x12\x1a\n" +
+	"\blamports\x18\n" +
+	" \x01(\x04R\blamports\x12\x14\n" +
+	"\x05space\x18\v \x01(\x04R\x05space\x12 \n" +
+	"\x06tx_fee\x18\f \x01(\v2\t.pb.TxFeeR\x05txFee\"\xe7\x04\n" +
+	"\x0eRouteSwapEvent\x12!\n" +
+	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\x04R\aeventId\x12\x12\n" +
+	"\x04slot\x18\x03 \x01(\x04R\x04slot\x12\x1d\n" +
+	"\n" +
+	"block_time\x18\x04 \x01(\x03R\tblockTime\x12\x17\n" +
+	"\atx_hash\x18\x05 \x01(\fR\x06txHash\x12\x18\n" +
+	"\asigners\x18\x06 \x03(\fR\asigners\x12\x18\n" +
+	"\aprogram\x18\a \x01(\fR\aprogram\x12\x1f\n" +
+	"\vuser_wallet\x18\b \x01(\fR\n" +
+	"userWallet\x12.\n" +
+	"\x13user_source_account\x18\t \x01(\fR\x11userSourceAccount\x12*\n" +
+	"\x11user_dest_account\x18\n" +
+	" \x01(\fR\x0fuserDestAccount\x12\x1d\n" +
+	"\n" +
+	"input_mint\x18\v \x01(\fR\tinputMint\x12\x1f\n" +
+	"\voutput_mint\x18\f \x01(\fR\n" +
+	"outputMint\x12!\n" +
+	"\finput_amount\x18\r \x01(\x04R\vinputAmount\x12#\n" +
+	"\routput_amount\x18\x0e \x01(\x04R\foutputAmount\x12%\n" +
+	"\x0einput_decimals\x18\x0f \x01(\rR\rinputDecimals\x12'\n" +
+	"\x0foutput_decimals\x18\x10 \x01(\rR\x0eoutputDecimals\x12 \n" +
+	"\x04hops\x18\x11 \x03(\v2\f.pb.RouteHopR\x04hops\x12 \n" +
+	"\x06tx_fee\x18\x12 \x01(\v2\t.pb.TxFeeR\x05txFee\"\xca\x01\n" +
+	"\bRouteHop\x12\x10\n" +
+	"\x03amm\x18\x01 \x01(\fR\x03amm\x12\x1d\n" +
+	"\n" +
+	"input_mint\x18\x02 \x01(\fR\tinputMint\x12!\n" +
+	"\finput_amount\x18\x03 \x01(\x04R\vinputAmount\x12\x1f\n" +
+	"\voutput_mint\x18\x04 \x01(\fR\n" +
+	"outputMint\x12#\n" +
+	"\routput_amount\x18\x05 \x01(\x04R\foutputAmount\x12$\n" +
+	"\x0etrade_event_id\x18\x06 \x01(\x04R\ftradeEventId\"\xab\x02\n" +
 	"\x12BalanceUpdateEvent\x12!\n" +
 	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
 	"\bevent_id\x18\x02 \x01(\x04R\aeventId\x12\x12\n" +
@@ -2165,7 +3734,7 @@ const file_event_proto_rawDesc = "" +
 	"preBalance\x12!\n" +
 	"\fpost_balance\x18\t \x01(\x04R\vpostBalance\x12\x1a\n" +
 	"\bdecimals\x18\n" +
-	" \x01(\rR\bdecimals\"\xcd\n" +
+	" \x01(\rR\bdecimals\"\xef\n" +
 	"\n" +
 	"\fMigrateEvent\x12!\n" +
 	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
@@ -2202,7 +3771,8 @@ const file_event_proto_rawDesc = "" +
 	"\x16src_pair_token_balance\x18\x1d \x01(\x04R\x13srcPairTokenBalance\x123\n" +
 	"\x16src_pair_quote_balance\x18\x1e \x01(\x04R\x13srcPairQuoteBalance\x125\n" +
 	"\x17dest_pair_token_balance\x18\x1f \x01(\x04R\x14destPairTokenBalance\x125\n" +
-	"\x17dest_pair_quote_balance\x18  \x01(\x04R\x14destPairQuoteBalance\"\xf7\x03\n" +
+	"\x17dest_pair_quote_balance\x18  \x01(\x04R\x14destPairQuoteBalance\x12 \n" +
+	"\x06tx_fee\x18! \x01(\v2\t.pb.TxFeeR\x05txFee\"\x99\x04\n" +
 	"\x13LaunchpadTokenEvent\x12!\n" +
 	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x19\n" +
 	"\bevent_id\x18\x02 \x01(\x04R\aeventId\x12\x12\n" +
@@ -2223,7 +3793,28 @@ const file_event_proto_rawDesc = "" +
 	"\x06symbol\x18\x0e \x01(\tR\x06symbol\x12\x12\n" +
 	"\x04name\x18\x0f \x01(\tR\x04name\x12\x10\n" +
 	"\x03uri\x18\x10 \x01(\tR\x03uri\x129\n" +
-	"\rtoken_program\x18\x11 \x01(\x0e2\x14.pb.TokenProgramTypeR\ftokenProgram*\xae\x01\n" +
+	"\rtoken_program\x18\x11 \x01(\x0e2\x14.pb.TokenProgramTypeR\ftokenProgram\x12 \n" +
+	"\x06tx_fee\x18\x12 \x01(\v2\t.pb.TxFeeR\x05txFee\"\xfc\x01\n" +
+	"\x11SlotRollbackEvent\x12!\n" +
+	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x12\n" +
+	"\x04slot\x18\x02 \x01(\x04R\x04slot\x12\x1d\n" +
+	"\n" +
+	"block_hash\x18\x03 \x01(\fR\tblockHash\x12\x1f\n" +
+	"\vparent_slot\x18\x04 \x01(\x04R\n" +
+	"parentSlot\x12\x1d\n" +
+	"\n" +
+	"block_time\x18\x05 \x01(\x03R\tblockTime\x12%\n" +
+	"\x0ecanonical_slot\x18\x06 \x01(\x04R\rcanonicalSlot\x12*\n" +
+	"\x06reason\x18\a \x01(\x0e2\x12.pb.RollbackReasonR\x06reason\"\xaa\x01\n" +
+	"\x12SlotFinalizedEvent\x12!\n" +
+	"\x04type\x18\x01 \x01(\x0e2\r.pb.EventTypeR\x04type\x12\x12\n" +
+	"\x04slot\x18\x02 \x01(\x04R\x04slot\x12\x1d\n" +
+	"\n" +
+	"block_hash\x18\x03 \x01(\fR\tblockHash\x12\x1f\n" +
+	"\vparent_slot\x18\x04 \x01(\x04R\n" +
+	"parentSlot\x12\x1d\n" +
+	"\n" +
+	"block_time\x18\x05 \x01(\x03R\tblockTime*\x9a\x02\n" +
 	"\aDexType\x12\x0f\n" +
 	"\vDEX_UNKNOWN\x10\x00\x12\x12\n" +
 	"\x0eDEX_RAYDIUM_V4\x10\x01\x12\x14\n" +
@@ -2232,12 +3823,18 @@ const file_event_proto_rawDesc = "" +
 	"\vDEX_PUMPFUN\x10\x04\x12\x14\n" +
 	"\x10DEX_RAYDIUM_CPMM\x10\x05\x12\x14\n" +
 	"\x10DEX_METEORA_DLMM\x10\x06\x12\x16\n" +
-	"\x12DEX_ORCA_WHIRLPOOL\x10\a*B\n" +
+	"\x12DEX_ORCA_WHIRLPOOL\x10\a\x12\x14\n" +
+	"\x10DEX_METEORA_DAMM\x10\b\x12\x19\n" +
+	"\x15DEX_RAYDIUM_LAUNCHLAB\x10\t\x12\x13\n" +
+	"\x0fDEX_METEORA_DBC\x10\n" +
+	"\x12\x13\n" +
+	"\x0fDEX_OPENBOOK_V2\x10\v\x12\x0f\n" +
+	"\vDEX_PHOENIX\x10\f*B\n" +
 	"\x10TokenProgramType\x12\x0f\n" +
 	"\vTOKEN_OTHER\x10\x00\x12\r\n" +
 	"\tTOKEN_SPL\x10\x01\x12\x0e\n" +
 	"\n" +
-	"TOKEN_2022\x10\x02*\xdf\x01\n" +
+	"TOKEN_2022\x10\x02*\xe3\x02\n" +
 	"\tEventType\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\r\n" +
 	"\tTRADE_BUY\x10\x01\x12\x0e\n" +
@@ -2252,8 +3849,21 @@ const file_event_proto_rawDesc = "" +
 	"\vCREATE_POOL\x10\t\x12\v\n" +
 	"\aMIGRATE\x10\n" +
 	"\x12\x13\n" +
-	"\x0fLAUNCHPAD_TOKEN\x10\v\x12\x12\n" +
-	"\x0eBALANCE_UPDATE\x10<B\x17Z\x15dex-indexer-sol/pb;pbb\x06proto3"
+	"\x0fLAUNCHPAD_TOKEN\x10\v\x12\x10\n" +
+	"\fFAILED_TRADE\x10\f\x12\x12\n" +
+	"\x0eACCOUNT_CREATE\x10\r\x12\x11\n" +
+	"\rACCOUNT_CLOSE\x10\x0e\x12\x0e\n" +
+	"\n" +
+	"ROUTE_SWAP\x10\x0f\x12\x12\n" +
+	"\x0eORDERBOOK_FILL\x10\x10\x12\x12\n" +
+	"\x0eBALANCE_UPDATE\x10<\x12\x11\n" +
+	"\rSLOT_ROLLBACK\x10=\x12\x12\n" +
+	"\x0eSLOT_FINALIZED\x10>*x\n" +
+	"\x0eRollbackReason\x12\x14\n" +
+	"\x10ROLLBACK_UNKNOWN\x10\x00\x12\x1c\n" +
+	"\x18ROLLBACK_PARENT_MISMATCH\x10\x01\x12\x1a\n" +
+	"\x16ROLLBACK_NOT_FINALIZED\x10\x02\x12\x16\n" +
+	"\x12ROLLBACK_DEAD_SLOT\x10\x03B\x17Z\x15dex-indexer-sol/pb;pbb\x06proto3"
 
 var (
 	file_event_proto_rawDescOnce sync.Once
@@ -2267,51 +3877,90 @@ func file_event_proto_rawDescGZIP() []byte {
 	return file_event_proto_rawDescData
 }
 
-var file_event_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_event_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_event_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_event_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_event_proto_goTypes = []any{
 	(DexType)(0),                // 0: pb.DexType
 	(TokenProgramType)(0),       // 1: pb.TokenProgramType
 	(EventType)(0),              // 2: pb.EventType
-	(*Events)(nil),              // 3: pb.Events
-	(*TokenPrice)(nil),          // 4: pb.TokenPrice
-	(*Event)(nil),               // 5: pb.Event
-	(*TradeEvent)(nil),          // 6: pb.TradeEvent
-	(*TransferEvent)(nil),       // 7: pb.TransferEvent
-	(*LiquidityEvent)(nil),      // 8: pb.LiquidityEvent
-	(*MintToEvent)(nil),         // 9: pb.MintToEvent
-	(*BurnEvent)(nil),           // 10: pb.BurnEvent
-	(*BalanceUpdateEvent)(nil),  // 11: pb.BalanceUpdateEvent
-	(*MigrateEvent)(nil),        // 12: pb.MigrateEvent
-	(*LaunchpadTokenEvent)(nil), // 13: pb.LaunchpadTokenEvent
+	(RollbackReason)(0),         // 3: pb.RollbackReason
+	(*Events)(nil),              // 4: pb.Events
+	(*TokenPrice)(nil),          // 5: pb.TokenPrice
+	(*Event)(nil),               // 6: pb.Event
+	(*TxFee)(nil),               // 7: pb.TxFee
+	(*TradeEvent)(nil),          // 8: pb.TradeEvent
+	(*OrderbookFill)(nil),       // 9: pb.OrderbookFill
+	(*OrderbookFillEvent)(nil),  // 10: pb.OrderbookFillEvent
+	(*FailedTradeEvent)(nil),    // 11: pb.FailedTradeEvent
+	(*TransferEvent)(nil),       // 12: pb.TransferEvent
+	(*LiquidityEvent)(nil),      // 13: pb.LiquidityEvent
+	(*MintToEvent)(nil),         // 14: pb.MintToEvent
+	(*BurnEvent)(nil),           // 15: pb.BurnEvent
+	(*AccountEvent)(nil),        // 16: pb.AccountEvent
+	(*RouteSwapEvent)(nil),      // 17: pb.RouteSwapEvent
+	(*RouteHop)(nil),            // 18: pb.RouteHop
+	(*BalanceUpdateEvent)(nil),  // 19: pb.BalanceUpdateEvent
+	(*MigrateEvent)(nil),        // 20: pb.MigrateEvent
+	(*LaunchpadTokenEvent)(nil), // 21: pb.LaunchpadTokenEvent
+	(*SlotRollbackEvent)(nil),   // 22: pb.SlotRollbackEvent
+	(*SlotFinalizedEvent)(nil),  // 23: pb.SlotFinalizedEvent
 }
 var file_event_proto_depIdxs = []int32{
-	5,  // 0: pb.Events.events:type_name -> pb.Event
-	4,  // 1: pb.Events.quote_prices:type_name -> pb.TokenPrice
-	6,  // 2: pb.Event.trade:type_name -> pb.TradeEvent
-	7,  // 3: pb.Event.transfer:type_name -> pb.TransferEvent
-	8,  // 4: pb.Event.liquidity:type_name -> pb.LiquidityEvent
-	9,  // 5: pb.Event.mint:type_name -> pb.MintToEvent
-	10, // 6: pb.Event.burn:type_name -> pb.BurnEvent
-	11, // 7: pb.Event.balance:type_name -> pb.BalanceUpdateEvent
-	12, // 8: pb.Event.migrate:type_name -> pb.MigrateEvent
-	13, // 9: pb.Event.token:type_name -> pb.LaunchpadTokenEvent
-	2,  // 10: pb.TradeEvent.type:type_name -> pb.EventType
-	2,  // 11: pb.TransferEvent.type:type_name -> pb.EventType
-	2,  // 12: pb.LiquidityEvent.type:type_name -> pb.EventType
-	1,  // 13: pb.LiquidityEvent.token_program:type_name -> pb.TokenProgramType
-	1,  // 14: pb.LiquidityEvent.quote_token_program:type_name -> pb.TokenProgramType
-	2,  // 15: pb.MintToEvent.type:type_name -> pb.EventType
-	2,  // 16: pb.BurnEvent.type:type_name -> pb.EventType
-	2,  // 17: pb.BalanceUpdateEvent.type:type_name -> pb.EventType
-	2,  // 18: pb.MigrateEvent.type:type_name -> pb.EventType
-	2,  // 19: pb.LaunchpadTokenEvent.type:type_name -> pb.EventType
-	1,  // 20: pb.LaunchpadTokenEvent.token_program:type_name -> pb.TokenProgramType
-	21, // [21:21] is the sub-list for method output_type
-	21, // [21:21] is the sub-list for method input_type
-	21, // [21:21] is the sub-list for extension type_name
-	21, // [21:21] is the sub-list for extension extendee
-	0,  // [0:21] is the sub-list for field type_name
+	6,  // 0: pb.Events.events:type_name -> pb.Event
+	5,  // 1: pb.Events.quote_prices:type_name -> pb.TokenPrice
+	8,  // 2: pb.Event.trade:type_name -> pb.TradeEvent
+	12, // 3: pb.Event.transfer:type_name -> pb.TransferEvent
+	13, // 4: pb.Event.liquidity:type_name -> pb.LiquidityEvent
+	14, // 5: pb.Event.mint:type_name -> pb.MintToEvent
+	15, // 6: pb.Event.burn:type_name -> pb.BurnEvent
+	19, // 7: pb.Event.balance:type_name -> pb.BalanceUpdateEvent
+	20, // 8: pb.Event.migrate:type_name -> pb.MigrateEvent
+	21, // 9: pb.Event.token:type_name -> pb.LaunchpadTokenEvent
+	22, // 10: pb.Event.rollback:type_name -> pb.SlotRollbackEvent
+	23, // 11: pb.Event.finalized:type_name -> pb.SlotFinalizedEvent
+	11, // 12: pb.Event.failed_trade:type_name -> pb.FailedTradeEvent
+	16, // 13: pb.Event.account:type_name -> pb.AccountEvent
+	17, // 14: pb.Event.route_swap:type_name -> pb.RouteSwapEvent
+	10, // 15: pb.Event.orderbook_fill:type_name -> pb.OrderbookFillEvent
+	2,  // 16: pb.TradeEvent.type:type_name -> pb.EventType
+	7,  // 17: pb.TradeEvent.tx_fee:type_name -> pb.TxFee
+	9,  // 18: pb.TradeEvent.fills:type_name -> pb.OrderbookFill
+	2,  // 19: pb.OrderbookFillEvent.type:type_name -> pb.EventType
+	2,  // 20: pb.OrderbookFillEvent.side:type_name -> pb.EventType
+	9,  // 21: pb.OrderbookFillEvent.fills:type_name -> pb.OrderbookFill
+	7,  // 22: pb.OrderbookFillEvent.tx_fee:type_name -> pb.TxFee
+	2,  // 23: pb.FailedTradeEvent.type:type_name -> pb.EventType
+	2,  // 24: pb.FailedTradeEvent.side:type_name -> pb.EventType
+	7,  // 25: pb.FailedTradeEvent.tx_fee:type_name -> pb.TxFee
+	2,  // 26: pb.TransferEvent.type:type_name -> pb.EventType
+	7,  // 27: pb.TransferEvent.tx_fee:type_name -> pb.TxFee
+	2,  // 28: pb.LiquidityEvent.type:type_name -> pb.EventType
+	1,  // 29: pb.LiquidityEvent.token_program:type_name -> pb.TokenProgramType
+	1,  // 30: pb.LiquidityEvent.quote_token_program:type_name -> pb.TokenProgramType
+	7,  // 31: pb.LiquidityEvent.tx_fee:type_name -> pb.TxFee
+	2,  // 32: pb.MintToEvent.type:type_name -> pb.EventType
+	7,  // 33: pb.MintToEvent.tx_fee:type_name -> pb.TxFee
+	2,  // 34: pb.BurnEvent.type:type_name -> pb.EventType
+	7,  // 35: pb.BurnEvent.tx_fee:type_name -> pb.TxFee
+	2,  // 36: pb.AccountEvent.type:type_name -> pb.EventType
+	7,  // 37: pb.AccountEvent.tx_fee:type_name -> pb.TxFee
+	2,  // 38: pb.RouteSwapEvent.type:type_name -> pb.EventType
+	18, // 39: pb.RouteSwapEvent.hops:type_name -> pb.RouteHop
+	7,  // 40: pb.RouteSwapEvent.tx_fee:type_name -> pb.TxFee
+	2,  // 41: pb.BalanceUpdateEvent.type:type_name -> pb.EventType
+	2,  // 42: pb.MigrateEvent.type:type_name -> pb.EventType
+	7,  // 43: pb.MigrateEvent.tx_fee:type_name -> pb.TxFee
+	2,  // 44: pb.LaunchpadTokenEvent.type:type_name -> pb.EventType
+	1,  // 45: pb.LaunchpadTokenEvent.token_program:type_name -> pb.TokenProgramType
+	7,  // 46: pb.LaunchpadTokenEvent.tx_fee:type_name -> pb.TxFee
+	2,  // 47: pb.SlotRollbackEvent.type:type_name -> pb.EventType
+	3,  // 48: pb.SlotRollbackEvent.reason:type_name -> pb.RollbackReason
+	2,  // 49: pb.SlotFinalizedEvent.type:type_name -> pb.EventType
+	50, // [50:50] is the sub-list for method output_type
+	50, // [50:50] is the sub-list for method input_type
+	50, // [50:50] is the sub-list for extension type_name
+	50, // [50:50] is the sub-list for extension extendee
+	0,  // [0:50] is the sub-list for field type_name
 }
 
 func init() { file_event_proto_init() }
@@ -2328,14 +3977,20 @@ func file_event_proto_init() {
 		(*Event_Balance)(nil),
 		(*Event_Migrate)(nil),
 		(*Event_Token)(nil),
+		(*Event_Rollback)(nil),
+		(*Event_Finalized)(nil),
+		(*Event_FailedTrade)(nil),
+		(*Event_Account)(nil),
+		(*Event_RouteSwap)(nil),
+		(*Event_OrderbookFill)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_event_proto_rawDesc), len(file_event_proto_rawDesc)),
-			NumEnums:      3,
-			NumMessages:   11,
+			NumEnums:      4,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   0,
 		},